@@ -161,18 +161,20 @@ func ServiceProviders() fx.Option {
 		func(
 			budgetRepo *infrastructure.BudgetRepository,
 			categoryService *category.Service,
+			reportRepo *infrastructure.ReportRepository,
 			userChecker *shared.UserCheckerService,
 		) *budget.Service {
-			return budget.NewService(budgetRepo, categoryService, userChecker)
+			return budget.NewService(budgetRepo, categoryService, reportRepo, userChecker)
 		},
 		// InvestmentService
 		func(
 			investmentRepo *infrastructure.InvestmentRepository,
 			transactionRepo *infrastructure.TransactionRepository,
 			accountService *account.Service,
+			categoryService *category.Service,
 			userChecker *shared.UserCheckerService,
 		) *investment.Service {
-			return investment.NewService(investmentRepo, transactionRepo, accountService, userChecker)
+			return investment.NewService(investmentRepo, transactionRepo, accountService, categoryService, userChecker)
 		},
 		// GoalService (sem TransactionService inicialmente, será atualizado depois)
 		func(
@@ -187,7 +189,6 @@ func ServiceProviders() fx.Option {
 			transactionRepo *infrastructure.TransactionRepository,
 			categoryService *category.Service,
 			accountService *account.Service,
-			budgetService *budget.Service,
 			goalService *goal.Service,
 			investmentService *investment.Service,
 			userChecker *shared.UserCheckerService,
@@ -196,7 +197,6 @@ func ServiceProviders() fx.Option {
 				transactionRepo,
 				categoryService,
 				accountService,
-				budgetService,
 				goalService,
 				investmentService,
 				userChecker,
@@ -225,10 +225,12 @@ func ServiceProviders() fx.Option {
 		func(
 			reportRepo *infrastructure.ReportRepository,
 			userService *user.Service,
+			investmentService *investment.Service,
 		) *report.Service {
 			return &report.Service{
 				Repository:  reportRepo,
 				UserService: userService,
+				Investments: investmentService,
 			}
 		},
 		// CreditCardService
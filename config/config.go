@@ -9,11 +9,18 @@ import (
 )
 
 type Config struct {
-	Database    DatabaseConfig
-	Server      ServerConfig
-	JWT         JWTConfig
-	App         AppConfig
-	GoogleOAuth GoogleOAuthConfig
+	Database     DatabaseConfig
+	Server       ServerConfig
+	JWT          JWTConfig
+	App          AppConfig
+	GoogleOAuth  GoogleOAuthConfig
+	Jobs         JobsConfig
+	Market       MarketConfig
+	Notification NotificationConfig
+	Webhook      WebhookConfig
+	Events       EventsConfig
+	Queue        QueueConfig
+	Admin        AdminConfig
 }
 
 type DatabaseConfig struct {
@@ -48,6 +55,74 @@ type AppConfig struct {
 	LogLevel    string
 }
 
+type JobsConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type MarketConfig struct {
+	Provider        string
+	DataDir         string
+	HTTPURL         string
+	HTTPToken       string
+	HTTPTimeout     time.Duration
+	RevalueInterval time.Duration
+}
+
+// NotificationConfig configura os canais externos de notificação. Sem SMTP_HOST o e-mail fica desativado
+// e sem as chaves VAPID o Web Push também; a caixa de entrada funciona sempre.
+type NotificationConfig struct {
+	SMTPHost        string
+	SMTPPort        int
+	SMTPUsername    string
+	SMTPPassword    string
+	SMTPFrom        string
+	SMTPTLSMode     string
+	SMTPTimeout     time.Duration
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	VAPIDSubject    string
+	PushTTL         time.Duration
+	PushTimeout     time.Duration
+}
+
+// WebhookConfig configura a entrega dos webhooks dos usuários. AllowInsecure aceita endpoints http e
+// destinos em rede privada, para testar com receptores locais; não deve ser usado em produção.
+type WebhookConfig struct {
+	Timeout        time.Duration
+	RetryInterval  time.Duration
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	AllowInsecure  bool
+}
+
+// EventsConfig configura o despacho dos eventos do domínio gravados na outbox. PollInterval é a
+// frequência com que as novas tentativas são buscadas; eventos novos são despachados logo após o commit.
+type EventsConfig struct {
+	PollInterval   time.Duration
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	Retention      time.Duration
+}
+
+// QueueConfig configura a fila de jobs em segundo plano. Concurrency é o número de jobs executados ao
+// mesmo tempo por instância; MaxAttempts vale para os tipos de job que não definem o próprio limite.
+type QueueConfig struct {
+	PollInterval   time.Duration
+	Concurrency    int
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	Retention      time.Duration
+}
+
+// AdminConfig configura os endpoints administrativos. Sem ADMIN_TOKEN eles ficam desativados.
+type AdminConfig struct {
+	Token string
+}
+
 type GoogleOAuthConfig struct {
 	ClientID     string
 	ClientSecret string
@@ -65,11 +140,18 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 	return &Config{
-		Database:    database,
-		Server:      loadServerConfig(),
-		JWT:         jwtCfg,
-		App:         loadAppConfig(),
-		GoogleOAuth: loadGoogleOAuthConfig(),
+		Database:     database,
+		Server:       loadServerConfig(),
+		JWT:          jwtCfg,
+		App:          loadAppConfig(),
+		GoogleOAuth:  loadGoogleOAuthConfig(),
+		Jobs:         loadJobsConfig(),
+		Market:       loadMarketConfig(),
+		Notification: loadNotificationConfig(),
+		Webhook:      loadWebhookConfig(),
+		Events:       loadEventsConfig(),
+		Queue:        loadQueueConfig(),
+		Admin:        loadAdminConfig(),
 	}, nil
 }
 
@@ -211,3 +293,89 @@ func loadGoogleOAuthConfig() GoogleOAuthConfig {
 		Enabled:      enabled,
 	}
 }
+
+func loadJobsConfig() JobsConfig {
+	enabledStr := strings.ToLower(strings.TrimSpace(getEnv("JOBS_ENABLED", "true")))
+	enabled := enabledStr == "true" || enabledStr == "1"
+	interval := getEnvAsDuration("JOBS_INTERVAL", time.Hour)
+
+	return JobsConfig{
+		Enabled:  enabled,
+		Interval: interval,
+	}
+}
+
+func loadMarketConfig() MarketConfig {
+	provider := strings.ToLower(strings.TrimSpace(getEnv("MARKET_PROVIDER", "")))
+	dataDir := getEnv("MARKET_DATA_DIR", "./data/market")
+	httpURL := getEnv("MARKET_HTTP_URL", "")
+	httpToken := getEnv("MARKET_HTTP_TOKEN", "")
+	httpTimeout := getEnvAsDuration("MARKET_HTTP_TIMEOUT", 10*time.Second)
+	revalueInterval := getEnvAsDuration("MARKET_REVALUE_INTERVAL", 24*time.Hour)
+
+	return MarketConfig{
+		Provider:        provider,
+		DataDir:         dataDir,
+		HTTPURL:         httpURL,
+		HTTPToken:       httpToken,
+		HTTPTimeout:     httpTimeout,
+		RevalueInterval: revalueInterval,
+	}
+}
+
+func loadNotificationConfig() NotificationConfig {
+	return NotificationConfig{
+		SMTPHost:        getEnv("SMTP_HOST", ""),
+		SMTPPort:        getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername:    getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:        getEnv("SMTP_FROM", "Fynance <no-reply@fynance.local>"),
+		SMTPTLSMode:     strings.ToLower(strings.TrimSpace(getEnv("SMTP_TLS_MODE", "starttls"))),
+		SMTPTimeout:     getEnvAsDuration("SMTP_TIMEOUT", 10*time.Second),
+		VAPIDPublicKey:  getEnv("VAPID_PUBLIC_KEY", ""),
+		VAPIDPrivateKey: getEnv("VAPID_PRIVATE_KEY", ""),
+		VAPIDSubject:    getEnv("VAPID_SUBJECT", "mailto:suporte@fynance.local"),
+		PushTTL:         getEnvAsDuration("PUSH_TTL", 24*time.Hour),
+		PushTimeout:     getEnvAsDuration("PUSH_TIMEOUT", 10*time.Second),
+	}
+}
+
+func loadWebhookConfig() WebhookConfig {
+	allowInsecureStr := strings.ToLower(strings.TrimSpace(getEnv("WEBHOOK_ALLOW_INSECURE", "false")))
+
+	return WebhookConfig{
+		Timeout:        getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		RetryInterval:  getEnvAsDuration("WEBHOOK_RETRY_INTERVAL", 30*time.Second),
+		MaxAttempts:    getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		RetryBaseDelay: getEnvAsDuration("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second),
+		RetryMaxDelay:  getEnvAsDuration("WEBHOOK_RETRY_MAX_DELAY", 6*time.Hour),
+		AllowInsecure:  allowInsecureStr == "true" || allowInsecureStr == "1",
+	}
+}
+
+func loadEventsConfig() EventsConfig {
+	return EventsConfig{
+		PollInterval:   getEnvAsDuration("EVENTS_POLL_INTERVAL", time.Second),
+		MaxAttempts:    getEnvAsInt("EVENTS_MAX_ATTEMPTS", 10),
+		RetryBaseDelay: getEnvAsDuration("EVENTS_RETRY_BASE_DELAY", 5*time.Second),
+		RetryMaxDelay:  getEnvAsDuration("EVENTS_RETRY_MAX_DELAY", time.Hour),
+		Retention:      getEnvAsDuration("EVENTS_RETENTION", 7*24*time.Hour),
+	}
+}
+
+func loadQueueConfig() QueueConfig {
+	return QueueConfig{
+		PollInterval:   getEnvAsDuration("QUEUE_POLL_INTERVAL", time.Second),
+		Concurrency:    getEnvAsInt("QUEUE_CONCURRENCY", 4),
+		MaxAttempts:    getEnvAsInt("QUEUE_MAX_ATTEMPTS", 5),
+		RetryBaseDelay: getEnvAsDuration("QUEUE_RETRY_BASE_DELAY", 30*time.Second),
+		RetryMaxDelay:  getEnvAsDuration("QUEUE_RETRY_MAX_DELAY", time.Hour),
+		Retention:      getEnvAsDuration("QUEUE_RETENTION", 7*24*time.Hour),
+	}
+}
+
+func loadAdminConfig() AdminConfig {
+	return AdminConfig{
+		Token: strings.TrimSpace(getEnv("ADMIN_TOKEN", "")),
+	}
+}
@@ -0,0 +1,7 @@
+package contracts
+
+import "Fynance/internal/domain/achievement"
+
+type AchievementSummaryResponse struct {
+	Summary *achievement.Summary `json:"summary"`
+}
@@ -53,3 +53,38 @@ type BudgetStatusResponse struct {
 	Status     string  `json:"status"`
 	AlertAt    float64 `json:"alertAt"`
 }
+
+type BudgetTemplatePreviewRequest struct {
+	Mode     string  `json:"mode" binding:"required,oneof=RULE HISTORY"`
+	Template string  `json:"template"`
+	Income   float64 `json:"income" binding:"omitempty,gt=0"`
+	Months   int     `json:"months" binding:"omitempty,min=1,max=24"`
+	Month    int     `json:"month" binding:"omitempty,min=1,max=12"`
+	Year     int     `json:"year" binding:"omitempty,min=2000,max=2100"`
+}
+
+type BudgetTemplateApplyItem struct {
+	CategoryId string  `json:"category_id" binding:"required"`
+	Amount     float64 `json:"amount" binding:"required,gt=0"`
+}
+
+type BudgetTemplateApplyRequest struct {
+	Month       int                       `json:"month" binding:"required,min=1,max=12"`
+	Year        int                       `json:"year" binding:"required,min=2000,max=2100"`
+	AlertAt     float64                   `json:"alert_at" binding:"omitempty,min=0,max=100"`
+	IsRecurring bool                      `json:"is_recurring"`
+	Items       []BudgetTemplateApplyItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type BudgetTemplateListResponse struct {
+	Templates map[string]budget.RuleTemplate `json:"templates"`
+}
+
+type BudgetTemplatePreviewResponse struct {
+	Plan *budget.BudgetPlan `json:"plan"`
+}
+
+type BudgetTemplateApplyResponse struct {
+	Message string                  `json:"message"`
+	Result  *budget.ApplyPlanResult `json:"result"`
+}
@@ -0,0 +1,12 @@
+package contracts
+
+type ReminderSettingsUpdateRequest struct {
+	Enabled  *bool `json:"enabled" binding:"omitempty"`
+	LeadDays []int `json:"lead_days" binding:"omitempty,max=5,dive,min=0,max=30"`
+}
+
+type CalendarFeedTokenResponse struct {
+	Message  string `json:"message"`
+	Token    string `json:"token"`
+	FeedPath string `json:"feedPath"`
+}
@@ -22,13 +22,22 @@ type GoalUpdateRequestDomain struct {
 }
 
 type CreateInvestmentRequestDomain struct {
-	UserId        ulid.ULID `json:"user_id"`
-	AccountId     ulid.ULID `json:"account_id"`
-	CategoryId    ulid.ULID `json:"category_id"`
-	Type          string    `json:"type"`
-	Name          string    `json:"name"`
-	InitialAmount float64   `json:"initial_amount"`
-	ReturnRate    float64   `json:"return_rate"`
+	UserId        ulid.ULID  `json:"user_id"`
+	AccountId     ulid.ULID  `json:"account_id"`
+	CategoryId    ulid.ULID  `json:"category_id"`
+	Type          string     `json:"type"`
+	Name          string     `json:"name"`
+	InitialAmount float64    `json:"initial_amount"`
+	ReturnRate    float64    `json:"return_rate"`
+	Ticker        string     `json:"ticker"`
+	Quantity      float64    `json:"quantity"`
+	UnitPrice     float64    `json:"unit_price"`
+	Fees          float64    `json:"fees"`
+	TradeDate     time.Time  `json:"trade_date"`
+	Indexer       string     `json:"indexer"`
+	IndexerRate   float64    `json:"indexer_rate"`
+	MaturityDate  *time.Time `json:"maturity_date"`
+	AssetClass    string     `json:"asset_class"`
 }
 
 type ContributionRequestDomain struct {
@@ -52,4 +61,5 @@ type UpdateInvestmentRequestDomain struct {
 	Name           *string   `json:"name,omitempty"`
 	Type           *string   `json:"type,omitempty"`
 	CurrentBalance *float64  `json:"current_balance,omitempty"`
+	AssetClass     *string   `json:"asset_class,omitempty"`
 }
@@ -15,9 +15,10 @@ type GoalUpdateRequest struct {
 }
 
 type GoalContributionRequest struct {
-	AccountID   string  `json:"account_id" binding:"required"`
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Description string  `json:"description" binding:"omitempty,max=255"`
+	AccountID    string  `json:"account_id" binding:"required"`
+	InvestmentID string  `json:"investment_id"`
+	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	Description  string  `json:"description" binding:"omitempty,max=255"`
 }
 
 type GoalWithdrawRequest struct {
@@ -25,3 +26,17 @@ type GoalWithdrawRequest struct {
 	Amount      float64 `json:"amount" binding:"required,gt=0"`
 	Description string  `json:"description" binding:"omitempty,max=255"`
 }
+
+type GoalScheduleRequest struct {
+	AccountID    string     `json:"account_id" binding:"required"`
+	Amount       float64    `json:"amount" binding:"omitempty,gt=0"`
+	UseSuggested bool       `json:"use_suggested"`
+	Frequency    string     `json:"frequency" binding:"required,oneof=WEEKLY BIWEEKLY MONTHLY"`
+	DayOfMonth   int        `json:"day_of_month" binding:"omitempty,min=1,max=31"`
+	DayOfWeek    int        `json:"day_of_week" binding:"omitempty,min=0,max=6"`
+	StartDate    *time.Time `json:"start_date"`
+}
+
+type GoalInvestmentLinkRequest struct {
+	InvestmentID string `json:"investment_id" binding:"required"`
+}
@@ -7,6 +7,7 @@ type HealthScoreResponse struct {
 	Color           string   `json:"color"`
 	BudgetHealth    int      `json:"budgetHealth"`
 	GoalsHealth     int      `json:"goalsHealth"`
+	DebtHealth      int      `json:"debtHealth"`
 	SavingsHealth   int      `json:"savingsHealth"`
 	Recommendations []string `json:"recommendations"`
 }
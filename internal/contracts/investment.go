@@ -1,18 +1,30 @@
 package contracts
 
+import "time"
+
 type InvestmentCreateRequest struct {
-	AccountID     string  `json:"account_id" binding:"required"`
-	Type          string  `json:"type" binding:"required,oneof=CDB LCI LCA TESOURO_DIRETO ACOES FUNDOS CRIPTOMOEDAS PREVIDENCIA"`
-	Name          string  `json:"name" binding:"required"`
-	InitialAmount float64 `json:"initial_amount" binding:"required,gt=0"`
-	ReturnRate    float64 `json:"return_rate" binding:"omitempty"`
-	CategoryID    string  `json:"category_id" binding:"omitempty"`
+	AccountID     string     `json:"account_id" binding:"required"`
+	Type          string     `json:"type" binding:"required,oneof=CDB LCI LCA TESOURO_DIRETO ACOES FII ETF BDR FUNDOS CRIPTOMOEDAS PREVIDENCIA"`
+	Name          string     `json:"name" binding:"required"`
+	InitialAmount float64    `json:"initial_amount" binding:"omitempty,gt=0"`
+	ReturnRate    float64    `json:"return_rate" binding:"omitempty"`
+	CategoryID    string     `json:"category_id" binding:"omitempty"`
+	Ticker        string     `json:"ticker" binding:"omitempty,max=20"`
+	Quantity      float64    `json:"quantity" binding:"omitempty,gt=0"`
+	UnitPrice     float64    `json:"unit_price" binding:"omitempty,gt=0"`
+	Fees          float64    `json:"fees" binding:"omitempty,gte=0"`
+	TradeDate     *time.Time `json:"trade_date"`
+	Indexer       string     `json:"indexer" binding:"omitempty,oneof=PREFIXADO CDI IPCA SELIC"`
+	IndexerRate   float64    `json:"indexer_rate" binding:"omitempty"`
+	MaturityDate  *time.Time `json:"maturity_date"`
+	AssetClass    string     `json:"asset_class" binding:"omitempty,max=40"`
 }
 
 type InvestmentUpdateRequest struct {
 	Name           *string  `json:"name" binding:"omitempty"`
-	Type           *string  `json:"type" binding:"omitempty,oneof=CDB LCI LCA TESOURO_DIRETO ACOES FUNDOS CRIPTOMOEDAS PREVIDENCIA"`
+	Type           *string  `json:"type" binding:"omitempty,oneof=CDB LCI LCA TESOURO_DIRETO ACOES FII ETF BDR FUNDOS CRIPTOMOEDAS PREVIDENCIA"`
 	CurrentBalance *float64 `json:"current_balance" binding:"omitempty,gte=0"`
+	AssetClass     *string  `json:"asset_class" binding:"omitempty,max=40"`
 }
 
 type InvestmentContributionRequest struct {
@@ -33,3 +45,40 @@ type InvestmentReturnResponse struct {
 	Profit           float64 `json:"profit"`
 	ReturnPercentage float64 `json:"returnPercentage"`
 }
+
+type InvestmentTradeRequest struct {
+	AccountID   string     `json:"account_id" binding:"required"`
+	Quantity    float64    `json:"quantity" binding:"required,gt=0"`
+	UnitPrice   float64    `json:"unit_price" binding:"required,gt=0"`
+	Fees        float64    `json:"fees" binding:"omitempty,gte=0"`
+	TradeDate   *time.Time `json:"trade_date"`
+	Description string     `json:"description" binding:"omitempty,max=255"`
+}
+
+type InvestmentIncomeRequest struct {
+	AccountID     string     `json:"account_id" binding:"required"`
+	Type          string     `json:"type" binding:"required,oneof=DIVIDENDO JCP RENDIMENTO CUPOM"`
+	AmountPerUnit float64    `json:"amount_per_unit" binding:"omitempty,gte=0"`
+	Amount        float64    `json:"amount" binding:"omitempty,gte=0"`
+	WithheldTax   *float64   `json:"withheld_tax" binding:"omitempty,gte=0"`
+	ExDate        *time.Time `json:"ex_date"`
+	PaymentDate   *time.Time `json:"payment_date"`
+	Description   string     `json:"description" binding:"omitempty,max=255"`
+}
+
+type AllocationTargetItem struct {
+	Class  string  `json:"class" binding:"required,max=40"`
+	Weight float64 `json:"weight" binding:"required,gt=0,lte=100"`
+}
+
+type AllocationTargetsRequest struct {
+	Targets []AllocationTargetItem `json:"targets" binding:"dive"`
+}
+
+type BrokerageNoteRequest struct {
+	AccountID string            `json:"account_id" binding:"omitempty"`
+	Format    string            `json:"format" binding:"required,oneof=TEXT CSV"`
+	Content   string            `json:"content" binding:"required"`
+	Tickers   map[string]string `json:"tickers"`
+	Types     map[string]string `json:"types"`
+}
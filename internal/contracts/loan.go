@@ -0,0 +1,34 @@
+package contracts
+
+import "time"
+
+type LoanCreateRequest struct {
+	AccountID    string    `json:"account_id" binding:"required"`
+	CategoryID   *string   `json:"category_id" binding:"omitempty"`
+	Name         string    `json:"name" binding:"required,max=100"`
+	Kind         string    `json:"kind" binding:"required,oneof=IMOVEL VEICULO PESSOAL OUTRO"`
+	System       string    `json:"system" binding:"required,oneof=SAC PRICE"`
+	Principal    float64   `json:"principal" binding:"required,gt=0"`
+	InterestRate float64   `json:"interest_rate" binding:"gte=0"`
+	RatePeriod   string    `json:"rate_period" binding:"required,oneof=MONTHLY YEARLY"`
+	TermMonths   int       `json:"term_months" binding:"required,gt=0,lte=600"`
+	FirstDueDate time.Time `json:"first_due_date" binding:"required"`
+}
+
+type LoanUpdateRequest struct {
+	Name       *string `json:"name" binding:"omitempty,max=100"`
+	AccountID  *string `json:"account_id" binding:"omitempty"`
+	CategoryID *string `json:"category_id" binding:"omitempty"`
+}
+
+type LoanInstallmentPaymentRequest struct {
+	AccountID *string    `json:"account_id" binding:"omitempty"`
+	Date      *time.Time `json:"date"`
+}
+
+type LoanExtraPaymentRequest struct {
+	AccountID *string    `json:"account_id" binding:"omitempty"`
+	Date      *time.Time `json:"date"`
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	Strategy  string     `json:"strategy" binding:"required,oneof=REDUCE_TERM REDUCE_INSTALLMENT"`
+}
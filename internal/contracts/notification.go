@@ -0,0 +1,32 @@
+package contracts
+
+type NotificationPreferencesUpdateRequest struct {
+	Locale   *string             `json:"locale" binding:"omitempty,oneof=pt-BR en"`
+	Channels map[string][]string `json:"channels" binding:"omitempty"`
+}
+
+type NotificationUnreadCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+type NotificationReadAllResponse struct {
+	Message string `json:"message"`
+	Updated int64  `json:"updated"`
+}
+
+type PushPublicKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// PushSubscriptionRequest segue o formato de PushSubscription.toJSON() do navegador
+type PushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,url,max=1000"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required"`
+		Auth   string `json:"auth" binding:"required"`
+	} `json:"keys" binding:"required"`
+}
+
+type PushUnsubscribeRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+}
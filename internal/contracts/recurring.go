@@ -8,16 +8,27 @@ import (
 )
 
 type RecurringCreateRequest struct {
-	Type        string     `json:"type" binding:"required,oneof=RECEIPT EXPENSE"`
-	CategoryId  string     `json:"category_id" binding:"required"`
+	Type        string     `json:"type" binding:"required_without=Kind,omitempty,oneof=RECEIPT EXPENSE"`
+	CategoryId  string     `json:"category_id" binding:"omitempty"`
 	AccountId   string     `json:"account_id" binding:"omitempty"`
 	Amount      float64    `json:"amount" binding:"required,gt=0"`
 	Description string     `json:"description" binding:"omitempty,max=255"`
-	Frequency   string     `json:"frequency" binding:"required,oneof=DAILY WEEKLY MONTHLY YEARLY"`
+	Frequency   string     `json:"frequency" binding:"required_without=Rule,omitempty,oneof=DAILY WEEKLY MONTHLY YEARLY"`
 	DayOfMonth  int        `json:"day_of_month" binding:"omitempty,min=1,max=31"`
 	DayOfWeek   int        `json:"day_of_week" binding:"omitempty,min=0,max=6"`
 	StartDate   time.Time  `json:"start_date" binding:"required"`
 	EndDate     *time.Time `json:"end_date" binding:"omitempty"`
+
+	Interval              int    `json:"interval" binding:"omitempty,min=1,max=1000"`
+	Rule                  string `json:"rule" binding:"omitempty,max=255"`
+	BusinessDayAdjustment string `json:"business_day_adjustment" binding:"omitempty,oneof=NONE PREVIOUS NEXT"`
+	IsEstimated           bool   `json:"is_estimated" binding:"omitempty"`
+
+	Kind                 string  `json:"kind" binding:"omitempty,oneof=TRANSACTION TRANSFER GOAL INVESTMENT CREDIT_CARD"`
+	DestinationAccountId *string `json:"destination_account_id" binding:"omitempty"`
+	GoalId               *string `json:"goal_id" binding:"omitempty"`
+	InvestmentId         *string `json:"investment_id" binding:"omitempty"`
+	CreditCardId         *string `json:"credit_card_id" binding:"omitempty"`
 }
 
 type RecurringUpdateRequest struct {
@@ -26,6 +37,10 @@ type RecurringUpdateRequest struct {
 	IsActive    *bool      `json:"is_active" binding:"omitempty"`
 	EndDate     *time.Time `json:"end_date" binding:"omitempty"`
 	NextDue     *time.Time `json:"next_due" binding:"omitempty"`
+
+	Rule                  *string `json:"rule" binding:"omitempty,max=255"`
+	BusinessDayAdjustment *string `json:"business_day_adjustment" binding:"omitempty,oneof=NONE PREVIOUS NEXT"`
+	IsEstimated           *bool   `json:"is_estimated" binding:"omitempty"`
 }
 
 type RecurringCreateResponse struct {
@@ -51,3 +66,32 @@ type RecurringProcessResponse struct {
 	Transaction *transaction.Transaction        `json:"transaction"`
 	Recurring   *recurring.RecurringTransaction `json:"recurring"`
 }
+
+type RecurringDetectedResponse struct {
+	Subscriptions []recurring.DetectedSubscription `json:"subscriptions"`
+	Total         int                              `json:"total"`
+}
+
+type RecurringConvertDetectedRequest struct {
+	AccountId  *string    `json:"account_id" binding:"omitempty"`
+	CategoryId *string    `json:"category_id" binding:"omitempty"`
+	Amount     *float64   `json:"amount" binding:"omitempty,gt=0"`
+	StartDate  *time.Time `json:"start_date" binding:"omitempty"`
+}
+
+type RecurringOccurrenceListResponse struct {
+	Occurrences []*recurring.EstimatedOccurrence `json:"occurrences"`
+	Total       int                              `json:"total"`
+}
+
+type RecurringOccurrenceConfirmRequest struct {
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	AccountId *string    `json:"account_id" binding:"omitempty"`
+	Date      *time.Time `json:"date" binding:"omitempty"`
+}
+
+type RecurringOccurrenceResponse struct {
+	Message     string                         `json:"message"`
+	Occurrence  *recurring.EstimatedOccurrence `json:"occurrence"`
+	Transaction *transaction.Transaction       `json:"transaction,omitempty"`
+}
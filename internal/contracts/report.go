@@ -13,3 +13,7 @@ type YearlyReportResponse struct {
 type CategoryReportResponse struct {
 	Report *report.CategoryReport `json:"report"`
 }
+
+type TaxReportResponse struct {
+	Report *report.TaxReport `json:"report"`
+}
@@ -0,0 +1,20 @@
+package contracts
+
+type RoundUpRuleCreateRequest struct {
+	AccountID string `json:"account_id" binding:"required"`
+	GoalID    string `json:"goal_id" binding:"required"`
+	Increment int    `json:"increment" binding:"required,oneof=1 5 10"`
+	Frequency string `json:"frequency" binding:"required,oneof=DAILY WEEKLY"`
+}
+
+type RoundUpRuleUpdateRequest struct {
+	GoalID    *string `json:"goal_id" binding:"omitempty"`
+	Increment *int    `json:"increment" binding:"omitempty,oneof=1 5 10"`
+	Frequency *string `json:"frequency" binding:"omitempty,oneof=DAILY WEEKLY"`
+	IsActive  *bool   `json:"is_active" binding:"omitempty"`
+}
+
+type RoundUpSweepResponse struct {
+	Message string  `json:"message"`
+	Amount  float64 `json:"amount"`
+}
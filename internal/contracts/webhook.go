@@ -0,0 +1,19 @@
+package contracts
+
+type WebhookEndpointCreateRequest struct {
+	URL         string   `json:"url" binding:"required,max=500"`
+	Description string   `json:"description" binding:"max=255"`
+	Events      []string `json:"events" binding:"required,min=1"`
+}
+
+// WebhookEndpointUpdateRequest altera só os campos enviados; events substitui a lista inteira
+type WebhookEndpointUpdateRequest struct {
+	URL         *string  `json:"url" binding:"omitempty,max=500"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	Events      []string `json:"events" binding:"omitempty,min=1"`
+	Active      *bool    `json:"active"`
+}
+
+type WebhookEventTypesResponse struct {
+	Events []string `json:"events"`
+}
@@ -0,0 +1,60 @@
+package achievement
+
+import (
+	"math"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// UserAchievement é uma conquista desbloqueada pelo usuário.
+// ReferenceId identifica o recurso de origem em regras por recurso (ex.: a meta que atingiu 50%).
+type UserAchievement struct {
+	Id          ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId      ulid.ULID `gorm:"type:varchar(26);not null;uniqueIndex:idx_user_achievements_unique,priority:1" json:"userId"`
+	Code        string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_achievements_unique,priority:2" json:"code"`
+	ReferenceId string    `gorm:"type:varchar(26);not null;default:'';uniqueIndex:idx_user_achievements_unique,priority:3" json:"referenceId,omitempty"`
+	XP          int       `gorm:"not null;default:0" json:"xp"`
+	UnlockedAt  time.Time `gorm:"not null" json:"unlockedAt"`
+}
+
+func (UserAchievement) TableName() string {
+	return "user_achievements"
+}
+
+// AchievementStatus combina a definição da regra com o andamento do usuário
+type AchievementStatus struct {
+	Rule
+	Unlocked bool    `json:"unlocked"`
+	Count    int     `json:"count"`
+	Progress float64 `json:"progress"`
+}
+
+type Summary struct {
+	XP             int                  `json:"xp"`
+	Level          int                  `json:"level"`
+	CurrentLevelXP int                  `json:"currentLevelXp"`
+	NextLevelXP    int                  `json:"nextLevelXp"`
+	Unlocked       []*UserAchievement   `json:"unlocked"`
+	Achievements   []*AchievementStatus `json:"achievements"`
+}
+
+// LevelForXP retorna o nível para o XP acumulado. O nível n exige 100*n*(n-1)/2 XP.
+func LevelForXP(xp int) int {
+	if xp <= 0 {
+		return 1
+	}
+	level := int(math.Floor((1 + math.Sqrt(1+8*float64(xp)/100)) / 2))
+	if level < 1 {
+		level = 1
+	}
+	return level
+}
+
+// XPForLevel retorna o XP acumulado necessário para alcançar o nível
+func XPForLevel(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	return 100 * level * (level - 1) / 2
+}
@@ -0,0 +1,26 @@
+package achievement
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// BudgetMonth resume os orçamentos de um mês: quantos existiam e quantos estouraram
+type BudgetMonth struct {
+	Month    int
+	Year     int
+	Budgets  int
+	Exceeded int
+}
+
+type AchievementRepository interface {
+	// Create grava a conquista e devolve false quando ela já estava desbloqueada
+	Create(ctx context.Context, achievement *UserAchievement) (bool, error)
+	ListByUser(ctx context.Context, userID ulid.ULID) ([]*UserAchievement, error)
+	GetCounters(ctx context.Context, userID ulid.ULID) (map[Metric]float64, error)
+	GetGoalProgress(ctx context.Context, userID ulid.ULID) (map[string]float64, error)
+	GetBudgetMonths(ctx context.Context, userID ulid.ULID, before time.Time) ([]BudgetMonth, error)
+	GetExpenseDays(ctx context.Context, userID ulid.ULID, since time.Time) ([]time.Time, error)
+}
@@ -0,0 +1,52 @@
+package achievement
+
+// Metric identifica um indicador calculado a partir dos dados do usuário
+type Metric string
+
+const (
+	MetricTransactions             Metric = "TRANSACTIONS"
+	MetricInvestments              Metric = "INVESTMENTS"
+	MetricGoalsCompleted           Metric = "GOALS_COMPLETED"
+	MetricGoalProgress             Metric = "GOAL_PROGRESS"
+	MetricInvoicesPaidOnTime       Metric = "INVOICES_PAID_ON_TIME"
+	MetricBudgetStreakMonths       Metric = "BUDGET_STREAK_MONTHS"
+	MetricExpenseLoggingStreakDays Metric = "EXPENSE_LOGGING_STREAK_DAYS"
+)
+
+// Rule descreve uma conquista de forma declarativa: ela é desbloqueada quando
+// a métrica atinge o limite. Regras PerReference são avaliadas por recurso
+// (ex.: cada meta) e podem ser desbloqueadas mais de uma vez.
+type Rule struct {
+	Code         string  `json:"code"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Icon         string  `json:"icon"`
+	XP           int     `json:"xp"`
+	Metric       Metric  `json:"metric"`
+	Threshold    float64 `json:"threshold"`
+	PerReference bool    `json:"perReference"`
+}
+
+// Rules é o catálogo de conquistas. Para criar uma nova conquista basta
+// adicionar uma entrada usando uma das métricas existentes.
+var Rules = []Rule{
+	{Code: "FIRST_TRANSACTION", Name: "Primeiro registro", Description: "Registre sua primeira transação", Icon: "pencil", XP: 10, Metric: MetricTransactions, Threshold: 1},
+	{Code: "TRANSACTIONS_100", Name: "Organizado", Description: "Registre 100 transações", Icon: "list", XP: 100, Metric: MetricTransactions, Threshold: 100},
+
+	{Code: "GOAL_25", Name: "Primeiro quarto", Description: "Atinja 25% de uma meta", Icon: "flag", XP: 25, Metric: MetricGoalProgress, Threshold: 25, PerReference: true},
+	{Code: "GOAL_50", Name: "Meio caminho", Description: "Atinja 50% de uma meta", Icon: "flag", XP: 50, Metric: MetricGoalProgress, Threshold: 50, PerReference: true},
+	{Code: "GOAL_75", Name: "Reta final", Description: "Atinja 75% de uma meta", Icon: "flag", XP: 75, Metric: MetricGoalProgress, Threshold: 75, PerReference: true},
+	{Code: "GOAL_100", Name: "Meta alcançada", Description: "Conclua uma meta", Icon: "trophy", XP: 150, Metric: MetricGoalProgress, Threshold: 100, PerReference: true},
+	{Code: "GOALS_COMPLETED_3", Name: "Colecionador de metas", Description: "Conclua 3 metas", Icon: "trophy", XP: 300, Metric: MetricGoalsCompleted, Threshold: 3},
+
+	{Code: "FIRST_INVESTMENT", Name: "Investidor", Description: "Cadastre seu primeiro investimento", Icon: "trending-up", XP: 100, Metric: MetricInvestments, Threshold: 1},
+
+	{Code: "INVOICE_ON_TIME", Name: "Em dia", Description: "Pague uma fatura até o vencimento", Icon: "credit-card", XP: 50, Metric: MetricInvoicesPaidOnTime, Threshold: 1},
+	{Code: "INVOICE_ON_TIME_6", Name: "Pagador exemplar", Description: "Pague 6 faturas até o vencimento", Icon: "credit-card", XP: 200, Metric: MetricInvoicesPaidOnTime, Threshold: 6},
+
+	{Code: "BUDGET_STREAK_3", Name: "Disciplina", Description: "Fique dentro do orçamento por 3 meses seguidos", Icon: "shield", XP: 150, Metric: MetricBudgetStreakMonths, Threshold: 3},
+	{Code: "BUDGET_STREAK_6", Name: "Mestre do orçamento", Description: "Fique dentro do orçamento por 6 meses seguidos", Icon: "shield", XP: 300, Metric: MetricBudgetStreakMonths, Threshold: 6},
+
+	{Code: "LOGGING_STREAK_7", Name: "Semana completa", Description: "Registre despesas por 7 dias seguidos", Icon: "calendar", XP: 50, Metric: MetricExpenseLoggingStreakDays, Threshold: 7},
+	{Code: "LOGGING_STREAK_30", Name: "Hábito formado", Description: "Registre despesas por 30 dias seguidos", Icon: "calendar", XP: 200, Metric: MetricExpenseLoggingStreakDays, Threshold: 30},
+}
@@ -0,0 +1,304 @@
+package achievement
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"Fynance/internal/domain/event"
+	"Fynance/internal/domain/shared"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Service struct {
+	Repository AchievementRepository
+	Rules      []Rule
+	shared.BaseService
+}
+
+var _ shared.AchievementEvaluator = (*Service)(nil)
+
+func NewService(repo AchievementRepository, userChecker *shared.UserCheckerService) *Service {
+	return &Service{
+		Repository: repo,
+		Rules:      Rules,
+		BaseService: shared.BaseService{
+			UserChecker: userChecker,
+		},
+	}
+}
+
+// metrics é o retrato dos indicadores do usuário usado na avaliação das regras
+type metrics struct {
+	values     map[Metric]float64
+	references map[Metric]map[string]float64
+}
+
+// EvaluateAchievements avalia as regras e desbloqueia as conquistas pendentes
+func (s *Service) EvaluateAchievements(ctx context.Context, userID ulid.ULID) error {
+	_, err := s.Evaluate(ctx, userID)
+	return err
+}
+
+// AchievementEvents lista os eventos que podem desbloquear conquistas, além de goal.milestone
+var AchievementEvents = []string{
+	shared.EventTransactionCreated,
+	shared.EventTransactionUpdated,
+	shared.EventTransactionDeleted,
+	shared.EventInvoicePaid,
+	shared.EventGoalCompleted,
+	shared.EventInvestmentCreated,
+}
+
+// HandleEvent reavalia as conquistas do usuário do evento. É o único caminho que desbloqueia conquistas;
+// a consulta do resumo só lê.
+func (s *Service) HandleEvent(ctx context.Context, evt *event.Event) error {
+	return s.EvaluateAchievements(ctx, evt.UserId)
+}
+
+// Evaluate avalia todas as regras e retorna as conquistas desbloqueadas nesta execução
+func (s *Service) Evaluate(ctx context.Context, userID ulid.ULID) ([]*UserAchievement, error) {
+	current, err := s.collectMetrics(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.unlock(ctx, userID, current)
+}
+
+func (s *Service) unlock(ctx context.Context, userID ulid.ULID, current *metrics) ([]*UserAchievement, error) {
+	existing, err := s.Repository.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	unlocked := make(map[string]struct{}, len(existing))
+	for _, a := range existing {
+		unlocked[unlockKey(a.Code, a.ReferenceId)] = struct{}{}
+	}
+
+	now := time.Now()
+	var created []*UserAchievement
+	for _, rule := range s.Rules {
+		for _, referenceID := range s.matches(rule, current) {
+			if _, ok := unlocked[unlockKey(rule.Code, referenceID)]; ok {
+				continue
+			}
+
+			entity := &UserAchievement{
+				Id:          pkg.GenerateULIDObject(),
+				UserId:      userID,
+				Code:        rule.Code,
+				ReferenceId: referenceID,
+				XP:          rule.XP,
+				UnlockedAt:  now,
+			}
+			inserted, err := s.Repository.Create(ctx, entity)
+			if err != nil {
+				return created, appErrors.NewDatabaseError(err)
+			}
+			unlocked[unlockKey(rule.Code, referenceID)] = struct{}{}
+			if !inserted {
+				// outra avaliação concorrente desbloqueou a mesma conquista
+				continue
+			}
+
+			created = append(created, entity)
+
+			logger.Info().
+				Str("user_id", userID.String()).
+				Str("achievement", rule.Code).
+				Str("reference_id", referenceID).
+				Msg("Conquista desbloqueada")
+		}
+	}
+
+	return created, nil
+}
+
+// GetSummary monta o resumo das conquistas a partir do que já foi desbloqueado pelos eventos
+func (s *Service) GetSummary(ctx context.Context, userID ulid.ULID) (*Summary, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.collectMetrics(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	achievements, err := s.Repository.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	counts := make(map[string]int)
+	xp := 0
+	for _, a := range achievements {
+		counts[a.Code]++
+		xp += a.XP
+	}
+
+	statuses := make([]*AchievementStatus, 0, len(s.Rules))
+	for _, rule := range s.Rules {
+		status := &AchievementStatus{
+			Rule:     rule,
+			Count:    counts[rule.Code],
+			Unlocked: counts[rule.Code] > 0,
+		}
+		status.Progress = s.progress(rule, current)
+		if status.Unlocked {
+			status.Progress = 100
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.SliceStable(achievements, func(i, j int) bool {
+		return achievements[i].UnlockedAt.After(achievements[j].UnlockedAt)
+	})
+
+	level := LevelForXP(xp)
+
+	return &Summary{
+		XP:             xp,
+		Level:          level,
+		CurrentLevelXP: XPForLevel(level),
+		NextLevelXP:    XPForLevel(level + 1),
+		Unlocked:       achievements,
+		Achievements:   statuses,
+	}, nil
+}
+
+// matches retorna as referências que satisfazem a regra ("" para regras globais)
+func (s *Service) matches(rule Rule, current *metrics) []string {
+	if rule.PerReference {
+		var refs []string
+		for referenceID, value := range current.references[rule.Metric] {
+			if value >= rule.Threshold {
+				refs = append(refs, referenceID)
+			}
+		}
+		sort.Strings(refs)
+		return refs
+	}
+
+	if current.values[rule.Metric] >= rule.Threshold {
+		return []string{""}
+	}
+	return nil
+}
+
+func (s *Service) progress(rule Rule, current *metrics) float64 {
+	if rule.Threshold <= 0 {
+		return 0
+	}
+
+	value := current.values[rule.Metric]
+	if rule.PerReference {
+		value = 0
+		for _, v := range current.references[rule.Metric] {
+			if v > value {
+				value = v
+			}
+		}
+	}
+
+	progress := value / rule.Threshold * 100
+	if progress > 100 {
+		progress = 100
+	}
+	return progress
+}
+
+func (s *Service) collectMetrics(ctx context.Context, userID ulid.ULID, now time.Time) (*metrics, error) {
+	counters, err := s.Repository.GetCounters(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	goalProgress, err := s.Repository.GetGoalProgress(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	budgetMonths, err := s.Repository.GetBudgetMonths(ctx, userID, monthStart)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	expenseDays, err := s.Repository.GetExpenseDays(ctx, userID, today.AddDate(-1, 0, 0))
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	values := make(map[Metric]float64, len(counters)+2)
+	for metric, value := range counters {
+		values[metric] = value
+	}
+	values[MetricBudgetStreakMonths] = float64(budgetStreak(budgetMonths, monthStart))
+	values[MetricExpenseLoggingStreakDays] = float64(dayStreak(expenseDays, today))
+
+	return &metrics{
+		values: values,
+		references: map[Metric]map[string]float64{
+			MetricGoalProgress: goalProgress,
+		},
+	}, nil
+}
+
+// budgetStreak conta os meses consecutivos, a partir do mês anterior a monthStart,
+// em que o usuário tinha orçamentos e nenhum foi estourado
+func budgetStreak(months []BudgetMonth, monthStart time.Time) int {
+	byMonth := make(map[string]BudgetMonth, len(months))
+	for _, m := range months {
+		byMonth[monthKey(m.Year, time.Month(m.Month))] = m
+	}
+
+	streak := 0
+	cursor := monthStart.AddDate(0, -1, 0)
+	for {
+		m, ok := byMonth[monthKey(cursor.Year(), cursor.Month())]
+		if !ok || m.Budgets == 0 || m.Exceeded > 0 {
+			return streak
+		}
+		streak++
+		cursor = cursor.AddDate(0, -1, 0)
+	}
+}
+
+// dayStreak conta os dias consecutivos com despesas registradas terminando hoje
+// (ou ontem, para não zerar a sequência antes do fim do dia)
+func dayStreak(days []time.Time, today time.Time) int {
+	set := make(map[string]struct{}, len(days))
+	for _, d := range days {
+		set[d.Format("2006-01-02")] = struct{}{}
+	}
+
+	cursor := today
+	if _, ok := set[cursor.Format("2006-01-02")]; !ok {
+		cursor = cursor.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for {
+		if _, ok := set[cursor.Format("2006-01-02")]; !ok {
+			return streak
+		}
+		streak++
+		cursor = cursor.AddDate(0, 0, -1)
+	}
+}
+
+func monthKey(year int, month time.Month) string {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+}
+
+func unlockKey(code, referenceID string) string {
+	return code + ":" + referenceID
+}
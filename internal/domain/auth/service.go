@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
+	"Fynance/internal/domain/shared"
 	"Fynance/internal/domain/user"
 	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
 
+	"github.com/oklog/ulid/v2"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/api/idtoken"
 )
@@ -17,6 +21,8 @@ type Service struct {
 	Repository     user.UserRepository
 	UserService    *user.Service
 	GoogleClientID string
+	// Notifier é opcional e avisa o usuário de cada novo acesso à conta
+	Notifier shared.Notifier
 }
 
 func NewService(
@@ -42,6 +48,7 @@ func (s *Service) Login(ctx context.Context, login Login) (*user.User, error) {
 	if err := PasswordValidate(login.Password, entity.Password); err != nil {
 		return nil, err
 	}
+	s.notifyLogin(ctx, entity.Id, "password")
 	return entity, nil
 }
 
@@ -121,9 +128,21 @@ func (s *Service) GoogleLogin(ctx context.Context, credential string) (*user.Use
 		return nil, err
 	}
 
+	s.notifyLogin(ctx, entity.Id, "google")
 	return entity, nil
 }
 
+// notifyLogin publica o evento de segurança do acesso; falhas não impedem o login
+func (s *Service) notifyLogin(ctx context.Context, userID ulid.ULID, method string) {
+	if s.Notifier == nil {
+		return
+	}
+	data := map[string]any{"method": method, "at": time.Now()}
+	if err := s.Notifier.Notify(ctx, userID, shared.NotificationSecurityLogin, data); err != nil {
+		logger.Warn().Err(err).Str("user_id", userID.String()).Msg("Erro ao notificar novo acesso")
+	}
+}
+
 func (s *Service) emailExists(ctx context.Context, email string) (bool, error) {
 	_, err := s.Repository.GetByEmail(ctx, email)
 	if err == nil {
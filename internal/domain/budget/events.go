@@ -0,0 +1,110 @@
+package budget
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"Fynance/internal/domain/event"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/transaction"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// transactionSnapshot traz os campos da transação publicada que afetam os orçamentos
+type transactionSnapshot struct {
+	UserId     ulid.ULID         `json:"userId"`
+	Type       transaction.Types `json:"type"`
+	CategoryId *ulid.ULID        `json:"categoryId"`
+	Amount     float64           `json:"amount"`
+	Date       time.Time         `json:"date"`
+}
+
+// spendingChange é um valor a somar ao gasto do orçamento da categoria no mês da data
+type spendingChange struct {
+	userID     ulid.ULID
+	categoryID ulid.ULID
+	amount     float64
+	date       time.Time
+}
+
+type spendingAlert struct {
+	budget           *Budget
+	notificationType string
+}
+
+// TransactionEvents lista os eventos de transação acompanhados pelos orçamentos
+var TransactionEvents = []string{
+	shared.EventTransactionCreated,
+	shared.EventTransactionUpdated,
+	shared.EventTransactionDeleted,
+}
+
+// HandleTransactionEvent mantém o gasto dos orçamentos a partir dos eventos de transação. As mudanças
+// de um evento são aplicadas juntas na transação do despacho, que também marca a entrega como
+// processada; por isso uma nova tentativa do mesmo evento não soma o gasto em dobro.
+func (s *Service) HandleTransactionEvent(ctx context.Context, evt *event.Event) error {
+	var changes []spendingChange
+	switch evt.Type {
+	case shared.EventTransactionCreated, shared.EventTransactionDeleted:
+		var snapshot transactionSnapshot
+		if err := evt.Decode(&snapshot); err != nil {
+			return err
+		}
+		sign := 1.0
+		if evt.Type == shared.EventTransactionDeleted {
+			sign = -1
+		}
+		changes = appendSpending(changes, &snapshot, sign)
+	case shared.EventTransactionUpdated:
+		var update struct {
+			Previous *transactionSnapshot `json:"previous"`
+			Current  *transactionSnapshot `json:"current"`
+		}
+		if err := evt.Decode(&update); err != nil {
+			return err
+		}
+		changes = appendSpending(changes, update.Previous, -1)
+		changes = appendSpending(changes, update.Current, 1)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var alerts []*spendingAlert
+	err := shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		alerts = alerts[:0]
+		for _, change := range changes {
+			alert, err := s.applySpending(ctx, change)
+			if err != nil {
+				return err
+			}
+			if alert != nil {
+				alerts = append(alerts, alert)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		s.notifyAlert(ctx, alert)
+	}
+	return nil
+}
+
+// appendSpending inclui a despesa categorizada com o sinal informado; outros tipos não afetam orçamentos
+func appendSpending(changes []spendingChange, snapshot *transactionSnapshot, sign float64) []spendingChange {
+	if snapshot == nil || snapshot.Type != transaction.Expense || snapshot.CategoryId == nil {
+		return changes
+	}
+	return append(changes, spendingChange{
+		userID:     snapshot.UserId,
+		categoryID: *snapshot.CategoryId,
+		amount:     sign * math.Abs(snapshot.Amount),
+		date:       snapshot.Date,
+	})
+}
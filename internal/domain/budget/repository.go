@@ -19,7 +19,9 @@ type BudgetRepository interface {
 	GetByID(ctx context.Context, budgetID, userID ulid.ULID) (*Budget, error)
 	GetByUserID(ctx context.Context, userID ulid.ULID, month, year int, filters *BudgetFilters, pagination *pkg.PaginationParams) ([]*Budget, int64, error)
 	GetByCategoryID(ctx context.Context, categoryID, userID ulid.ULID, month, year int) (*Budget, error)
+	ListByPeriod(ctx context.Context, userID ulid.ULID, month, year int) ([]*Budget, error)
 	UpdateSpent(ctx context.Context, budgetID ulid.ULID, amount float64) error
 	GetRecurring(ctx context.Context, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*Budget, int64, error)
 	GetSummary(ctx context.Context, userID ulid.ULID, month, year int) (*BudgetSummary, error)
+	CountByUserID(ctx context.Context, userID ulid.ULID) (int64, error)
 }
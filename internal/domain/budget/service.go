@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"Fynance/internal/domain/category"
+	"Fynance/internal/domain/report"
 	"Fynance/internal/domain/shared"
 	appErrors "Fynance/internal/errors"
 	"Fynance/internal/logger"
@@ -16,17 +17,23 @@ import (
 )
 
 type Service struct {
-	Repository      BudgetRepository
-	CategoryService *category.Service
+	Repository       BudgetRepository
+	CategoryService  *category.Service
+	ReportRepository report.ReportRepository
+	// Notifier é opcional; sem ele os alertas de orçamento não são enviados
+	Notifier shared.Notifier
+	// Events é opcional e publica o estouro do orçamento
+	Events shared.EventPublisher
+	// Transactor é opcional e aplica as mudanças de gasto de um mesmo evento de forma atômica
+	Transactor shared.Transactor
 	shared.BaseService
 }
 
-var _ shared.BudgetUpdater = (*Service)(nil)
-
-func NewService(repo BudgetRepository, categoryService *category.Service, userChecker *shared.UserCheckerService) *Service {
+func NewService(repo BudgetRepository, categoryService *category.Service, reportRepo report.ReportRepository, userChecker *shared.UserCheckerService) *Service {
 	return &Service{
-		Repository:      repo,
-		CategoryService: categoryService,
+		Repository:       repo,
+		CategoryService:  categoryService,
+		ReportRepository: reportRepo,
 		BaseService: shared.BaseService{
 			UserChecker: userChecker,
 		},
@@ -147,40 +154,88 @@ func (s *Service) GetBudgetSummary(ctx context.Context, userID ulid.ULID, month,
 	return s.Repository.GetSummary(ctx, userID, month, year)
 }
 
-func (s *Service) UpdateSpent(ctx context.Context, categoryID, userID ulid.ULID, amount float64) error {
-	return s.UpdateSpentWithDate(ctx, categoryID, userID, amount, time.Now())
-}
-
-func (s *Service) UpdateSpentWithDate(ctx context.Context, categoryID, userID ulid.ULID, amount float64, transactionDate time.Time) error {
-	budget, err := s.Repository.GetByCategoryID(ctx, categoryID, userID, int(transactionDate.Month()), transactionDate.Year())
-
+// applySpending soma o valor ao gasto do orçamento da categoria no mês da data. Sem orçamento para a
+// categoria nada muda; o alerta devolvido deve ser enviado depois da transação com notifyAlert.
+func (s *Service) applySpending(ctx context.Context, change spendingChange) (*spendingAlert, error) {
+	month, year := int(change.date.Month()), change.date.Year()
+	budget, err := s.Repository.GetByCategoryID(ctx, change.categoryID, change.userID, month, year)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resolvedID, resolveErr := s.resolveCategoryID(ctx, change.categoryID, change.userID)
+		if resolveErr == nil && resolvedID != change.categoryID {
+			budget, err = s.Repository.GetByCategoryID(ctx, resolvedID, change.userID, month, year)
+		}
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			resolvedID, resolveErr := s.resolveCategoryID(ctx, categoryID, userID)
-			if resolveErr == nil && resolvedID != categoryID {
-				budget, err = s.Repository.GetByCategoryID(ctx, resolvedID, userID, int(transactionDate.Month()), transactionDate.Year())
-				if err == nil && budget != nil {
-					return s.Repository.UpdateSpent(ctx, budget.Id, amount)
-				}
-			}
-
 			logger.Debug().
-				Str("category_id", categoryID.String()).
-				Str("user_id", userID.String()).
-				Int("month", int(transactionDate.Month())).
-				Int("year", transactionDate.Year()).
+				Str("category_id", change.categoryID.String()).
+				Str("user_id", change.userID.String()).
+				Int("month", month).
+				Int("year", year).
 				Msg("budget not found for category, skipping update")
-		} else {
-			logger.Error().
-				Err(err).
-				Str("category_id", categoryID.String()).
-				Str("user_id", userID.String()).
-				Msg("error getting budget for category")
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s.applySpent(ctx, budget, change.amount)
+}
+
+// applySpent soma o valor ao gasto do orçamento e devolve o alerta quando o gasto cruza o percentual
+// de alerta ou o valor do orçamento; o estouro também é publicado como evento
+func (s *Service) applySpent(ctx context.Context, budget *Budget, amount float64) (*spendingAlert, error) {
+	if err := s.Repository.UpdateSpent(ctx, budget.Id, amount); err != nil {
+		return nil, err
+	}
+	if amount <= 0 || budget.Amount <= 0 {
+		return nil, nil
+	}
+
+	before := budget.Spent
+	after := budget.Spent + amount
+	alertAt := budget.Amount * budget.AlertAt / 100
+
+	notificationType := ""
+	switch {
+	case before <= budget.Amount && after > budget.Amount:
+		notificationType = shared.NotificationBudgetExceeded
+	case budget.AlertAt > 0 && before < alertAt && after >= alertAt:
+		notificationType = shared.NotificationBudgetAlert
+	default:
+		return nil, nil
+	}
+
+	s.loadCategoryName(ctx, budget)
+	budget.Spent = after
+
+	if s.Events != nil && notificationType == shared.NotificationBudgetExceeded {
+		if err := s.Events.Publish(ctx, budget.UserId, shared.EventBudgetExceeded, budget); err != nil {
+			return nil, err
 		}
-		return nil
 	}
+	return &spendingAlert{budget: budget, notificationType: notificationType}, nil
+}
 
-	return s.Repository.UpdateSpent(ctx, budget.Id, amount)
+// notifyAlert avisa o usuário do alerta ou do estouro do orçamento
+func (s *Service) notifyAlert(ctx context.Context, alert *spendingAlert) {
+	if s.Notifier == nil || alert == nil {
+		return
+	}
+
+	budget := alert.budget
+	data := map[string]any{
+		"budgetId":   budget.Id.String(),
+		"category":   budget.CategoryName,
+		"spent":      budget.Spent,
+		"amount":     budget.Amount,
+		"percentage": budget.Spent / budget.Amount * 100,
+	}
+	if err := s.Notifier.Notify(ctx, budget.UserId, alert.notificationType, data); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("budget_id", budget.Id.String()).
+			Msg("Erro ao notificar alerta de orcamento")
+	}
 }
 
 func (s *Service) GetBudgetStatus(ctx context.Context, budgetID, userID ulid.ULID) (*BudgetStatusResponse, error) {
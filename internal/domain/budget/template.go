@@ -0,0 +1,474 @@
+package budget
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/category"
+	"Fynance/internal/domain/plan"
+	"Fynance/internal/domain/shared"
+	appErrors "Fynance/internal/errors"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// TemplateMode define como o plano de orçamentos é gerado
+type TemplateMode string
+
+const (
+	TemplateModeRule    TemplateMode = "RULE"
+	TemplateModeHistory TemplateMode = "HISTORY"
+)
+
+func (m TemplateMode) IsValid() bool {
+	switch m {
+	case TemplateModeRule, TemplateModeHistory:
+		return true
+	}
+	return false
+}
+
+const defaultHistoryMonths = 3
+
+// RuleTemplate distribui a renda mensal entre os grupos de despesa (em percentual)
+type RuleTemplate struct {
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	GroupShares  map[string]float64 `json:"groupShares"`
+	SavingsShare float64            `json:"savingsShare"`
+}
+
+// RuleTemplates são as regras disponíveis, indexadas pelo código
+var RuleTemplates = map[string]RuleTemplate{
+	"50_30_20": {
+		Name:        "50/30/20",
+		Description: "50% necessidades, 30% desejos e 20% poupança",
+		GroupShares: map[string]float64{
+			"Essencial": 50,
+			"Variável":  12,
+			"Eventual":  5,
+			"Lazer":     13,
+		},
+		SavingsShare: 20,
+	},
+	"70_30": {
+		Name:        "70/30",
+		Description: "70% consumo e 30% poupança",
+		GroupShares: map[string]float64{
+			"Essencial": 45,
+			"Variável":  12,
+			"Eventual":  5,
+			"Lazer":     8,
+		},
+		SavingsShare: 30,
+	},
+}
+
+type GeneratePlanRequest struct {
+	UserId     ulid.ULID
+	Mode       TemplateMode
+	Template   string
+	Income     float64
+	Months     int
+	Month      int
+	Year       int
+	MaxBudgets int
+}
+
+type BudgetPlan struct {
+	Mode           TemplateMode      `json:"mode"`
+	Template       string            `json:"template,omitempty"`
+	Month          int               `json:"month"`
+	Year           int               `json:"year"`
+	Income         float64           `json:"income"`
+	TotalAmount    float64           `json:"totalAmount"`
+	Savings        float64           `json:"savings"`
+	AvailableSlots int               `json:"availableSlots"`
+	Truncated      bool              `json:"truncated"`
+	Items          []*BudgetPlanItem `json:"items"`
+}
+
+type BudgetPlanItem struct {
+	CategoryId    ulid.ULID `json:"categoryId"`
+	CategoryName  string    `json:"categoryName"`
+	GroupName     string    `json:"groupName,omitempty"`
+	Amount        float64   `json:"amount"`
+	HistoryMedian float64   `json:"historyMedian"`
+}
+
+type ApplyPlanItem struct {
+	CategoryId ulid.ULID
+	Amount     float64
+}
+
+type ApplyPlanRequest struct {
+	UserId      ulid.ULID
+	Month       int
+	Year        int
+	AlertAt     float64
+	IsRecurring bool
+	Items       []ApplyPlanItem
+	MaxBudgets  int
+}
+
+type SkippedPlanItem struct {
+	CategoryId ulid.ULID `json:"categoryId"`
+	Reason     string    `json:"reason"`
+}
+
+type ApplyPlanResult struct {
+	Created []*Budget         `json:"created"`
+	Skipped []SkippedPlanItem `json:"skipped"`
+}
+
+// GeneratePlan monta uma prévia de orçamentos para o mês, sem persistir nada
+func (s *Service) GeneratePlan(ctx context.Context, req *GeneratePlanRequest) (*BudgetPlan, error) {
+	if err := s.EnsureUserExists(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if !req.Mode.IsValid() {
+		return nil, appErrors.NewValidationError("mode", "modo invalido")
+	}
+
+	if s.ReportRepository == nil || s.CategoryService == nil {
+		return nil, appErrors.ErrInternalServer
+	}
+
+	req.Month, req.Year = s.normalizePeriod(req.Month, req.Year)
+	if req.Months <= 0 {
+		req.Months = defaultHistoryMonths
+	}
+	if req.Months > 24 {
+		return nil, appErrors.NewValidationError("months", "deve ser no maximo 24")
+	}
+
+	var rule RuleTemplate
+	if req.Mode == TemplateModeRule {
+		if req.Template == "" {
+			req.Template = "50_30_20"
+		}
+		found, ok := RuleTemplates[req.Template]
+		if !ok {
+			return nil, appErrors.NewValidationError("template", "modelo invalido")
+		}
+		rule = found
+	}
+
+	expenseMedians, err := s.categoryMedians(req.UserId, "EXPENSE", req.Month, req.Year, req.Months)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	incomeMedian, err := s.incomeMedian(req.UserId, req.Month, req.Year, req.Months)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	income := req.Income
+	if income <= 0 {
+		income = incomeMedian
+	}
+
+	categories, _, err := s.CategoryService.GetAll(ctx, req.UserId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Repository.ListByPeriod(ctx, req.UserId, req.Month, req.Year)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	budgeted := make(map[ulid.ULID]struct{}, len(existing))
+	for _, b := range existing {
+		budgeted[b.CategoryId] = struct{}{}
+	}
+
+	candidates := make([]*category.Category, 0, len(categories))
+	for _, cat := range categories {
+		if strings.EqualFold(cat.Type, string(category.GroupTypeReceipt)) {
+			continue
+		}
+		if _, ok := budgeted[cat.Id]; ok {
+			continue
+		}
+		candidates = append(candidates, cat)
+	}
+
+	var items []*BudgetPlanItem
+	switch req.Mode {
+	case TemplateModeRule:
+		if income <= 0 {
+			return nil, appErrors.NewValidationError("income", "informe a renda mensal ou registre receitas para usar o modelo")
+		}
+		items = s.buildRuleItems(rule, income, candidates, expenseMedians)
+	case TemplateModeHistory:
+		items = s.buildHistoryItems(candidates, expenseMedians)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Amount > items[j].Amount
+	})
+
+	result := &BudgetPlan{
+		Mode:           req.Mode,
+		Template:       req.Template,
+		Month:          req.Month,
+		Year:           req.Year,
+		Income:         roundCurrency(income),
+		AvailableSlots: -1,
+	}
+	if req.Mode == TemplateModeHistory {
+		result.Template = ""
+	}
+
+	if !plan.IsUnlimited(req.MaxBudgets) {
+		count, err := s.Repository.CountByUserID(ctx, req.UserId)
+		if err != nil {
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		available := req.MaxBudgets - int(count)
+		if available < 0 {
+			available = 0
+		}
+		result.AvailableSlots = available
+		if len(items) > available {
+			items = items[:available]
+			result.Truncated = true
+		}
+	}
+
+	for _, item := range items {
+		result.TotalAmount += item.Amount
+	}
+	result.TotalAmount = roundCurrency(result.TotalAmount)
+	if result.Income > 0 {
+		result.Savings = roundCurrency(result.Income - result.TotalAmount)
+	}
+	result.Items = items
+
+	return result, nil
+}
+
+// ApplyPlan cria de uma vez os orçamentos aceitos a partir de uma prévia. Itens recusados pela validação
+// vão para Skipped; uma falha ao gravar desfaz todos os orçamentos criados pelo plano.
+func (s *Service) ApplyPlan(ctx context.Context, req *ApplyPlanRequest) (*ApplyPlanResult, error) {
+	if err := s.EnsureUserExists(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	req.Month, req.Year = s.normalizePeriod(req.Month, req.Year)
+
+	if len(req.Items) == 0 {
+		return nil, appErrors.NewValidationError("items", "é obrigatório")
+	}
+
+	seen := make(map[ulid.ULID]struct{}, len(req.Items))
+	for _, item := range req.Items {
+		if _, dup := seen[item.CategoryId]; dup {
+			return nil, appErrors.NewValidationError("items", "categoria repetida no plano")
+		}
+		seen[item.CategoryId] = struct{}{}
+		if item.Amount <= 0 {
+			return nil, appErrors.NewValidationError("amount", "deve ser maior que zero")
+		}
+	}
+
+	if !plan.IsUnlimited(req.MaxBudgets) {
+		count, err := s.Repository.CountByUserID(ctx, req.UserId)
+		if err != nil {
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		if int(count)+len(req.Items) > req.MaxBudgets {
+			appErr := appErrors.WrapError(nil, "PLAN_LIMIT_REACHED",
+				"O plano excede o limite de orçamentos do seu plano atual", http.StatusForbidden)
+			appErr.Details = map[string]interface{}{
+				"resource":  "budgets",
+				"current":   count,
+				"requested": len(req.Items),
+				"limit":     req.MaxBudgets,
+			}
+			return nil, appErr
+		}
+	}
+
+	var result *ApplyPlanResult
+	err := shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		result = &ApplyPlanResult{
+			Created: make([]*Budget, 0, len(req.Items)),
+			Skipped: make([]SkippedPlanItem, 0),
+		}
+
+		for _, item := range req.Items {
+			created, err := s.CreateBudget(ctx, &CreateBudgetRequest{
+				UserId:      req.UserId,
+				CategoryId:  item.CategoryId,
+				Amount:      item.Amount,
+				Month:       req.Month,
+				Year:        req.Year,
+				AlertAt:     req.AlertAt,
+				IsRecurring: req.IsRecurring,
+			})
+			if err != nil {
+				appErr, ok := appErrors.AsAppError(err)
+				if !ok || appErr.StatusCode >= http.StatusInternalServerError {
+					return err
+				}
+				result.Skipped = append(result.Skipped, SkippedPlanItem{
+					CategoryId: item.CategoryId,
+					Reason:     appErr.Message,
+				})
+				continue
+			}
+			result.Created = append(result.Created, created)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) buildRuleItems(rule RuleTemplate, income float64, categories []*category.Category, medians map[ulid.ULID]float64) []*BudgetPlanItem {
+	byGroup := make(map[string][]*category.Category)
+	for _, cat := range categories {
+		group := category.ExpenseGroupForCategory(cat.Name)
+		if group == "" {
+			continue
+		}
+		byGroup[group] = append(byGroup[group], cat)
+	}
+
+	items := make([]*BudgetPlanItem, 0, len(categories))
+	for group, share := range rule.GroupShares {
+		groupCategories := byGroup[group]
+		if share <= 0 || len(groupCategories) == 0 {
+			continue
+		}
+
+		groupAmount := income * share / 100
+
+		var weightTotal float64
+		for _, cat := range groupCategories {
+			weightTotal += medians[cat.Id]
+		}
+
+		for _, cat := range groupCategories {
+			var amount float64
+			if weightTotal > 0 {
+				amount = groupAmount * medians[cat.Id] / weightTotal
+			} else {
+				amount = groupAmount / float64(len(groupCategories))
+			}
+			amount = roundCurrency(amount)
+			if amount <= 0 {
+				continue
+			}
+			items = append(items, &BudgetPlanItem{
+				CategoryId:    cat.Id,
+				CategoryName:  cat.Name,
+				GroupName:     group,
+				Amount:        amount,
+				HistoryMedian: roundCurrency(medians[cat.Id]),
+			})
+		}
+	}
+
+	return items
+}
+
+func (s *Service) buildHistoryItems(categories []*category.Category, medians map[ulid.ULID]float64) []*BudgetPlanItem {
+	items := make([]*BudgetPlanItem, 0, len(categories))
+	for _, cat := range categories {
+		median := roundCurrency(medians[cat.Id])
+		if median <= 0 {
+			continue
+		}
+		items = append(items, &BudgetPlanItem{
+			CategoryId:    cat.Id,
+			CategoryName:  cat.Name,
+			GroupName:     category.ExpenseGroupForCategory(cat.Name),
+			Amount:        median,
+			HistoryMedian: median,
+		})
+	}
+	return items
+}
+
+// categoryMedians calcula a mediana mensal por categoria nos N meses anteriores ao período.
+// Meses sem lançamentos contam como zero.
+func (s *Service) categoryMedians(userID ulid.ULID, txType string, month, year, months int) (map[ulid.ULID]float64, error) {
+	startDate, endDate := historyWindow(month, year, months)
+
+	amounts, err := s.ReportRepository.GetCategoryMonthlyAmounts(userID, txType, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make(map[ulid.ULID][]float64)
+	for _, amount := range amounts {
+		series[amount.CategoryId] = append(series[amount.CategoryId], amount.Amount)
+	}
+
+	medians := make(map[ulid.ULID]float64, len(series))
+	for categoryID, values := range series {
+		medians[categoryID] = median(padWithZeros(values, months))
+	}
+	return medians, nil
+}
+
+func (s *Service) incomeMedian(userID ulid.ULID, month, year, months int) (float64, error) {
+	startDate, endDate := historyWindow(month, year, months)
+
+	amounts, err := s.ReportRepository.GetCategoryMonthlyAmounts(userID, "RECEIPT", startDate, endDate)
+	if err != nil {
+		return 0, err
+	}
+
+	totals := make(map[string]float64)
+	for _, amount := range amounts {
+		key := time.Date(amount.Year, time.Month(amount.Month), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+		totals[key] += amount.Amount
+	}
+
+	values := make([]float64, 0, len(totals))
+	for _, total := range totals {
+		values = append(values, total)
+	}
+	return median(padWithZeros(values, months)), nil
+}
+
+func historyWindow(month, year, months int) (time.Time, time.Time) {
+	periodStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return periodStart.AddDate(0, -months, 0), periodStart.Add(-time.Second)
+}
+
+func padWithZeros(values []float64, size int) []float64 {
+	for len(values) < size {
+		values = append(values, 0)
+	}
+	return values
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func roundCurrency(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,258 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type DueSource string
+
+const (
+	SourceRecurring    DueSource = "RECURRING"
+	SourceInvoice      DueSource = "INVOICE"
+	SourceLoan         DueSource = "LOAN"
+	SourceGoalDeadline DueSource = "GOAL_DEADLINE"
+)
+
+const (
+	DefaultDays = 30
+	MaxDays     = 365
+
+	// FeedDays é o horizonte do feed iCalendar
+	FeedDays = 180
+
+	// maxLeadDays e maxReminders limitam a antecedência e a quantidade de lembretes por vencimento
+	maxLeadDays  = 30
+	maxReminders = 5
+
+	// reminderHour é a hora local dos alarmes do feed
+	reminderHour = 9
+)
+
+// DefaultLeadDays são os lembretes usados enquanto o usuário não configura os seus: três dias antes,
+// na véspera e no dia do vencimento
+var DefaultLeadDays = []int{3, 1, 0}
+
+// DueItem é um vencimento do calendário. Key identifica o vencimento entre consultas e é o UID do evento
+// no feed. Amount é zero nos prazos de metas. Reminders são as datas dos lembretes ainda por vir.
+type DueItem struct {
+	Key         string      `json:"key"`
+	Source      DueSource   `json:"source"`
+	Title       string      `json:"title"`
+	Date        time.Time   `json:"date"`
+	Amount      float64     `json:"amount"`
+	AccountId   *ulid.ULID  `json:"accountId,omitempty"`
+	ReferenceId ulid.ULID   `json:"referenceId"`
+	Overdue     bool        `json:"overdue"`
+	Estimated   bool        `json:"estimated"`
+	Reminders   []time.Time `json:"reminders"`
+}
+
+// UpcomingDues são os vencimentos entre From e To, inclusive
+type UpcomingDues struct {
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Items []DueItem `json:"items"`
+	Total int       `json:"total"`
+}
+
+// ReminderSettings guarda a antecedência dos lembretes e o token do feed iCalendar do usuário.
+// FeedEnabled indica se há um link do feed ativo, sem expor o token.
+type ReminderSettings struct {
+	UserId      ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"userId"`
+	Enabled     bool      `gorm:"not null;default:true" json:"enabled"`
+	LeadDays    []int     `gorm:"type:varchar(100);serializer:json;not null" json:"leadDays"`
+	FeedToken   *string   `gorm:"type:varchar(64);uniqueIndex:idx_reminder_settings_feed_token" json:"-"`
+	FeedEnabled bool      `gorm:"-" json:"feedEnabled"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (ReminderSettings) TableName() string {
+	return "reminder_settings"
+}
+
+// ReminderLog registra o lembrete enviado, para que cada antecedência de um vencimento seja avisada uma vez
+type ReminderLog struct {
+	Id       ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId   ulid.ULID `gorm:"type:varchar(26);uniqueIndex:idx_reminder_logs_item,priority:1;not null" json:"userId"`
+	ItemKey  string    `gorm:"type:varchar(120);uniqueIndex:idx_reminder_logs_item,priority:2;not null" json:"itemKey"`
+	LeadDays int       `gorm:"uniqueIndex:idx_reminder_logs_item,priority:3;not null" json:"leadDays"`
+	DueDate  time.Time `gorm:"type:date;not null" json:"dueDate"`
+	SentAt   time.Time `gorm:"type:timestamp;not null" json:"sentAt"`
+}
+
+func (ReminderLog) TableName() string {
+	return "reminder_logs"
+}
+
+// NormalizeLeadDays remove repetições e ordena as antecedências da maior para a menor
+func NormalizeLeadDays(leadDays []int) ([]int, error) {
+	if len(leadDays) > maxReminders {
+		return nil, fmt.Errorf("no maximo %d lembretes por vencimento", maxReminders)
+	}
+	seen := make(map[int]bool, len(leadDays))
+	normalized := make([]int, 0, len(leadDays))
+	for _, days := range leadDays {
+		if days < 0 || days > maxLeadDays {
+			return nil, fmt.Errorf("antecedencia deve estar entre 0 e %d dias", maxLeadDays)
+		}
+		if seen[days] {
+			continue
+		}
+		seen[days] = true
+		normalized = append(normalized, days)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(normalized)))
+	return normalized, nil
+}
+
+// itemKey monta a chave estável do vencimento a partir da origem, do recurso e da data
+func itemKey(source DueSource, referenceID ulid.ULID, date time.Time) string {
+	return strings.ToLower(string(source)) + ":" + referenceID.String() + ":" + date.Format("20060102")
+}
+
+// applyReminders preenche as datas dos lembretes a partir de today e ordena os vencimentos por data
+func applyReminders(items []DueItem, leadDays []int, today time.Time) {
+	for i := range items {
+		items[i].Reminders = []time.Time{}
+		if items[i].Overdue {
+			continue
+		}
+		for _, days := range leadDays {
+			remindAt := items[i].Date.AddDate(0, 0, -days)
+			if !remindAt.Before(today) {
+				items[i].Reminders = append(items[i].Reminders, remindAt)
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].Date.Equal(items[j].Date) {
+			return items[i].Date.Before(items[j].Date)
+		}
+		return items[i].Key < items[j].Key
+	})
+}
+
+// BuildICS gera o calendário iCalendar (RFC 5545) com um evento de dia inteiro por vencimento e um
+// alarme às 9h de cada dia de lembrete
+func BuildICS(items []DueItem, leadDays []int, now time.Time) []byte {
+	var b strings.Builder
+	writeLine := func(line string) {
+		b.WriteString(foldLine(line))
+		b.WriteString("\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//Fynance//Vencimentos//PT")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	writeLine("X-WR-CALNAME:Fynance - Vencimentos")
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, item := range items {
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + item.Key + "@fynance")
+		writeLine("DTSTAMP:" + stamp)
+		writeLine("DTSTART;VALUE=DATE:" + item.Date.Format("20060102"))
+		writeLine("DTEND;VALUE=DATE:" + item.Date.AddDate(0, 0, 1).Format("20060102"))
+		writeLine("SUMMARY:" + escapeText(summary(item)))
+		writeLine("DESCRIPTION:" + escapeText(description(item)))
+		writeLine("CATEGORIES:" + string(item.Source))
+		writeLine("TRANSP:TRANSPARENT")
+		for _, days := range leadDays {
+			writeLine("BEGIN:VALARM")
+			writeLine("ACTION:DISPLAY")
+			writeLine("DESCRIPTION:" + escapeText(summary(item)))
+			writeLine("TRIGGER:" + alarmTrigger(days))
+			writeLine("END:VALARM")
+		}
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func summary(item DueItem) string {
+	if item.Source == SourceGoalDeadline {
+		return "Prazo da meta: " + item.Title
+	}
+	return item.Title + " - " + FormatBRL(item.Amount)
+}
+
+func description(item DueItem) string {
+	switch item.Source {
+	case SourceInvoice:
+		return "Vencimento da fatura do cartao"
+	case SourceLoan:
+		return "Parcela de emprestimo"
+	case SourceGoalDeadline:
+		return "Data limite para atingir a meta"
+	default:
+		if item.Estimated {
+			return "Conta recorrente com valor estimado"
+		}
+		return "Conta recorrente"
+	}
+}
+
+// alarmTrigger posiciona o alarme às 9h do dia do lembrete, relativo ao início do evento de dia inteiro
+func alarmTrigger(leadDays int) string {
+	hours := reminderHour - 24*leadDays
+	if hours >= 0 {
+		return fmt.Sprintf("PT%dH", hours)
+	}
+	return fmt.Sprintf("-PT%dH", -hours)
+}
+
+// escapeText aplica o escape de TEXT do iCalendar
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+// foldLine quebra linhas com mais de 75 octetos, continuando com um espaço, sem partir caracteres UTF-8
+func foldLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
+
+// FormatBRL formata o valor em reais, como "R$ 1.234,56"
+func FormatBRL(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	integer := fmt.Sprintf("%d", cents/100)
+
+	var groups []string
+	for len(integer) > 3 {
+		groups = append([]string{integer[len(integer)-3:]}, groups...)
+		integer = integer[:len(integer)-3]
+	}
+	groups = append([]string{integer}, groups...)
+	return fmt.Sprintf("%sR$ %s,%02d", sign, strings.Join(groups, "."), cents%100)
+}
@@ -0,0 +1,17 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Repository interface {
+	GetSettings(ctx context.Context, userID ulid.ULID) (*ReminderSettings, error)
+	GetSettingsByFeedToken(ctx context.Context, token string) (*ReminderSettings, error)
+	SaveSettings(ctx context.Context, settings *ReminderSettings) error
+
+	// CreateReminderLog registra o envio e devolve false quando o lembrete já tinha sido registrado
+	CreateReminderLog(ctx context.Context, log *ReminderLog) (bool, error)
+	DeleteReminderLog(ctx context.Context, logID ulid.ULID) error
+}
@@ -0,0 +1,437 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"Fynance/internal/domain/creditcard"
+	"Fynance/internal/domain/goal"
+	"Fynance/internal/domain/loan"
+	"Fynance/internal/domain/recurring"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// pageSize é o tamanho da página usada para percorrer usuários e metas
+const pageSize = 1000
+
+// UserLister percorre os usuários cadastrados para o envio dos lembretes
+type UserLister interface {
+	ListUserIDs(ctx context.Context, pagination *pkg.PaginationParams) ([]ulid.ULID, int64, error)
+}
+
+type Service struct {
+	Repository        Repository
+	RecurringService  *recurring.Service
+	CreditCardService *creditcard.Service
+	LoanService       *loan.Service
+	GoalService       *goal.Service
+	Users             UserLister
+	// Notifier é opcional; sem ele o job de lembretes não envia avisos
+	Notifier shared.Notifier
+	shared.BaseService
+}
+
+func NewService(
+	repo Repository,
+	recurringService *recurring.Service,
+	creditCardService *creditcard.Service,
+	loanService *loan.Service,
+	goalService *goal.Service,
+	users UserLister,
+	userChecker *shared.UserCheckerService,
+) *Service {
+	return &Service{
+		Repository:        repo,
+		RecurringService:  recurringService,
+		CreditCardService: creditCardService,
+		LoanService:       loanService,
+		GoalService:       goalService,
+		Users:             users,
+		BaseService: shared.BaseService{
+			UserChecker: userChecker,
+		},
+	}
+}
+
+// Upcoming lista os vencimentos dos próximos dias (contas recorrentes, faturas de cartão, parcelas de
+// empréstimos e prazos de metas), incluindo os atrasados, com as datas dos lembretes configurados
+func (s *Service) Upcoming(ctx context.Context, userID ulid.ULID, days int) (*UpcomingDues, error) {
+	if days < 1 || days > MaxDays {
+		return nil, appErrors.NewValidationError("days", "deve estar entre 1 e 365")
+	}
+
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := truncateDay(time.Now())
+	until := today.AddDate(0, 0, days-1)
+	items, err := s.collect(ctx, userID, today, until)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []DueItem{}
+	}
+	applyReminders(items, settings.activeLeadDays(), today)
+	return &UpcomingDues{From: today, To: until, Items: items, Total: len(items)}, nil
+}
+
+// GetSettings devolve a configuração de lembretes do usuário ou a configuração padrão
+func (s *Service) GetSettings(ctx context.Context, userID ulid.ULID) (*ReminderSettings, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.Repository.GetSettings(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultSettings(userID), nil
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings altera a antecedência dos lembretes e se eles estão ativos
+func (s *Service) UpdateSettings(ctx context.Context, userID ulid.ULID, enabled *bool, leadDays []int) (*ReminderSettings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled != nil {
+		settings.Enabled = *enabled
+	}
+	if leadDays != nil {
+		normalized, err := NormalizeLeadDays(leadDays)
+		if err != nil {
+			return nil, appErrors.NewValidationError("lead_days", err.Error())
+		}
+		settings.LeadDays = normalized
+	}
+
+	settings.UpdatedAt = time.Now()
+	if err := s.Repository.SaveSettings(ctx, settings); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return settings, nil
+}
+
+// RotateFeedToken gera um novo token para o feed iCalendar; o link anterior deixa de funcionar
+func (s *Service) RotateFeedToken(ctx context.Context, userID ulid.ULID) (string, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", appErrors.ErrInternalServer.WithError(err)
+	}
+	token := hex.EncodeToString(raw)
+
+	settings.FeedToken = &token
+	settings.UpdatedAt = time.Now()
+	if err := s.Repository.SaveSettings(ctx, settings); err != nil {
+		return "", appErrors.NewDatabaseError(err)
+	}
+	return token, nil
+}
+
+// RevokeFeedToken desativa o feed iCalendar do usuário
+func (s *Service) RevokeFeedToken(ctx context.Context, userID ulid.ULID) error {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	settings.FeedToken = nil
+	settings.UpdatedAt = time.Now()
+	if err := s.Repository.SaveSettings(ctx, settings); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// Feed gera o calendário iCalendar dos vencimentos do usuário dono do token
+func (s *Service) Feed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, appErrors.NewNotFoundError("calendario")
+	}
+
+	settings, err := s.Repository.GetSettingsByFeedToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("calendario")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	now := time.Now()
+	today := truncateDay(now)
+	items, err := s.collect(ctx, settings.UserId, today, today.AddDate(0, 0, FeedDays-1))
+	if err != nil {
+		return nil, err
+	}
+	leadDays := settings.activeLeadDays()
+	applyReminders(items, leadDays, today)
+	return BuildICS(items, leadDays, now), nil
+}
+
+// ProcessReminders envia os lembretes cujo dia chegou. Cada antecedência de um vencimento é avisada uma
+// única vez, mesmo que o job rode várias vezes no dia.
+func (s *Service) ProcessReminders(ctx context.Context, now time.Time) error {
+	if s.Notifier == nil || s.Users == nil {
+		return nil
+	}
+
+	today := truncateDay(now)
+	loaded := 0
+	for page := 1; ; page++ {
+		userIDs, total, err := s.Users.ListUserIDs(ctx, &pkg.PaginationParams{Page: page, Limit: pageSize})
+		if err != nil {
+			return err
+		}
+		loaded += len(userIDs)
+		for _, userID := range userIDs {
+			if err := s.remindUser(ctx, userID, today); err != nil {
+				logger.Error().
+					Err(err).
+					Str("user_id", userID.String()).
+					Msg("Erro ao enviar lembretes de vencimento")
+			}
+		}
+		if len(userIDs) == 0 || int64(loaded) >= total {
+			return nil
+		}
+	}
+}
+
+func (s *Service) remindUser(ctx context.Context, userID ulid.ULID, today time.Time) error {
+	settings, err := s.Repository.GetSettings(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		settings = defaultSettings(userID)
+	}
+	leadDays := settings.activeLeadDays()
+	if len(leadDays) == 0 {
+		return nil
+	}
+
+	items, err := s.collect(ctx, userID, today, today.AddDate(0, 0, leadDays[0]))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.Overdue {
+			continue
+		}
+		for _, days := range leadDays {
+			if !item.Date.AddDate(0, 0, -days).Equal(today) {
+				continue
+			}
+			if err := s.sendReminder(ctx, userID, item, days); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) sendReminder(ctx context.Context, userID ulid.ULID, item DueItem, days int) error {
+	entry := &ReminderLog{
+		Id:       pkg.GenerateULIDObject(),
+		UserId:   userID,
+		ItemKey:  item.Key,
+		LeadDays: days,
+		DueDate:  item.Date,
+		SentAt:   time.Now(),
+	}
+	created, err := s.Repository.CreateReminderLog(ctx, entry)
+	if err != nil || !created {
+		return err
+	}
+
+	if err := s.Notifier.Notify(ctx, userID, reminderType(item.Source), reminderData(item, days)); err != nil {
+		// libera o registro para que o lembrete seja tentado de novo na próxima execução
+		_ = s.Repository.DeleteReminderLog(ctx, entry.Id)
+		return err
+	}
+	return nil
+}
+
+// reminderType escolhe o tipo de notificação pela origem do vencimento
+func reminderType(source DueSource) string {
+	switch source {
+	case SourceInvoice:
+		return shared.NotificationInvoiceDue
+	case SourceLoan:
+		return shared.NotificationLoanDue
+	case SourceGoalDeadline:
+		return shared.NotificationGoalDeadline
+	default:
+		return shared.NotificationBillDue
+	}
+}
+
+func reminderData(item DueItem, days int) map[string]any {
+	return map[string]any{
+		"key":         item.Key,
+		"referenceId": item.ReferenceId.String(),
+		"title":       item.Title,
+		"goal":        item.Title,
+		"amount":      item.Amount,
+		"dueDate":     item.Date,
+		"daysBefore":  days,
+		"estimated":   item.Estimated,
+	}
+}
+
+// collect reúne os vencimentos até until. Recorrências de receita, transferência e aportes não são
+// contas a pagar e ficam de fora; prazos de metas vencidos também.
+func (s *Service) collect(ctx context.Context, userID ulid.ULID, today, until time.Time) ([]DueItem, error) {
+	var items []DueItem
+
+	occurrences, err := s.RecurringService.UpcomingOccurrences(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, occurrence := range occurrences {
+		rec := occurrence.Recurring
+		isBill := rec.Kind == recurring.KindCreditCard ||
+			(rec.Kind == recurring.KindTransaction && transaction.Types(rec.Type) == transaction.Expense)
+		if !isBill {
+			continue
+		}
+		date := truncateDay(occurrence.Date)
+		items = append(items, DueItem{
+			Key:         itemKey(SourceRecurring, rec.Id, date),
+			Source:      SourceRecurring,
+			Title:       rec.Description,
+			Date:        date,
+			Amount:      occurrence.Amount,
+			AccountId:   rec.AccountId,
+			ReferenceId: rec.Id,
+			Overdue:     occurrence.Overdue,
+			Estimated:   occurrence.Estimated,
+		})
+	}
+
+	invoices, err := s.CreditCardService.UpcomingInvoicePayments(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range invoices {
+		date := truncateDay(payment.DueDate)
+		accountID := payment.AccountId
+		items = append(items, DueItem{
+			Key:         itemKey(SourceInvoice, payment.CreditCardId, date),
+			Source:      SourceInvoice,
+			Title:       "Fatura " + payment.CreditCardName,
+			Date:        date,
+			Amount:      payment.Amount,
+			AccountId:   &accountID,
+			ReferenceId: payment.CreditCardId,
+			Overdue:     payment.Overdue,
+			Estimated:   payment.InvoiceId == nil,
+		})
+	}
+
+	installments, err := s.LoanService.UpcomingInstallments(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, upcoming := range installments {
+		date := truncateDay(upcoming.Installment.DueDate)
+		accountID := upcoming.AccountId
+		items = append(items, DueItem{
+			Key:         itemKey(SourceLoan, upcoming.LoanId, date),
+			Source:      SourceLoan,
+			Title:       fmt.Sprintf("Parcela %d - %s", upcoming.Installment.Number, upcoming.LoanName),
+			Date:        date,
+			Amount:      upcoming.Installment.Payment,
+			AccountId:   &accountID,
+			ReferenceId: upcoming.LoanId,
+			Overdue:     date.Before(today),
+		})
+	}
+
+	deadlines, err := s.goalDeadlines(ctx, userID, today, until)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, deadlines...), nil
+}
+
+func (s *Service) goalDeadlines(ctx context.Context, userID ulid.ULID, today, until time.Time) ([]DueItem, error) {
+	status := goal.Active
+	filters := &goal.GoalFilters{Status: &status}
+
+	var items []DueItem
+	loaded := 0
+	for page := 1; ; page++ {
+		goals, total, err := s.GoalService.GetGoalsByUserID(ctx, userID, filters, &pkg.PaginationParams{Page: page, Limit: pageSize})
+		if err != nil {
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		loaded += len(goals)
+		for _, g := range goals {
+			if g.EndedAt == nil {
+				continue
+			}
+			date := truncateDay(*g.EndedAt)
+			if date.Before(today) || date.After(until) {
+				continue
+			}
+			items = append(items, DueItem{
+				Key:         itemKey(SourceGoalDeadline, g.Id, date),
+				Source:      SourceGoalDeadline,
+				Title:       g.Name,
+				Date:        date,
+				ReferenceId: g.Id,
+			})
+		}
+		if len(goals) == 0 || int64(loaded) >= total {
+			return items, nil
+		}
+	}
+}
+
+func defaultSettings(userID ulid.ULID) *ReminderSettings {
+	now := time.Now()
+	return &ReminderSettings{
+		UserId:    userID,
+		Enabled:   true,
+		LeadDays:  append([]int(nil), DefaultLeadDays...),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// activeLeadDays devolve as antecedências em uso; lembretes desativados não geram avisos nem alarmes
+func (r *ReminderSettings) activeLeadDays() []int {
+	if !r.Enabled {
+		return nil
+	}
+	return r.LeadDays
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -1,6 +1,9 @@
 package category
 
 import (
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -99,3 +102,59 @@ var DefaultReceiptCategoriesByGroup = map[string]map[string][]string{
 		"_root": {"Prêmios", "Presentes Recebidos", "Reembolsos", "Vendas", "Cashback"},
 	},
 }
+
+// CategoryExpenseGroup associa uma categoria padrão (plana) a um grupo de despesa
+type CategoryExpenseGroup struct {
+	Category string
+	Group    string
+}
+
+// DefaultCategoryExpenseGroups associa as categorias padrão (planas) aos grupos de despesa, na ordem
+// em que são consultadas
+var DefaultCategoryExpenseGroups = []CategoryExpenseGroup{
+	{Category: "Moradia", Group: "Essencial"},
+	{Category: "Alimentação", Group: "Essencial"},
+	{Category: "Transporte", Group: "Essencial"},
+	{Category: "Saúde", Group: "Essencial"},
+	{Category: "Contas", Group: "Essencial"},
+	{Category: "Educação", Group: "Variável"},
+	{Category: "Compras", Group: "Variável"},
+	{Category: "Outros", Group: "Eventual"},
+	{Category: "Lazer", Group: "Lazer"},
+}
+
+// ExpenseGroupForCategory retorna o grupo de despesa de uma categoria pelo nome.
+// Retorna string vazia quando a categoria não pertence a nenhum grupo de despesa.
+// A busca segue a ordem de DefaultCategoryExpenseGroups e DefaultExpenseGroups, então o resultado
+// é sempre o mesmo para um nome presente em mais de um grupo.
+func ExpenseGroupForCategory(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+
+	for _, entry := range DefaultCategoryExpenseGroups {
+		if strings.EqualFold(entry.Category, name) {
+			return entry.Group
+		}
+	}
+
+	for _, group := range DefaultExpenseGroups {
+		if strings.EqualFold(group.Name, name) {
+			return group.Name
+		}
+		parents := DefaultCategoriesByGroup[group.Name]
+		for _, parent := range slices.Sorted(maps.Keys(parents)) {
+			if strings.EqualFold(parent, name) {
+				return group.Name
+			}
+			for _, child := range parents[parent] {
+				if strings.EqualFold(child, name) {
+					return group.Name
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -2,6 +2,7 @@ package creditcard
 
 import (
 	"context"
+	"time"
 
 	"Fynance/internal/pkg"
 
@@ -23,8 +24,12 @@ type CreditCardRepository interface {
 	GetInvoicesByCreditCardId(ctx context.Context, cardID, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*Invoice, int64, error)
 	GetCurrentInvoice(ctx context.Context, cardID, userID ulid.ULID) (*Invoice, error)
 	GetInvoiceByReference(ctx context.Context, cardID ulid.ULID, month, year int) (*Invoice, error)
+	GetUnpaidInvoicesByUserId(ctx context.Context, userID ulid.ULID) ([]*Invoice, error)
+	GetOpenInvoicesClosingBy(ctx context.Context, date time.Time) ([]*Invoice, error)
 
 	CreateTransaction(ctx context.Context, transaction *CreditCardTransaction) error
 	GetTransactionsByInvoice(ctx context.Context, invoiceID, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*CreditCardTransaction, int64, error)
 	GetTransactionsByCreditCard(ctx context.Context, cardID, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*CreditCardTransaction, int64, error)
+	GetTransactionsByUserSince(ctx context.Context, userID ulid.ULID, since time.Time) ([]*CreditCardTransaction, error)
+	GetInstallmentTransactionsByInvoices(ctx context.Context, userID ulid.ULID, invoiceIDs []ulid.ULID) ([]*CreditCardTransaction, error)
 }
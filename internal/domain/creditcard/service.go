@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/shared"
 	"Fynance/internal/domain/user"
 	appErrors "Fynance/internal/errors"
 	"Fynance/internal/pkg"
@@ -18,6 +19,10 @@ type Service struct {
 	Repository     CreditCardRepository
 	AccountService *account.Service
 	UserService    *user.Service
+	// Events é opcional e publica o fechamento e o pagamento das faturas
+	Events shared.EventPublisher
+	// Transactor é opcional e grava o pagamento ou o fechamento da fatura junto com o evento
+	Transactor shared.Transactor
 }
 
 func (s *Service) CreateCreditCard(ctx context.Context, req *CreateCreditCardRequest) (*CreditCard, error) {
@@ -292,10 +297,6 @@ func (s *Service) PayInvoice(ctx context.Context, cardID, invoiceID, accountID,
 		amount = remainingAmount
 	}
 
-	if err := s.AccountService.UpdateBalance(ctx, accountID, userID, -amount); err != nil {
-		return err
-	}
-
 	invoice.PaidAmount += amount
 	now := time.Now()
 
@@ -308,14 +309,58 @@ func (s *Service) PayInvoice(ctx context.Context, cardID, invoiceID, accountID,
 
 	invoice.UpdatedAt = now
 
-	if err := s.Repository.UpdateInvoice(ctx, invoice); err != nil {
-		return appErrors.NewDatabaseError(err)
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.AccountService.UpdateBalance(ctx, accountID, userID, -amount); err != nil {
+			return err
+		}
+
+		if err := s.Repository.UpdateInvoice(ctx, invoice); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+
+		if err := s.Repository.UpdateAvailableLimit(ctx, cardID, amount); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+
+		if invoice.Status == InvoicePaid {
+			return s.publishInvoice(ctx, shared.EventInvoicePaid, invoice)
+		}
+		return nil
+	})
+}
+
+// CloseDueInvoices fecha as faturas abertas cuja data de fechamento chegou e devolve quantas foram fechadas
+func (s *Service) CloseDueInvoices(ctx context.Context, now time.Time) (int, error) {
+	invoices, err := s.Repository.GetOpenInvoicesClosingBy(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, invoice := range invoices {
+		invoice.Status = InvoiceClosed
+		invoice.UpdatedAt = now
+		err := shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+			if err := s.Repository.UpdateInvoice(ctx, invoice); err != nil {
+				return err
+			}
+			return s.publishInvoice(ctx, shared.EventInvoiceClosed, invoice)
+		})
+		if err != nil {
+			return closed, err
+		}
+		closed++
 	}
+	return closed, nil
+}
 
-	if err := s.Repository.UpdateAvailableLimit(ctx, cardID, amount); err != nil {
+func (s *Service) publishInvoice(ctx context.Context, eventType string, invoice *Invoice) error {
+	if s.Events == nil {
+		return nil
+	}
+	if err := s.Events.Publish(ctx, invoice.UserId, eventType, invoice); err != nil {
 		return appErrors.NewDatabaseError(err)
 	}
-
 	return nil
 }
 
@@ -368,6 +413,15 @@ func (s *Service) ListTransactions(ctx context.Context, cardID, userID ulid.ULID
 	return s.Repository.GetTransactionsByCreditCard(ctx, cardID, userID, pagination)
 }
 
+// ListChargesSince lista as compras de todos os cartões do usuário feitas a partir da data informada
+func (s *Service) ListChargesSince(ctx context.Context, userID ulid.ULID, since time.Time) ([]*CreditCardTransaction, error) {
+	transactions, err := s.Repository.GetTransactionsByUserSince(ctx, userID, since)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return transactions, nil
+}
+
 func (s *Service) getOrCreateCurrentInvoice(ctx context.Context, card *CreditCard) (*Invoice, error) {
 	now := time.Now()
 	currentMonth := int(now.Month())
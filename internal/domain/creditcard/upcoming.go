@@ -0,0 +1,149 @@
+package creditcard
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// InvoicePayment é o pagamento previsto de uma fatura, debitado da conta de pagamento do cartão.
+// InvoiceId é nil quando a fatura ainda não existe e o valor vem só de parcelas de compras anteriores.
+type InvoicePayment struct {
+	CreditCardId   ulid.ULID  `json:"creditCardId"`
+	CreditCardName string     `json:"creditCardName"`
+	AccountId      ulid.ULID  `json:"accountId"`
+	InvoiceId      *ulid.ULID `json:"invoiceId,omitempty"`
+	DueDate        time.Time  `json:"dueDate"`
+	Amount         float64    `json:"amount"`
+	Installments   float64    `json:"installments"`
+	Overdue        bool       `json:"overdue"`
+}
+
+// cardPageSize cobre todos os cartões do usuário em uma única página
+const cardPageSize = 1000
+
+// UpcomingInvoicePayments projeta os pagamentos de fatura até a data informada. Compras parceladas
+// entram na fatura atual pelo valor cheio; na projeção, só a primeira parcela fica nela e as demais
+// são distribuídas pelos vencimentos seguintes do cartão.
+func (s *Service) UpcomingInvoicePayments(ctx context.Context, userID ulid.ULID, until time.Time) ([]InvoicePayment, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	cards, _, err := s.Repository.GetCreditCardsByUserId(ctx, userID, &pkg.PaginationParams{Page: 1, Limit: cardPageSize})
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	cardsByID := make(map[ulid.ULID]*CreditCard, len(cards))
+	for _, card := range cards {
+		cardsByID[card.Id] = card
+	}
+
+	invoices, err := s.Repository.GetUnpaidInvoicesByUserId(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	invoiceIDs := make([]ulid.ULID, 0, len(invoices))
+	for _, invoice := range invoices {
+		invoiceIDs = append(invoiceIDs, invoice.Id)
+	}
+	installments, err := s.Repository.GetInstallmentTransactionsByInvoices(ctx, userID, invoiceIDs)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	byInvoice := make(map[ulid.ULID][]*CreditCardTransaction)
+	for _, transaction := range installments {
+		byInvoice[transaction.InvoiceId] = append(byInvoice[transaction.InvoiceId], transaction)
+	}
+
+	type dueKey struct {
+		cardID ulid.ULID
+		date   time.Time
+	}
+	payments := make(map[dueKey]*InvoicePayment)
+	var keys []dueKey
+	paymentFor := func(card *CreditCard, date time.Time) *InvoicePayment {
+		key := dueKey{cardID: card.Id, date: date}
+		if payment, ok := payments[key]; ok {
+			return payment
+		}
+		payment := &InvoicePayment{
+			CreditCardId:   card.Id,
+			CreditCardName: card.Name,
+			AccountId:      card.AccountId,
+			DueDate:        date,
+		}
+		payments[key] = payment
+		keys = append(keys, key)
+		return payment
+	}
+
+	for _, invoice := range invoices {
+		card, ok := cardsByID[invoice.CreditCardId]
+		if !ok {
+			continue
+		}
+
+		dueDate := truncateDay(invoice.DueDate)
+		remaining := invoice.TotalAmount - invoice.PaidAmount
+		for _, transaction := range byInvoice[invoice.Id] {
+			parcel := roundCents(transaction.Amount / float64(transaction.Installments))
+			deferred := roundCents(parcel * float64(transaction.Installments-1))
+			remaining -= deferred
+			for k := 1; k < transaction.Installments; k++ {
+				future := paymentFor(card, nextDueDate(dueDate, k, card.DueDay))
+				future.Amount += parcel
+				future.Installments += parcel
+			}
+		}
+
+		payment := paymentFor(card, dueDate)
+		invoiceID := invoice.Id
+		payment.InvoiceId = &invoiceID
+		payment.Amount += math.Max(roundCents(remaining), 0)
+	}
+
+	today := truncateDay(time.Now())
+	until = truncateDay(until)
+	result := make([]InvoicePayment, 0, len(keys))
+	for _, key := range keys {
+		payment := payments[key]
+		payment.Amount = roundCents(payment.Amount)
+		payment.Installments = roundCents(payment.Installments)
+		if payment.Amount <= 0 || payment.DueDate.After(until) {
+			continue
+		}
+		payment.Overdue = payment.DueDate.Before(today)
+		result = append(result, *payment)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DueDate.Before(result[j].DueDate)
+	})
+	return result, nil
+}
+
+// nextDueDate é o vencimento k meses depois, no dia de vencimento do cartão (ou no último dia do mês)
+func nextDueDate(dueDate time.Time, months, dueDay int) time.Time {
+	target := time.Date(dueDate.Year(), dueDate.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := target.AddDate(0, 1, -1).Day()
+	if dueDay > lastDay {
+		dueDay = lastDay
+	}
+	return time.Date(target.Year(), target.Month(), dueDay, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -97,6 +97,9 @@ type FinancialSummary struct {
 	MonthBalance     float64 `json:"monthBalance"`
 	TotalInvestments float64 `json:"totalInvestments"`
 	TotalGoals       float64 `json:"totalGoals"`
+	// TotalDebt é o saldo devedor dos empréstimos ativos, descontado do patrimônio líquido
+	TotalDebt float64 `json:"totalDebt"`
+	NetWorth  float64 `json:"netWorth"`
 }
 
 type MonthlyTrendItem struct {
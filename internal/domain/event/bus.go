@@ -0,0 +1,306 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	// claimLease reserva as entregas durante o processamento; se o processo cair antes do resultado,
+	// outra execução retoma a entrega depois desse prazo
+	claimLease = 5 * time.Minute
+	// handlerTimeout limita cada chamada de handler
+	handlerTimeout = 30 * time.Second
+	// dispatchBatchSize limita as entregas reservadas de uma vez; o lote inteiro com todos os handlers
+	// esgotando handlerTimeout ainda termina dentro de claimLease
+	dispatchBatchSize = 8
+	// errorLimit limita o trecho do erro guardado na entrega
+	errorLimit = 1024
+)
+
+// Bus publica os eventos do domínio na outbox e os despacha aos assinantes registrados. Publish grava
+// o evento na transação do ctx, então o evento só existe se a mudança de estado for confirmada; o
+// despacho acontece logo após o commit e, para as novas tentativas, a cada PollInterval.
+type Bus struct {
+	Repository   Repository
+	Retry        RetryPolicy
+	PollInterval time.Duration
+	// Transactor abre a transação em que cada handler roda junto com o marcador de processamento
+	Transactor shared.Transactor
+
+	mu          sync.RWMutex
+	handlers    map[string]Handler
+	subscribers map[string][]string
+
+	wake    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+var _ shared.EventPublisher = (*Bus)(nil)
+
+func NewBus(repo Repository) *Bus {
+	return &Bus{
+		Repository:   repo,
+		Retry:        DefaultRetryPolicy,
+		PollInterval: time.Second,
+		handlers:     make(map[string]Handler),
+		subscribers:  make(map[string][]string),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Subscribe registra o handler com um nome estável nos tipos de evento. O nome identifica as entregas
+// gravadas na outbox, então renomear um assinante deixa as entregas pendentes do nome antigo como falhas.
+func (b *Bus) Subscribe(name string, handler Handler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.handlers[name]; exists {
+		logger.Warn().Str("subscriber", name).Msg("Assinante de eventos ja registrado, ignorando")
+		return
+	}
+	b.handlers[name] = handler
+	for _, eventType := range eventTypes {
+		b.subscribers[eventType] = append(b.subscribers[eventType], name)
+	}
+}
+
+// Publish grava o evento e uma entrega por assinante do tipo. Tipos sem assinantes não são gravados.
+func (b *Bus) Publish(ctx context.Context, userID ulid.ULID, eventType string, data any) error {
+	b.mu.RLock()
+	names := append([]string(nil), b.subscribers[eventType]...)
+	b.mu.RUnlock()
+	if len(names) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	evt := &Event{
+		Id:         pkg.GenerateULIDObject(),
+		UserId:     userID,
+		Type:       eventType,
+		Payload:    payload,
+		OccurredAt: now,
+	}
+	deliveries := make([]*Delivery, 0, len(names))
+	for _, name := range names {
+		deliveries = append(deliveries, &Delivery{
+			Id:            pkg.GenerateULIDObject(),
+			EventId:       evt.Id,
+			Subscriber:    name,
+			Status:        DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := b.Repository.Create(ctx, evt, deliveries); err != nil {
+		return err
+	}
+	shared.AfterCommit(ctx, b.notify)
+	return nil
+}
+
+// Dispatch processa as entregas vencidas até a data e devolve quantas foram processadas com sucesso
+func (b *Bus) Dispatch(ctx context.Context, now time.Time) (int, error) {
+	processed := 0
+	for {
+		leaseUntil := time.Now().Add(claimLease)
+		deliveries, err := b.Repository.ClaimDue(ctx, now, leaseUntil, dispatchBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		for _, delivery := range deliveries {
+			if b.process(ctx, delivery, leaseUntil) {
+				processed++
+			}
+		}
+		if len(deliveries) < dispatchBatchSize || ctx.Err() != nil {
+			return processed, ctx.Err()
+		}
+	}
+}
+
+func (b *Bus) process(ctx context.Context, delivery *Delivery, leaseUntil time.Time) bool {
+	b.mu.RLock()
+	handler, ok := b.handlers[delivery.Subscriber]
+	b.mu.RUnlock()
+
+	now := time.Now()
+	delivery.Attempts++
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("assinante %q nao registrado", delivery.Subscriber)
+	} else {
+		err = b.run(ctx, handler, delivery)
+	}
+
+	if err == nil {
+		delivery.Status = DeliveryProcessed
+		delivery.ProcessedAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = truncate(err.Error(), errorLimit)
+		if !ok || delivery.Attempts >= b.Retry.MaxAttempts {
+			delivery.Status = DeliveryFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(b.Retry.Backoff(delivery.Attempts))
+		}
+		logger.Warn().
+			Err(err).
+			Str("event_id", delivery.EventId.String()).
+			Str("event", delivery.Event.Type).
+			Str("subscriber", delivery.Subscriber).
+			Int("attempts", delivery.Attempts).
+			Str("status", string(delivery.Status)).
+			Msg("Falha ao processar evento do dominio")
+	}
+
+	updateErr := b.Repository.UpdateDelivery(context.WithoutCancel(ctx), delivery, leaseUntil)
+	switch {
+	case errors.Is(updateErr, gorm.ErrRecordNotFound):
+		logger.Warn().
+			Str("delivery_id", delivery.Id.String()).
+			Msg("Reserva da entrega do evento expirou; o resultado fica com o despacho que a retomou")
+	case updateErr != nil:
+		logger.Error().
+			Err(updateErr).
+			Str("delivery_id", delivery.Id.String()).
+			Msg("Erro ao registrar processamento do evento")
+	}
+	return err == nil
+}
+
+// run chama o handler na transação que grava o marcador de processamento da entrega. Se o marcador já
+// existe, uma tentativa anterior confirmou o handler e só faltou registrar o resultado da entrega.
+func (b *Bus) run(ctx context.Context, handler Handler, delivery *Delivery) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic no handler: %v", rec)
+		}
+	}()
+	return shared.WithinTransaction(ctx, b.Transactor, func(ctx context.Context) error {
+		first, err := b.Repository.MarkProcessed(ctx, delivery.Id, time.Now())
+		if err != nil || !first {
+			return err
+		}
+		return handler(ctx, delivery.Event)
+	})
+}
+
+// Start inicia o despacho em segundo plano até Stop
+func (b *Bus) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.started {
+		return
+	}
+	b.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.wg.Add(1)
+	go b.loop(ctx)
+
+	logger.Info().Dur("poll_interval", b.PollInterval).Msg("Despacho de eventos iniciado")
+}
+
+func (b *Bus) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.started {
+		b.mu.Unlock()
+		return nil
+	}
+	b.cancel()
+	b.started = false
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info().Msg("Despacho de eventos parado")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) loop(ctx context.Context) {
+	defer b.wg.Done()
+
+	interval := b.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		b.dispatchSafely(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.wake:
+		}
+	}
+}
+
+func (b *Bus) dispatchSafely(ctx context.Context) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error().Err(fmt.Errorf("%v", rec)).Msg("Despacho de eventos interrompido por panic")
+		}
+	}()
+
+	if _, err := b.Dispatch(ctx, time.Now()); err != nil && ctx.Err() == nil {
+		logger.Error().Err(err).Msg("Falha ao despachar eventos do dominio")
+	}
+}
+
+// notify acorda o despacho sem bloquear; avisos seguidos se acumulam numa única rodada
+func (b *Bus) notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Cleanup remove os eventos já processados por todos os assinantes há mais de retention
+func (b *Bus) Cleanup(ctx context.Context, now time.Time, retention time.Duration) (int64, error) {
+	return b.Repository.DeleteProcessedBefore(ctx, now.Add(-retention))
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
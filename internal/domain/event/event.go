@@ -0,0 +1,99 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Event é um fato do domínio gravado na outbox junto com a mudança de estado que o originou.
+// Payload é o JSON do dado publicado e é repassado como está aos assinantes.
+type Event struct {
+	Id         ulid.ULID       `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId     ulid.ULID       `gorm:"type:varchar(26);index:idx_domain_events_user;not null" json:"userId"`
+	Type       string          `gorm:"type:varchar(50);not null" json:"type"`
+	Payload    json.RawMessage `gorm:"type:text;not null" json:"payload"`
+	OccurredAt time.Time       `gorm:"type:timestamp;not null" json:"occurredAt"`
+}
+
+func (Event) TableName() string {
+	return "domain_events"
+}
+
+// Decode converte o payload no tipo do assinante
+func (e *Event) Decode(target any) error {
+	return json.Unmarshal(e.Payload, target)
+}
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending aguarda o despacho ou uma nova tentativa em NextAttemptAt
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryProcessed DeliveryStatus = "PROCESSED"
+	// DeliveryFailed esgotou as tentativas ou não tem mais assinante registrado com o nome
+	DeliveryFailed DeliveryStatus = "FAILED"
+)
+
+// Delivery acompanha o processamento de um evento por um assinante. Cada assinante tem a própria
+// entrega, então a falha de um não faz os outros processarem o evento de novo.
+type Delivery struct {
+	Id            ulid.ULID      `gorm:"type:varchar(26);primaryKey" json:"id"`
+	EventId       ulid.ULID      `gorm:"type:varchar(26);index:idx_domain_event_deliveries_event;not null" json:"eventId"`
+	Subscriber    string         `gorm:"type:varchar(50);not null" json:"subscriber"`
+	Status        DeliveryStatus `gorm:"type:varchar(20);not null;index:idx_domain_event_deliveries_due,priority:1" json:"status"`
+	Attempts      int            `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time      `gorm:"type:timestamp;not null;index:idx_domain_event_deliveries_due,priority:2" json:"nextAttemptAt"`
+	LastError     string         `gorm:"type:text" json:"lastError"`
+	ProcessedAt   *time.Time     `gorm:"type:timestamp" json:"processedAt"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime;not null" json:"createdAt"`
+	Event         *Event         `gorm:"-" json:"event,omitempty"`
+}
+
+func (Delivery) TableName() string {
+	return "domain_event_deliveries"
+}
+
+// ProcessedDelivery marca uma entrega cujo handler foi confirmado. É gravada na mesma transação do
+// handler, então uma entrega despachada de novo depois do commit é reconhecida e não é aplicada duas vezes.
+type ProcessedDelivery struct {
+	DeliveryId  ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"deliveryId"`
+	ProcessedAt time.Time `gorm:"type:timestamp;not null" json:"processedAt"`
+}
+
+func (ProcessedDelivery) TableName() string {
+	return "domain_event_processed_deliveries"
+}
+
+// Handler processa um evento para um assinante. O handler roda na transação que marca a entrega como
+// processada, então as mudanças que ele grava pelo ctx são aplicadas uma única vez; se falhar, o evento é
+// entregue de novo. Efeitos fora do banco, como e-mails, continuam sendo pelo menos uma vez.
+type Handler func(ctx context.Context, event *Event) error
+
+// RetryPolicy define quantas vezes um assinante tenta processar o evento e a espera entre as
+// tentativas, que dobra a cada falha a partir de BaseDelay até MaxDelay
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   5 * time.Second,
+	MaxDelay:    time.Hour,
+}
+
+// Backoff devolve a espera antes da próxima tentativa, depois de attempts tentativas sem sucesso
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return min(delay, p.MaxDelay)
+}
@@ -0,0 +1,26 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Repository interface {
+	// Create grava o evento e as entregas dos assinantes; participa da transação aberta no ctx
+	Create(ctx context.Context, event *Event, deliveries []*Delivery) error
+	// ClaimDue reserva até limit entregas pendentes com tentativa vencida, adiando NextAttemptAt para
+	// leaseUntil, e devolve as entregas com o evento carregado. Entregas reservadas por outra instância
+	// são puladas.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Delivery, error)
+	// MarkProcessed grava o marcador de processamento da entrega na transação do ctx; devolve false se a
+	// entrega já tinha sido processada
+	MarkProcessed(ctx context.Context, deliveryID ulid.ULID, processedAt time.Time) (bool, error)
+	// UpdateDelivery grava o resultado da tentativa reservada até leaseUntil; devolve gorm.ErrRecordNotFound
+	// se a reserva expirou e a entrega foi retomada por outro despacho
+	UpdateDelivery(ctx context.Context, delivery *Delivery, leaseUntil time.Time) error
+	// DeleteProcessedBefore remove os eventos anteriores à data cujas entregas foram todas processadas,
+	// junto com as entregas e os marcadores de processamento
+	DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,199 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Source string
+
+const (
+	SourceRecurring   Source = "RECURRING"
+	SourceInvoice     Source = "INVOICE"
+	SourceInstallment Source = "INSTALLMENT"
+	SourceGoal        Source = "GOAL"
+	SourceLoan        Source = "LOAN"
+)
+
+// Event é um lançamento previsto em uma conta; Amount é positivo para entradas e negativo para saídas
+type Event struct {
+	Date        time.Time `json:"date"`
+	AccountId   ulid.ULID `json:"accountId"`
+	Amount      float64   `json:"amount"`
+	Source      Source    `json:"source"`
+	Description string    `json:"description"`
+	ReferenceId ulid.ULID `json:"referenceId"`
+	Overdue     bool      `json:"overdue"`
+}
+
+// DailyBalance é o saldo projetado ao fim do dia
+type DailyBalance struct {
+	Date    time.Time `json:"date"`
+	Inflow  float64   `json:"inflow"`
+	Outflow float64   `json:"outflow"`
+	Balance float64   `json:"balance"`
+}
+
+// AccountForecast é a projeção de uma conta. FirstNegativeDate é o primeiro dia com saldo negativo.
+type AccountForecast struct {
+	AccountId         ulid.ULID      `json:"accountId"`
+	Name              string         `json:"name"`
+	CurrentBalance    float64        `json:"currentBalance"`
+	ProjectedBalance  float64        `json:"projectedBalance"`
+	LowestBalance     float64        `json:"lowestBalance"`
+	LowestBalanceDate time.Time      `json:"lowestBalanceDate"`
+	FirstNegativeDate *time.Time     `json:"firstNegativeDate,omitempty"`
+	Days              []DailyBalance `json:"days"`
+	Events            []Event        `json:"events"`
+}
+
+// Forecast consolida as projeções das contas. Os totais somam apenas as contas que entram no saldo total.
+type Forecast struct {
+	From              time.Time          `json:"from"`
+	To                time.Time          `json:"to"`
+	Days              int                `json:"days"`
+	CurrentBalance    float64            `json:"currentBalance"`
+	ProjectedBalance  float64            `json:"projectedBalance"`
+	LowestBalance     float64            `json:"lowestBalance"`
+	LowestBalanceDate time.Time          `json:"lowestBalanceDate"`
+	FirstNegativeDate *time.Time         `json:"firstNegativeDate,omitempty"`
+	NegativeAccountId *ulid.ULID         `json:"negativeAccountId,omitempty"`
+	Accounts          []*AccountForecast `json:"accounts"`
+	Total             []DailyBalance     `json:"total"`
+}
+
+// AccountBalance é o ponto de partida da projeção de uma conta
+type AccountBalance struct {
+	AccountId      ulid.ULID
+	Name           string
+	Balance        float64
+	IncludeInTotal bool
+}
+
+// Build projeta o saldo diário de cada conta de from até from+days-1. Eventos anteriores a from
+// (pendentes) são lançados no primeiro dia e eventos de contas desconhecidas são ignorados.
+func Build(accounts []AccountBalance, events []Event, from time.Time, days int) *Forecast {
+	from = truncateDay(from)
+	to := from.AddDate(0, 0, days-1)
+
+	byAccount := make(map[ulid.ULID][]Event, len(accounts))
+	for _, event := range events {
+		event.Date = truncateDay(event.Date)
+		if event.Date.After(to) {
+			continue
+		}
+		if event.Date.Before(from) {
+			event.Date = from
+			event.Overdue = true
+		}
+		byAccount[event.AccountId] = append(byAccount[event.AccountId], event)
+	}
+
+	result := &Forecast{
+		From:     from,
+		To:       to,
+		Days:     days,
+		Accounts: make([]*AccountForecast, 0, len(accounts)),
+		Total:    make([]DailyBalance, days),
+	}
+	for i := range result.Total {
+		result.Total[i].Date = from.AddDate(0, 0, i)
+	}
+
+	for _, account := range accounts {
+		accountEvents := byAccount[account.AccountId]
+		sort.SliceStable(accountEvents, func(i, j int) bool {
+			return accountEvents[i].Date.Before(accountEvents[j].Date)
+		})
+		projection := projectAccount(account, accountEvents, from, days)
+		result.Accounts = append(result.Accounts, projection)
+
+		if projection.FirstNegativeDate != nil && (result.FirstNegativeDate == nil || projection.FirstNegativeDate.Before(*result.FirstNegativeDate)) {
+			date, accountID := *projection.FirstNegativeDate, projection.AccountId
+			result.FirstNegativeDate, result.NegativeAccountId = &date, &accountID
+		}
+
+		if !account.IncludeInTotal {
+			continue
+		}
+		result.CurrentBalance += projection.CurrentBalance
+		for i, day := range projection.Days {
+			result.Total[i].Inflow += day.Inflow
+			result.Total[i].Outflow += day.Outflow
+			result.Total[i].Balance += day.Balance
+		}
+	}
+
+	result.CurrentBalance = roundCents(result.CurrentBalance)
+	result.LowestBalance = result.CurrentBalance
+	result.LowestBalanceDate = from
+	for i := range result.Total {
+		day := &result.Total[i]
+		day.Inflow, day.Outflow, day.Balance = roundCents(day.Inflow), roundCents(day.Outflow), roundCents(day.Balance)
+		if day.Balance < result.LowestBalance {
+			result.LowestBalance, result.LowestBalanceDate = day.Balance, day.Date
+		}
+	}
+	result.ProjectedBalance = result.Total[days-1].Balance
+	return result
+}
+
+func projectAccount(account AccountBalance, events []Event, from time.Time, days int) *AccountForecast {
+	projection := &AccountForecast{
+		AccountId:         account.AccountId,
+		Name:              account.Name,
+		CurrentBalance:    roundCents(account.Balance),
+		LowestBalance:     roundCents(account.Balance),
+		LowestBalanceDate: from,
+		Days:              make([]DailyBalance, days),
+		Events:            events,
+	}
+	if projection.Events == nil {
+		projection.Events = []Event{}
+	}
+	if account.Balance < 0 {
+		date := from
+		projection.FirstNegativeDate = &date
+	}
+
+	balance := account.Balance
+	next := 0
+	for i := 0; i < days; i++ {
+		day := DailyBalance{Date: from.AddDate(0, 0, i)}
+		for ; next < len(events) && !events[next].Date.After(day.Date); next++ {
+			amount := events[next].Amount
+			if amount >= 0 {
+				day.Inflow += amount
+			} else {
+				day.Outflow -= amount
+			}
+			balance += amount
+		}
+
+		day.Inflow, day.Outflow = roundCents(day.Inflow), roundCents(day.Outflow)
+		day.Balance = roundCents(balance)
+		projection.Days[i] = day
+
+		if day.Balance < projection.LowestBalance {
+			projection.LowestBalance, projection.LowestBalanceDate = day.Balance, day.Date
+		}
+		if day.Balance < 0 && projection.FirstNegativeDate == nil {
+			date := day.Date
+			projection.FirstNegativeDate = &date
+		}
+	}
+
+	projection.ProjectedBalance = projection.Days[days-1].Balance
+	return projection
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,189 @@
+package forecast
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/creditcard"
+	"Fynance/internal/domain/goal"
+	"Fynance/internal/domain/loan"
+	"Fynance/internal/domain/recurring"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	DefaultDays = 90
+	MaxDays     = 365
+
+	// accountPageSize cobre todas as contas do usuário em uma única página
+	accountPageSize = 1000
+)
+
+type Service struct {
+	AccountService    *account.Service
+	RecurringService  *recurring.Service
+	CreditCardService *creditcard.Service
+	GoalService       *goal.Service
+	LoanService       *loan.Service
+	shared.BaseService
+}
+
+func NewService(
+	accountService *account.Service,
+	recurringService *recurring.Service,
+	creditCardService *creditcard.Service,
+	goalService *goal.Service,
+	loanService *loan.Service,
+	userChecker *shared.UserCheckerService,
+) *Service {
+	return &Service{
+		AccountService:    accountService,
+		RecurringService:  recurringService,
+		CreditCardService: creditCardService,
+		GoalService:       goalService,
+		LoanService:       loanService,
+		BaseService: shared.BaseService{
+			UserChecker: userChecker,
+		},
+	}
+}
+
+// GetForecast projeta o saldo diário das contas nos próximos dias a partir dos saldos atuais, das
+// recorrências ativas, das faturas de cartão, dos aportes automáticos em metas e das parcelas de empréstimos
+func (s *Service) GetForecast(ctx context.Context, userID ulid.ULID, days int) (*Forecast, error) {
+	if days < 1 || days > MaxDays {
+		return nil, appErrors.NewValidationError("days", "deve estar entre 1 e 365")
+	}
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	accounts, _, err := s.AccountService.ListActiveAccounts(ctx, userID, &pkg.PaginationParams{Page: 1, Limit: accountPageSize})
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]AccountBalance, 0, len(accounts))
+	for _, acc := range accounts {
+		if acc.Type == account.TypeCreditCard {
+			continue
+		}
+		balances = append(balances, AccountBalance{
+			AccountId:      acc.Id,
+			Name:           acc.Name,
+			Balance:        acc.Balance,
+			IncludeInTotal: acc.IncludeInTotal,
+		})
+	}
+
+	from := truncateDay(time.Now())
+	until := from.AddDate(0, 0, days-1)
+	events, err := s.collectEvents(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+
+	return Build(balances, events, from, days), nil
+}
+
+func (s *Service) collectEvents(ctx context.Context, userID ulid.ULID, until time.Time) ([]Event, error) {
+	var events []Event
+
+	occurrences, err := s.RecurringService.UpcomingOccurrences(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, occurrence := range occurrences {
+		events = append(events, recurringEvents(occurrence)...)
+	}
+
+	invoices, err := s.CreditCardService.UpcomingInvoicePayments(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range invoices {
+		source, referenceID := SourceInstallment, payment.CreditCardId
+		if payment.InvoiceId != nil {
+			source, referenceID = SourceInvoice, *payment.InvoiceId
+		}
+		events = append(events, Event{
+			Date:        payment.DueDate,
+			AccountId:   payment.AccountId,
+			Amount:      -payment.Amount,
+			Source:      source,
+			Description: "Fatura " + payment.CreditCardName,
+			ReferenceId: referenceID,
+		})
+	}
+
+	contributions, err := s.GoalService.UpcomingContributions(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, contribution := range contributions {
+		events = append(events, Event{
+			Date:        contribution.Date,
+			AccountId:   contribution.AccountId,
+			Amount:      -contribution.Amount,
+			Source:      SourceGoal,
+			Description: "Aporte automatico: " + contribution.GoalName,
+			ReferenceId: contribution.GoalId,
+		})
+	}
+
+	installments, err := s.LoanService.UpcomingInstallments(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, upcoming := range installments {
+		events = append(events, Event{
+			Date:        upcoming.Installment.DueDate,
+			AccountId:   upcoming.AccountId,
+			Amount:      -upcoming.Installment.Payment,
+			Source:      SourceLoan,
+			Description: "Parcela " + upcoming.LoanName,
+			ReferenceId: upcoming.LoanId,
+		})
+	}
+
+	return events, nil
+}
+
+// recurringEvents converte a ocorrência nos lançamentos das contas. Transferências debitam a origem e
+// creditam o destino; cobranças no cartão só afetam as contas no pagamento da fatura.
+func recurringEvents(occurrence recurring.Occurrence) []Event {
+	rec := occurrence.Recurring
+	if rec.AccountId == nil || rec.Kind == recurring.KindCreditCard {
+		return nil
+	}
+
+	amount := occurrence.Amount
+	if rec.Kind != recurring.KindTransaction || transaction.Types(rec.Type) == transaction.Expense {
+		amount = -amount
+	}
+	events := []Event{{
+		Date:        occurrence.Date,
+		AccountId:   *rec.AccountId,
+		Amount:      amount,
+		Source:      SourceRecurring,
+		Description: rec.Description,
+		ReferenceId: rec.Id,
+	}}
+
+	if rec.Kind == recurring.KindTransfer && rec.DestinationAccountId != nil {
+		events = append(events, Event{
+			Date:        occurrence.Date,
+			AccountId:   *rec.DestinationAccountId,
+			Amount:      occurrence.Amount,
+			Source:      SourceRecurring,
+			Description: rec.Description,
+			ReferenceId: rec.Id,
+		})
+	}
+	return events
+}
@@ -0,0 +1,281 @@
+package goal
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/investment"
+	"Fynance/internal/domain/shared"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// GetGoalInvestments retorna os investimentos que lastreiam a meta
+func (s *Service) GetGoalInvestments(ctx context.Context, goalID, userID ulid.ULID) ([]*investment.Investment, error) {
+	if err := s.CheckGoalBelongsToUser(ctx, goalID, userID); err != nil {
+		return nil, err
+	}
+	return s.linkedInvestments(ctx, goalID, userID)
+}
+
+// LinkInvestment vincula um investimento existente à meta e recalcula o saldo
+func (s *Service) LinkInvestment(ctx context.Context, goalID, investmentID, userID ulid.ULID) error {
+	if s.InvestmentService == nil {
+		return appErrors.ErrInternalServer
+	}
+
+	goal, err := s.GetGoalByID(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+
+	if goal.Status == Cancelled {
+		return appErrors.NewValidationError("goal", "meta cancelada nao aceita investimentos")
+	}
+
+	if _, err := s.InvestmentService.LinkToGoal(ctx, investmentID, userID, goalID); err != nil {
+		return err
+	}
+
+	if err := s.SyncInvestmentBackedAmount(ctx, goalID, userID); err != nil {
+		return err
+	}
+
+	if err := s.recordMilestone(ctx, goalID, userID); err != nil {
+		return err
+	}
+
+	return s.refreshSuggestedAmount(ctx, goalID, userID)
+}
+
+// UnlinkInvestment desvincula o investimento da meta. O saldo do investimento deixa de compor a meta.
+func (s *Service) UnlinkInvestment(ctx context.Context, goalID, investmentID, userID ulid.ULID) error {
+	if s.InvestmentService == nil {
+		return appErrors.ErrInternalServer
+	}
+
+	if err := s.CheckGoalBelongsToUser(ctx, goalID, userID); err != nil {
+		return err
+	}
+
+	if err := s.InvestmentService.UnlinkFromGoal(ctx, investmentID, userID, goalID); err != nil {
+		return err
+	}
+
+	if err := s.SyncInvestmentBackedAmount(ctx, goalID, userID); err != nil {
+		return err
+	}
+
+	return s.refreshSuggestedAmount(ctx, goalID, userID)
+}
+
+// SyncInvestmentBackedAmount recalcula o saldo da meta como a parcela em dinheiro
+// mais o saldo atual (principal + rendimento) dos investimentos vinculados
+func (s *Service) SyncInvestmentBackedAmount(ctx context.Context, goalID, userID ulid.ULID) error {
+	goal, err := s.GetGoalByID(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+
+	contributions, err := s.Repository.GetContributionsByGoalID(ctx, goalID, userID)
+	if err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	investments, err := s.linkedInvestments(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+
+	amount := cashBalance(contributions)
+	for _, inv := range investments {
+		amount += inv.CurrentBalance
+	}
+	amount = math.Round(amount*100) / 100
+
+	now := time.Now()
+	fields := map[string]interface{}{
+		"current_amount": amount,
+		"updated_at":     now,
+	}
+
+	completed := false
+	switch {
+	case goal.Status == Active && amount >= goal.TargetAmount:
+		fields["status"] = Completed
+		fields["completed_at"] = &now
+		completed = true
+	case goal.Status == Completed && amount < goal.TargetAmount:
+		fields["status"] = Active
+		fields["completed_at"] = nil
+	}
+
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.Repository.UpdateFields(ctx, goalID, fields); err != nil {
+			return err
+		}
+		if completed {
+			return s.publishCompleted(ctx, goalID, userID)
+		}
+		return nil
+	})
+}
+
+// contributeToInvestment aplica o aporte da meta diretamente em um investimento vinculado, na mesma
+// transação que grava a movimentação da meta
+func (s *Service) contributeToInvestment(ctx context.Context, goal *Goal, target *investment.Investment, accountID, userID ulid.ULID, amount float64, description string) error {
+	description = strings.TrimSpace(description)
+	movementDescription := description
+	if movementDescription == "" {
+		movementDescription = "Aporte na meta: " + goal.Name
+	}
+
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.InvestmentService.MakeContribution(ctx, target.Id, accountID, userID, amount, movementDescription); err != nil {
+			return err
+		}
+
+		contribution := &Contribution{
+			Id:           pkg.GenerateULIDObject(),
+			GoalId:       goal.Id,
+			UserId:       userID,
+			AccountId:    accountID,
+			InvestmentId: &target.Id,
+			Type:         ContributionDeposit,
+			Amount:       amount,
+			Description:  description,
+			CreatedAt:    time.Now(),
+		}
+
+		if err := s.Repository.CreateContribution(ctx, contribution); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+
+		if err := s.SyncInvestmentBackedAmount(ctx, goal.Id, userID); err != nil {
+			return err
+		}
+
+		if err := s.recordMilestone(ctx, goal.Id, userID); err != nil {
+			return err
+		}
+
+		return s.refreshSuggestedAmount(ctx, goal.Id, userID)
+	})
+}
+
+// withdrawFromBackedGoal resgata primeiro a parcela em dinheiro e depois os investimentos
+// vinculados, do maior saldo para o menor, creditando tudo na conta informada. Roda na transação
+// aberta por WithdrawFromGoal, então uma falha desfaz os resgates já feitos.
+func (s *Service) withdrawFromBackedGoal(ctx context.Context, goal *Goal, investments []*investment.Investment, accountID, userID ulid.ULID, amount float64, description string) error {
+	contributions, err := s.Repository.GetContributionsByGoalID(ctx, goal.Id, userID)
+	if err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	description = strings.TrimSpace(description)
+	remaining := amount
+
+	cash := math.Min(cashBalance(contributions), remaining)
+	if cash > 0 {
+		contribution := &Contribution{
+			Id:          pkg.GenerateULIDObject(),
+			GoalId:      goal.Id,
+			UserId:      userID,
+			AccountId:   accountID,
+			Type:        ContributionWithdraw,
+			Amount:      cash,
+			Description: description,
+			CreatedAt:   time.Now(),
+		}
+		if err := s.Repository.CreateContribution(ctx, contribution); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+		if err := s.AccountService.UpdateBalance(ctx, accountID, userID, cash); err != nil {
+			return err
+		}
+		remaining -= cash
+	}
+
+	sort.SliceStable(investments, func(i, j int) bool {
+		return investments[i].CurrentBalance > investments[j].CurrentBalance
+	})
+
+	movementDescription := description
+	if movementDescription == "" {
+		movementDescription = "Resgate da meta: " + goal.Name
+	}
+
+	for _, inv := range investments {
+		if remaining < 0.005 {
+			break
+		}
+		if inv.CurrentBalance <= 0 {
+			continue
+		}
+
+		part := math.Round(math.Min(remaining, inv.CurrentBalance)*100) / 100
+		if _, err := s.InvestmentService.MakeWithdraw(ctx, inv.Id, accountID, userID, part, movementDescription); err != nil {
+			return err
+		}
+
+		investmentID := inv.Id
+		contribution := &Contribution{
+			Id:           pkg.GenerateULIDObject(),
+			GoalId:       goal.Id,
+			UserId:       userID,
+			AccountId:    accountID,
+			InvestmentId: &investmentID,
+			Type:         ContributionWithdraw,
+			Amount:       part,
+			Description:  description,
+			CreatedAt:    time.Now(),
+		}
+		if err := s.Repository.CreateContribution(ctx, contribution); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+		remaining -= part
+	}
+
+	if remaining >= 0.005 {
+		// o saldo dos investimentos não cobre o resgate; a transação desfaz o que já foi movimentado
+		return appErrors.NewValidationError("amount", "saldo insuficiente na meta")
+	}
+
+	if err := s.SyncInvestmentBackedAmount(ctx, goal.Id, userID); err != nil {
+		return err
+	}
+
+	return s.refreshSuggestedAmount(ctx, goal.Id, userID)
+}
+
+func (s *Service) linkedInvestments(ctx context.Context, goalID, userID ulid.ULID) ([]*investment.Investment, error) {
+	if s.InvestmentService == nil {
+		return nil, nil
+	}
+	return s.InvestmentService.ListByGoal(ctx, goalID, userID)
+}
+
+// cashBalance soma a parcela da meta mantida em dinheiro (movimentações sem investimento)
+func cashBalance(contributions []*Contribution) float64 {
+	total := 0.0
+	for _, c := range contributions {
+		if c.InvestmentId != nil {
+			continue
+		}
+		switch c.Type {
+		case ContributionDeposit:
+			total += c.Amount
+		case ContributionWithdraw:
+			total -= c.Amount
+		}
+	}
+	if total < 0 {
+		return 0
+	}
+	return total
+}
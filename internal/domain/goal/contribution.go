@@ -19,6 +19,7 @@ type Contribution struct {
 	UserId        ulid.ULID        `gorm:"type:varchar(26);index:idx_contributions_user_id;not null" json:"userId"`
 	AccountId     ulid.ULID        `gorm:"type:varchar(26);index:idx_contributions_account_id;not null" json:"accountId"`
 	TransactionId *ulid.ULID       `gorm:"type:varchar(26);index:idx_contributions_transaction_id" json:"transactionId,omitempty"`
+	InvestmentId  *ulid.ULID       `gorm:"type:varchar(26);index:idx_contributions_investment_id" json:"investmentId,omitempty"`
 	Type          ContributionType `gorm:"type:varchar(20);not null" json:"type"`
 	Amount        float64          `gorm:"type:decimal(15,2);not null" json:"amount"`
 	Description   string           `gorm:"type:varchar(255)" json:"description"`
@@ -1,6 +1,7 @@
 package goal
 
 import (
+	"math"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -25,6 +26,9 @@ type Goal struct {
 	LastMilestone   int      `gorm:"default:0" json:"lastMilestone"`
 	TotalContribs   int      `gorm:"default:0" json:"totalContribs"`
 	SuggestedAmount *float64 `gorm:"type:decimal(15,2)" json:"suggestedAmount"`
+
+	// CompletedAt registra quando a meta foi concluída; EndedAt continua sendo o prazo
+	CompletedAt *time.Time `gorm:"type:timestamp" json:"completedAt"`
 }
 
 func (Goal) TableName() string {
@@ -56,3 +60,48 @@ func (g *Goal) HasNewMilestone() bool {
 	currentMilestone := g.GetCurrentMilestone()
 	return currentMilestone > g.LastMilestone
 }
+
+// RequiredMonthlyAmount calcula o aporte mensal necessário para atingir o alvo até EndedAt.
+// Retorna nil quando a meta não possui prazo.
+func (g *Goal) RequiredMonthlyAmount(now time.Time) *float64 {
+	if g.EndedAt == nil {
+		return nil
+	}
+
+	remaining := g.TargetAmount - g.CurrentAmount
+	if remaining <= 0 {
+		zero := 0.0
+		return &zero
+	}
+
+	months := monthsBetween(now, *g.EndedAt)
+	if months < 1 {
+		months = 1
+	}
+
+	amount := math.Ceil(remaining/float64(months)*100) / 100
+	return &amount
+}
+
+// ProjectedCompletion estima quando o alvo será atingido mantendo o ritmo mensal informado
+func (g *Goal) ProjectedCompletion(monthlyPace float64, now time.Time) *time.Time {
+	remaining := g.TargetAmount - g.CurrentAmount
+	if remaining <= 0 {
+		return &now
+	}
+	if monthlyPace <= 0 {
+		return nil
+	}
+
+	months := int(math.Ceil(remaining / monthlyPace))
+	projected := now.AddDate(0, months, 0)
+	return &projected
+}
+
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return months
+}
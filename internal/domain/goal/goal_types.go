@@ -7,3 +7,27 @@ const (
 	Completed GoalStatus = "COMPLETED"
 	Cancelled GoalStatus = "CANCELLED"
 )
+
+type ScheduleFrequency string
+
+const (
+	ScheduleWeekly   ScheduleFrequency = "WEEKLY"
+	ScheduleBiweekly ScheduleFrequency = "BIWEEKLY"
+	ScheduleMonthly  ScheduleFrequency = "MONTHLY"
+)
+
+func (f ScheduleFrequency) IsValid() bool {
+	switch f {
+	case ScheduleWeekly, ScheduleBiweekly, ScheduleMonthly:
+		return true
+	}
+	return false
+}
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunSuccess ScheduleRunStatus = "SUCCESS"
+	ScheduleRunSkipped ScheduleRunStatus = "SKIPPED"
+	ScheduleRunFailed  ScheduleRunStatus = "FAILED"
+)
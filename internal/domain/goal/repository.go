@@ -2,6 +2,7 @@ package goal
 
 import (
 	"context"
+	"time"
 
 	"Fynance/internal/pkg"
 
@@ -29,4 +30,10 @@ type GoalRepository interface {
 	DeleteContribution(ctx context.Context, contributionId ulid.ULID) error
 	UpdateCurrentAmount(ctx context.Context, goalId ulid.ULID, amount float64) error
 	UpdateCurrentAmountAtomic(ctx context.Context, goalId ulid.ULID, delta float64) error
+	CreateSchedule(ctx context.Context, schedule *ContributionSchedule) error
+	UpdateSchedule(ctx context.Context, schedule *ContributionSchedule) error
+	GetScheduleByGoalID(ctx context.Context, goalId ulid.ULID, userId ulid.ULID) (*ContributionSchedule, error)
+	DeleteSchedule(ctx context.Context, goalId ulid.ULID, userId ulid.ULID) error
+	GetDueSchedules(ctx context.Context, date time.Time, limit int) ([]*ContributionSchedule, error)
+	GetActiveSchedulesByUserID(ctx context.Context, userId ulid.ULID) ([]*ContributionSchedule, error)
 }
@@ -0,0 +1,95 @@
+package goal
+
+import (
+	"math"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ContributionSchedule é o aporte automático de uma meta, executado pela fila de jobs
+type ContributionSchedule struct {
+	Id           ulid.ULID         `gorm:"type:varchar(26);primaryKey" json:"id"`
+	GoalId       ulid.ULID         `gorm:"type:varchar(26);uniqueIndex:idx_goal_schedules_goal_id;not null" json:"goalId"`
+	UserId       ulid.ULID         `gorm:"type:varchar(26);index:idx_goal_schedules_user_id;not null" json:"userId"`
+	AccountId    ulid.ULID         `gorm:"type:varchar(26);not null" json:"accountId"`
+	Amount       float64           `gorm:"type:decimal(15,2);not null;default:0" json:"amount"`
+	UseSuggested bool              `gorm:"not null;default:false" json:"useSuggested"`
+	Frequency    ScheduleFrequency `gorm:"type:varchar(20);not null" json:"frequency"`
+	DayOfMonth   int               `gorm:"default:1" json:"dayOfMonth"`
+	DayOfWeek    int               `gorm:"default:0" json:"dayOfWeek"`
+	NextRunAt    time.Time         `gorm:"type:date;not null;index:idx_goal_schedules_next_run" json:"nextRunAt"`
+	LastRunAt    *time.Time        `gorm:"type:timestamp" json:"lastRunAt"`
+	LastStatus   ScheduleRunStatus `gorm:"type:varchar(20)" json:"lastStatus,omitempty"`
+	LastMessage  string            `gorm:"type:varchar(255)" json:"lastMessage,omitempty"`
+	SkippedRuns  int               `gorm:"not null;default:0" json:"skippedRuns"`
+	IsActive     bool              `gorm:"not null;default:true;index:idx_goal_schedules_active" json:"isActive"`
+	CreatedAt    time.Time         `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt    time.Time         `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (ContributionSchedule) TableName() string {
+	return "goal_contribution_schedules"
+}
+
+// FirstRun retorna a primeira execução a partir da data informada (inclusive)
+func (cs *ContributionSchedule) FirstRun(from time.Time) time.Time {
+	from = truncateDay(from)
+
+	switch cs.Frequency {
+	case ScheduleMonthly:
+		candidate := dayInMonth(from.Year(), from.Month(), cs.DayOfMonth)
+		if candidate.Before(from) {
+			next := from.AddDate(0, 0, -from.Day()+1).AddDate(0, 1, 0)
+			candidate = dayInMonth(next.Year(), next.Month(), cs.DayOfMonth)
+		}
+		return candidate
+	default:
+		daysUntil := (cs.DayOfWeek - int(from.Weekday()) + 7) % 7
+		return from.AddDate(0, 0, daysUntil)
+	}
+}
+
+// NextRunAfter retorna a execução seguinte a uma execução agendada
+func (cs *ContributionSchedule) NextRunAfter(scheduled time.Time) time.Time {
+	scheduled = truncateDay(scheduled)
+
+	switch cs.Frequency {
+	case ScheduleWeekly:
+		return scheduled.AddDate(0, 0, 7)
+	case ScheduleBiweekly:
+		return scheduled.AddDate(0, 0, 14)
+	default:
+		next := scheduled.AddDate(0, 0, -scheduled.Day()+1).AddDate(0, 1, 0)
+		return dayInMonth(next.Year(), next.Month(), cs.DayOfMonth)
+	}
+}
+
+// AmountFromMonthly converte um valor mensal para o valor de cada execução
+func (cs *ContributionSchedule) AmountFromMonthly(monthly float64) float64 {
+	var amount float64
+	switch cs.Frequency {
+	case ScheduleWeekly:
+		amount = monthly * 12 / 52
+	case ScheduleBiweekly:
+		amount = monthly * 12 / 26
+	default:
+		amount = monthly
+	}
+	return math.Ceil(amount*100) / 100
+}
+
+func dayInMonth(year int, month time.Month, day int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	if day < 1 {
+		day = 1
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
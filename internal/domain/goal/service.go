@@ -3,15 +3,18 @@ package goal
 import (
 	"context"
 	"errors"
+	"math"
 	"strings"
 	"time"
 
 	"Fynance/internal/contracts"
 	"Fynance/internal/domain/account"
 	"Fynance/internal/domain/category"
+	"Fynance/internal/domain/investment"
 	"Fynance/internal/domain/shared"
 	"Fynance/internal/domain/transaction"
 	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
 	"Fynance/internal/pkg"
 
 	"github.com/oklog/ulid/v2"
@@ -22,10 +25,19 @@ type Service struct {
 	Repository         GoalRepository
 	AccountService     account.AccountServiceInterface
 	TransactionService transaction.TransactionHandler
+	// InvestmentService é opcional e habilita metas lastreadas por investimentos
+	InvestmentService *investment.Service
+	// Events é opcional e publica a conclusão e os marcos das metas; conquistas e notificações
+	// acompanham os marcos por esses eventos
+	Events shared.EventPublisher
+	// Transactor é opcional e grava cada movimentação da meta, com o novo status, os marcos e os
+	// eventos, de forma atômica
+	Transactor shared.Transactor
 	shared.BaseService
 }
 
 var _ shared.GoalContributionDeleter = (*Service)(nil)
+var _ shared.GoalInvestmentSyncer = (*Service)(nil)
 
 func NewService(repo GoalRepository, accountService account.AccountServiceInterface, transactionService transaction.TransactionHandler, userChecker *shared.UserCheckerService) *Service {
 	return &Service{
@@ -60,6 +72,7 @@ func (s *Service) CreateGoal(ctx context.Context, request *contracts.GoalCreateR
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
+	entity.SuggestedAmount = entity.RequiredMonthlyAmount(now)
 
 	return s.Repository.Create(ctx, entity)
 }
@@ -78,6 +91,14 @@ func (s *Service) MakeContribution(ctx context.Context, goalID, accountID, userI
 		return appErrors.NewValidationError("goal", "meta nao esta ativa")
 	}
 
+	investments, err := s.linkedInvestments(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+	if len(investments) > 0 {
+		return s.contributeToInvestment(ctx, goal, investments[0], accountID, userID, amount, description)
+	}
+
 	accountEntity, err := s.AccountService.GetAccountByID(ctx, accountID, userID)
 	if err != nil {
 		return err
@@ -91,44 +112,78 @@ func (s *Service) MakeContribution(ctx context.Context, goalID, accountID, userI
 		return appErrors.NewValidationError("amount", "saldo insuficiente na conta")
 	}
 
-	if err := s.AccountService.UpdateBalance(ctx, accountID, userID, -amount); err != nil {
-		return err
-	}
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.AccountService.UpdateBalance(ctx, accountID, userID, -amount); err != nil {
+			return err
+		}
 
-	var transactionID *ulid.ULID
-	if s.TransactionService != nil {
-		tx, err := s.createGoalTransaction(ctx, goal, accountID, userID, amount, description)
-		if err != nil {
-			_ = s.AccountService.UpdateBalance(ctx, accountID, userID, amount)
+		var transactionID *ulid.ULID
+		if s.TransactionService != nil {
+			tx, err := s.createGoalTransaction(ctx, goal, accountID, userID, amount, description)
+			if err != nil {
+				return err
+			}
+			transactionID = &tx.Id
+		}
+
+		contribution := &Contribution{
+			Id:            pkg.GenerateULIDObject(),
+			GoalId:        goalID,
+			UserId:        userID,
+			AccountId:     accountID,
+			TransactionId: transactionID,
+			Type:          ContributionDeposit,
+			Amount:        amount,
+			Description:   strings.TrimSpace(description),
+			CreatedAt:     time.Now(),
+		}
+
+		if err := s.Repository.CreateContribution(ctx, contribution); err != nil {
 			return err
 		}
-		transactionID = &tx.Id
-	}
 
-	contribution := &Contribution{
-		Id:            pkg.GenerateULIDObject(),
-		GoalId:        goalID,
-		UserId:        userID,
-		AccountId:     accountID,
-		TransactionId: transactionID,
-		Type:          ContributionDeposit,
-		Amount:        amount,
-		Description:   strings.TrimSpace(description),
-		CreatedAt:     time.Now(),
+		if err := s.Repository.UpdateCurrentAmountAtomic(ctx, goalID, amount); err != nil {
+			return err
+		}
+
+		if err := s.checkAndUpdateGoalStatus(ctx, goalID, userID); err != nil {
+			return err
+		}
+
+		if err := s.recordMilestone(ctx, goalID, userID); err != nil {
+			return err
+		}
+
+		return s.refreshSuggestedAmount(ctx, goalID, userID)
+	})
+}
+
+// MakeInvestmentContribution aporta na meta aplicando o valor em um investimento vinculado específico
+func (s *Service) MakeInvestmentContribution(ctx context.Context, goalID, investmentID, accountID, userID ulid.ULID, amount float64, description string) error {
+	if amount <= 0 {
+		return appErrors.NewValidationError("amount", "deve ser maior que zero")
 	}
 
-	if err := s.Repository.CreateContribution(ctx, contribution); err != nil {
-		s.rollbackContribution(ctx, transactionID, accountID, userID, amount)
+	goal, err := s.GetGoalByID(ctx, goalID, userID)
+	if err != nil {
 		return err
 	}
 
-	if err := s.Repository.UpdateCurrentAmountAtomic(ctx, goalID, amount); err != nil {
-		s.rollbackContribution(ctx, transactionID, accountID, userID, amount)
-		_ = s.Repository.DeleteContribution(ctx, contribution.Id)
+	if goal.Status != Active {
+		return appErrors.NewValidationError("goal", "meta nao esta ativa")
+	}
+
+	investments, err := s.linkedInvestments(ctx, goalID, userID)
+	if err != nil {
 		return err
 	}
+	for _, inv := range investments {
+		if inv.Id == investmentID {
+			return s.contributeToInvestment(ctx, goal, inv, accountID, userID, amount, description)
+		}
+	}
 
-	return s.checkAndUpdateGoalStatus(ctx, goalID, userID)
+	return appErrors.NewValidationError("investment_id", "investimento nao esta vinculado a esta meta")
 }
 
 func (s *Service) WithdrawFromGoal(ctx context.Context, goalID, accountID, userID ulid.ULID, amount float64, description string) error {
@@ -154,43 +209,53 @@ func (s *Service) WithdrawFromGoal(ctx context.Context, goalID, accountID, userI
 		return appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
 	}
 
-	contribution := &Contribution{
-		Id:          pkg.GenerateULIDObject(),
-		GoalId:      goalID,
-		UserId:      userID,
-		Type:        ContributionWithdraw,
-		Amount:      amount,
-		Description: strings.TrimSpace(description),
-		CreatedAt:   time.Now(),
-	}
-
-	if err := s.Repository.CreateContribution(ctx, contribution); err != nil {
+	investments, err := s.linkedInvestments(ctx, goalID, userID)
+	if err != nil {
 		return err
 	}
 
-	if err := s.Repository.UpdateCurrentAmountAtomic(ctx, goalID, -amount); err != nil {
-		return err
-	}
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if len(investments) > 0 {
+			return s.withdrawFromBackedGoal(ctx, goal, investments, accountID, userID, amount, description)
+		}
 
-	if err := s.AccountService.UpdateBalance(ctx, accountID, userID, amount); err != nil {
-		_ = s.Repository.UpdateCurrentAmountAtomic(ctx, goalID, amount)
-		return err
-	}
+		contribution := &Contribution{
+			Id:          pkg.GenerateULIDObject(),
+			GoalId:      goalID,
+			UserId:      userID,
+			Type:        ContributionWithdraw,
+			Amount:      amount,
+			Description: strings.TrimSpace(description),
+			CreatedAt:   time.Now(),
+		}
 
-	goal, err = s.GetGoalByID(ctx, goalID, userID)
-	if err != nil {
-		return err
-	}
+		if err := s.Repository.CreateContribution(ctx, contribution); err != nil {
+			return err
+		}
 
-	if goal.Status == Completed && goal.CurrentAmount < goal.TargetAmount {
-		return s.Repository.UpdateFields(ctx, goalID, map[string]interface{}{
-			"status":     Active,
-			"ended_at":   nil,
-			"updated_at": time.Now(),
-		})
-	}
+		if err := s.Repository.UpdateCurrentAmountAtomic(ctx, goalID, -amount); err != nil {
+			return err
+		}
 
-	return nil
+		if err := s.AccountService.UpdateBalance(ctx, accountID, userID, amount); err != nil {
+			return err
+		}
+
+		goal, err := s.GetGoalByID(ctx, goalID, userID)
+		if err != nil {
+			return err
+		}
+
+		if goal.Status == Completed && goal.CurrentAmount < goal.TargetAmount {
+			return s.Repository.UpdateFields(ctx, goalID, map[string]interface{}{
+				"status":       Active,
+				"completed_at": nil,
+				"updated_at":   time.Now(),
+			})
+		}
+
+		return s.refreshSuggestedAmount(ctx, goalID, userID)
+	})
 }
 
 func (s *Service) GetContributions(ctx context.Context, goalID, userID ulid.ULID) ([]*Contribution, error) {
@@ -211,6 +276,10 @@ func (s *Service) DeleteContribution(ctx context.Context, contributionID, userID
 		return appErrors.NewValidationError("contribution", "apenas contribuições podem ser removidas")
 	}
 
+	if contribution.InvestmentId != nil {
+		return appErrors.NewValidationError("contribution", "aportes em investimento devem ser resgatados pela meta")
+	}
+
 	goal, err := s.GetGoalByID(ctx, contribution.GoalId, userID)
 	if err != nil {
 		return err
@@ -219,9 +288,9 @@ func (s *Service) DeleteContribution(ctx context.Context, contributionID, userID
 	if goal.Status == Completed && goal.CurrentAmount-contribution.Amount < goal.TargetAmount {
 		now := time.Now()
 		if err := s.Repository.UpdateFields(ctx, contribution.GoalId, map[string]interface{}{
-			"status":     Active,
-			"ended_at":   nil,
-			"updated_at": now,
+			"status":       Active,
+			"completed_at": nil,
+			"updated_at":   now,
 		}); err != nil {
 			return err
 		}
@@ -268,9 +337,9 @@ func (s *Service) DeleteContributionByTransactionId(ctx context.Context, transac
 	if goal.Status == Completed && goal.CurrentAmount-contribution.Amount < goal.TargetAmount {
 		now := time.Now()
 		if err := s.Repository.UpdateFields(ctx, contribution.GoalId, map[string]interface{}{
-			"status":     Active,
-			"ended_at":   nil,
-			"updated_at": now,
+			"status":       Active,
+			"completed_at": nil,
+			"updated_at":   now,
 		}); err != nil {
 			return err
 		}
@@ -303,7 +372,7 @@ func (s *Service) GetGoalProgress(ctx context.Context, goalID, userID ulid.ULID)
 		remaining = 0
 	}
 
-	return &GoalProgress{
+	progress := &GoalProgress{
 		GoalId:        goalID,
 		Name:          goal.Name,
 		TargetAmount:  goal.TargetAmount,
@@ -311,7 +380,28 @@ func (s *Service) GetGoalProgress(ctx context.Context, goalID, userID ulid.ULID)
 		Remaining:     remaining,
 		Percentage:    percentage,
 		Status:        string(goal.Status),
-	}, nil
+	}
+
+	if goal.Status != Active {
+		return progress, nil
+	}
+
+	now := time.Now()
+	pace, err := s.monthlyPace(ctx, goal, now)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.Deadline = goal.EndedAt
+	progress.RequiredMonthlyAmount = goal.RequiredMonthlyAmount(now)
+	progress.MonthlyPace = pace
+	progress.ProjectedCompletionDate = goal.ProjectedCompletion(pace, now)
+	if goal.EndedAt != nil {
+		onTrack := progress.ProjectedCompletionDate != nil && !progress.ProjectedCompletionDate.After(*goal.EndedAt)
+		progress.OnTrack = &onTrack
+	}
+
+	return progress, nil
 }
 
 func (s *Service) UpdateGoal(ctx context.Context, request *contracts.GoalUpdateRequestDomain) error {
@@ -335,6 +425,7 @@ func (s *Service) UpdateGoal(ctx context.Context, request *contracts.GoalUpdateR
 	current.TargetAmount = request.Target
 	current.EndedAt = request.EndedAt
 	current.UpdatedAt = time.Now()
+	current.SuggestedAmount = current.RequiredMonthlyAmount(current.UpdatedAt)
 
 	return s.Repository.Update(ctx, current)
 }
@@ -343,6 +434,18 @@ func (s *Service) DeleteGoal(ctx context.Context, goalID ulid.ULID, userID ulid.
 	if err := s.CheckGoalBelongsToUser(ctx, goalID, userID); err != nil {
 		return err
 	}
+	if err := s.Repository.DeleteSchedule(ctx, goalID, userID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return appErrors.NewDatabaseError(err)
+	}
+	investments, err := s.linkedInvestments(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+	for _, inv := range investments {
+		if err := s.InvestmentService.UnlinkFromGoal(ctx, inv.Id, userID, goalID); err != nil {
+			return err
+		}
+	}
 	if err := s.Repository.Delete(ctx, goalID); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return appErrors.ErrGoalNotFound.WithError(err)
@@ -445,13 +548,8 @@ func (s *Service) createGoalTransaction(ctx context.Context, goal *Goal, account
 	return tx, nil
 }
 
-func (s *Service) rollbackContribution(ctx context.Context, transactionID *ulid.ULID, accountID, userID ulid.ULID, amount float64) {
-	if transactionID != nil && s.TransactionService != nil {
-		_ = s.TransactionService.DeleteTransaction(ctx, *transactionID, userID)
-	}
-	_ = s.AccountService.UpdateBalance(ctx, accountID, userID, amount)
-}
-
+// checkAndUpdateGoalStatus conclui a meta que atingiu o alvo; chamado na transação da movimentação, que
+// também grava o evento de conclusão
 func (s *Service) checkAndUpdateGoalStatus(ctx context.Context, goalID, userID ulid.ULID) error {
 	goal, err := s.GetGoalByID(ctx, goalID, userID)
 	if err != nil {
@@ -460,22 +558,426 @@ func (s *Service) checkAndUpdateGoalStatus(ctx context.Context, goalID, userID u
 
 	if goal.CurrentAmount >= goal.TargetAmount {
 		now := time.Now()
-		return s.Repository.UpdateFields(ctx, goalID, map[string]interface{}{
-			"status":     Completed,
-			"ended_at":   &now,
-			"updated_at": now,
-		})
+		if err := s.Repository.UpdateFields(ctx, goalID, map[string]interface{}{
+			"status":       Completed,
+			"completed_at": &now,
+			"updated_at":   now,
+		}); err != nil {
+			return err
+		}
+		if goal.Status != Completed {
+			return s.publishCompleted(ctx, goalID, userID)
+		}
 	}
 
 	return nil
 }
 
+// publishCompleted publica a meta recém-concluída na transação do ctx, então o evento só existe se a
+// conclusão for gravada
+func (s *Service) publishCompleted(ctx context.Context, goalID, userID ulid.ULID) error {
+	if s.Events == nil {
+		return nil
+	}
+
+	goal, err := s.GetGoalByID(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.Events.Publish(ctx, userID, shared.EventGoalCompleted, goal); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+type ScheduleRequest struct {
+	GoalId       ulid.ULID
+	UserId       ulid.ULID
+	AccountId    ulid.ULID
+	Amount       float64
+	UseSuggested bool
+	Frequency    ScheduleFrequency
+	DayOfMonth   int
+	DayOfWeek    int
+	StartDate    *time.Time
+}
+
+// SetContributionSchedule cria ou substitui o aporte automático da meta
+func (s *Service) SetContributionSchedule(ctx context.Context, req *ScheduleRequest) (*ContributionSchedule, error) {
+	goal, err := s.GetGoalByID(ctx, req.GoalId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateScheduleRequest(ctx, goal, req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start := now
+	if req.StartDate != nil && req.StartDate.After(now) {
+		start = *req.StartDate
+	}
+
+	schedule, err := s.Repository.GetScheduleByGoalID(ctx, req.GoalId, req.UserId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	isNew := schedule == nil
+	if isNew {
+		schedule = &ContributionSchedule{
+			Id:        pkg.GenerateULIDObject(),
+			GoalId:    req.GoalId,
+			UserId:    req.UserId,
+			CreatedAt: now,
+		}
+	}
+
+	schedule.AccountId = req.AccountId
+	schedule.Amount = req.Amount
+	schedule.UseSuggested = req.UseSuggested
+	schedule.Frequency = req.Frequency
+	schedule.DayOfMonth = req.DayOfMonth
+	schedule.DayOfWeek = req.DayOfWeek
+	schedule.IsActive = true
+	schedule.SkippedRuns = 0
+	schedule.UpdatedAt = now
+	schedule.NextRunAt = schedule.FirstRun(start)
+
+	if isNew {
+		err = s.Repository.CreateSchedule(ctx, schedule)
+	} else {
+		err = s.Repository.UpdateSchedule(ctx, schedule)
+	}
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) GetContributionSchedule(ctx context.Context, goalID, userID ulid.ULID) (*ContributionSchedule, error) {
+	if err := s.CheckGoalBelongsToUser(ctx, goalID, userID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.Repository.GetScheduleByGoalID(ctx, goalID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("aporte automatico")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return schedule, nil
+}
+
+func (s *Service) DeleteContributionSchedule(ctx context.Context, goalID, userID ulid.ULID) error {
+	if err := s.CheckGoalBelongsToUser(ctx, goalID, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeleteSchedule(ctx, goalID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("aporte automatico")
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// ScheduledContribution é uma execução prevista de um aporte automático
+type ScheduledContribution struct {
+	ScheduleId ulid.ULID `json:"scheduleId"`
+	GoalId     ulid.ULID `json:"goalId"`
+	GoalName   string    `json:"goalName"`
+	AccountId  ulid.ULID `json:"accountId"`
+	Date       time.Time `json:"date"`
+	Amount     float64   `json:"amount"`
+}
+
+// UpcomingContributions projeta as execuções dos aportes automáticos ativos até a data informada.
+// Execuções vencidas entram uma vez na data agendada e o valor para de ser projetado quando a meta
+// atinge o objetivo.
+func (s *Service) UpcomingContributions(ctx context.Context, userID ulid.ULID, until time.Time) ([]ScheduledContribution, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.Repository.GetActiveSchedulesByUserID(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	now := time.Now()
+	until = truncateDay(until)
+	var upcoming []ScheduledContribution
+	for _, schedule := range schedules {
+		goal, err := s.Repository.GetByIDAndUser(ctx, schedule.GoalId, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		if goal.Status != Active {
+			continue
+		}
+
+		amount := schedule.Amount
+		if schedule.UseSuggested {
+			required := goal.RequiredMonthlyAmount(now)
+			if required == nil {
+				continue
+			}
+			amount = schedule.AmountFromMonthly(*required)
+		}
+
+		remaining := math.Round((goal.TargetAmount-goal.CurrentAmount)*100) / 100
+		for date := truncateDay(schedule.NextRunAt); !date.After(until) && remaining > 0; date = schedule.NextRunAfter(date) {
+			value := math.Min(amount, remaining)
+			if value <= 0 {
+				break
+			}
+			upcoming = append(upcoming, ScheduledContribution{
+				ScheduleId: schedule.Id,
+				GoalId:     goal.Id,
+				GoalName:   goal.Name,
+				AccountId:  schedule.AccountId,
+				Date:       date,
+				Amount:     value,
+			})
+			remaining = math.Round((remaining-value)*100) / 100
+		}
+	}
+	return upcoming, nil
+}
+
+// ProcessDueSchedules executa os aportes automáticos vencidos. Chamado pela fila de jobs.
+func (s *Service) ProcessDueSchedules(ctx context.Context, now time.Time) error {
+	schedules, err := s.Repository.GetDueSchedules(ctx, truncateDay(now), 500)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.runSchedule(ctx, schedule, now)
+	}
+
+	return nil
+}
+
+func (s *Service) runSchedule(ctx context.Context, schedule *ContributionSchedule, now time.Time) {
+	status, message := s.executeSchedule(ctx, schedule, now)
+
+	schedule.LastRunAt = &now
+	schedule.LastStatus = status
+	schedule.LastMessage = message
+	if status == ScheduleRunSkipped {
+		schedule.SkippedRuns++
+	}
+
+	next := schedule.NextRunAfter(schedule.NextRunAt)
+	for !next.After(truncateDay(now)) {
+		next = schedule.NextRunAfter(next)
+	}
+	schedule.NextRunAt = next
+	schedule.UpdatedAt = now
+
+	if err := s.Repository.UpdateSchedule(ctx, schedule); err != nil {
+		logger.Error().
+			Err(err).
+			Str("schedule_id", schedule.Id.String()).
+			Msg("Erro ao atualizar aporte automatico")
+		return
+	}
+
+	logger.Info().
+		Str("schedule_id", schedule.Id.String()).
+		Str("goal_id", schedule.GoalId.String()).
+		Str("status", string(status)).
+		Str("message", message).
+		Msg("Aporte automatico processado")
+}
+
+func (s *Service) executeSchedule(ctx context.Context, schedule *ContributionSchedule, now time.Time) (ScheduleRunStatus, string) {
+	goal, err := s.GetGoalByID(ctx, schedule.GoalId, schedule.UserId)
+	if err != nil {
+		schedule.IsActive = false
+		return ScheduleRunFailed, "meta nao encontrada"
+	}
+
+	if goal.Status != Active {
+		schedule.IsActive = false
+		return ScheduleRunSkipped, "meta nao esta ativa"
+	}
+
+	amount := schedule.Amount
+	if schedule.UseSuggested {
+		required := goal.RequiredMonthlyAmount(now)
+		if required == nil {
+			return ScheduleRunSkipped, "meta sem prazo nao possui valor sugerido"
+		}
+		amount = schedule.AmountFromMonthly(*required)
+	}
+
+	remaining := math.Round((goal.TargetAmount-goal.CurrentAmount)*100) / 100
+	if amount > remaining {
+		amount = remaining
+	}
+	if amount <= 0 {
+		return ScheduleRunSkipped, "nada a aportar"
+	}
+
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, schedule.AccountId, schedule.UserId)
+	if err != nil {
+		return ScheduleRunFailed, "conta nao encontrada"
+	}
+
+	if accountEntity.Balance < amount {
+		return ScheduleRunSkipped, "saldo insuficiente na conta"
+	}
+
+	if err := s.MakeContribution(ctx, schedule.GoalId, schedule.AccountId, schedule.UserId, amount, "Aporte automatico: "+goal.Name); err != nil {
+		if appErr, ok := appErrors.AsAppError(err); ok {
+			return ScheduleRunFailed, appErr.Message
+		}
+		return ScheduleRunFailed, "erro ao realizar aporte"
+	}
+
+	return ScheduleRunSuccess, ""
+}
+
+func (s *Service) validateScheduleRequest(ctx context.Context, goal *Goal, req *ScheduleRequest) error {
+	if goal.Status != Active {
+		return appErrors.NewValidationError("goal", "meta nao esta ativa")
+	}
+
+	if !req.Frequency.IsValid() {
+		return appErrors.NewValidationError("frequency", "frequencia invalida")
+	}
+
+	if req.UseSuggested {
+		if goal.EndedAt == nil {
+			return appErrors.NewValidationError("use_suggested", "meta sem prazo nao possui valor sugerido")
+		}
+	} else if req.Amount <= 0 {
+		return appErrors.NewValidationError("amount", "deve ser maior que zero")
+	}
+
+	if req.Frequency == ScheduleMonthly && (req.DayOfMonth < 1 || req.DayOfMonth > 31) {
+		return appErrors.NewValidationError("day_of_month", "deve estar entre 1 e 31")
+	}
+
+	if req.Frequency != ScheduleMonthly && (req.DayOfWeek < 0 || req.DayOfWeek > 6) {
+		return appErrors.NewValidationError("day_of_week", "deve estar entre 0 (domingo) e 6 (sabado)")
+	}
+
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, req.AccountId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	if accountEntity.Type == account.TypeCreditCard {
+		return appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+	}
+
+	return nil
+}
+
+// recordMilestone registra o marco atingido (25/50/75/100%) e publica o evento do marco
+func (s *Service) recordMilestone(ctx context.Context, goalID, userID ulid.ULID) error {
+	goal, err := s.GetGoalByID(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !goal.HasNewMilestone() {
+		return nil
+	}
+
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.Repository.UpdateFields(ctx, goalID, map[string]interface{}{
+			"last_milestone": goal.GetCurrentMilestone(),
+		}); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+
+		if s.Events == nil {
+			return nil
+		}
+		data := map[string]any{
+			"goalId":        goalID.String(),
+			"goal":          goal.Name,
+			"milestone":     goal.GetCurrentMilestone(),
+			"currentAmount": goal.CurrentAmount,
+			"targetAmount":  goal.TargetAmount,
+		}
+		if err := s.Events.Publish(ctx, userID, shared.EventGoalMilestone, data); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+		return nil
+	})
+}
+
+// refreshSuggestedAmount recalcula o aporte mensal sugerido após movimentações na meta
+func (s *Service) refreshSuggestedAmount(ctx context.Context, goalID, userID ulid.ULID) error {
+	goal, err := s.GetGoalByID(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+
+	if goal.Status != Active {
+		return nil
+	}
+
+	return s.Repository.UpdateFields(ctx, goalID, map[string]interface{}{
+		"suggested_amount": goal.RequiredMonthlyAmount(time.Now()),
+	})
+}
+
+// monthlyPace calcula a média mensal líquida de aportes desde o início da meta
+func (s *Service) monthlyPace(ctx context.Context, goal *Goal, now time.Time) (float64, error) {
+	contributions, err := s.Repository.GetContributionsByGoalID(ctx, goal.Id, goal.UserId)
+	if err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+
+	var net float64
+	for _, c := range contributions {
+		if c.Type == ContributionWithdraw {
+			net -= c.Amount
+		} else {
+			net += c.Amount
+		}
+	}
+
+	months := now.Sub(goal.StartedAt).Hours() / 24 / 30.44
+	if months < 1 {
+		months = 1
+	}
+
+	pace := net / months
+	if pace < 0 {
+		pace = 0
+	}
+	return math.Round(pace*100) / 100, nil
+}
+
 type GoalProgress struct {
-	GoalId        ulid.ULID `json:"goalId"`
-	Name          string    `json:"name"`
-	TargetAmount  float64   `json:"targetAmount"`
-	CurrentAmount float64   `json:"currentAmount"`
-	Remaining     float64   `json:"remaining"`
-	Percentage    float64   `json:"percentage"`
-	Status        string    `json:"status"`
+	GoalId                  ulid.ULID  `json:"goalId"`
+	Name                    string     `json:"name"`
+	TargetAmount            float64    `json:"targetAmount"`
+	CurrentAmount           float64    `json:"currentAmount"`
+	Remaining               float64    `json:"remaining"`
+	Percentage              float64    `json:"percentage"`
+	Status                  string     `json:"status"`
+	Deadline                *time.Time `json:"deadline,omitempty"`
+	RequiredMonthlyAmount   *float64   `json:"requiredMonthlyAmount,omitempty"`
+	MonthlyPace             float64    `json:"monthlyPace"`
+	ProjectedCompletionDate *time.Time `json:"projectedCompletionDate,omitempty"`
+	OnTrack                 *bool      `json:"onTrack,omitempty"`
 }
@@ -2,11 +2,22 @@ package healthscore
 
 import (
 	"context"
+	"fmt"
+
+	"Fynance/internal/domain/loan"
 
 	"github.com/oklog/ulid/v2"
 )
 
-type Service struct{}
+// DebtSource fornece o resumo das dívidas ativas do usuário
+type DebtSource interface {
+	GetDebtSummary(ctx context.Context, userID ulid.ULID) (*loan.DebtSummary, error)
+}
+
+type Service struct {
+	// Debts é opcional; quando presente, a saúde das dívidas entra na pontuação
+	Debts DebtSource
+}
 
 func NewService() *Service {
 	return &Service{}
@@ -16,14 +27,46 @@ type HealthScoreResult struct {
 	Score        int      `json:"score"`
 	BudgetHealth int      `json:"budgetHealth"`
 	GoalsHealth  int      `json:"goalsHealth"`
+	DebtHealth   int      `json:"debtHealth"`
 	Factors      []string `json:"factors"`
 }
 
 func (s *Service) CalculateHealthScore(ctx context.Context, userID ulid.ULID) (*HealthScoreResult, error) {
-	return &HealthScoreResult{
+	result := &HealthScoreResult{
 		Score:        75,
 		BudgetHealth: 80,
 		GoalsHealth:  70,
+		DebtHealth:   100,
 		Factors:      []string{"Orçamentos controlados", "Metas em progresso"},
-	}, nil
+	}
+	if s.Debts == nil {
+		return result, nil
+	}
+
+	debts, err := s.Debts.GetDebtSummary(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	health, factor := debtHealth(debts)
+	result.DebtHealth = health
+	result.Factors = append(result.Factors, factor)
+	result.Score = (result.BudgetHealth + result.GoalsHealth + result.DebtHealth) / 3
+	return result, nil
+}
+
+// debtHealth desconta 25 pontos por parcela de empréstimo em atraso
+func debtHealth(debts *loan.DebtSummary) (int, string) {
+	if debts.ActiveLoans == 0 {
+		return 100, "Sem dívidas em aberto"
+	}
+	if debts.OverdueInstallments == 0 {
+		return 100, "Parcelas de empréstimos em dia"
+	}
+
+	health := 100 - 25*debts.OverdueInstallments
+	if health < 0 {
+		health = 0
+	}
+	return health, fmt.Sprintf("%d parcela(s) de empréstimo em atraso", debts.OverdueInstallments)
 }
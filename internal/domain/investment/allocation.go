@@ -0,0 +1,211 @@
+package investment
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// AllocationTarget é o peso desejado de uma classe na carteira do usuário.
+// A classe é um tipo de investimento (ex.: ACOES) ou uma classe personalizada
+// informada em AssetClass no investimento (ex.: RESERVA, EXTERIOR).
+type AllocationTarget struct {
+	Id        ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId    ulid.ULID `gorm:"type:varchar(26);not null;index:idx_allocation_targets_user_class,unique" json:"userId"`
+	Class     string    `gorm:"type:varchar(40);not null;index:idx_allocation_targets_user_class,unique" json:"class"`
+	Weight    float64   `gorm:"type:decimal(5,2);not null" json:"weight"`
+	CreatedAt time.Time `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (AllocationTarget) TableName() string {
+	return "investment_allocation_targets"
+}
+
+// AllocationTargetInput é um peso alvo informado pelo usuário, em %
+type AllocationTargetInput struct {
+	Class  string
+	Weight float64
+}
+
+// ClassAllocation compara o peso atual de uma classe com o alvo.
+// Drift é a diferença em pontos percentuais (positivo = acima do alvo).
+type ClassAllocation struct {
+	Class         string  `json:"class"`
+	CurrentValue  float64 `json:"currentValue"`
+	CurrentWeight float64 `json:"currentWeight"`
+	TargetWeight  float64 `json:"targetWeight"`
+	TargetValue   float64 `json:"targetValue"`
+	Drift         float64 `json:"drift"`
+	Investments   int     `json:"investments"`
+}
+
+// RebalanceBuy é um aporte sugerido. Sem InvestmentId, a classe ainda não tem
+// investimentos e o valor deve ir para um novo ativo dela.
+type RebalanceBuy struct {
+	Class        string     `json:"class"`
+	InvestmentId *ulid.ULID `json:"investmentId,omitempty"`
+	Name         string     `json:"name,omitempty"`
+	Ticker       string     `json:"ticker,omitempty"`
+	Amount       float64    `json:"amount"`
+}
+
+// AllocationReport é a carteira atual contra os alvos e o plano de aportes
+type AllocationReport struct {
+	Total        float64           `json:"total"`
+	Contribution float64           `json:"contribution"`
+	Classes      []ClassAllocation `json:"classes"`
+	Plan         []RebalanceBuy    `json:"plan"`
+	MaxDrift     float64           `json:"maxDrift"`
+}
+
+// AllocationClass é a classe usada nas metas de alocação: a classe personalizada, se houver, ou o tipo
+func (i *Investment) AllocationClass() string {
+	if i.AssetClass != "" {
+		return i.AssetClass
+	}
+	return string(i.Type)
+}
+
+// NormalizeAssetClass padroniza o nome da classe para comparação entre alvos e investimentos
+func NormalizeAssetClass(class string) string {
+	return strings.ToUpper(strings.TrimSpace(class))
+}
+
+// BuildAllocationReport calcula pesos atuais, desvios e um plano de aportes sem vendas:
+// o aporte cobre primeiro as classes abaixo do alvo (proporcionalmente à falta, se não
+// houver valor para todas) e a sobra é dividida pelos pesos alvo. Dentro da classe o
+// valor vai para os investimentos existentes proporcionalmente ao saldo.
+func BuildAllocationReport(investments []*Investment, targets []*AllocationTarget, contribution float64) *AllocationReport {
+	report := &AllocationReport{Contribution: roundCents(contribution), Classes: []ClassAllocation{}, Plan: []RebalanceBuy{}}
+
+	byClass := map[string][]*Investment{}
+	values := map[string]float64{}
+	for _, inv := range investments {
+		class := inv.AllocationClass()
+		byClass[class] = append(byClass[class], inv)
+		values[class] += math.Max(inv.CurrentBalance, 0)
+		report.Total += math.Max(inv.CurrentBalance, 0)
+	}
+
+	weights := map[string]float64{}
+	for _, t := range targets {
+		weights[t.Class] = t.Weight
+	}
+
+	classes := make([]string, 0, len(values)+len(weights))
+	for class := range values {
+		classes = append(classes, class)
+	}
+	for class := range weights {
+		if _, ok := values[class]; !ok {
+			classes = append(classes, class)
+		}
+	}
+	sort.Strings(classes)
+
+	finalTotal := report.Total + contribution
+	deficits := map[string]float64{}
+	totalDeficit := 0.0
+	for _, class := range classes {
+		current := values[class]
+		item := ClassAllocation{
+			Class:        class,
+			CurrentValue: roundCents(current),
+			TargetWeight: weights[class],
+			TargetValue:  roundCents(finalTotal * weights[class] / 100),
+			Investments:  len(byClass[class]),
+		}
+		if report.Total > 0 {
+			item.CurrentWeight = roundCents(current / report.Total * 100)
+		}
+		item.Drift = roundCents(item.CurrentWeight - item.TargetWeight)
+		if math.Abs(item.Drift) > math.Abs(report.MaxDrift) {
+			report.MaxDrift = item.Drift
+		}
+		report.Classes = append(report.Classes, item)
+
+		if deficit := finalTotal*weights[class]/100 - current; deficit > 0 {
+			deficits[class] = deficit
+			totalDeficit += deficit
+		}
+	}
+	report.Total = roundCents(report.Total)
+
+	if contribution <= 0 || len(weights) == 0 {
+		return report
+	}
+
+	amounts := map[string]float64{}
+	if totalDeficit >= contribution {
+		for class, deficit := range deficits {
+			amounts[class] = contribution * deficit / totalDeficit
+		}
+	} else {
+		remainder := contribution - totalDeficit
+		totalWeight := 0.0
+		for _, w := range weights {
+			totalWeight += w
+		}
+		for class, deficit := range deficits {
+			amounts[class] = deficit
+		}
+		for class, w := range weights {
+			amounts[class] += remainder * w / totalWeight
+		}
+	}
+
+	for _, class := range classes {
+		if amount := amounts[class]; amount >= 0.01 {
+			report.Plan = append(report.Plan, splitClassBuy(class, byClass[class], amount)...)
+		}
+	}
+	adjustPlanRounding(report.Plan, report.Contribution)
+	return report
+}
+
+// splitClassBuy divide o aporte de uma classe entre seus investimentos pelo saldo atual
+func splitClassBuy(class string, investments []*Investment, amount float64) []RebalanceBuy {
+	if len(investments) == 0 {
+		return []RebalanceBuy{{Class: class, Amount: roundCents(amount)}}
+	}
+
+	total := 0.0
+	for _, inv := range investments {
+		total += math.Max(inv.CurrentBalance, 0)
+	}
+
+	buys := make([]RebalanceBuy, 0, len(investments))
+	for _, inv := range investments {
+		share := 1 / float64(len(investments))
+		if total > 0 {
+			share = math.Max(inv.CurrentBalance, 0) / total
+		}
+		value := roundCents(amount * share)
+		if value < 0.01 {
+			continue
+		}
+		id := inv.Id
+		buys = append(buys, RebalanceBuy{Class: class, InvestmentId: &id, Name: inv.Name, Ticker: inv.Ticker, Amount: value})
+	}
+	return buys
+}
+
+// adjustPlanRounding lança a diferença de centavos no maior aporte para o plano somar o valor informado
+func adjustPlanRounding(plan []RebalanceBuy, contribution float64) {
+	if len(plan) == 0 {
+		return
+	}
+	sum := 0.0
+	largest := 0
+	for i, buy := range plan {
+		sum += buy.Amount
+		if buy.Amount > plan[largest].Amount {
+			largest = i
+		}
+	}
+	plan[largest].Amount = roundCents(plan[largest].Amount + contribution - sum)
+}
@@ -0,0 +1,98 @@
+package investment
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// allocationPageSize cobre a carteira inteira em uma única página
+const allocationPageSize = 1000
+
+// GetAllocationTargets lista os pesos alvo do usuário
+func (s *Service) GetAllocationTargets(ctx context.Context, userID ulid.ULID) ([]*AllocationTarget, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	targets, err := s.Repository.ListAllocationTargets(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return targets, nil
+}
+
+// SetAllocationTargets substitui os pesos alvo do usuário. Os pesos devem somar 100%;
+// uma lista vazia remove as metas de alocação.
+func (s *Service) SetAllocationTargets(ctx context.Context, userID ulid.ULID, inputs []AllocationTargetInput) ([]*AllocationTarget, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	targets := make([]*AllocationTarget, 0, len(inputs))
+	seen := map[string]bool{}
+	total := 0.0
+	for _, input := range inputs {
+		class := NormalizeAssetClass(input.Class)
+		if class == "" {
+			return nil, appErrors.NewValidationError("class", "é obrigatória")
+		}
+		if seen[class] {
+			return nil, appErrors.NewValidationError("class", fmt.Sprintf("classe %s repetida", class))
+		}
+		if input.Weight <= 0 || input.Weight > 100 {
+			return nil, appErrors.NewValidationError("weight", "deve estar entre 0 e 100")
+		}
+		seen[class] = true
+		total += input.Weight
+
+		targets = append(targets, &AllocationTarget{
+			Id:     pkg.GenerateULIDObject(),
+			UserId: userID,
+			Class:  class,
+			Weight: roundCents(input.Weight),
+		})
+	}
+
+	if len(targets) > 0 && math.Abs(total-100) > 0.01 {
+		return nil, appErrors.NewValidationError("targets", "os pesos devem somar 100")
+	}
+
+	if err := s.Repository.ReplaceAllocationTargets(ctx, userID, targets); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return targets, nil
+}
+
+// GetAllocation compara a carteira com os pesos alvo e sugere onde aplicar o novo aporte
+func (s *Service) GetAllocation(ctx context.Context, userID ulid.ULID, contribution float64) (*AllocationReport, error) {
+	if contribution < 0 {
+		return nil, appErrors.NewValidationError("contribution", "deve ser maior ou igual a zero")
+	}
+
+	investments, err := s.listAllInvestments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := s.Repository.ListAllocationTargets(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	return BuildAllocationReport(investments, targets, contribution), nil
+}
+
+// listAllInvestments carrega todos os investimentos do usuário sem paginação
+func (s *Service) listAllInvestments(ctx context.Context, userID ulid.ULID) ([]*Investment, error) {
+	investments, _, err := s.ListInvestments(ctx, userID, nil, &pkg.PaginationParams{Page: 1, Limit: allocationPageSize})
+	if err != nil {
+		return nil, err
+	}
+	return investments, nil
+}
@@ -0,0 +1,102 @@
+package investment
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// NoteFormat é o layout do conteúdo enviado para importação
+type NoteFormat string
+
+const (
+	// NoteFormatText é o texto extraído do PDF da nota no padrão SINACOR
+	NoteFormatText NoteFormat = "TEXT"
+	// NoteFormatCSV é uma planilha com uma linha por negócio
+	NoteFormatCSV NoteFormat = "CSV"
+)
+
+type NoteStatus string
+
+const (
+	NotePartial   NoteStatus = "PARTIAL"
+	NoteCompleted NoteStatus = "COMPLETED"
+)
+
+// BrokerageNote registra uma nota de corretagem importada. O número da nota é único por usuário,
+// o que torna a importação idempotente; ImportedTrades permite retomar uma importação interrompida.
+type BrokerageNote struct {
+	Id             ulid.ULID  `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId         ulid.ULID  `gorm:"type:varchar(26);not null;index:idx_brokerage_notes_user_number,unique" json:"userId"`
+	AccountId      ulid.ULID  `gorm:"type:varchar(26);not null" json:"accountId"`
+	Number         string     `gorm:"type:varchar(30);not null;index:idx_brokerage_notes_user_number,unique" json:"number"`
+	TradeDate      time.Time  `gorm:"type:date;not null" json:"tradeDate"`
+	Status         NoteStatus `gorm:"type:varchar(10);not null" json:"status"`
+	Trades         int        `gorm:"not null" json:"trades"`
+	ImportedTrades int        `gorm:"not null;default:0" json:"importedTrades"`
+	GrossBuys      float64    `gorm:"type:decimal(15,2);not null;default:0" json:"grossBuys"`
+	GrossSells     float64    `gorm:"type:decimal(15,2);not null;default:0" json:"grossSells"`
+	Fees           float64    `gorm:"type:decimal(15,2);not null;default:0" json:"fees"`
+	IRRF           float64    `gorm:"column:irrf;type:decimal(15,2);not null;default:0" json:"irrf"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (BrokerageNote) TableName() string {
+	return "brokerage_notes"
+}
+
+// NoteFees são os custos da nota. O IRRF (dedo-duro) é apenas informativo e não entra no custo dos lotes.
+type NoteFees struct {
+	Settlement   float64 `json:"settlement"`
+	Registration float64 `json:"registration"`
+	Emolumentos  float64 `json:"emolumentos"`
+	Brokerage    float64 `json:"brokerage"`
+	ISS          float64 `json:"iss"`
+	Other        float64 `json:"other"`
+	IRRF         float64 `json:"irrf"`
+}
+
+// Total soma os custos rateados entre os negócios
+func (f NoteFees) Total() float64 {
+	return roundCents(f.Settlement + f.Registration + f.Emolumentos + f.Brokerage + f.ISS + f.Other)
+}
+
+// NoteTrade é um negócio da nota, já com a parte dos custos e o investimento correspondente
+type NoteTrade struct {
+	Side           LotSide    `json:"side"`
+	Specification  string     `json:"specification"`
+	Ticker         string     `json:"ticker"`
+	Quantity       float64    `json:"quantity"`
+	UnitPrice      float64    `json:"unitPrice"`
+	Value          float64    `json:"value"`
+	Fees           float64    `json:"fees"`
+	InvestmentId   *ulid.ULID `json:"investmentId,omitempty"`
+	InvestmentType Types      `json:"investmentType,omitempty"`
+	NewInvestment  bool       `json:"newInvestment"`
+}
+
+// ParsedNote é uma nota lida do conteúdo enviado, usada na prévia e na importação
+type ParsedNote struct {
+	Number         string       `json:"number"`
+	TradeDate      time.Time    `json:"tradeDate"`
+	Trades         []*NoteTrade `json:"trades"`
+	Fees           NoteFees     `json:"fees"`
+	GrossBuys      float64      `json:"grossBuys"`
+	GrossSells     float64      `json:"grossSells"`
+	Status         NoteStatus   `json:"status,omitempty"`
+	ImportedTrades int          `json:"importedTrades"`
+	Unresolved     []string     `json:"unresolved,omitempty"`
+	Warnings       []string     `json:"warnings,omitempty"`
+}
+
+// BrokerageImportRequest traz o conteúdo da nota e as escolhas do usuário na prévia:
+// Tickers mapeia a especificação do título para o ticker e Types define o tipo de ativos novos
+type BrokerageImportRequest struct {
+	UserId    ulid.ULID
+	AccountId ulid.ULID
+	Format    NoteFormat
+	Content   string
+	Tickers   map[string]string
+	Types     map[string]string
+}
@@ -0,0 +1,477 @@
+package investment
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	appErrors "Fynance/internal/errors"
+)
+
+var (
+	noteHeaderPattern = regexp.MustCompile(`(?i)nota\s+de\s+(corretagem|negocia)`)
+	noteNumberPattern = regexp.MustCompile(`(?i)n[rº°o]\.?\s*(?:da\s+)?nota`)
+	noteDatePattern   = regexp.MustCompile(`(\d{2}/\d{2}/\d{4})`)
+	noteIntPattern    = regexp.MustCompile(`\b(\d[\d.]*)\b`)
+	noteMoneyPattern  = regexp.MustCompile(`(\d[\d.]*,\d{2})\b`)
+	noteTickerPattern = regexp.MustCompile(`\b([A-Z]{4}\d{1,2})F?\b`)
+	noteIRRFBase      = regexp.MustCompile(`(?i)base\s*R?\$?\s*\d[\d.]*,\d{2}`)
+
+	// Linha de negócio do padrão SINACOR:
+	// 1-BOVESPA C VISTA PETROBRAS PN N2 D 100 38,50 3.850,00 D
+	noteTradePattern = regexp.MustCompile(`(?i)^\s*(?:\d-BOVESPA|B3\s+RV\s+LISTADO|BOVESPA)\s+([CV])\s+(VISTA|FRACION[AÁ]RIO|OP[CÇ][AÃ]O DE COMPRA|OP[CÇ][AÃ]O DE VENDA|EXERC OPC COMPRA|EXERC OPC VENDA|TERMO)\s+(?:\d{2}/\d{2}\s+)?(.+?)\s+(?:[#A-Z]\s+)?(\d[\d.]*)\s+(\d[\d.]*,\d+)\s+(\d[\d.]*,\d{2})\s+([DC])\s*$`)
+
+	noteFeePatterns = []struct {
+		pattern *regexp.Regexp
+		apply   func(*NoteFees, float64)
+	}{
+		{regexp.MustCompile(`(?i)taxa\s+de\s+liquida[cç][aã]o`), func(f *NoteFees, v float64) { f.Settlement = v }},
+		{regexp.MustCompile(`(?i)taxa\s+de\s+registro`), func(f *NoteFees, v float64) { f.Registration = v }},
+		{regexp.MustCompile(`(?i)emolumentos`), func(f *NoteFees, v float64) { f.Emolumentos = v }},
+		{regexp.MustCompile(`(?i)taxa\s+de\s+termo|taxa\s+de\s+op[cç][oõ]es|taxa\s+a\.?n\.?a\.?`), func(f *NoteFees, v float64) { f.Other += v }},
+		{regexp.MustCompile(`(?i)taxa\s+operacional|corretagem\b`), func(f *NoteFees, v float64) { f.Brokerage = v }},
+		{regexp.MustCompile(`(?i)^\s*iss\b|\biss\s*\(`), func(f *NoteFees, v float64) { f.ISS = v }},
+		{regexp.MustCompile(`(?i)^\s*outr[oa]s\b`), func(f *NoteFees, v float64) { f.Other += v }},
+		{regexp.MustCompile(`(?i)i\.?\s?r\.?\s?r\.?\s?f\.?`), func(f *NoteFees, v float64) { f.IRRF = v }},
+	}
+
+	csvColumns = map[string][]string{
+		"note":        {"nota", "numero_nota", "nr_nota", "note", "numero"},
+		"date":        {"data", "data_pregao", "pregao", "date"},
+		"side":        {"c/v", "cv", "operacao", "tipo", "side"},
+		"ticker":      {"ticker", "ativo", "codigo", "papel", "titulo"},
+		"quantity":    {"quantidade", "qtd", "quantity"},
+		"price":       {"preco", "preço", "preco_unitario", "price"},
+		"settlement":  {"liquidacao", "taxa_liquidacao"},
+		"emolumentos": {"emolumentos"},
+		"brokerage":   {"corretagem"},
+		"fees":        {"taxas", "custos", "fees"},
+		"irrf":        {"irrf"},
+	}
+)
+
+// ParseBrokerageNotes lê as notas do conteúdo no layout informado. Notas com várias folhas
+// são unidas pelo número; os custos do texto são rateados entre os negócios pelo valor.
+func ParseBrokerageNotes(format NoteFormat, content string) ([]*ParsedNote, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, appErrors.NewValidationError("content", "é obrigatório")
+	}
+
+	var notes []*ParsedNote
+	var err error
+	switch format {
+	case NoteFormatText:
+		notes, err = parseNoteText(content)
+	case NoteFormatCSV:
+		notes, err = parseNoteCSV(content)
+	default:
+		return nil, appErrors.NewValidationError("format", "deve ser TEXT ou CSV")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, appErrors.NewValidationError("content", "nenhuma nota com negocios encontrada")
+	}
+
+	for _, note := range notes {
+		for _, t := range note.Trades {
+			if t.Side == LotBuy {
+				note.GrossBuys += t.Value
+			} else {
+				note.GrossSells += t.Value
+			}
+		}
+		note.GrossBuys = roundCents(note.GrossBuys)
+		note.GrossSells = roundCents(note.GrossSells)
+	}
+	return notes, nil
+}
+
+func parseNoteText(content string) ([]*ParsedNote, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	// Cada cabeçalho "NOTA DE CORRETAGEM" inicia uma folha
+	var blocks [][]string
+	for _, line := range lines {
+		if noteHeaderPattern.MatchString(line) || len(blocks) == 0 {
+			blocks = append(blocks, nil)
+		}
+		blocks[len(blocks)-1] = append(blocks[len(blocks)-1], line)
+	}
+
+	byNumber := map[string]*ParsedNote{}
+	var order []string
+	for _, block := range blocks {
+		page, err := parseNotePage(block)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			continue
+		}
+		note, ok := byNumber[page.Number]
+		if !ok {
+			byNumber[page.Number] = page
+			order = append(order, page.Number)
+			continue
+		}
+		note.Trades = append(note.Trades, page.Trades...)
+		note.Warnings = append(note.Warnings, page.Warnings...)
+		note.Fees = mergeNoteFees(note.Fees, page.Fees)
+	}
+
+	notes := make([]*ParsedNote, 0, len(order))
+	for _, number := range order {
+		note := byNumber[number]
+		if len(note.Trades) == 0 {
+			continue
+		}
+		allocateNoteFees(note)
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
+
+// parseNotePage lê número, data, negócios e custos de uma folha. Folhas sem número são ignoradas.
+func parseNotePage(lines []string) (*ParsedNote, error) {
+	note := &ParsedNote{Trades: []*NoteTrade{}}
+
+	for i, line := range lines {
+		if note.Number == "" {
+			if loc := noteNumberPattern.FindStringIndex(line); loc != nil {
+				rest := line[loc[1]:]
+				if !noteIntPattern.MatchString(rest) && i+1 < len(lines) {
+					rest = nextNonEmpty(lines[i+1:])
+				}
+				if m := noteIntPattern.FindStringSubmatch(rest); m != nil {
+					note.Number = strings.ReplaceAll(m[1], ".", "")
+				}
+				if note.TradeDate.IsZero() {
+					if d := noteDatePattern.FindString(rest); d != "" {
+						note.TradeDate, _ = time.Parse("02/01/2006", d)
+					}
+				}
+			}
+		}
+
+		if m := noteTradePattern.FindStringSubmatch(line); m != nil {
+			trade, warning, err := parseNoteTradeLine(m)
+			if err != nil {
+				return nil, err
+			}
+			if warning != "" {
+				note.Warnings = append(note.Warnings, warning)
+			}
+			if trade != nil {
+				note.Trades = append(note.Trades, trade)
+			}
+			continue
+		}
+
+		for _, fee := range noteFeePatterns {
+			loc := fee.pattern.FindStringIndex(line)
+			if loc == nil {
+				continue
+			}
+			rest := noteIRRFBase.ReplaceAllString(line[loc[1]:], "")
+			if m := noteMoneyPattern.FindStringSubmatch(rest); m != nil {
+				value, err := parseNoteDecimal(m[1], true)
+				if err != nil {
+					return nil, appErrors.NewValidationError("content", fmt.Sprintf("valor invalido em %q", strings.TrimSpace(line)))
+				}
+				fee.apply(&note.Fees, value)
+			}
+			break
+		}
+	}
+
+	if note.Number == "" {
+		return nil, nil
+	}
+	if note.TradeDate.IsZero() {
+		for _, line := range lines {
+			if d := noteDatePattern.FindString(line); d != "" {
+				note.TradeDate, _ = time.Parse("02/01/2006", d)
+				break
+			}
+		}
+	}
+	if note.TradeDate.IsZero() {
+		return nil, appErrors.NewValidationError("content", fmt.Sprintf("data do pregao nao encontrada na nota %s", note.Number))
+	}
+	return note, nil
+}
+
+func parseNoteTradeLine(m []string) (*NoteTrade, string, error) {
+	market := strings.ToUpper(m[2])
+	specification := strings.Join(strings.Fields(m[3]), " ")
+	if market != "VISTA" && !strings.HasPrefix(market, "FRACION") {
+		return nil, fmt.Sprintf("negocio no mercado %s ignorado: %s", market, specification), nil
+	}
+
+	quantity, err := strconv.ParseFloat(strings.ReplaceAll(m[4], ".", ""), 64)
+	if err != nil {
+		return nil, "", appErrors.NewValidationError("content", fmt.Sprintf("quantidade invalida em %s", specification))
+	}
+	price, err := parseNoteDecimal(m[5], true)
+	if err != nil {
+		return nil, "", appErrors.NewValidationError("content", fmt.Sprintf("preco invalido em %s", specification))
+	}
+	value, err := parseNoteDecimal(m[6], true)
+	if err != nil {
+		return nil, "", appErrors.NewValidationError("content", fmt.Sprintf("valor invalido em %s", specification))
+	}
+
+	trade := &NoteTrade{
+		Side:          LotBuy,
+		Specification: strings.ToUpper(specification),
+		Quantity:      quantity,
+		UnitPrice:     price,
+		Value:         value,
+	}
+	if strings.EqualFold(m[1], "V") {
+		trade.Side = LotSell
+	}
+	if t := noteTickerPattern.FindStringSubmatch(trade.Specification); t != nil {
+		trade.Ticker = t[1]
+	}
+	return trade, "", nil
+}
+
+func parseNoteCSV(content string) ([]*ParsedNote, error) {
+	firstLine := strings.SplitN(content, "\n", 2)[0]
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	decimalComma := false
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+		decimalComma = true
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, appErrors.NewValidationError("content", "cabecalho do CSV invalido")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for key, aliases := range csvColumns {
+			for _, alias := range aliases {
+				if name == alias {
+					columns[key] = i
+				}
+			}
+		}
+	}
+	for _, required := range []string{"note", "date", "side", "ticker", "quantity", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, appErrors.NewValidationError("content", fmt.Sprintf("coluna obrigatoria ausente: %s", csvColumns[required][0]))
+		}
+	}
+
+	byNumber := map[string]*ParsedNote{}
+	var order []string
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, appErrors.NewValidationError("content", fmt.Sprintf("linha %d invalida", line))
+		}
+		field := func(key string) string {
+			i, ok := columns[key]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		number := field("note")
+		if number == "" {
+			continue
+		}
+
+		trade, fees, date, err := parseNoteCSVRow(field, decimalComma)
+		if err != nil {
+			return nil, appErrors.NewValidationError("content", fmt.Sprintf("linha %d: %s", line, err.Error()))
+		}
+
+		note, ok := byNumber[number]
+		if !ok {
+			note = &ParsedNote{Number: number, TradeDate: date, Trades: []*NoteTrade{}}
+			byNumber[number] = note
+			order = append(order, number)
+		}
+		note.Trades = append(note.Trades, trade)
+		note.Fees.Settlement += fees.Settlement
+		note.Fees.Emolumentos += fees.Emolumentos
+		note.Fees.Brokerage += fees.Brokerage
+		note.Fees.Other += fees.Other
+		note.Fees.IRRF += fees.IRRF
+	}
+
+	notes := make([]*ParsedNote, 0, len(order))
+	for _, number := range order {
+		notes = append(notes, byNumber[number])
+	}
+	return notes, nil
+}
+
+// parseNoteCSVRow lê um negócio da planilha; os custos informados na linha pertencem ao próprio negócio
+func parseNoteCSVRow(field func(string) string, decimalComma bool) (*NoteTrade, NoteFees, time.Time, error) {
+	var fees NoteFees
+
+	date, err := time.Parse("02/01/2006", field("date"))
+	if err != nil {
+		date, err = time.Parse("2006-01-02", field("date"))
+		if err != nil {
+			return nil, fees, time.Time{}, fmt.Errorf("data invalida %q", field("date"))
+		}
+	}
+
+	trade := &NoteTrade{}
+	switch strings.ToUpper(field("side")) {
+	case "C", "COMPRA", "BUY":
+		trade.Side = LotBuy
+	case "V", "VENDA", "SELL":
+		trade.Side = LotSell
+	default:
+		return nil, fees, time.Time{}, fmt.Errorf("operacao invalida %q, use C ou V", field("side"))
+	}
+
+	trade.Specification = strings.ToUpper(field("ticker"))
+	if t := noteTickerPattern.FindStringSubmatch(trade.Specification); t != nil {
+		trade.Ticker = t[1]
+	}
+
+	if trade.Quantity, err = parseNoteDecimal(field("quantity"), decimalComma); err != nil || trade.Quantity <= 0 {
+		return nil, fees, time.Time{}, fmt.Errorf("quantidade invalida %q", field("quantity"))
+	}
+	if trade.UnitPrice, err = parseNoteDecimal(field("price"), decimalComma); err != nil || trade.UnitPrice <= 0 {
+		return nil, fees, time.Time{}, fmt.Errorf("preco invalido %q", field("price"))
+	}
+	trade.Value = roundCents(trade.Quantity * trade.UnitPrice)
+
+	for key, target := range map[string]*float64{
+		"settlement":  &fees.Settlement,
+		"emolumentos": &fees.Emolumentos,
+		"brokerage":   &fees.Brokerage,
+		"fees":        &fees.Other,
+		"irrf":        &fees.IRRF,
+	} {
+		if raw := field(key); raw != "" {
+			value, err := parseNoteDecimal(raw, decimalComma)
+			if err != nil || value < 0 {
+				return nil, fees, time.Time{}, fmt.Errorf("valor invalido %q", raw)
+			}
+			*target = value
+		}
+	}
+	trade.Fees = fees.Total()
+	return trade, fees, date, nil
+}
+
+// allocateNoteFees rateia os custos da nota entre os negócios proporcionalmente ao valor;
+// a diferença de centavos fica no último negócio
+func allocateNoteFees(note *ParsedNote) {
+	total := note.Fees.Total()
+	gross := 0.0
+	for _, t := range note.Trades {
+		gross += t.Value
+	}
+	if total <= 0 || gross <= 0 {
+		return
+	}
+
+	allocated := 0.0
+	for i, t := range note.Trades {
+		if i == len(note.Trades)-1 {
+			t.Fees = roundCents(total - allocated)
+			break
+		}
+		t.Fees = roundCents(total * t.Value / gross)
+		allocated += t.Fees
+	}
+}
+
+// mergeNoteFees une os custos de folhas da mesma nota; folhas repetem ou omitem o resumo, por isso vale o maior valor
+func mergeNoteFees(a, b NoteFees) NoteFees {
+	max := func(x, y float64) float64 {
+		if x > y {
+			return x
+		}
+		return y
+	}
+	return NoteFees{
+		Settlement:   max(a.Settlement, b.Settlement),
+		Registration: max(a.Registration, b.Registration),
+		Emolumentos:  max(a.Emolumentos, b.Emolumentos),
+		Brokerage:    max(a.Brokerage, b.Brokerage),
+		ISS:          max(a.ISS, b.ISS),
+		Other:        max(a.Other, b.Other),
+		IRRF:         max(a.IRRF, b.IRRF),
+	}
+}
+
+// parseNoteDecimal lê "1.234,56" (vírgula decimal) ou "1234.56"
+func parseNoteDecimal(value string, decimalComma bool) (float64, error) {
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "R$"))
+	if decimalComma || strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+// InferTradableType sugere o tipo de um ticker novo: final 11 é FII, 32 a 35 é BDR e os demais ações.
+// ETFs também terminam em 11 e devem ser indicados na importação.
+func InferTradableType(ticker string) Types {
+	switch {
+	case strings.HasSuffix(ticker, "11"):
+		return TypeFII
+	case len(ticker) == 6 && ticker[4:] >= "32" && ticker[4:] <= "35":
+		return TypeBDR
+	}
+	return TypeAcoes
+}
+
+func nextNonEmpty(lines []string) string {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// importOrder define a ordem de execução: vendas de ativos que a nota não compra vêm antes, para que
+// o valor da venda esteja na conta ao liquidar as compras; os demais negócios seguem a ordem da nota
+func importOrder(note *ParsedNote) []int {
+	bought := map[string]bool{}
+	for _, t := range note.Trades {
+		if t.Side == LotBuy {
+			bought[t.Ticker] = true
+		}
+	}
+
+	order := make([]int, 0, len(note.Trades))
+	for i, t := range note.Trades {
+		if t.Side == LotSell && !bought[t.Ticker] {
+			order = append(order, i)
+		}
+	}
+	for i, t := range note.Trades {
+		if t.Side == LotBuy || bought[t.Ticker] {
+			order = append(order, i)
+		}
+	}
+	return order
+}
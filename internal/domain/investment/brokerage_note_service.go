@@ -0,0 +1,289 @@
+package investment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/account"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// PreviewBrokerageNotes lê as notas e mostra os negócios com custos rateados, o investimento de cada
+// ticker (ou o tipo sugerido para ativos novos), especificações sem ticker e notas já importadas
+func (s *Service) PreviewBrokerageNotes(ctx context.Context, req BrokerageImportRequest) ([]*ParsedNote, error) {
+	if err := s.EnsureUserExists(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	notes, err := ParseBrokerageNotes(req.Format, req.Content)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveNoteTrades(ctx, req, notes); err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		existing, err := s.findBrokerageNote(ctx, req.UserId, note.Number)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			note.Status = existing.Status
+			note.ImportedTrades = existing.ImportedTrades
+		}
+	}
+	return notes, nil
+}
+
+// ImportBrokerageNotes cria os lotes e as movimentações na conta de cada negócio. Notas já importadas
+// são ignoradas; uma importação interrompida é retomada a partir do último negócio gravado.
+func (s *Service) ImportBrokerageNotes(ctx context.Context, req BrokerageImportRequest) ([]*ParsedNote, error) {
+	if err := s.EnsureUserExists(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, req.AccountId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if accountEntity.Type == account.TypeCreditCard {
+		return nil, appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+	}
+
+	notes, err := ParseBrokerageNotes(req.Format, req.Content)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveNoteTrades(ctx, req, notes); err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if len(note.Unresolved) > 0 {
+			return nil, appErrors.NewValidationError("tickers", fmt.Sprintf("informe o ticker de: %s", strings.Join(note.Unresolved, ", ")))
+		}
+	}
+
+	for _, note := range notes {
+		if err := s.importBrokerageNote(ctx, req, note); err != nil {
+			return nil, err
+		}
+	}
+	return notes, nil
+}
+
+// ListBrokerageNotes lista as notas importadas pelo usuário
+func (s *Service) ListBrokerageNotes(ctx context.Context, userID ulid.ULID) ([]*BrokerageNote, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.Repository.ListBrokerageNotes(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return notes, nil
+}
+
+func (s *Service) importBrokerageNote(ctx context.Context, req BrokerageImportRequest, note *ParsedNote) error {
+	record, err := s.findBrokerageNote(ctx, req.UserId, note.Number)
+	if err != nil {
+		return err
+	}
+	if record != nil && record.Status == NoteCompleted {
+		note.Status = record.Status
+		note.ImportedTrades = record.ImportedTrades
+		return nil
+	}
+
+	order := importOrder(note)
+	if record == nil {
+		if err := validateNewPositionSells(note, order); err != nil {
+			return err
+		}
+
+		record = &BrokerageNote{
+			Id:         pkg.GenerateULIDObject(),
+			UserId:     req.UserId,
+			AccountId:  req.AccountId,
+			Number:     note.Number,
+			TradeDate:  note.TradeDate,
+			Status:     NotePartial,
+			Trades:     len(order),
+			GrossBuys:  note.GrossBuys,
+			GrossSells: note.GrossSells,
+			Fees:       note.Fees.Total(),
+			IRRF:       roundCents(note.Fees.IRRF),
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+		if err := s.Repository.CreateBrokerageNote(ctx, record); err != nil {
+			return appErrors.ErrConflict.WithDetails(map[string]interface{}{"number": note.Number}).WithError(err)
+		}
+	}
+
+	created := map[string]ulid.ULID{}
+	for i := record.ImportedTrades; i < len(order); i++ {
+		if err := s.importNoteTrade(ctx, req, note, note.Trades[order[i]], created); err != nil {
+			return err
+		}
+		record.ImportedTrades = i + 1
+		if err := s.Repository.UpdateBrokerageNoteProgress(ctx, record.Id, record.ImportedTrades, NotePartial); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+	}
+
+	if err := s.Repository.UpdateBrokerageNoteProgress(ctx, record.Id, record.ImportedTrades, NoteCompleted); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	note.Status = NoteCompleted
+	note.ImportedTrades = record.ImportedTrades
+	return nil
+}
+
+// importNoteTrade registra um negócio como lote; a primeira compra de um ticker novo cria o investimento
+func (s *Service) importNoteTrade(ctx context.Context, req BrokerageImportRequest, note *ParsedNote, trade *NoteTrade, created map[string]ulid.ULID) error {
+	investmentID := trade.InvestmentId
+	if investmentID == nil {
+		if id, ok := created[trade.Ticker]; ok {
+			investmentID = &id
+		}
+	}
+
+	if investmentID == nil {
+		inv, err := s.CreateInvestment(ctx, contracts.CreateInvestmentRequestDomain{
+			UserId:    req.UserId,
+			AccountId: req.AccountId,
+			Type:      string(trade.InvestmentType),
+			Name:      trade.Ticker,
+			Ticker:    trade.Ticker,
+			Quantity:  trade.Quantity,
+			UnitPrice: trade.UnitPrice,
+			Fees:      trade.Fees,
+			TradeDate: note.TradeDate,
+		})
+		if err != nil {
+			return err
+		}
+		created[trade.Ticker] = inv.Id
+		return nil
+	}
+
+	tradeReq := TradeRequest{
+		InvestmentId: *investmentID,
+		AccountId:    req.AccountId,
+		UserId:       req.UserId,
+		Quantity:     trade.Quantity,
+		UnitPrice:    trade.UnitPrice,
+		Fees:         trade.Fees,
+		TradeDate:    note.TradeDate,
+	}
+	if trade.Side == LotSell {
+		tradeReq.Description = fmt.Sprintf("Nota %s - Venda %s", note.Number, trade.Ticker)
+		_, _, err := s.Sell(ctx, tradeReq)
+		return err
+	}
+	tradeReq.Description = fmt.Sprintf("Nota %s - Compra %s", note.Number, trade.Ticker)
+	_, err := s.Buy(ctx, tradeReq)
+	return err
+}
+
+// resolveNoteTrades associa cada negócio a um ticker (pela especificação, pelo mapeamento informado ou
+// pelo nome de um investimento existente) e ao investimento com esse ticker
+func (s *Service) resolveNoteTrades(ctx context.Context, req BrokerageImportRequest, notes []*ParsedNote) error {
+	investments, err := s.listAllInvestments(ctx, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	byTicker := map[string]*Investment{}
+	byName := map[string]*Investment{}
+	for _, inv := range investments {
+		if inv.IsPosition() {
+			byTicker[inv.Ticker] = inv
+			byName[strings.ToUpper(strings.TrimSpace(inv.Name))] = inv
+		}
+	}
+
+	tickers := map[string]string{}
+	for spec, ticker := range req.Tickers {
+		tickers[strings.ToUpper(strings.Join(strings.Fields(spec), " "))] = NormalizeTicker(ticker)
+	}
+	types := map[string]Types{}
+	for ticker, t := range req.Types {
+		investmentType := Types(strings.ToUpper(strings.TrimSpace(t)))
+		if !investmentType.IsTradable() {
+			return appErrors.NewValidationError("types", fmt.Sprintf("tipo invalido para %s: use ACOES, FII, ETF, BDR ou CRIPTOMOEDAS", ticker))
+		}
+		types[NormalizeTicker(ticker)] = investmentType
+	}
+
+	for _, note := range notes {
+		unresolved := map[string]bool{}
+		for _, trade := range note.Trades {
+			if mapped, ok := tickers[trade.Specification]; ok && mapped != "" {
+				trade.Ticker = mapped
+			} else if trade.Ticker == "" {
+				if inv, ok := byName[trade.Specification]; ok {
+					trade.Ticker = inv.Ticker
+				}
+			}
+			if trade.Ticker == "" {
+				if !unresolved[trade.Specification] {
+					unresolved[trade.Specification] = true
+					note.Unresolved = append(note.Unresolved, trade.Specification)
+				}
+				continue
+			}
+
+			if inv, ok := byTicker[trade.Ticker]; ok {
+				id := inv.Id
+				trade.InvestmentId = &id
+				trade.InvestmentType = inv.Type
+				continue
+			}
+			trade.NewInvestment = true
+			trade.InvestmentType = InferTradableType(trade.Ticker)
+			if t, ok := types[trade.Ticker]; ok {
+				trade.InvestmentType = t
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) findBrokerageNote(ctx context.Context, userID ulid.ULID, number string) (*BrokerageNote, error) {
+	note, err := s.Repository.GetBrokerageNote(ctx, userID, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return note, nil
+}
+
+// validateNewPositionSells rejeita vendas de tickers sem posição que não foram comprados antes na própria nota
+func validateNewPositionSells(note *ParsedNote, order []int) error {
+	bought := map[string]bool{}
+	for _, i := range order {
+		trade := note.Trades[i]
+		if !trade.NewInvestment {
+			continue
+		}
+		if trade.Side == LotBuy {
+			bought[trade.Ticker] = true
+		} else if !bought[trade.Ticker] {
+			return appErrors.NewValidationError("content", fmt.Sprintf("venda de %s sem posicao na nota %s", trade.Ticker, note.Number))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package investment
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"Fynance/internal/pkg"
+)
+
+// CashFlow é uma movimentação de principal: aporte (positivo) ou resgate (negativo)
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// Tranche é a parcela de um aporte ainda aplicada, com data própria para IR e IOF
+type Tranche struct {
+	Date      time.Time `json:"date"`
+	Principal float64   `json:"principal"`
+	Gross     float64   `json:"gross"`
+}
+
+// Accrual é o valor de um título de renda fixa em uma data
+type Accrual struct {
+	Date      time.Time `json:"date"`
+	Principal float64   `json:"principal"`
+	Gross     float64   `json:"gross"`
+	Yield     float64   `json:"yield"`
+	IOF       float64   `json:"iof"`
+	IncomeTax float64   `json:"incomeTax"`
+	Net       float64   `json:"net"`
+	Tranches  []Tranche `json:"tranches"`
+}
+
+// IndexPoint é o valor de um índice em uma data (CDI/SELIC: taxa diária em %; IPCA: variação mensal em %)
+type IndexPoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// IndexSeries é a série local de um índice. Datas sem valor usam o último valor conhecido,
+// o que também serve de premissa para projeções futuras.
+type IndexSeries struct {
+	points []IndexPoint
+}
+
+func NewIndexSeries(points []IndexPoint) *IndexSeries {
+	sorted := make([]IndexPoint, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+	return &IndexSeries{points: sorted}
+}
+
+func (s *IndexSeries) valueAt(day time.Time) (float64, bool) {
+	if s == nil || len(s.points) == 0 {
+		return 0, false
+	}
+	i := sort.Search(len(s.points), func(i int) bool { return s.points[i].Date.After(day) })
+	if i == 0 {
+		return 0, false
+	}
+	return s.points[i-1].Value, true
+}
+
+// CalculateAccrual aplica a remuneração do indexador dia útil a dia útil sobre cada aporte até a data
+// informada (limitada ao vencimento) e calcula IOF e IR regressivos por parcela.
+// Dias sem série do índice não rendem a parte indexada.
+func CalculateAccrual(inv *Investment, flows []CashFlow, series *IndexSeries, at time.Time) *Accrual {
+	end := truncateDay(at)
+	result := &Accrual{Date: end, Tranches: []Tranche{}}
+	if len(flows) == 0 {
+		return result
+	}
+
+	sorted := make([]CashFlow, len(flows))
+	copy(sorted, flows)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	accrueUntil := end
+	if inv.MaturityDate != nil && truncateDay(*inv.MaturityDate).Before(end) {
+		accrueUntil = truncateDay(*inv.MaturityDate)
+	}
+
+	factors := dailyFactors{inv: inv, series: series, monthDays: map[time.Time]int{}}
+	var tranches []*Tranche
+	next := 0
+
+	for day := truncateDay(sorted[0].Date); !day.After(end); day = day.AddDate(0, 0, 1) {
+		for next < len(sorted) && !truncateDay(sorted[next].Date).After(day) {
+			tranches = applyCashFlow(tranches, day, sorted[next].Amount)
+			next++
+		}
+		if !day.Before(accrueUntil) {
+			continue
+		}
+		if !pkg.IsBusinessDay(day) {
+			continue
+		}
+		factor := factors.at(day)
+		for _, t := range tranches {
+			t.Gross *= factor
+		}
+	}
+
+	for _, t := range tranches {
+		days := int(end.Sub(t.Date).Hours() / 24)
+		yield := math.Max(t.Gross-t.Principal, 0)
+		iof := yield * IOFRate(days)
+		incomeTax := 0.0
+		if !inv.Type.IsIncomeTaxExempt() {
+			incomeTax = (yield - iof) * IncomeTaxRate(days)
+		}
+
+		result.Principal += t.Principal
+		result.Gross += t.Gross
+		result.IOF += iof
+		result.IncomeTax += incomeTax
+		result.Tranches = append(result.Tranches, Tranche{
+			Date:      t.Date,
+			Principal: roundCents(t.Principal),
+			Gross:     roundCents(t.Gross),
+		})
+	}
+
+	result.Principal = roundCents(result.Principal)
+	result.Gross = roundCents(result.Gross)
+	result.Yield = roundCents(result.Gross - result.Principal)
+	result.IOF = roundCents(result.IOF)
+	result.IncomeTax = roundCents(result.IncomeTax)
+	result.Net = roundCents(result.Gross - result.IOF - result.IncomeTax)
+	return result
+}
+
+// applyCashFlow cria uma nova parcela para aportes e consome as parcelas mais antigas nos resgates,
+// reduzindo o principal na mesma proporção do valor resgatado
+func applyCashFlow(tranches []*Tranche, day time.Time, amount float64) []*Tranche {
+	if amount > 0 {
+		return append(tranches, &Tranche{Date: day, Principal: amount, Gross: amount})
+	}
+
+	remaining := -amount
+	kept := tranches[:0]
+	for _, t := range tranches {
+		if remaining > 0 && t.Gross > 0 {
+			take := math.Min(remaining, t.Gross)
+			t.Principal -= t.Principal * take / t.Gross
+			t.Gross -= take
+			remaining -= take
+		}
+		if t.Gross > 0.000001 {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+type dailyFactors struct {
+	inv       *Investment
+	series    *IndexSeries
+	monthDays map[time.Time]int
+}
+
+// at retorna o fator de rendimento de um dia útil conforme o indexador
+func (f *dailyFactors) at(day time.Time) float64 {
+	rate := f.inv.IndexerRate
+	spread := math.Pow(1+rate/100, 1.0/252)
+
+	switch f.inv.Indexer {
+	case IndexerPrefixado:
+		return spread
+	case IndexerCDI:
+		cdi, ok := f.series.valueAt(day)
+		if !ok {
+			return 1
+		}
+		return 1 + cdi/100*rate/100
+	case IndexerSelic:
+		selic, ok := f.series.valueAt(day)
+		if !ok {
+			return spread
+		}
+		return (1 + selic/100) * spread
+	case IndexerIPCA:
+		month := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		ipca, ok := f.series.valueAt(month)
+		if !ok {
+			return spread
+		}
+		days, cached := f.monthDays[month]
+		if !cached {
+			days = pkg.BusinessDaysBetween(month, month.AddDate(0, 1, 0))
+			f.monthDays[month] = days
+		}
+		return math.Pow(1+ipca/100, 1/float64(days)) * spread
+	}
+	return 1
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
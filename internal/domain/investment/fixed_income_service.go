@@ -0,0 +1,209 @@
+package investment
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// IndexSource fornece a série local de um índice econômico
+type IndexSource interface {
+	GetIndexRates(ctx context.Context, index string, from, to time.Time) ([]*market.IndexRate, error)
+}
+
+// FixedIncomeValuation traz o valor do título na data consultada e a projeção até o vencimento
+type FixedIncomeValuation struct {
+	Indexer      Indexer    `json:"indexer"`
+	IndexerRate  float64    `json:"indexerRate"`
+	MaturityDate *time.Time `json:"maturityDate,omitempty"`
+	Current      *Accrual   `json:"current"`
+	AtMaturity   *Accrual   `json:"atMaturity,omitempty"`
+}
+
+// AccruesDaily indica se o saldo do investimento é calculado pelo indexador de renda fixa
+func (i *Investment) AccruesDaily() bool {
+	return i.Indexer != ""
+}
+
+// GetFixedIncomeValuation calcula os valores bruto e líquido na data e a projeção até o vencimento,
+// usando o último valor conhecido dos índices para os dias futuros
+func (s *Service) GetFixedIncomeValuation(ctx context.Context, investmentID, userID ulid.ULID, at time.Time) (*FixedIncomeValuation, error) {
+	inv, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
+	if err != nil {
+		return nil, appErrors.ErrInvestmentNotFound.WithError(err)
+	}
+
+	if !inv.AccruesDaily() {
+		return nil, appErrors.NewValidationError("investment", "investimento nao possui indexador de renda fixa")
+	}
+
+	if truncateDay(at).Before(truncateDay(inv.ApplicationDate)) {
+		return nil, appErrors.NewValidationError("date", "deve ser posterior a data de aplicacao")
+	}
+
+	current, err := s.calculateAccrual(ctx, inv, at, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	valuation := &FixedIncomeValuation{
+		Indexer:      inv.Indexer,
+		IndexerRate:  inv.IndexerRate,
+		MaturityDate: inv.MaturityDate,
+		Current:      current,
+	}
+
+	if inv.MaturityDate != nil && inv.MaturityDate.After(at) {
+		projection, err := s.calculateAccrual(ctx, inv, *inv.MaturityDate, nil)
+		if err != nil {
+			return nil, err
+		}
+		valuation.AtMaturity = projection
+	}
+
+	return valuation, nil
+}
+
+// AccrueFixedIncome atualiza o saldo bruto e o retorno de todos os títulos com indexador.
+// Retorna quantos investimentos foram atualizados.
+func (s *Service) AccrueFixedIncome(ctx context.Context, now time.Time) (int, error) {
+	investments, err := s.Repository.ListAccruing(ctx)
+	if err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+
+	updated := 0
+	for _, inv := range investments {
+		if _, err := s.refreshAccrual(ctx, inv, now, nil); err != nil {
+			logger.Error().Err(err).Str("investment_id", inv.Id.String()).Msg("Falha ao atualizar rendimento de renda fixa")
+			continue
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// refreshAccrual grava o valor bruto calculado como saldo atual. O retorno é o rendimento bruto
+// sobre o principal ainda aplicado, substituindo a taxa informada manualmente.
+func (s *Service) refreshAccrual(ctx context.Context, inv *Investment, now time.Time, skipTransaction *ulid.ULID) (*Accrual, error) {
+	accrual, err := s.calculateAccrual(ctx, inv, now, skipTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	returnRate := 0.0
+	if accrual.Principal > 0 {
+		returnRate = math.Round(accrual.Yield/accrual.Principal*10000) / 100
+	}
+
+	updatedAt := time.Now()
+	if err := s.Repository.UpdateFields(ctx, inv.Id, inv.UserId, map[string]interface{}{
+		"current_balance": accrual.Gross,
+		"return_balance":  accrual.Yield,
+		"return_rate":     returnRate,
+		"updated_at":      updatedAt,
+	}); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	inv.CurrentBalance = accrual.Gross
+	inv.ReturnBalance = accrual.Yield
+	inv.ReturnRate = returnRate
+	inv.UpdatedAt = updatedAt
+
+	if err := s.syncGoal(ctx, inv); err != nil {
+		return nil, err
+	}
+	return accrual, nil
+}
+
+func (s *Service) calculateAccrual(ctx context.Context, inv *Investment, at time.Time, skipTransaction *ulid.ULID) (*Accrual, error) {
+	flows, err := s.cashFlows(ctx, inv, skipTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	var series *IndexSeries
+	if len(flows) > 0 && inv.Indexer != IndexerPrefixado {
+		from := flows[0].Date
+		for _, f := range flows {
+			if f.Date.Before(from) {
+				from = f.Date
+			}
+		}
+		series, err = s.indexSeries(ctx, string(inv.Indexer), from, at)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return CalculateAccrual(inv, flows, series, at), nil
+}
+
+// cashFlows reconstrói aportes e resgates do investimento a partir das movimentações
+func (s *Service) cashFlows(ctx context.Context, inv *Investment, skipTransaction *ulid.ULID) ([]CashFlow, error) {
+	transactions, err := s.investmentTransactions(ctx, inv.Id, inv.UserId)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	flows := make([]CashFlow, 0, len(transactions))
+	for _, tx := range transactions {
+		if skipTransaction != nil && tx.Id == *skipTransaction {
+			continue
+		}
+		switch tx.Type {
+		case transaction.Investment:
+			flows = append(flows, CashFlow{Date: tx.Date, Amount: math.Abs(tx.Amount)})
+		case transaction.Withdraw:
+			flows = append(flows, CashFlow{Date: tx.Date, Amount: -math.Abs(tx.Amount)})
+		}
+	}
+	return flows, nil
+}
+
+// indexSeries carrega a série do índice com folga antes do primeiro aporte,
+// para que o IPCA mensal e o último CDI conhecido estejam disponíveis desde o início
+func (s *Service) indexSeries(ctx context.Context, index string, from, to time.Time) (*IndexSeries, error) {
+	if s.Indexes == nil {
+		return nil, nil
+	}
+
+	rates, err := s.Indexes.GetIndexRates(ctx, index, truncateDay(from).AddDate(0, -3, 0), truncateDay(to))
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]IndexPoint, 0, len(rates))
+	for _, r := range rates {
+		points = append(points, IndexPoint{Date: truncateDay(r.Date), Value: r.Value})
+	}
+	return NewIndexSeries(points), nil
+}
+
+// validateFixedIncome confere indexador, taxa e vencimento informados na criação
+func validateFixedIncome(investmentType Types, indexer Indexer, rate float64, maturity *time.Time, now time.Time) error {
+	if !investmentType.IsFixedIncome() {
+		return appErrors.NewValidationError("indexer", "apenas CDB, LCI, LCA e Tesouro Direto possuem indexador")
+	}
+	if !indexer.IsValid() {
+		return appErrors.NewValidationError("indexer", "deve ser PREFIXADO, CDI, IPCA ou SELIC")
+	}
+	if (indexer == IndexerPrefixado || indexer == IndexerCDI) && rate <= 0 {
+		return appErrors.NewValidationError("indexer_rate", "deve ser maior que zero")
+	}
+	if maturity == nil {
+		return appErrors.NewValidationError("maturity_date", "é obrigatório para renda fixa com indexador")
+	}
+	if !truncateDay(*maturity).After(truncateDay(now)) {
+		return appErrors.NewValidationError("maturity_date", "deve ser uma data futura")
+	}
+	return nil
+}
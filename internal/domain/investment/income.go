@@ -0,0 +1,91 @@
+package investment
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type IncomeType string
+
+const (
+	IncomeDividend   IncomeType = "DIVIDENDO"
+	IncomeJCP        IncomeType = "JCP"
+	IncomeRealEstate IncomeType = "RENDIMENTO"
+	IncomeCoupon     IncomeType = "CUPOM"
+)
+
+func (t IncomeType) IsValid() bool {
+	switch t {
+	case IncomeDividend, IncomeJCP, IncomeRealEstate, IncomeCoupon:
+		return true
+	}
+	return false
+}
+
+type IncomeStatus string
+
+const (
+	IncomeAnnounced IncomeStatus = "ANNOUNCED"
+	IncomePaid      IncomeStatus = "PAID"
+)
+
+// jcpWithholdingRate é o IR retido na fonte sobre juros sobre capital próprio
+const jcpWithholdingRate = 0.15
+
+// IncomeEvent é um provento do investimento: anunciado (pagamento futuro) ou pago.
+// O pagamento credita o valor líquido na conta como uma receita na categoria Investimentos.
+type IncomeEvent struct {
+	Id            ulid.ULID    `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId        ulid.ULID    `gorm:"type:varchar(26);index:idx_income_events_user_payment,priority:1;not null" json:"userId"`
+	InvestmentId  ulid.ULID    `gorm:"type:varchar(26);index:idx_income_events_investment_id;not null" json:"investmentId"`
+	AccountId     ulid.ULID    `gorm:"type:varchar(26);not null" json:"accountId"`
+	TransactionId *ulid.ULID   `gorm:"type:varchar(26);index:idx_income_events_transaction_id" json:"transactionId,omitempty"`
+	Type          IncomeType   `gorm:"type:varchar(20);not null" json:"type"`
+	Status        IncomeStatus `gorm:"type:varchar(10);not null;index:idx_income_events_status" json:"status"`
+	AmountPerUnit float64      `gorm:"type:decimal(18,8);not null;default:0" json:"amountPerUnit"`
+	GrossAmount   float64      `gorm:"type:decimal(15,2);not null" json:"grossAmount"`
+	WithheldTax   float64      `gorm:"type:decimal(15,2);not null;default:0" json:"withheldTax"`
+	NetAmount     float64      `gorm:"type:decimal(15,2);not null" json:"netAmount"`
+	ExDate        *time.Time   `gorm:"type:date" json:"exDate,omitempty"`
+	PaymentDate   time.Time    `gorm:"type:date;not null;index:idx_income_events_user_payment,priority:2" json:"paymentDate"`
+	Description   string       `gorm:"type:varchar(255)" json:"description"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (IncomeEvent) TableName() string {
+	return "investment_income_events"
+}
+
+// IncomeRequest registra um provento pago ou anunciado
+type IncomeRequest struct {
+	InvestmentId  ulid.ULID
+	AccountId     ulid.ULID
+	UserId        ulid.ULID
+	Type          IncomeType
+	AmountPerUnit float64
+	GrossAmount   float64
+	WithheldTax   *float64
+	ExDate        *time.Time
+	PaymentDate   time.Time
+	Description   string
+}
+
+// IncomeSummary reúne os proventos de um investimento e o yield on cost dos últimos 12 meses
+type IncomeSummary struct {
+	Events         []*IncomeEvent `json:"events"`
+	TotalReceived  float64        `json:"totalReceived"`
+	Last12Months   float64        `json:"last12Months"`
+	CostBasis      float64        `json:"costBasis"`
+	YieldOnCost    float64        `json:"yieldOnCost"`
+	AnnouncedTotal float64        `json:"announcedTotal"`
+}
+
+// MonthlyIncome é o total de proventos recebidos em um mês, por tipo
+type MonthlyIncome struct {
+	Year   int                    `json:"year"`
+	Month  int                    `json:"month"`
+	Total  float64                `json:"total"`
+	ByType map[IncomeType]float64 `json:"byType"`
+}
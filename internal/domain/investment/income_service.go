@@ -0,0 +1,392 @@
+package investment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/category"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// incomeCategoryName é a categoria das receitas geradas por proventos
+const incomeCategoryName = "Investimentos"
+
+// RecordIncome registra um provento. Com data de pagamento futura ele fica anunciado e entra no
+// calendário; caso contrário a conta é creditada imediatamente pelo valor líquido.
+// Sem valor bruto, usa valor por cota vezes a quantidade atual da posição. JCP retém 15% por padrão.
+func (s *Service) RecordIncome(ctx context.Context, req IncomeRequest) (*IncomeEvent, error) {
+	inv, err := s.Repository.GetInvestmentByID(ctx, req.InvestmentId, req.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.ErrInvestmentNotFound.WithError(err)
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	if !req.Type.IsValid() {
+		return nil, appErrors.NewValidationError("type", "deve ser DIVIDENDO, JCP, RENDIMENTO ou CUPOM")
+	}
+
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, req.AccountId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if accountEntity.Type == account.TypeCreditCard {
+		return nil, appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+	}
+
+	gross := req.GrossAmount
+	if gross <= 0 && req.AmountPerUnit > 0 && inv.IsPosition() {
+		gross = req.AmountPerUnit * inv.Quantity
+	}
+	gross = roundCents(gross)
+	if gross <= 0 {
+		return nil, appErrors.NewValidationError("amount", "informe o valor bruto ou o valor por cota de uma posicao com quantidade")
+	}
+
+	withheld := 0.0
+	if req.WithheldTax != nil {
+		withheld = roundCents(*req.WithheldTax)
+	} else if req.Type == IncomeJCP {
+		withheld = roundCents(gross * jcpWithholdingRate)
+	}
+	if withheld < 0 || withheld >= gross {
+		return nil, appErrors.NewValidationError("withheld_tax", "deve ser menor que o valor bruto")
+	}
+
+	paymentDate := req.PaymentDate
+	if paymentDate.IsZero() {
+		paymentDate = time.Now()
+	}
+	paymentDate = truncateDay(paymentDate)
+	if req.ExDate != nil && truncateDay(*req.ExDate).After(paymentDate) {
+		return nil, appErrors.NewValidationError("ex_date", "deve ser anterior ao pagamento")
+	}
+
+	event := &IncomeEvent{
+		Id:            pkg.GenerateULIDObject(),
+		UserId:        req.UserId,
+		InvestmentId:  inv.Id,
+		AccountId:     req.AccountId,
+		Type:          req.Type,
+		Status:        IncomeAnnounced,
+		AmountPerUnit: req.AmountPerUnit,
+		GrossAmount:   gross,
+		WithheldTax:   withheld,
+		NetAmount:     roundCents(gross - withheld),
+		ExDate:        req.ExDate,
+		PaymentDate:   paymentDate,
+		Description:   strings.TrimSpace(req.Description),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := s.Repository.CreateIncomeEvent(ctx, event); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	if paymentDate.After(truncateDay(time.Now())) {
+		return event, nil
+	}
+
+	if err := s.payIncome(ctx, event, inv); err != nil {
+		_ = s.Repository.DeleteIncomeEvent(ctx, event.Id)
+		return nil, err
+	}
+	return event, nil
+}
+
+// ReceiveIncome confirma o recebimento de um provento anunciado antes do job diário
+func (s *Service) ReceiveIncome(ctx context.Context, eventID, userID ulid.ULID) (*IncomeEvent, error) {
+	event, err := s.getIncomeEvent(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == IncomePaid {
+		return nil, appErrors.NewValidationError("status", "provento ja recebido")
+	}
+
+	inv, err := s.Repository.GetInvestmentByID(ctx, event.InvestmentId, userID)
+	if err != nil {
+		return nil, appErrors.ErrInvestmentNotFound.WithError(err)
+	}
+
+	if today := truncateDay(time.Now()); event.PaymentDate.After(today) {
+		event.PaymentDate = today
+	}
+	if err := s.payIncome(ctx, event, inv); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// DeleteIncome remove um provento; se já foi pago, estorna a receita e o crédito na conta
+func (s *Service) DeleteIncome(ctx context.Context, eventID, userID ulid.ULID) error {
+	event, err := s.getIncomeEvent(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+
+	if event.Status == IncomePaid && event.TransactionId != nil {
+		if err := s.AccountService.UpdateBalance(ctx, event.AccountId, userID, -event.NetAmount); err != nil {
+			return err
+		}
+		if err := s.TransactionRepo.Delete(ctx, *event.TransactionId); err != nil {
+			_ = s.AccountService.UpdateBalance(ctx, event.AccountId, userID, event.NetAmount)
+			return err
+		}
+	}
+
+	if err := s.Repository.DeleteIncomeEvent(ctx, event.Id); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// GetIncomeSummary lista os proventos do investimento com o yield on cost:
+// proventos líquidos dos últimos 12 meses sobre o custo da posição (ou o valor líquido aportado)
+func (s *Service) GetIncomeSummary(ctx context.Context, investmentID, userID ulid.ULID, now time.Time) (*IncomeSummary, error) {
+	inv, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
+	if err != nil {
+		return nil, appErrors.ErrInvestmentNotFound.WithError(err)
+	}
+
+	events, err := s.Repository.ListIncomeEvents(ctx, investmentID, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	summary := &IncomeSummary{Events: events}
+	since := truncateDay(now).AddDate(-1, 0, 0)
+	for _, e := range events {
+		if e.Status == IncomeAnnounced {
+			summary.AnnouncedTotal += e.NetAmount
+			continue
+		}
+		summary.TotalReceived += e.NetAmount
+		if e.PaymentDate.After(since) {
+			summary.Last12Months += e.NetAmount
+		}
+	}
+
+	costBasis, err := s.incomeCostBasis(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.TotalReceived = roundCents(summary.TotalReceived)
+	summary.Last12Months = roundCents(summary.Last12Months)
+	summary.AnnouncedTotal = roundCents(summary.AnnouncedTotal)
+	summary.CostBasis = roundCents(costBasis)
+	if costBasis > 0 {
+		summary.YieldOnCost = roundCents(summary.Last12Months / costBasis * 100)
+	}
+	return summary, nil
+}
+
+// GetMonthlyIncome retorna a renda passiva recebida mês a mês, incluindo meses sem proventos
+func (s *Service) GetMonthlyIncome(ctx context.Context, userID ulid.ULID, months int, now time.Time) ([]MonthlyIncome, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	if months <= 0 || months > 60 {
+		months = 12
+	}
+
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+
+	events, err := s.Repository.ListUserIncomeEvents(ctx, userID, IncomePaid, start, end)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	result := make([]MonthlyIncome, months)
+	for i := range result {
+		month := start.AddDate(0, i, 0)
+		result[i] = MonthlyIncome{Year: month.Year(), Month: int(month.Month()), ByType: map[IncomeType]float64{}}
+	}
+
+	for _, e := range events {
+		idx := (e.PaymentDate.Year()-start.Year())*12 + int(e.PaymentDate.Month()) - int(start.Month())
+		if idx < 0 || idx >= months {
+			continue
+		}
+		result[idx].Total = roundCents(result[idx].Total + e.NetAmount)
+		result[idx].ByType[e.Type] = roundCents(result[idx].ByType[e.Type] + e.NetAmount)
+	}
+	return result, nil
+}
+
+// GetIncomeCalendar lista os proventos anunciados com pagamento no período
+func (s *Service) GetIncomeCalendar(ctx context.Context, userID ulid.ULID, from, to time.Time) ([]*IncomeEvent, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, appErrors.NewValidationError("days", "periodo invalido")
+	}
+
+	events, err := s.Repository.ListUserIncomeEvents(ctx, userID, IncomeAnnounced, truncateDay(from), truncateDay(to).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return events, nil
+}
+
+// ProcessDueIncome credita os proventos anunciados cuja data de pagamento chegou
+func (s *Service) ProcessDueIncome(ctx context.Context, now time.Time) error {
+	events, err := s.Repository.ListDueIncomeEvents(ctx, truncateDay(now))
+	if err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	for _, event := range events {
+		inv, err := s.Repository.GetInvestmentByID(ctx, event.InvestmentId, event.UserId)
+		if err != nil {
+			logger.Error().Err(err).Str("income_event_id", event.Id.String()).Msg("Investimento do provento nao encontrado")
+			continue
+		}
+		if err := s.payIncome(ctx, event, inv); err != nil {
+			logger.Error().Err(err).Str("income_event_id", event.Id.String()).Msg("Falha ao creditar provento anunciado")
+		}
+	}
+	return nil
+}
+
+// payIncome cria a receita na categoria Investimentos, credita a conta e marca o provento como pago
+func (s *Service) payIncome(ctx context.Context, event *IncomeEvent, inv *Investment) error {
+	categoryID, err := s.incomeCategoryID(ctx, event.UserId)
+	if err != nil {
+		return err
+	}
+
+	description := event.Description
+	if description == "" {
+		description = incomeDescription(event.Type, inv)
+	}
+
+	now := pkg.SetTimestamps()
+	investmentID := inv.Id
+	receipt := &transaction.Transaction{
+		Id:           pkg.GenerateULIDObject(),
+		UserId:       event.UserId,
+		AccountId:    event.AccountId,
+		CategoryId:   &categoryID,
+		Type:         transaction.Receipt,
+		Amount:       event.NetAmount,
+		Description:  description,
+		Date:         event.PaymentDate,
+		InvestmentId: &investmentID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.TransactionRepo.Create(ctx, receipt); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	if err := s.AccountService.UpdateBalance(ctx, event.AccountId, event.UserId, event.NetAmount); err != nil {
+		_ = s.TransactionRepo.Delete(ctx, receipt.Id)
+		return err
+	}
+
+	event.Status = IncomePaid
+	event.TransactionId = &receipt.Id
+	if err := s.Repository.UpdateIncomeEvent(ctx, event); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// deleteIncomeByTransaction remove o provento ligado a uma receita excluída.
+// O estorno na conta é feito pela exclusão da própria receita.
+func (s *Service) deleteIncomeByTransaction(ctx context.Context, transactionID, userID ulid.ULID) error {
+	event, err := s.Repository.GetIncomeEventByTransactionID(ctx, transactionID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	return s.Repository.DeleteIncomeEvent(ctx, event.Id)
+}
+
+func (s *Service) getIncomeEvent(ctx context.Context, eventID, userID ulid.ULID) (*IncomeEvent, error) {
+	event, err := s.Repository.GetIncomeEvent(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("provento")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return event, nil
+}
+
+// incomeCostBasis é o custo da posição ou, sem ticker, o valor líquido aportado
+func (s *Service) incomeCostBasis(ctx context.Context, inv *Investment) (float64, error) {
+	if inv.IsPosition() {
+		lots, err := s.Repository.GetLots(ctx, inv.Id, inv.UserId)
+		if err != nil {
+			return 0, appErrors.NewDatabaseError(err)
+		}
+		position, err := CalculatePosition(inv.Ticker, lots, inv.LastPrice)
+		if err != nil {
+			return 0, err
+		}
+		return position.CostBasis, nil
+	}
+
+	flows, err := s.cashFlows(ctx, inv, nil)
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, f := range flows {
+		total += f.Amount
+	}
+	if total < 0 {
+		return 0, nil
+	}
+	return total, nil
+}
+
+// incomeCategoryID resolve a categoria padrão Investimentos do usuário, criando-a se necessário
+func (s *Service) incomeCategoryID(ctx context.Context, userID ulid.ULID) (ulid.ULID, error) {
+	categoryID := category.GenerateDeterministicID(userID.String(), incomeCategoryName)
+	if s.CategoryService == nil {
+		return categoryID, nil
+	}
+	if err := s.CategoryService.ValidateAndEnsureExists(ctx, categoryID, userID); err != nil {
+		return ulid.ULID{}, err
+	}
+	return s.CategoryService.ResolveCategoryID(ctx, categoryID, userID)
+}
+
+func incomeDescription(incomeType IncomeType, inv *Investment) string {
+	name := inv.Name
+	if inv.Ticker != "" {
+		name = inv.Ticker
+	}
+
+	switch incomeType {
+	case IncomeDividend:
+		return "Dividendos " + name
+	case IncomeJCP:
+		return "JCP " + name
+	case IncomeRealEstate:
+		return "Rendimentos " + name
+	case IncomeCoupon:
+		return "Cupom " + name
+	}
+	return "Proventos " + name
+}
@@ -7,16 +7,24 @@ import (
 )
 
 type Investment struct {
-	Id              ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"id"`
-	UserId          ulid.ULID `gorm:"type:varchar(26);index:idx_investments_user_id;not null" json:"userId"`
-	Type            Types     `gorm:"type:varchar(20);not null;index:idx_investments_type" json:"type"`
-	Name            string    `gorm:"type:varchar(100);not null;index:idx_investments_user_name,unique" json:"name"`
-	CurrentBalance  float64   `gorm:"type:decimal(15,2);not null;default:0" json:"currentBalance"`
-	ReturnBalance   float64   `gorm:"type:decimal(15,2);not null;default:0" json:"returnBalance"`
-	ReturnRate      float64   `gorm:"type:decimal(5,2);default:0" json:"returnRate"`
-	ApplicationDate time.Time `gorm:"type:date;not null;index:idx_investments_app_date" json:"applicationDate"`
-	CreatedAt       time.Time `gorm:"autoCreateTime;not null" json:"createdAt"`
-	UpdatedAt       time.Time `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+	Id              ulid.ULID  `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId          ulid.ULID  `gorm:"type:varchar(26);index:idx_investments_user_id;not null" json:"userId"`
+	Type            Types      `gorm:"type:varchar(20);not null;index:idx_investments_type" json:"type"`
+	Name            string     `gorm:"type:varchar(100);not null;index:idx_investments_user_name,unique" json:"name"`
+	Ticker          string     `gorm:"type:varchar(20);index:idx_investments_ticker" json:"ticker,omitempty"`
+	Quantity        float64    `gorm:"type:decimal(24,8);not null;default:0" json:"quantity"`
+	LastPrice       float64    `gorm:"type:decimal(18,8);not null;default:0" json:"lastPrice"`
+	CurrentBalance  float64    `gorm:"type:decimal(15,2);not null;default:0" json:"currentBalance"`
+	ReturnBalance   float64    `gorm:"type:decimal(15,2);not null;default:0" json:"returnBalance"`
+	ReturnRate      float64    `gorm:"type:decimal(5,2);default:0" json:"returnRate"`
+	Indexer         Indexer    `gorm:"type:varchar(20);index:idx_investments_indexer" json:"indexer,omitempty"`
+	IndexerRate     float64    `gorm:"type:decimal(9,4);not null;default:0" json:"indexerRate"`
+	MaturityDate    *time.Time `gorm:"type:date" json:"maturityDate,omitempty"`
+	AssetClass      string     `gorm:"type:varchar(40)" json:"assetClass,omitempty"`
+	ApplicationDate time.Time  `gorm:"type:date;not null;index:idx_investments_app_date" json:"applicationDate"`
+	GoalId          *ulid.ULID `gorm:"type:varchar(26);index:idx_investments_goal_id" json:"goalId,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime;not null" json:"updatedAt"`
 }
 
 func (Investment) TableName() string {
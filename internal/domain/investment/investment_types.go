@@ -8,7 +8,55 @@ const (
 	TypeLCA         Types = "LCA"
 	TypeTesouro     Types = "TESOURO_DIRETO"
 	TypeAcoes       Types = "ACOES"
+	TypeFII         Types = "FII"
+	TypeETF         Types = "ETF"
+	TypeBDR         Types = "BDR"
 	TypeFundos      Types = "FUNDOS"
 	TypeCripto      Types = "CRIPTOMOEDAS"
 	TypePrevidencia Types = "PREVIDENCIA"
 )
+
+// IsTradable indica se o tipo é negociado por ticker e quantidade (posição em lotes)
+func (t Types) IsTradable() bool {
+	switch t {
+	case TypeAcoes, TypeFII, TypeETF, TypeBDR, TypeCripto:
+		return true
+	}
+	return false
+}
+
+type LotSide string
+
+const (
+	LotBuy  LotSide = "BUY"
+	LotSell LotSide = "SELL"
+)
+
+// IsFixedIncome indica se o tipo é renda fixa e pode ter rendimento calculado por indexador
+func (t Types) IsFixedIncome() bool {
+	switch t {
+	case TypeCDB, TypeLCI, TypeLCA, TypeTesouro:
+		return true
+	}
+	return false
+}
+
+// Indexer é a forma de remuneração de um título de renda fixa.
+// A taxa do investimento (IndexerRate) é interpretada conforme o indexador:
+// PREFIXADO = taxa anual em %, CDI = percentual do CDI, IPCA e SELIC = spread anual em %.
+type Indexer string
+
+const (
+	IndexerPrefixado Indexer = "PREFIXADO"
+	IndexerCDI       Indexer = "CDI"
+	IndexerIPCA      Indexer = "IPCA"
+	IndexerSelic     Indexer = "SELIC"
+)
+
+func (i Indexer) IsValid() bool {
+	switch i {
+	case IndexerPrefixado, IndexerCDI, IndexerIPCA, IndexerSelic:
+		return true
+	}
+	return false
+}
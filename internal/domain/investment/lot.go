@@ -0,0 +1,39 @@
+package investment
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Lot é uma operação de compra ou venda de um ativo negociado por ticker
+type Lot struct {
+	Id            ulid.ULID  `gorm:"type:varchar(26);primaryKey" json:"id"`
+	InvestmentId  ulid.ULID  `gorm:"type:varchar(26);index:idx_investment_lots_investment_id;not null" json:"investmentId"`
+	UserId        ulid.ULID  `gorm:"type:varchar(26);index:idx_investment_lots_user_id;not null" json:"userId"`
+	AccountId     ulid.ULID  `gorm:"type:varchar(26);not null" json:"accountId"`
+	TransactionId *ulid.ULID `gorm:"type:varchar(26);index:idx_investment_lots_transaction_id" json:"transactionId,omitempty"`
+	Side          LotSide    `gorm:"type:varchar(4);not null" json:"side"`
+	Quantity      float64    `gorm:"type:decimal(24,8);not null" json:"quantity"`
+	UnitPrice     float64    `gorm:"type:decimal(18,8);not null" json:"unitPrice"`
+	Fees          float64    `gorm:"type:decimal(15,2);not null;default:0" json:"fees"`
+	TradeDate     time.Time  `gorm:"type:date;not null;index:idx_investment_lots_trade_date" json:"tradeDate"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (Lot) TableName() string {
+	return "investment_lots"
+}
+
+// Gross é o valor financeiro da operação sem taxas
+func (l *Lot) Gross() float64 {
+	return l.Quantity * l.UnitPrice
+}
+
+// Total é o valor que sai da conta na compra ou entra na conta na venda
+func (l *Lot) Total() float64 {
+	if l.Side == LotSell {
+		return l.Gross() - l.Fees
+	}
+	return l.Gross() + l.Fees
+}
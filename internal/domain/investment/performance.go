@@ -0,0 +1,291 @@
+package investment
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/market"
+	"Fynance/internal/pkg"
+)
+
+// Períodos aceitos no cálculo de desempenho
+const (
+	Period1M  = "1M"
+	Period3M  = "3M"
+	Period6M  = "6M"
+	Period12M = "12M"
+	PeriodYTD = "YTD"
+	PeriodAll = "ALL"
+)
+
+// BenchmarkIndexes são os índices usados na comparação de desempenho
+var BenchmarkIndexes = []string{market.IndexCDI, market.IndexIPCA, market.IndexIBOV}
+
+// ValueAt estima o valor de um investimento no início do dia, antes das movimentações daquele dia
+type ValueAt func(day time.Time) float64
+
+// BenchmarkReturn é o retorno de um índice no mesmo período; Excess é o TWR menos o índice, em pontos percentuais
+type BenchmarkReturn struct {
+	Index      string   `json:"index"`
+	Return     float64  `json:"return"`
+	Annualized *float64 `json:"annualized,omitempty"`
+	Excess     float64  `json:"excess"`
+}
+
+// Performance é o desempenho de um investimento ou da carteira em um período. Retornos em %.
+// TWR elimina o efeito dos aportes e resgates; XIRR é a taxa anual que iguala o fluxo do investidor.
+type Performance struct {
+	Period           string            `json:"period"`
+	From             time.Time         `json:"from"`
+	To               time.Time         `json:"to"`
+	StartValue       float64           `json:"startValue"`
+	EndValue         float64           `json:"endValue"`
+	NetContributions float64           `json:"netContributions"`
+	Gain             float64           `json:"gain"`
+	TWR              float64           `json:"twr"`
+	AnnualizedTWR    *float64          `json:"annualizedTwr,omitempty"`
+	XIRR             *float64          `json:"xirr,omitempty"`
+	Benchmarks       []BenchmarkReturn `json:"benchmarks"`
+}
+
+// PeriodStart retorna o início de um período pré-definido. Para ALL retorna a data zero,
+// e o período começa na primeira movimentação.
+func PeriodStart(period string, now time.Time) (time.Time, bool) {
+	today := truncateDay(now)
+	switch strings.ToUpper(period) {
+	case Period1M:
+		return today.AddDate(0, -1, 0), true
+	case Period3M:
+		return today.AddDate(0, -3, 0), true
+	case Period6M:
+		return today.AddDate(0, -6, 0), true
+	case Period12M, "":
+		return today.AddDate(-1, 0, 0), true
+	case PeriodYTD:
+		return time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC), true
+	case PeriodAll:
+		return time.Time{}, true
+	}
+	return time.Time{}, false
+}
+
+// CalculatePerformance mede o desempenho entre from e to (inclusive) a partir das movimentações
+// (aportes positivos, resgates e proventos negativos) e da função de valor.
+// O TWR encadeia sub-períodos entre as datas de movimentação pelo método de Dietz modificado.
+func CalculatePerformance(flows []CashFlow, valueAt ValueAt, from, to time.Time) *Performance {
+	from, to = truncateDay(from), truncateDay(to)
+	result := &Performance{From: from, To: to, Benchmarks: []BenchmarkReturn{}}
+
+	period := make([]CashFlow, 0, len(flows))
+	for _, f := range flows {
+		day := truncateDay(f.Date)
+		if day.Before(from) || day.After(to) {
+			continue
+		}
+		period = append(period, CashFlow{Date: day, Amount: f.Amount})
+		result.NetContributions += f.Amount
+	}
+	sort.SliceStable(period, func(i, j int) bool { return period[i].Date.Before(period[j].Date) })
+
+	result.StartValue = valueAt(from)
+	result.EndValue = valueAt(to.AddDate(0, 0, 1))
+
+	growth := 1.0
+	segmentStart, startValue := from, result.StartValue
+	var segment []CashFlow
+	for _, f := range period {
+		if f.Date.After(segmentStart) {
+			endValue := valueAt(f.Date)
+			growth *= 1 + modifiedDietz(startValue, endValue, segment, segmentStart, f.Date)
+			segmentStart, startValue, segment = f.Date, endValue, nil
+		}
+		segment = append(segment, f)
+	}
+	growth *= 1 + modifiedDietz(startValue, result.EndValue, segment, segmentStart, to.AddDate(0, 0, 1))
+
+	days := to.Sub(from).Hours()/24 + 1
+	result.TWR = roundCents((growth - 1) * 100)
+	if days >= 365 && growth > 0 {
+		annualized := roundCents((math.Pow(growth, 365/days) - 1) * 100)
+		result.AnnualizedTWR = &annualized
+	}
+
+	investorFlows := make([]CashFlow, 0, len(period)+2)
+	if result.StartValue > 0 {
+		investorFlows = append(investorFlows, CashFlow{Date: from, Amount: -result.StartValue})
+	}
+	for _, f := range period {
+		investorFlows = append(investorFlows, CashFlow{Date: f.Date, Amount: -f.Amount})
+	}
+	if result.EndValue > 0 {
+		investorFlows = append(investorFlows, CashFlow{Date: to.AddDate(0, 0, 1), Amount: result.EndValue})
+	}
+	if rate, ok := XIRR(investorFlows); ok {
+		xirr := roundCents(rate * 100)
+		result.XIRR = &xirr
+	}
+
+	result.Gain = roundCents(result.EndValue - result.StartValue - result.NetContributions)
+	result.StartValue = roundCents(result.StartValue)
+	result.EndValue = roundCents(result.EndValue)
+	result.NetContributions = roundCents(result.NetContributions)
+	return result
+}
+
+// modifiedDietz é o retorno de um sub-período ponderando cada movimentação pelo tempo em que ficou aplicada
+func modifiedDietz(startValue, endValue float64, flows []CashFlow, start, end time.Time) float64 {
+	total := end.Sub(start).Hours() / 24
+	if total <= 0 {
+		return 0
+	}
+
+	net, weighted := 0.0, 0.0
+	for _, f := range flows {
+		net += f.Amount
+		weighted += f.Amount * end.Sub(f.Date).Hours() / 24 / total
+	}
+
+	base := startValue + weighted
+	if base <= 0 {
+		return 0
+	}
+	return (endValue - startValue - net) / base
+}
+
+// XIRR resolve a taxa anual que zera o valor presente dos fluxos (saídas negativas, entradas positivas)
+// por Newton-Raphson, recorrendo à bisseção quando não converge.
+func XIRR(flows []CashFlow) (float64, bool) {
+	hasIn, hasOut := false, false
+	for _, f := range flows {
+		hasIn = hasIn || f.Amount > 0
+		hasOut = hasOut || f.Amount < 0
+	}
+	if !hasIn || !hasOut {
+		return 0, false
+	}
+
+	first := flows[0].Date
+	for _, f := range flows {
+		if f.Date.Before(first) {
+			first = f.Date
+		}
+	}
+	years := make([]float64, len(flows))
+	for i, f := range flows {
+		years[i] = f.Date.Sub(first).Hours() / 24 / 365
+	}
+
+	npv := func(rate float64) (float64, float64) {
+		value, derivative := 0.0, 0.0
+		for i, f := range flows {
+			discount := math.Pow(1+rate, years[i])
+			value += f.Amount / discount
+			derivative -= years[i] * f.Amount / (discount * (1 + rate))
+		}
+		return value, derivative
+	}
+
+	rate := 0.1
+	for i := 0; i < 100; i++ {
+		value, derivative := npv(rate)
+		if math.Abs(value) < 1e-7 {
+			return rate, true
+		}
+		if derivative == 0 {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < 1e-10 {
+			return next, true
+		}
+		rate = next
+	}
+
+	low, high := -0.9999, 100.0
+	lowValue, _ := npv(low)
+	highValue, _ := npv(high)
+	if lowValue*highValue > 0 {
+		return 0, false
+	}
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		value, _ := npv(mid)
+		if math.Abs(value) < 1e-7 || high-low < 1e-10 {
+			return mid, true
+		}
+		if value*lowValue < 0 {
+			high = mid
+		} else {
+			low, lowValue = mid, value
+		}
+	}
+	return (low + high) / 2, true
+}
+
+// growthValue estima o valor de um investimento sem cotação nem indexador: cada movimentação
+// anterior ao dia cresce à taxa anual informada (a XIRR de toda a vida do investimento)
+func growthValue(flows []CashFlow, rate float64) ValueAt {
+	return func(day time.Time) float64 {
+		value := 0.0
+		for _, f := range flows {
+			if !truncateDay(f.Date).Before(day) {
+				continue
+			}
+			value += f.Amount * math.Pow(1+rate, day.Sub(truncateDay(f.Date)).Hours()/24/365)
+		}
+		return math.Max(value, 0)
+	}
+}
+
+// BenchmarkGrowth calcula o fator de valorização de um índice entre from e to (inclusive).
+// CDI, SELIC e IPCA são acumulados dia útil a dia útil como na renda fixa; o IBOV usa a pontuação.
+func BenchmarkGrowth(index string, series *IndexSeries, from, to time.Time) (float64, bool) {
+	from, end := truncateDay(from), truncateDay(to).AddDate(0, 0, 1)
+	if series == nil || len(series.points) == 0 || series.points[0].Date.After(end) {
+		return 0, false
+	}
+
+	switch index {
+	case market.IndexIBOV:
+		start, okStart := series.valueAt(from.AddDate(0, 0, -1))
+		final, okEnd := series.valueAt(to)
+		if !okStart || !okEnd || start <= 0 {
+			return 0, false
+		}
+		return final / start, true
+	case market.IndexCDI, market.IndexSelic, market.IndexIPCA:
+		benchmark := &Investment{Indexer: Indexer(index)}
+		if index == market.IndexCDI {
+			benchmark.IndexerRate = 100
+		}
+		factors := dailyFactors{inv: benchmark, series: series, monthDays: map[time.Time]int{}}
+		growth := 1.0
+		for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+			if pkg.IsBusinessDay(day) {
+				growth *= factors.at(day)
+			}
+		}
+		return growth, true
+	}
+	return 0, false
+}
+
+// compareBenchmark converte o fator do índice em retorno no período e excesso do TWR
+func compareBenchmark(index string, growth float64, perf *Performance) BenchmarkReturn {
+	result := BenchmarkReturn{
+		Index:  index,
+		Return: roundCents((growth - 1) * 100),
+	}
+	result.Excess = roundCents(perf.TWR - result.Return)
+	if perf.AnnualizedTWR != nil {
+		days := perf.To.Sub(perf.From).Hours()/24 + 1
+		annualized := roundCents((math.Pow(growth, 365/days) - 1) * 100)
+		result.Annualized = &annualized
+	}
+	return result
+}
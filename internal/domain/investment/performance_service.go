@@ -0,0 +1,270 @@
+package investment
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// PriceHistorySource fornece o histórico de cotações armazenado de um ativo
+type PriceHistorySource interface {
+	GetPriceHistory(ctx context.Context, symbol string, from, to time.Time) ([]*market.PriceHistory, error)
+}
+
+// PerformanceQuery define o período do cálculo: um período pré-definido ou datas explícitas
+type PerformanceQuery struct {
+	Period string
+	From   *time.Time
+	To     *time.Time
+}
+
+// performanceSource reúne as movimentações e a função de valor de um investimento
+type performanceSource struct {
+	flows   []CashFlow
+	valueAt ValueAt
+}
+
+// GetPerformance calcula TWR, XIRR e retorno anualizado de um investimento no período, comparados aos índices
+func (s *Service) GetPerformance(ctx context.Context, investmentID, userID ulid.ULID, query PerformanceQuery, now time.Time) (*Performance, error) {
+	inv, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
+	if err != nil {
+		return nil, appErrors.ErrInvestmentNotFound.WithError(err)
+	}
+
+	source, err := s.performanceSource(ctx, inv, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.measure(ctx, source.flows, source.valueAt, query, now)
+}
+
+// GetPortfolioPerformance calcula o desempenho da carteira inteira, somando valores e movimentações de todos os investimentos
+func (s *Service) GetPortfolioPerformance(ctx context.Context, userID ulid.ULID, query PerformanceQuery, now time.Time) (*Performance, error) {
+	investments, err := s.listAllInvestments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []CashFlow
+	values := make([]ValueAt, 0, len(investments))
+	for _, inv := range investments {
+		source, err := s.performanceSource(ctx, inv, now)
+		if err != nil {
+			return nil, err
+		}
+		flows = append(flows, source.flows...)
+		values = append(values, source.valueAt)
+	}
+
+	total := func(day time.Time) float64 {
+		sum := 0.0
+		for _, value := range values {
+			sum += value(day)
+		}
+		return sum
+	}
+	return s.measure(ctx, flows, total, query, now)
+}
+
+// measure resolve o período, calcula o desempenho e adiciona a comparação com os índices
+func (s *Service) measure(ctx context.Context, flows []CashFlow, valueAt ValueAt, query PerformanceQuery, now time.Time) (*Performance, error) {
+	today := truncateDay(now)
+	label := query.Period
+	if label == "" {
+		label = Period12M
+	}
+
+	var from time.Time
+	if query.From != nil {
+		from, label = truncateDay(*query.From), "CUSTOM"
+	} else {
+		start, ok := PeriodStart(query.Period, now)
+		if !ok {
+			return nil, appErrors.NewValidationError("period", "deve ser 1M, 3M, 6M, 12M, YTD ou ALL")
+		}
+		from = start
+	}
+
+	to := today
+	if query.To != nil && truncateDay(*query.To).Before(today) {
+		to = truncateDay(*query.To)
+	}
+
+	// O período começa na primeira movimentação quando o investimento é mais recente que o início pedido
+	if first, ok := firstFlowDate(flows); ok && first.After(from) {
+		from = first
+	} else if !ok {
+		from = to
+	}
+	if from.After(to) {
+		return nil, appErrors.NewValidationError("from", "deve ser anterior a data final")
+	}
+
+	perf := CalculatePerformance(flows, valueAt, from, to)
+	perf.Period = label
+
+	if s.Indexes == nil {
+		return perf, nil
+	}
+	for _, index := range BenchmarkIndexes {
+		series, err := s.indexSeries(ctx, index, from, to)
+		if err != nil {
+			logger.Warn().Err(err).Str("index", index).Msg("Serie de indice indisponivel para comparacao de desempenho")
+			continue
+		}
+		if growth, ok := BenchmarkGrowth(index, series, from, to); ok {
+			perf.Benchmarks = append(perf.Benchmarks, compareBenchmark(index, growth, perf))
+		}
+	}
+	return perf, nil
+}
+
+// performanceSource monta as movimentações do ponto de vista do investimento (aportes positivos,
+// resgates e proventos negativos) e a forma de avaliá-lo em datas passadas: cotações para posições,
+// o motor de renda fixa para títulos com indexador e, nos demais, a taxa interna de toda a vida do investimento.
+// Datas futuras usam o saldo atual.
+func (s *Service) performanceSource(ctx context.Context, inv *Investment, now time.Time) (*performanceSource, error) {
+	transactions, err := s.investmentTransactions(ctx, inv.Id, inv.UserId)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	var flows, principal []CashFlow
+	for _, tx := range transactions {
+		day := truncateDay(tx.Date)
+		switch tx.Type {
+		case transaction.Investment:
+			principal = append(principal, CashFlow{Date: day, Amount: math.Abs(tx.Amount)})
+		case transaction.Withdraw:
+			principal = append(principal, CashFlow{Date: day, Amount: -math.Abs(tx.Amount)})
+		case transaction.Receipt:
+			flows = append(flows, CashFlow{Date: day, Amount: -math.Abs(tx.Amount)})
+		}
+	}
+	flows = append(flows, principal...)
+
+	today := truncateDay(now)
+	var base ValueAt
+	switch {
+	case inv.IsPosition():
+		base, err = s.positionValue(ctx, inv, today)
+		if err != nil {
+			return nil, err
+		}
+	case inv.AccruesDaily():
+		base, err = s.accrualValue(ctx, inv, principal, today)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		lifetime := make([]CashFlow, 0, len(flows)+1)
+		for _, f := range flows {
+			lifetime = append(lifetime, CashFlow{Date: f.Date, Amount: -f.Amount})
+		}
+		lifetime = append(lifetime, CashFlow{Date: today.AddDate(0, 0, 1), Amount: inv.CurrentBalance})
+		rate, ok := XIRR(lifetime)
+		if !ok {
+			rate = 0
+		}
+		base = growthValue(flows, rate)
+	}
+
+	current := inv.CurrentBalance
+	return &performanceSource{
+		flows: flows,
+		valueAt: func(day time.Time) float64 {
+			if day.After(today) {
+				return current
+			}
+			return base(day)
+		},
+	}, nil
+}
+
+// positionValue avalia a posição pela quantidade dos lotes anteriores ao dia e a última cotação
+// armazenada antes dele; sem cotação, vale o preço da última operação
+func (s *Service) positionValue(ctx context.Context, inv *Investment, today time.Time) (ValueAt, error) {
+	lots, err := s.Repository.GetLots(ctx, inv.Id, inv.UserId)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	sort.SliceStable(lots, func(i, j int) bool { return lots[i].TradeDate.Before(lots[j].TradeDate) })
+
+	var prices []IndexPoint
+	if s.PriceHistory != nil && len(lots) > 0 {
+		history, err := s.PriceHistory.GetPriceHistory(ctx, inv.Ticker, truncateDay(lots[0].TradeDate), today)
+		if err != nil {
+			logger.Warn().Err(err).Str("ticker", inv.Ticker).Msg("Historico de cotacoes indisponivel para desempenho")
+		}
+		for _, p := range history {
+			prices = append(prices, IndexPoint{Date: truncateDay(p.Date), Value: p.Price})
+		}
+	}
+	series := NewIndexSeries(prices)
+
+	return func(day time.Time) float64 {
+		quantity, lastTrade := 0.0, 0.0
+		for _, lot := range lots {
+			if !truncateDay(lot.TradeDate).Before(day) {
+				break
+			}
+			lastTrade = lot.UnitPrice
+			if lot.Side == LotSell {
+				quantity -= lot.Quantity
+			} else {
+				quantity += lot.Quantity
+			}
+		}
+		if quantity <= quantityEpsilon {
+			return 0
+		}
+		if price, ok := series.valueAt(day.AddDate(0, 0, -1)); ok {
+			return quantity * price
+		}
+		return quantity * lastTrade
+	}, nil
+}
+
+// accrualValue avalia o título pelo valor bruto acumulado até o dia, sem as movimentações do próprio dia
+func (s *Service) accrualValue(ctx context.Context, inv *Investment, principal []CashFlow, today time.Time) (ValueAt, error) {
+	first, ok := firstFlowDate(principal)
+	if !ok {
+		return func(time.Time) float64 { return 0 }, nil
+	}
+
+	series, err := s.indexSeries(ctx, string(inv.Indexer), first, today)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(day time.Time) float64 {
+		before := make([]CashFlow, 0, len(principal))
+		for _, f := range principal {
+			if f.Date.Before(day) {
+				before = append(before, f)
+			}
+		}
+		return CalculateAccrual(inv, before, series, day).Gross
+	}, nil
+}
+
+func firstFlowDate(flows []CashFlow) (time.Time, bool) {
+	if len(flows) == 0 {
+		return time.Time{}, false
+	}
+	first := flows[0].Date
+	for _, f := range flows[1:] {
+		if f.Date.Before(first) {
+			first = f.Date
+		}
+	}
+	return truncateDay(first), true
+}
@@ -0,0 +1,135 @@
+package investment
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// quantityEpsilon absorve erros de ponto flutuante em quantidades fracionárias (cripto)
+const quantityEpsilon = 1e-9
+
+var ErrSellExceedsPosition = errors.New("quantidade vendida maior que a posicao")
+
+// Position é o resumo de uma posição calculada a partir dos lotes
+type Position struct {
+	Ticker            string  `json:"ticker"`
+	Quantity          float64 `json:"quantity"`
+	AverageCost       float64 `json:"averageCost"`
+	CostBasis         float64 `json:"costBasis"`
+	FIFOCostBasis     float64 `json:"fifoCostBasis"`
+	RealizedPnL       float64 `json:"realizedPnl"`
+	RealizedPnLFIFO   float64 `json:"realizedPnlFifo"`
+	MarketPrice       float64 `json:"marketPrice"`
+	MarketValue       float64 `json:"marketValue"`
+	UnrealizedPnL     float64 `json:"unrealizedPnl"`
+	UnrealizedPnLFIFO float64 `json:"unrealizedPnlFifo"`
+	UnrealizedPnLRate float64 `json:"unrealizedPnlRate"`
+	TotalBought       float64 `json:"totalBought"`
+	TotalSold         float64 `json:"totalSold"`
+	TotalFees         float64 `json:"totalFees"`
+	Lots              []*Lot  `json:"lots"`
+}
+
+type openLot struct {
+	quantity float64
+	unitCost float64
+}
+
+// CalculatePosition consolida os lotes em ordem cronológica pelos métodos de custo médio e FIFO.
+// As taxas de compra compõem o custo; as taxas de venda reduzem o valor realizado.
+// Quando marketPrice é zero, o preço da última operação é usado para avaliar a posição.
+func CalculatePosition(ticker string, lots []*Lot, marketPrice float64) (*Position, error) {
+	ordered := make([]*Lot, len(lots))
+	copy(ordered, lots)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].TradeDate.Equal(ordered[j].TradeDate) {
+			return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+		}
+		return ordered[i].TradeDate.Before(ordered[j].TradeDate)
+	})
+
+	position := &Position{Ticker: ticker, Lots: ordered}
+	var queue []openLot
+	lastPrice := 0.0
+
+	for _, lot := range ordered {
+		lastPrice = lot.UnitPrice
+		position.TotalFees += lot.Fees
+
+		switch lot.Side {
+		case LotBuy:
+			position.Quantity += lot.Quantity
+			position.CostBasis += lot.Gross() + lot.Fees
+			position.TotalBought += lot.Gross()
+			queue = append(queue, openLot{
+				quantity: lot.Quantity,
+				unitCost: (lot.Gross() + lot.Fees) / lot.Quantity,
+			})
+
+		case LotSell:
+			if lot.Quantity > position.Quantity+quantityEpsilon {
+				return nil, ErrSellExceedsPosition
+			}
+
+			proceeds := lot.Gross() - lot.Fees
+			averageCost := position.CostBasis / position.Quantity
+			position.RealizedPnL += proceeds - lot.Quantity*averageCost
+			position.CostBasis -= lot.Quantity * averageCost
+			position.Quantity -= lot.Quantity
+			position.TotalSold += lot.Gross()
+
+			remaining := lot.Quantity
+			consumedCost := 0.0
+			for remaining > quantityEpsilon && len(queue) > 0 {
+				take := math.Min(remaining, queue[0].quantity)
+				consumedCost += take * queue[0].unitCost
+				queue[0].quantity -= take
+				remaining -= take
+				if queue[0].quantity <= quantityEpsilon {
+					queue = queue[1:]
+				}
+			}
+			position.RealizedPnLFIFO += proceeds - consumedCost
+
+			if position.Quantity <= quantityEpsilon {
+				position.Quantity = 0
+				position.CostBasis = 0
+				queue = nil
+			}
+		}
+	}
+
+	for _, open := range queue {
+		position.FIFOCostBasis += open.quantity * open.unitCost
+	}
+
+	if position.Quantity > 0 {
+		position.AverageCost = position.CostBasis / position.Quantity
+	}
+
+	position.MarketPrice = marketPrice
+	if position.MarketPrice <= 0 {
+		position.MarketPrice = lastPrice
+	}
+	position.MarketValue = position.Quantity * position.MarketPrice
+	position.UnrealizedPnL = position.MarketValue - position.CostBasis
+	position.UnrealizedPnLFIFO = position.MarketValue - position.FIFOCostBasis
+	if position.CostBasis > 0 {
+		position.UnrealizedPnLRate = position.UnrealizedPnL / position.CostBasis * 100
+	}
+
+	position.round()
+	return position, nil
+}
+
+func (p *Position) round() {
+	for _, v := range []*float64{
+		&p.CostBasis, &p.FIFOCostBasis, &p.RealizedPnL, &p.RealizedPnLFIFO,
+		&p.MarketValue, &p.UnrealizedPnL, &p.UnrealizedPnLFIFO, &p.UnrealizedPnLRate,
+		&p.TotalBought, &p.TotalSold, &p.TotalFees,
+	} {
+		*v = math.Round(*v*100) / 100
+	}
+	p.AverageCost = math.Round(p.AverageCost*1e6) / 1e6
+}
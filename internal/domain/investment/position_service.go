@@ -0,0 +1,331 @@
+package investment
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// TradeRequest descreve uma compra ou venda de um ativo da posição
+type TradeRequest struct {
+	InvestmentId ulid.ULID
+	AccountId    ulid.ULID
+	UserId       ulid.ULID
+	Quantity     float64
+	UnitPrice    float64
+	Fees         float64
+	TradeDate    time.Time
+	Description  string
+}
+
+// IsPosition indica se o investimento é controlado por ticker e lotes
+func (i *Investment) IsPosition() bool {
+	return i.Ticker != ""
+}
+
+// NormalizeTicker padroniza o código do ativo (ex.: " petr4 " -> "PETR4")
+func NormalizeTicker(ticker string) string {
+	return strings.ToUpper(strings.TrimSpace(ticker))
+}
+
+// Buy registra um lote de compra debitando a conta pelo valor bruto mais taxas
+func (s *Service) Buy(ctx context.Context, req TradeRequest) (*Position, error) {
+	inv, err := s.getPositionInvestment(ctx, req.InvestmentId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateTrade(req); err != nil {
+		return nil, err
+	}
+
+	lot := newLot(req, LotBuy)
+	total := roundCents(lot.Total())
+
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, req.AccountId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if accountEntity.Type == account.TypeCreditCard {
+		return nil, appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+	}
+	if accountEntity.Balance < total {
+		return nil, appErrors.NewValidationError("quantity", "saldo insuficiente na conta")
+	}
+
+	description := req.Description
+	if strings.TrimSpace(description) == "" {
+		description = "Compra " + inv.Ticker
+	}
+
+	if err := s.AccountService.UpdateBalance(ctx, req.AccountId, req.UserId, -total); err != nil {
+		return nil, err
+	}
+
+	movement := s.createMovementTransaction(inv.Id, req.AccountId, req.UserId, total, description, transaction.Investment)
+	movement.Date = lot.TradeDate
+	if err := s.TransactionRepo.Create(ctx, movement); err != nil {
+		_ = s.AccountService.UpdateBalance(ctx, req.AccountId, req.UserId, total)
+		return nil, err
+	}
+
+	lot.TransactionId = &movement.Id
+	if err := s.Repository.CreateLot(ctx, lot); err != nil {
+		_ = s.TransactionRepo.Delete(ctx, movement.Id)
+		_ = s.AccountService.UpdateBalance(ctx, req.AccountId, req.UserId, total)
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	inv.LastPrice = req.UnitPrice
+	return s.refreshPosition(ctx, inv)
+}
+
+// Sell registra um lote de venda, apura o IR (operação comum ou day-trade) e credita na conta
+// o valor da venda menos taxas e imposto
+func (s *Service) Sell(ctx context.Context, req TradeRequest) (*Position, *Redemption, error) {
+	inv, err := s.getPositionInvestment(ctx, req.InvestmentId, req.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateTrade(req); err != nil {
+		return nil, nil, err
+	}
+
+	lots, err := s.Repository.GetLots(ctx, inv.Id, req.UserId)
+	if err != nil {
+		return nil, nil, appErrors.NewDatabaseError(err)
+	}
+
+	lot := newLot(req, LotSell)
+	if _, err := CalculatePosition(inv.Ticker, append(lots, lot), req.UnitPrice); err != nil {
+		if errors.Is(err, ErrSellExceedsPosition) {
+			return nil, nil, appErrors.NewValidationError("quantity", "quantidade maior que a posicao na data da venda")
+		}
+		return nil, nil, err
+	}
+
+	proceeds := roundCents(lot.Total())
+	if proceeds < 0 {
+		return nil, nil, appErrors.NewValidationError("fees", "taxas maiores que o valor da venda")
+	}
+
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, req.AccountId, req.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if accountEntity.Type == account.TypeCreditCard {
+		return nil, nil, appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+	}
+
+	monthSales, err := s.monthlySales(ctx, inv, req.UserId, lot.TradeDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	tax := AssessSale(inv.Type, inv.Ticker, lots, lot, monthSales)
+
+	description := req.Description
+	if strings.TrimSpace(description) == "" {
+		description = "Venda " + inv.Ticker
+	}
+
+	var (
+		position   *Position
+		redemption *Redemption
+	)
+	err = shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.AccountService.UpdateBalance(ctx, req.AccountId, req.UserId, tax.Net); err != nil {
+			return err
+		}
+
+		movement := s.createMovementTransaction(inv.Id, req.AccountId, req.UserId, proceeds, description, transaction.Withdraw)
+		movement.Date = lot.TradeDate
+		if err := s.TransactionRepo.Create(ctx, movement); err != nil {
+			return err
+		}
+
+		lot.TransactionId = &movement.Id
+		if err := s.Repository.CreateLot(ctx, lot); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+
+		var err error
+		redemption, err = s.recordRedemption(ctx, inv, req.AccountId, req.UserId, movement, RedemptionSale, tax)
+		if err != nil {
+			return err
+		}
+
+		inv.LastPrice = req.UnitPrice
+		position, err = s.refreshPosition(ctx, inv)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return position, redemption, nil
+}
+
+// GetPosition retorna a posição consolidada com os lotes do investimento
+func (s *Service) GetPosition(ctx context.Context, investmentID, userID ulid.ULID) (*Position, error) {
+	inv, err := s.getPositionInvestment(ctx, investmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := s.Repository.GetLots(ctx, inv.Id, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	return CalculatePosition(inv.Ticker, lots, inv.LastPrice)
+}
+
+// refreshPosition recalcula quantidade, saldo e retorno do investimento a partir dos lotes.
+// O retorno segue a mesma regra dos demais investimentos: saldo atual menos o valor líquido aportado.
+func (s *Service) refreshPosition(ctx context.Context, inv *Investment) (*Position, error) {
+	lots, err := s.Repository.GetLots(ctx, inv.Id, inv.UserId)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	position, err := CalculatePosition(inv.Ticker, lots, inv.LastPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	netInvested := 0.0
+	for _, lot := range lots {
+		if lot.Side == LotBuy {
+			netInvested += lot.Total()
+		} else {
+			netInvested -= lot.Total()
+		}
+	}
+
+	returnBalance := roundCents(position.MarketValue - netInvested)
+	returnRate := 0.0
+	if netInvested > 0 {
+		returnRate = math.Round(returnBalance/netInvested*10000) / 100
+	}
+
+	now := time.Now()
+	if err := s.Repository.UpdateFields(ctx, inv.Id, inv.UserId, map[string]interface{}{
+		"quantity":        position.Quantity,
+		"last_price":      inv.LastPrice,
+		"current_balance": position.MarketValue,
+		"return_balance":  returnBalance,
+		"return_rate":     returnRate,
+		"updated_at":      now,
+	}); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	inv.Quantity = position.Quantity
+	inv.CurrentBalance = position.MarketValue
+	inv.ReturnBalance = returnBalance
+	inv.ReturnRate = returnRate
+	inv.UpdatedAt = now
+
+	if err := s.syncGoal(ctx, inv); err != nil {
+		return nil, err
+	}
+	return position, nil
+}
+
+// deleteLotByTransaction remove o lote ligado a uma movimentação excluída e recalcula a posição
+func (s *Service) deleteLotByTransaction(ctx context.Context, inv *Investment, transactionID ulid.ULID) (bool, error) {
+	lot, err := s.Repository.GetLotByTransactionID(ctx, transactionID, inv.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, appErrors.NewDatabaseError(err)
+	}
+
+	lots, err := s.Repository.GetLots(ctx, inv.Id, inv.UserId)
+	if err != nil {
+		return false, appErrors.NewDatabaseError(err)
+	}
+
+	remaining := make([]*Lot, 0, len(lots))
+	for _, l := range lots {
+		if l.Id != lot.Id {
+			remaining = append(remaining, l)
+		}
+	}
+	if _, err := CalculatePosition(inv.Ticker, remaining, inv.LastPrice); err != nil {
+		return false, appErrors.NewValidationError("transaction", "remover esta compra deixaria vendas posteriores sem posicao")
+	}
+
+	if err := s.Repository.DeleteLot(ctx, lot.Id); err != nil {
+		return false, appErrors.NewDatabaseError(err)
+	}
+
+	_, err = s.refreshPosition(ctx, inv)
+	return true, err
+}
+
+func (s *Service) getPositionInvestment(ctx context.Context, investmentID, userID ulid.ULID) (*Investment, error) {
+	inv, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.ErrInvestmentNotFound.WithError(err)
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	if !inv.IsPosition() {
+		return nil, appErrors.NewValidationError("investment", "investimento nao possui ticker para controle por lotes")
+	}
+	return inv, nil
+}
+
+func validateTrade(req TradeRequest) error {
+	if req.Quantity <= 0 {
+		return appErrors.NewValidationError("quantity", "deve ser maior que zero")
+	}
+	if req.UnitPrice <= 0 {
+		return appErrors.NewValidationError("unit_price", "deve ser maior que zero")
+	}
+	if req.Fees < 0 {
+		return appErrors.NewValidationError("fees", "nao pode ser negativo")
+	}
+	if req.TradeDate.After(time.Now()) {
+		return appErrors.NewValidationError("trade_date", "nao pode ser futura")
+	}
+	return nil
+}
+
+func newLot(req TradeRequest, side LotSide) *Lot {
+	tradeDate := req.TradeDate
+	if tradeDate.IsZero() {
+		tradeDate = time.Now()
+	}
+	return &Lot{
+		Id:           pkg.GenerateULIDObject(),
+		InvestmentId: req.InvestmentId,
+		UserId:       req.UserId,
+		AccountId:    req.AccountId,
+		Side:         side,
+		Quantity:     req.Quantity,
+		UnitPrice:    req.UnitPrice,
+		Fees:         req.Fees,
+		TradeDate:    tradeDate,
+		CreatedAt:    time.Now(),
+	}
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,41 @@
+package investment
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type RedemptionKind string
+
+const (
+	RedemptionWithdraw RedemptionKind = "WITHDRAW"
+	RedemptionSale     RedemptionKind = "SALE"
+)
+
+// Redemption registra a apuração de impostos de um resgate ou de uma venda.
+// O valor bruto sai do investimento, os impostos viram movimentações TAX e a conta recebe o líquido.
+type Redemption struct {
+	Id                     ulid.ULID      `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId                 ulid.ULID      `gorm:"type:varchar(26);index:idx_investment_redemptions_user_date,priority:1;not null" json:"userId"`
+	InvestmentId           ulid.ULID      `gorm:"type:varchar(26);index:idx_investment_redemptions_investment_id;not null" json:"investmentId"`
+	TransactionId          ulid.ULID      `gorm:"type:varchar(26);uniqueIndex:idx_investment_redemptions_transaction_id;not null" json:"transactionId"`
+	IncomeTaxTransactionId *ulid.ULID     `gorm:"type:varchar(26)" json:"incomeTaxTransactionId,omitempty"`
+	IOFTransactionId       *ulid.ULID     `gorm:"type:varchar(26)" json:"iofTransactionId,omitempty"`
+	Kind                   RedemptionKind `gorm:"type:varchar(10);not null" json:"kind"`
+	InvestmentType         Types          `gorm:"type:varchar(20);not null" json:"investmentType"`
+	Gross                  float64        `gorm:"type:decimal(15,2);not null" json:"gross"`
+	CostBasis              float64        `gorm:"type:decimal(15,2);not null" json:"costBasis"`
+	Gain                   float64        `gorm:"type:decimal(15,2);not null" json:"gain"`
+	DayTradeGain           float64        `gorm:"type:decimal(15,2);not null;default:0" json:"dayTradeGain"`
+	IOF                    float64        `gorm:"type:decimal(15,2);not null;default:0" json:"iof"`
+	IncomeTax              float64        `gorm:"type:decimal(15,2);not null;default:0" json:"incomeTax"`
+	Net                    float64        `gorm:"type:decimal(15,2);not null" json:"net"`
+	Exempt                 bool           `gorm:"not null;default:false" json:"exempt"`
+	Date                   time.Time      `gorm:"type:date;not null;index:idx_investment_redemptions_user_date,priority:2" json:"date"`
+	CreatedAt              time.Time      `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (Redemption) TableName() string {
+	return "investment_redemptions"
+}
@@ -0,0 +1,142 @@
+package investment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// redemptionTranches retorna as parcelas aplicadas com o valor atual de cada uma.
+// Com indexador, vem do rendimento acumulado; sem indexador, o saldo atual é rateado
+// entre os aportes na proporção do principal.
+func (s *Service) redemptionTranches(ctx context.Context, inv *Investment, at time.Time) ([]Tranche, error) {
+	if inv.AccruesDaily() {
+		accrual, err := s.calculateAccrual(ctx, inv, at, nil)
+		if err != nil {
+			return nil, err
+		}
+		return accrual.Tranches, nil
+	}
+
+	flows, err := s.cashFlows(ctx, inv, nil)
+	if err != nil {
+		return nil, err
+	}
+	principal := CalculateAccrual(&Investment{Type: inv.Type}, flows, nil, at)
+	if principal.Gross <= 0 {
+		return nil, nil
+	}
+
+	scale := inv.CurrentBalance / principal.Gross
+	tranches := make([]Tranche, 0, len(principal.Tranches))
+	for _, t := range principal.Tranches {
+		tranches = append(tranches, Tranche{Date: t.Date, Principal: t.Principal, Gross: t.Gross * scale})
+	}
+	return tranches, nil
+}
+
+// recordRedemption grava as movimentações de IR e IOF e a apuração ligada à movimentação do valor bruto.
+// O crédito do valor líquido na conta fica a cargo de quem chama.
+func (s *Service) recordRedemption(ctx context.Context, inv *Investment, accountID, userID ulid.ULID, movement *transaction.Transaction, kind RedemptionKind, tax RedemptionTax) (*Redemption, error) {
+	redemption := &Redemption{
+		Id:             pkg.GenerateULIDObject(),
+		UserId:         userID,
+		InvestmentId:   inv.Id,
+		TransactionId:  movement.Id,
+		Kind:           kind,
+		InvestmentType: inv.Type,
+		Gross:          tax.Gross,
+		CostBasis:      tax.CostBasis,
+		Gain:           tax.Gain,
+		DayTradeGain:   tax.DayTradeGain,
+		IOF:            tax.IOF,
+		IncomeTax:      tax.IncomeTax,
+		Net:            tax.Net,
+		Exempt:         tax.Exempt,
+		Date:           movement.Date,
+	}
+
+	if tax.IncomeTax > 0 {
+		taxMovement := s.createTaxTransaction(inv, accountID, userID, tax.IncomeTax, "IR sobre "+movement.Description, movement.Date)
+		if err := s.TransactionRepo.Create(ctx, taxMovement); err != nil {
+			return nil, err
+		}
+		redemption.IncomeTaxTransactionId = &taxMovement.Id
+	}
+
+	if tax.IOF > 0 {
+		taxMovement := s.createTaxTransaction(inv, accountID, userID, tax.IOF, "IOF sobre "+movement.Description, movement.Date)
+		if err := s.TransactionRepo.Create(ctx, taxMovement); err != nil {
+			return nil, err
+		}
+		redemption.IOFTransactionId = &taxMovement.Id
+	}
+
+	if err := s.Repository.CreateRedemption(ctx, redemption); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return redemption, nil
+}
+
+// deleteRedemptionByTransaction remove a apuração e as movimentações de imposto de um resgate excluído
+func (s *Service) deleteRedemptionByTransaction(ctx context.Context, transactionID, userID ulid.ULID) error {
+	redemption, err := s.Repository.GetRedemptionByTransactionID(ctx, transactionID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+
+	for _, taxID := range []*ulid.ULID{redemption.IncomeTaxTransactionId, redemption.IOFTransactionId} {
+		if taxID == nil {
+			continue
+		}
+		if err := s.TransactionRepo.Delete(ctx, *taxID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Repository.DeleteRedemption(ctx, redemption.Id); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// monthlySales soma as vendas do mês no mesmo grupo de isenção, antes da venda atual
+func (s *Service) monthlySales(ctx context.Context, inv *Investment, userID ulid.ULID, date time.Time) (float64, error) {
+	if !inv.Type.HasMonthlySalesExemption() {
+		return 0, nil
+	}
+	from := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	total, err := s.Repository.SumMonthlySales(ctx, userID, inv.Type, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+	return total, nil
+}
+
+func (s *Service) createTaxTransaction(inv *Investment, accountID, userID ulid.ULID, amount float64, description string, date time.Time) *transaction.Transaction {
+	now := pkg.SetTimestamps()
+	investmentID := inv.Id
+
+	return &transaction.Transaction{
+		Id:           pkg.GenerateULIDObject(),
+		UserId:       userID,
+		AccountId:    accountID,
+		Type:         transaction.Tax,
+		Amount:       -amount,
+		Description:  description,
+		Date:         date,
+		InvestmentId: &investmentID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
@@ -2,6 +2,7 @@ package investment
 
 import (
 	"context"
+	"time"
 
 	"Fynance/internal/pkg"
 
@@ -22,4 +23,35 @@ type InvestmentRepository interface {
 	GetTotalBalance(ctx context.Context, userId ulid.ULID) (float64, error)
 	GetByType(ctx context.Context, userId ulid.ULID, investmentType Types, pagination *pkg.PaginationParams) ([]*Investment, int64, error)
 	UpdateBalanceAtomic(ctx context.Context, investmentID ulid.ULID, delta float64) error
+	ListPositions(ctx context.Context) ([]*Investment, error)
+	ListAccruing(ctx context.Context) ([]*Investment, error)
+	GetByGoalID(ctx context.Context, goalID ulid.ULID, userId ulid.ULID) ([]*Investment, error)
+	SetGoal(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID, goalID *ulid.ULID) error
+	UpdateFields(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID, fields map[string]interface{}) error
+	CreateLot(ctx context.Context, lot *Lot) error
+	GetLots(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) ([]*Lot, error)
+	GetLotByTransactionID(ctx context.Context, transactionID ulid.ULID, userId ulid.ULID) (*Lot, error)
+	DeleteLot(ctx context.Context, lotID ulid.ULID) error
+	DeleteLotsByInvestment(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) error
+	SumMonthlySales(ctx context.Context, userId ulid.ULID, investmentType Types, from, to time.Time) (float64, error)
+	CreateRedemption(ctx context.Context, redemption *Redemption) error
+	GetRedemptionByTransactionID(ctx context.Context, transactionID ulid.ULID, userId ulid.ULID) (*Redemption, error)
+	ListUserRedemptions(ctx context.Context, userId ulid.ULID, from, to time.Time) ([]*Redemption, error)
+	DeleteRedemption(ctx context.Context, redemptionID ulid.ULID) error
+	DeleteRedemptionsByInvestment(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) error
+	CreateIncomeEvent(ctx context.Context, event *IncomeEvent) error
+	UpdateIncomeEvent(ctx context.Context, event *IncomeEvent) error
+	GetIncomeEvent(ctx context.Context, eventID ulid.ULID, userId ulid.ULID) (*IncomeEvent, error)
+	GetIncomeEventByTransactionID(ctx context.Context, transactionID ulid.ULID, userId ulid.ULID) (*IncomeEvent, error)
+	ListIncomeEvents(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) ([]*IncomeEvent, error)
+	ListUserIncomeEvents(ctx context.Context, userId ulid.ULID, status IncomeStatus, from, to time.Time) ([]*IncomeEvent, error)
+	ListDueIncomeEvents(ctx context.Context, until time.Time) ([]*IncomeEvent, error)
+	DeleteIncomeEvent(ctx context.Context, eventID ulid.ULID) error
+	DeleteIncomeEventsByInvestment(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) error
+	ListAllocationTargets(ctx context.Context, userId ulid.ULID) ([]*AllocationTarget, error)
+	ReplaceAllocationTargets(ctx context.Context, userId ulid.ULID, targets []*AllocationTarget) error
+	CreateBrokerageNote(ctx context.Context, note *BrokerageNote) error
+	GetBrokerageNote(ctx context.Context, userId ulid.ULID, number string) (*BrokerageNote, error)
+	UpdateBrokerageNoteProgress(ctx context.Context, noteID ulid.ULID, importedTrades int, status NoteStatus) error
+	ListBrokerageNotes(ctx context.Context, userId ulid.ULID) ([]*BrokerageNote, error)
 }
@@ -7,6 +7,7 @@ import (
 
 	"Fynance/internal/contracts"
 	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/category"
 	"Fynance/internal/domain/shared"
 	"Fynance/internal/domain/transaction"
 	appErrors "Fynance/internal/errors"
@@ -15,20 +16,38 @@ import (
 	"github.com/oklog/ulid/v2"
 )
 
+// transactionPageSize é o tamanho da página usada para ler o histórico completo de movimentações
+const transactionPageSize = 500
+
 type Service struct {
 	Repository      InvestmentRepository
 	TransactionRepo transaction.TransactionRepository
 	AccountService  account.AccountServiceInterface
+	CategoryService *category.Service
+	// GoalSync é opcional e recalcula metas lastreadas quando o saldo muda
+	GoalSync shared.GoalInvestmentSyncer
+	// Prices é opcional e alimenta a marcação a mercado das posições
+	Prices PriceSource
+	// Indexes é opcional e fornece CDI, SELIC e IPCA para o rendimento da renda fixa
+	// e os índices de comparação de desempenho
+	Indexes IndexSource
+	// PriceHistory é opcional e avalia posições em datas passadas no cálculo de desempenho
+	PriceHistory PriceHistorySource
+	// Events é opcional e publica os investimentos criados; as conquistas reagem a eles
+	Events shared.EventPublisher
+	// Transactor é opcional e grava aplicações, aportes, resgates e vendas de forma atômica
+	Transactor shared.Transactor
 	shared.BaseService
 }
 
 var _ shared.InvestmentTransactionDeleter = (*Service)(nil)
 
-func NewService(repo InvestmentRepository, transactionRepo transaction.TransactionRepository, accountService account.AccountServiceInterface, userChecker *shared.UserCheckerService) *Service {
+func NewService(repo InvestmentRepository, transactionRepo transaction.TransactionRepository, accountService account.AccountServiceInterface, categoryService *category.Service, userChecker *shared.UserCheckerService) *Service {
 	return &Service{
 		Repository:      repo,
 		TransactionRepo: transactionRepo,
 		AccountService:  accountService,
+		CategoryService: categoryService,
 		BaseService: shared.BaseService{
 			UserChecker: userChecker,
 		},
@@ -49,6 +68,25 @@ func (s *Service) CreateInvestment(ctx context.Context, req contracts.CreateInve
 		return nil, appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
 	}
 
+	req.Ticker = NormalizeTicker(req.Ticker)
+	if req.Ticker != "" {
+		if !Types(req.Type).IsTradable() {
+			return nil, appErrors.NewValidationError("ticker", "apenas acoes, FIIs, ETFs, BDRs e criptomoedas possuem ticker")
+		}
+		if err := validateTrade(TradeRequest{Quantity: req.Quantity, UnitPrice: req.UnitPrice, Fees: req.Fees, TradeDate: req.TradeDate}); err != nil {
+			return nil, err
+		}
+		req.InitialAmount = roundCents(req.Quantity*req.UnitPrice + req.Fees)
+	} else if req.InitialAmount <= 0 {
+		return nil, appErrors.NewValidationError("initial_amount", "deve ser maior que zero")
+	}
+
+	if req.Indexer != "" {
+		if err := validateFixedIncome(Types(req.Type), Indexer(req.Indexer), req.IndexerRate, req.MaturityDate, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
 	if accountEntity.Balance < req.InitialAmount {
 		return nil, appErrors.NewValidationError("initial_amount", "saldo insuficiente na conta")
 	}
@@ -62,34 +100,74 @@ func (s *Service) CreateInvestment(ctx context.Context, req contracts.CreateInve
 	investmentID := pkg.GenerateULIDObject()
 	entity := s.createInvestmentEntity(req, investmentID)
 
-	if err := s.Repository.Create(ctx, entity); err != nil {
-		return nil, err
-	}
+	err = shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.Repository.Create(ctx, entity); err != nil {
+			return err
+		}
 
-	if err := s.AccountService.UpdateBalance(ctx, req.AccountId, req.UserId, -req.InitialAmount); err != nil {
-		_ = s.Repository.Delete(ctx, investmentID, req.UserId)
-		return nil, err
-	}
+		if err := s.AccountService.UpdateBalance(ctx, req.AccountId, req.UserId, -req.InitialAmount); err != nil {
+			return err
+		}
+
+		movement := s.createInitialTransaction(req, investmentID)
+		if err := s.TransactionRepo.Create(ctx, movement); err != nil {
+			return err
+		}
+
+		if entity.IsPosition() {
+			lot := newLot(TradeRequest{
+				InvestmentId: investmentID,
+				AccountId:    req.AccountId,
+				UserId:       req.UserId,
+				Quantity:     req.Quantity,
+				UnitPrice:    req.UnitPrice,
+				Fees:         req.Fees,
+				TradeDate:    req.TradeDate,
+			}, LotBuy)
+			lot.TransactionId = &movement.Id
+			if err := s.Repository.CreateLot(ctx, lot); err != nil {
+				return appErrors.NewDatabaseError(err)
+			}
+			if _, err := s.refreshPosition(ctx, entity); err != nil {
+				return err
+			}
+		}
 
-	movement := s.createInitialTransaction(req, investmentID)
-	if err := s.TransactionRepo.Create(ctx, movement); err != nil {
-		_ = s.Repository.Delete(ctx, investmentID, req.UserId)
-		_ = s.AccountService.UpdateBalance(ctx, req.AccountId, req.UserId, req.InitialAmount)
+		return s.publishCreated(ctx, entity)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return entity, nil
 }
 
+// publishCreated publica o investimento na transação do ctx, então o evento só existe se a aplicação
+// for gravada
+func (s *Service) publishCreated(ctx context.Context, entity *Investment) error {
+	if s.Events == nil {
+		return nil
+	}
+	if err := s.Events.Publish(ctx, entity.UserId, shared.EventInvestmentCreated, entity); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
 func (s *Service) MakeContribution(ctx context.Context, investmentID, accountID, userID ulid.ULID, amount float64, description string) error {
 	if amount <= 0 {
 		return appErrors.NewValidationError("amount", "deve ser maior que zero")
 	}
 
-	if _, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID); err != nil {
+	investment, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
+	if err != nil {
 		return err
 	}
 
+	if investment.IsPosition() {
+		return appErrors.NewValidationError("investment", "use compra de lotes para investimentos com ticker")
+	}
+
 	accountEntity, err := s.AccountService.GetAccountByID(ctx, accountID, userID)
 	if err != nil {
 		return err
@@ -103,64 +181,94 @@ func (s *Service) MakeContribution(ctx context.Context, investmentID, accountID,
 		return appErrors.NewValidationError("amount", "Saldo insuficiente na conta")
 	}
 
-	if err := s.AccountService.UpdateBalance(ctx, accountID, userID, -amount); err != nil {
-		return err
-	}
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.AccountService.UpdateBalance(ctx, accountID, userID, -amount); err != nil {
+			return err
+		}
 
-	movement := s.createMovementTransaction(investmentID, accountID, userID, amount, description, transaction.Investment)
-	if err := s.TransactionRepo.Create(ctx, movement); err != nil {
-		_ = s.AccountService.UpdateBalance(ctx, accountID, userID, amount)
-		return err
-	}
+		movement := s.createMovementTransaction(investmentID, accountID, userID, amount, description, transaction.Investment)
+		if err := s.TransactionRepo.Create(ctx, movement); err != nil {
+			return err
+		}
 
-	if err := s.Repository.UpdateBalanceAtomic(ctx, investmentID, amount); err != nil {
-		_ = s.AccountService.UpdateBalance(ctx, accountID, userID, amount)
-		return err
-	}
+		if err := s.Repository.UpdateBalanceAtomic(ctx, investmentID, amount); err != nil {
+			return err
+		}
 
-	return nil
+		if investment.AccruesDaily() {
+			_, err := s.refreshAccrual(ctx, investment, time.Now(), nil)
+			return err
+		}
+		return s.syncGoal(ctx, investment)
+	})
 }
 
-func (s *Service) MakeWithdraw(ctx context.Context, investmentID, accountID, userID ulid.ULID, amount float64, description string) error {
+// MakeWithdraw resgata o valor bruto do investimento, retém IOF e IR conforme o tipo e o prazo
+// de cada aporte e credita o valor líquido na conta
+func (s *Service) MakeWithdraw(ctx context.Context, investmentID, accountID, userID ulid.ULID, amount float64, description string) (*Redemption, error) {
 	if amount <= 0 {
-		return appErrors.NewValidationError("amount", "deve ser maior que zero")
+		return nil, appErrors.NewValidationError("amount", "deve ser maior que zero")
 	}
 
 	investment, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if investment.IsPosition() {
+		return nil, appErrors.NewValidationError("investment", "use venda de lotes para investimentos com ticker")
 	}
 
 	if investment.CurrentBalance < amount {
-		return appErrors.NewValidationError("amount", "Saldo insuficiente no investimento")
+		return nil, appErrors.NewValidationError("amount", "Saldo insuficiente no investimento")
 	}
 
 	accountEntity, err := s.AccountService.GetAccountByID(ctx, accountID, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if accountEntity.Type == account.TypeCreditCard {
-		return appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+		return nil, appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
 	}
 
-	if err := s.Repository.UpdateBalanceAtomic(ctx, investmentID, -amount); err != nil {
-		return err
+	now := time.Now()
+	tranches, err := s.redemptionTranches(ctx, investment, now)
+	if err != nil {
+		return nil, err
 	}
+	tax := AssessFixedIncomeRedemption(investment.Type, tranches, amount, now)
 
-	if err := s.AccountService.UpdateBalance(ctx, accountID, userID, amount); err != nil {
-		_ = s.Repository.UpdateBalanceAtomic(ctx, investmentID, amount)
-		return err
-	}
+	var redemption *Redemption
+	err = shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.Repository.UpdateBalanceAtomic(ctx, investmentID, -amount); err != nil {
+			return err
+		}
 
-	movement := s.createMovementTransaction(investmentID, accountID, userID, amount, description, transaction.Withdraw)
-	if err := s.TransactionRepo.Create(ctx, movement); err != nil {
-		_ = s.Repository.UpdateBalanceAtomic(ctx, investmentID, amount)
-		_ = s.AccountService.UpdateBalance(ctx, accountID, userID, -amount)
-		return err
-	}
+		if err := s.AccountService.UpdateBalance(ctx, accountID, userID, tax.Net); err != nil {
+			return err
+		}
 
-	return nil
+		movement := s.createMovementTransaction(investmentID, accountID, userID, amount, description, transaction.Withdraw)
+		if err := s.TransactionRepo.Create(ctx, movement); err != nil {
+			return err
+		}
+
+		redemption, err = s.recordRedemption(ctx, investment, accountID, userID, movement, RedemptionWithdraw, tax)
+		if err != nil {
+			return err
+		}
+
+		if investment.AccruesDaily() {
+			_, err := s.refreshAccrual(ctx, investment, now, nil)
+			return err
+		}
+		return s.syncGoal(ctx, investment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redemption, nil
 }
 
 func (s *Service) ListInvestments(ctx context.Context, userID ulid.ULID, filters *InvestmentFilters, pagination *pkg.PaginationParams) ([]*Investment, int64, error) {
@@ -177,8 +285,21 @@ func (s *Service) GetInvestment(ctx context.Context, investmentID, userID ulid.U
 	return s.Repository.GetInvestmentByID(ctx, investmentID, userID)
 }
 
+// CheckAcceptsContributions confere se o investimento existe e recebe aportes em valor; investimentos
+// com ticker são movimentados pela compra de lotes
+func (s *Service) CheckAcceptsContributions(ctx context.Context, investmentID, userID ulid.ULID) error {
+	investment, err := s.GetInvestment(ctx, investmentID, userID)
+	if err != nil {
+		return err
+	}
+	if investment.IsPosition() {
+		return appErrors.NewValidationError("investment", "use compra de lotes para investimentos com ticker")
+	}
+	return nil
+}
+
 func (s *Service) GetTotalInvested(ctx context.Context, investmentID, userID ulid.ULID) (float64, error) {
-	transactions, _, err := s.TransactionRepo.GetByInvestmentID(ctx, investmentID, userID, nil)
+	transactions, err := s.investmentTransactions(ctx, investmentID, userID)
 	if err != nil {
 		return 0, err
 	}
@@ -196,6 +317,21 @@ func (s *Service) GetTotalInvested(ctx context.Context, investmentID, userID uli
 	return total, nil
 }
 
+// investmentTransactions carrega todas as movimentações do investimento, página a página
+func (s *Service) investmentTransactions(ctx context.Context, investmentID, userID ulid.ULID) ([]*transaction.Transaction, error) {
+	var all []*transaction.Transaction
+	for page := 1; ; page++ {
+		transactions, total, err := s.TransactionRepo.GetByInvestmentID(ctx, investmentID, userID, &pkg.PaginationParams{Page: page, Limit: transactionPageSize})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, transactions...)
+		if len(transactions) == 0 || int64(len(all)) >= total {
+			return all, nil
+		}
+	}
+}
+
 func (s *Service) CalculateReturn(ctx context.Context, investmentID, userID ulid.ULID) (float64, float64, error) {
 	investment, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
 	if err != nil {
@@ -227,7 +363,7 @@ func (s *Service) DeleteInvestment(ctx context.Context, investmentID, userID uli
 		return appErrors.NewValidationError("investment", "Não é possível excluir investimento com saldo. Faça um resgate total antes de excluir.")
 	}
 
-	transactions, _, err := s.TransactionRepo.GetByInvestmentID(ctx, investmentID, userID, nil)
+	transactions, err := s.investmentTransactions(ctx, investmentID, userID)
 	if err != nil {
 		return err
 	}
@@ -238,6 +374,18 @@ func (s *Service) DeleteInvestment(ctx context.Context, investmentID, userID uli
 		}
 	}
 
+	if err := s.Repository.DeleteLotsByInvestment(ctx, investmentID, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeleteRedemptionsByInvestment(ctx, investmentID, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeleteIncomeEventsByInvestment(ctx, investmentID, userID); err != nil {
+		return err
+	}
+
 	return s.Repository.Delete(ctx, investmentID, userID)
 }
 
@@ -251,11 +399,34 @@ func (s *Service) DeleteInvestmentTransactionByTransactionId(ctx context.Context
 		return nil
 	}
 
+	if tx.Type == transaction.Receipt {
+		return s.deleteIncomeByTransaction(ctx, transactionID, userID)
+	}
+
 	investment, err := s.Repository.GetInvestmentByID(ctx, *tx.InvestmentId, userID)
 	if err != nil {
 		return err
 	}
 
+	if tx.Type == transaction.Withdraw {
+		if err := s.deleteRedemptionByTransaction(ctx, transactionID, userID); err != nil {
+			return err
+		}
+	}
+
+	if investment.IsPosition() {
+		_, err := s.deleteLotByTransaction(ctx, investment, transactionID)
+		return err
+	}
+
+	if investment.AccruesDaily() {
+		if tx.Type != transaction.Investment && tx.Type != transaction.Withdraw {
+			return nil
+		}
+		_, err := s.refreshAccrual(ctx, investment, time.Now(), &transactionID)
+		return err
+	}
+
 	var balanceDelta float64
 	var totalInvestedDelta float64
 	switch tx.Type {
@@ -294,7 +465,11 @@ func (s *Service) DeleteInvestmentTransactionByTransactionId(ctx context.Context
 	}
 
 	investment.UpdatedAt = time.Now()
-	return s.Repository.Update(ctx, investment)
+	if err := s.Repository.Update(ctx, investment); err != nil {
+		return err
+	}
+
+	return s.syncGoal(ctx, investment)
 }
 
 func (s *Service) UpdateInvestment(ctx context.Context, investmentID, userID ulid.ULID, req contracts.UpdateInvestmentRequestDomain) error {
@@ -312,9 +487,16 @@ func (s *Service) UpdateInvestment(ctx context.Context, investmentID, userID uli
 	}
 
 	if req.Type != nil && *req.Type != "" {
+		if investment.AccruesDaily() && !Types(*req.Type).IsFixedIncome() {
+			return appErrors.NewValidationError("type", "investimento com indexador deve ser de renda fixa")
+		}
 		investment.Type = Types(*req.Type)
 	}
 
+	if req.CurrentBalance != nil && investment.AccruesDaily() {
+		return appErrors.NewValidationError("current_balance", "saldo de renda fixa com indexador e calculado automaticamente")
+	}
+
 	if req.CurrentBalance != nil {
 		investment.CurrentBalance = *req.CurrentBalance
 
@@ -333,19 +515,98 @@ func (s *Service) UpdateInvestment(ctx context.Context, investmentID, userID uli
 	}
 
 	investment.UpdatedAt = time.Now()
-	return s.Repository.Update(ctx, investment)
+	if err := s.Repository.Update(ctx, investment); err != nil {
+		return err
+	}
+
+	// A classe pode ser removida (string vazia), o que o Update ignora por ser valor zero
+	if req.AssetClass != nil {
+		investment.AssetClass = NormalizeAssetClass(*req.AssetClass)
+		if err := s.Repository.UpdateFields(ctx, investmentID, userID, map[string]interface{}{"asset_class": investment.AssetClass}); err != nil {
+			return err
+		}
+	}
+
+	return s.syncGoal(ctx, investment)
+}
+
+// ListByGoal retorna os investimentos que lastreiam a meta
+func (s *Service) ListByGoal(ctx context.Context, goalID, userID ulid.ULID) ([]*Investment, error) {
+	investments, err := s.Repository.GetByGoalID(ctx, goalID, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return investments, nil
+}
+
+// LinkToGoal vincula o investimento a uma meta. Um investimento lastreia no máximo uma meta.
+func (s *Service) LinkToGoal(ctx context.Context, investmentID, userID, goalID ulid.ULID) (*Investment, error) {
+	investment, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
+	if err != nil {
+		return nil, appErrors.ErrInvestmentNotFound.WithError(err)
+	}
+
+	if investment.GoalId != nil && *investment.GoalId != goalID {
+		return nil, appErrors.ErrConflict.WithDetails(map[string]interface{}{
+			"investment_id": "investimento ja vinculado a outra meta",
+		})
+	}
+
+	if err := s.Repository.SetGoal(ctx, investmentID, userID, &goalID); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	investment.GoalId = &goalID
+
+	return investment, nil
+}
+
+// UnlinkFromGoal remove o vínculo do investimento com a meta
+func (s *Service) UnlinkFromGoal(ctx context.Context, investmentID, userID, goalID ulid.ULID) error {
+	investment, err := s.Repository.GetInvestmentByID(ctx, investmentID, userID)
+	if err != nil {
+		return appErrors.ErrInvestmentNotFound.WithError(err)
+	}
+
+	if investment.GoalId == nil || *investment.GoalId != goalID {
+		return appErrors.NewValidationError("investment_id", "investimento nao esta vinculado a esta meta")
+	}
+
+	if err := s.Repository.SetGoal(ctx, investmentID, userID, nil); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) syncGoal(ctx context.Context, investment *Investment) error {
+	if s.GoalSync == nil || investment == nil || investment.GoalId == nil {
+		return nil
+	}
+	return s.GoalSync.SyncInvestmentBackedAmount(ctx, *investment.GoalId, investment.UserId)
 }
 func (s *Service) createInvestmentEntity(req contracts.CreateInvestmentRequestDomain, investmentID ulid.ULID) *Investment {
 	now := pkg.SetTimestamps()
 
+	// Com indexador, o retorno passa a ser calculado pelo rendimento acumulado
+	returnRate := req.ReturnRate
+	if req.Indexer != "" {
+		returnRate = 0
+	}
+
 	return &Investment{
 		Id:              investmentID,
 		UserId:          req.UserId,
 		Type:            Types(req.Type),
 		Name:            req.Name,
+		Ticker:          req.Ticker,
+		Quantity:        req.Quantity,
+		LastPrice:       req.UnitPrice,
 		CurrentBalance:  req.InitialAmount,
 		ReturnBalance:   0,
-		ReturnRate:      req.ReturnRate,
+		ReturnRate:      returnRate,
+		Indexer:         Indexer(req.Indexer),
+		IndexerRate:     req.IndexerRate,
+		MaturityDate:    req.MaturityDate,
+		AssetClass:      NormalizeAssetClass(req.AssetClass),
 		ApplicationDate: now,
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -0,0 +1,195 @@
+package investment
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// StockSalesExemptionLimit é o total de vendas de ações no mês até o qual o ganho em operações comuns é isento
+	StockSalesExemptionLimit = 20000.0
+	// CryptoSalesExemptionLimit é o total de vendas de criptoativos no mês até o qual o ganho é isento
+	CryptoSalesExemptionLimit = 35000.0
+
+	swingTradeRate     = 0.15
+	dayTradeRate       = 0.20
+	realEstateFundRate = 0.20
+)
+
+// iofTable é a alíquota regressiva de IOF sobre o rendimento, indexada pelos dias corridos (1 a 29)
+var iofTable = [30]float64{
+	1.00, 0.96, 0.93, 0.90, 0.86, 0.83, 0.80, 0.76, 0.73, 0.70,
+	0.66, 0.63, 0.60, 0.56, 0.53, 0.50, 0.46, 0.43, 0.40, 0.36,
+	0.33, 0.30, 0.26, 0.23, 0.20, 0.16, 0.13, 0.10, 0.06, 0.03,
+}
+
+// RedemptionTax é a apuração de um resgate ou venda antes de ser gravada
+type RedemptionTax struct {
+	Gross        float64
+	CostBasis    float64
+	Gain         float64
+	DayTradeGain float64
+	IOF          float64
+	IncomeTax    float64
+	Net          float64
+	Exempt       bool
+}
+
+// IncomeTaxRate retorna a alíquota regressiva de IR da renda fixa pelo prazo em dias corridos
+func IncomeTaxRate(days int) float64 {
+	switch {
+	case days <= 180:
+		return 0.225
+	case days <= 360:
+		return 0.20
+	case days <= 720:
+		return 0.175
+	default:
+		return 0.15
+	}
+}
+
+// IOFRate retorna a alíquota de IOF sobre o rendimento; resgates a partir de 30 dias são isentos
+func IOFRate(days int) float64 {
+	if days >= 30 {
+		return 0
+	}
+	if days < 0 {
+		days = 0
+	}
+	return iofTable[days]
+}
+
+// IsIncomeTaxExempt indica se o rendimento do tipo é isento de IR para pessoa física
+func (t Types) IsIncomeTaxExempt() bool {
+	return t == TypeLCI || t == TypeLCA
+}
+
+// hasRegressiveTax indica se o resgate segue a tabela regressiva de IR e o IOF
+func (t Types) hasRegressiveTax() bool {
+	return t.IsFixedIncome() || t == TypeFundos
+}
+
+// AssessFixedIncomeRedemption apura IOF e IR de um resgate consumindo as parcelas mais antigas primeiro.
+// Cada parcela usa o próprio prazo; LCI e LCA pagam apenas IOF. Previdência não sofre retenção no resgate.
+func AssessFixedIncomeRedemption(investmentType Types, tranches []Tranche, amount float64, at time.Time) RedemptionTax {
+	result := RedemptionTax{Gross: roundCents(amount), Exempt: investmentType.IsIncomeTaxExempt()}
+	end := truncateDay(at)
+	remaining := amount
+
+	for _, t := range tranches {
+		if remaining <= 0 {
+			break
+		}
+		if t.Gross <= 0 {
+			continue
+		}
+
+		take := math.Min(remaining, t.Gross)
+		principal := t.Principal * take / t.Gross
+		yield := math.Max(take-principal, 0)
+		remaining -= take
+
+		result.CostBasis += principal
+		result.Gain += yield
+
+		if !investmentType.hasRegressiveTax() {
+			continue
+		}
+
+		days := int(end.Sub(truncateDay(t.Date)).Hours() / 24)
+		iof := yield * IOFRate(days)
+		result.IOF += iof
+		if !result.Exempt {
+			result.IncomeTax += (yield - iof) * IncomeTaxRate(days)
+		}
+	}
+
+	// Valor além das parcelas conhecidas é tratado como principal
+	result.CostBasis += math.Max(remaining, 0)
+
+	result.CostBasis = roundCents(result.CostBasis)
+	result.Gain = roundCents(result.Gain)
+	result.IOF = roundCents(result.IOF)
+	result.IncomeTax = roundCents(result.IncomeTax)
+	result.Net = roundCents(result.Gross - result.IOF - result.IncomeTax)
+	return result
+}
+
+// AssessSale apura o IR de uma venda de ativo com ticker. A parte casada com compras do mesmo dia é
+// day-trade (20%); o restante usa o custo médio das compras anteriores (15%, ou 20% para FII).
+// Ações ficam isentas em operações comuns quando as vendas do mês, incluindo esta, somam até R$ 20 mil;
+// criptoativos até R$ 35 mil. monthSales é o total já vendido no mês no mesmo grupo de isenção.
+// Prejuízos não são compensados automaticamente.
+func AssessSale(investmentType Types, ticker string, lots []*Lot, sale *Lot, monthSales float64) RedemptionTax {
+	saleDay := truncateDay(sale.TradeDate)
+
+	var earlier []*Lot
+	dayBoughtQty, dayBoughtCost, daySoldQty := 0.0, 0.0, 0.0
+	for _, l := range lots {
+		if l.Id == sale.Id {
+			continue
+		}
+		day := truncateDay(l.TradeDate)
+		switch {
+		case day.Before(saleDay):
+			earlier = append(earlier, l)
+		case day.Equal(saleDay) && l.Side == LotBuy:
+			dayBoughtQty += l.Quantity
+			dayBoughtCost += l.Total()
+		case day.Equal(saleDay) && l.Side == LotSell:
+			daySoldQty += l.Quantity
+		}
+	}
+
+	dayTradeQty := math.Min(sale.Quantity, math.Max(dayBoughtQty-daySoldQty, 0))
+	swingQty := sale.Quantity - dayTradeQty
+
+	averageCost := 0.0
+	if position, err := CalculatePosition(ticker, earlier, 0); err == nil {
+		averageCost = position.AverageCost
+	}
+	dayTradeUnitCost := 0.0
+	if dayBoughtQty > 0 {
+		dayTradeUnitCost = dayBoughtCost / dayBoughtQty
+	}
+
+	proceeds := sale.Total()
+	unitProceeds := proceeds / sale.Quantity
+	dayTradeGain := dayTradeQty * (unitProceeds - dayTradeUnitCost)
+	swingGain := swingQty * (unitProceeds - averageCost)
+
+	swingRate := swingTradeRate
+	if investmentType == TypeFII {
+		swingRate = realEstateFundRate
+	}
+
+	exempt := false
+	switch investmentType {
+	case TypeAcoes:
+		exempt = monthSales+sale.Gross() <= StockSalesExemptionLimit
+	case TypeCripto:
+		exempt = monthSales+sale.Gross() <= CryptoSalesExemptionLimit
+	}
+
+	incomeTax := math.Max(dayTradeGain, 0) * dayTradeRate
+	if !exempt {
+		incomeTax += math.Max(swingGain, 0) * swingRate
+	}
+
+	result := RedemptionTax{
+		Gross:        roundCents(proceeds),
+		CostBasis:    roundCents(dayTradeQty*dayTradeUnitCost + swingQty*averageCost),
+		Gain:         roundCents(dayTradeGain + swingGain),
+		DayTradeGain: roundCents(dayTradeGain),
+		IncomeTax:    roundCents(incomeTax),
+		Exempt:       exempt,
+	}
+	result.Net = roundCents(result.Gross - result.IncomeTax)
+	return result
+}
+
+// HasMonthlySalesExemption indica se as vendas do tipo contam para um limite mensal de isenção
+func (t Types) HasMonthlySalesExemption() bool {
+	return t == TypeAcoes || t == TypeCripto
+}
@@ -0,0 +1,159 @@
+package investment
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"Fynance/internal/domain/report"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// SummarizeTaxIncome agrupa proventos pagos e resgates/vendas do ano em rendimentos isentos e de
+// tributação exclusiva. Dividendos, rendimentos de FII, LCI/LCA e o ganho em vendas isentas são isentos;
+// JCP, cupons e o rendimento da renda fixa tributada são exclusivos. Day-trade e vendas acima do limite
+// ficam apenas na apuração mensal.
+func SummarizeTaxIncome(investments map[ulid.ULID]*Investment, events []*IncomeEvent, redemptions []*Redemption) []report.TaxIncome {
+	type key struct {
+		kind   report.TaxIncomeKind
+		source string
+		id     ulid.ULID
+	}
+	totals := map[key]*report.TaxIncome{}
+	var order []key
+
+	add := func(kind report.TaxIncomeKind, source string, id ulid.ULID, investmentType Types, gross, withheld float64) {
+		k := key{kind: kind, source: source, id: id}
+		item, ok := totals[k]
+		if !ok {
+			item = &report.TaxIncome{Kind: kind, Source: source, InvestmentId: id, Type: string(investmentType)}
+			if inv, found := investments[id]; found {
+				item.Name, item.Ticker = inv.Name, inv.Ticker
+			}
+			totals[k] = item
+			order = append(order, k)
+		}
+		item.Gross += gross
+		item.WithheldTax += withheld
+	}
+
+	for _, e := range events {
+		investmentType := Types("")
+		if inv, ok := investments[e.InvestmentId]; ok {
+			investmentType = inv.Type
+		}
+		kind := report.TaxIncomeExempt
+		if e.Type == IncomeJCP || e.Type == IncomeCoupon || (e.Type == IncomeRealEstate && investmentType != TypeFII) {
+			kind = report.TaxIncomeExclusive
+		}
+		add(kind, string(e.Type), e.InvestmentId, investmentType, e.GrossAmount, e.WithheldTax)
+	}
+
+	exemptMonths := map[time.Month]map[Types]bool{}
+	for _, g := range SummarizeStockGains(redemptions) {
+		if exemptMonths[time.Month(g.Month)] == nil {
+			exemptMonths[time.Month(g.Month)] = map[Types]bool{}
+		}
+		exemptMonths[time.Month(g.Month)][Types(g.Type)] = g.Exempt
+	}
+
+	for _, r := range redemptions {
+		switch r.Kind {
+		case RedemptionWithdraw:
+			if r.Gain <= 0 {
+				continue
+			}
+			kind := report.TaxIncomeExclusive
+			if r.Exempt || r.InvestmentType.IsIncomeTaxExempt() {
+				kind = report.TaxIncomeExempt
+			}
+			add(kind, report.TaxSourceRedemption, r.InvestmentId, r.InvestmentType, r.Gain, r.IncomeTax+r.IOF)
+		case RedemptionSale:
+			swingGain := r.Gain - r.DayTradeGain
+			if exemptMonths[r.Date.Month()][r.InvestmentType] && swingGain > 0 {
+				add(report.TaxIncomeExempt, report.TaxSourceSale, r.InvestmentId, r.InvestmentType, swingGain, 0)
+			}
+		}
+	}
+
+	result := make([]report.TaxIncome, 0, len(order))
+	for _, k := range order {
+		item := totals[k]
+		item.Gross = roundCents(item.Gross)
+		item.WithheldTax = roundCents(item.WithheldTax)
+		item.Net = roundCents(item.Gross - item.WithheldTax)
+		result = append(result, *item)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// SummarizeStockGains apura as vendas do ano por mês e tipo de ativo. A isenção e o imposto devido são
+// recalculados pelo total vendido no mês, já que vendas posteriores podem ultrapassar o limite.
+func SummarizeStockGains(redemptions []*Redemption) []report.TaxStockGain {
+	type key struct {
+		month int
+		kind  Types
+	}
+	totals := map[key]*report.TaxStockGain{}
+	var order []key
+
+	for _, r := range redemptions {
+		if r.Kind != RedemptionSale {
+			continue
+		}
+		k := key{month: int(r.Date.Month()), kind: r.InvestmentType}
+		item, ok := totals[k]
+		if !ok {
+			item = &report.TaxStockGain{Month: k.month, Type: string(k.kind)}
+			totals[k] = item
+			order = append(order, k)
+		}
+		item.Sales += r.Gross
+		item.SwingGain += r.Gain - r.DayTradeGain
+		item.DayTradeGain += r.DayTradeGain
+	}
+
+	result := make([]report.TaxStockGain, 0, len(order))
+	for _, k := range order {
+		item := totals[k]
+		switch k.kind {
+		case TypeAcoes:
+			item.Exempt = item.Sales <= StockSalesExemptionLimit
+		case TypeCripto:
+			item.Exempt = item.Sales <= CryptoSalesExemptionLimit
+		}
+
+		swingRate := swingTradeRate
+		if k.kind == TypeFII {
+			swingRate = realEstateFundRate
+		}
+		item.TaxDue = math.Max(item.DayTradeGain, 0) * dayTradeRate
+		if !item.Exempt {
+			item.TaxDue += math.Max(item.SwingGain, 0) * swingRate
+		}
+		item.Sales = roundCents(item.Sales)
+		item.SwingGain = roundCents(item.SwingGain)
+		item.DayTradeGain = roundCents(item.DayTradeGain)
+		item.TaxDue = roundCents(item.TaxDue)
+		result = append(result, *item)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Month != result[j].Month {
+			return result[i].Month < result[j].Month
+		}
+		return result[i].Type < result[j].Type
+	})
+	return result
+}
+
+// yearEnd retorna 31/12 do ano
+func yearEnd(year int) time.Time {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+}
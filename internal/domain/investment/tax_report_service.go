@@ -0,0 +1,129 @@
+package investment
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"Fynance/internal/domain/report"
+	appErrors "Fynance/internal/errors"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// GetTaxData monta os dados de investimentos da declaração do ano: bens pelo custo de aquisição
+// em 31/12, rendimentos isentos e exclusivos e a apuração mensal de renda variável
+func (s *Service) GetTaxData(ctx context.Context, userID ulid.ULID, year int) (*report.InvestmentTaxData, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	if year < 2000 || year > 2100 {
+		return nil, appErrors.NewValidationError("year", "ano invalido")
+	}
+
+	investments, err := s.listAllInvestments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[ulid.ULID]*Investment, len(investments))
+	assets := make([]report.TaxAsset, 0, len(investments))
+	for _, inv := range investments {
+		byID[inv.Id] = inv
+
+		asset, err := s.taxAsset(ctx, inv, year)
+		if err != nil {
+			return nil, err
+		}
+		if asset.Cost > 0 || asset.PreviousCost > 0 {
+			assets = append(assets, *asset)
+		}
+	}
+	sort.SliceStable(assets, func(i, j int) bool {
+		if assets[i].Type != assets[j].Type {
+			return assets[i].Type < assets[j].Type
+		}
+		return assets[i].Name < assets[j].Name
+	})
+
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	events, err := s.Repository.ListUserIncomeEvents(ctx, userID, IncomePaid, from, to)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	redemptions, err := s.Repository.ListUserRedemptions(ctx, userID, from, to)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	return &report.InvestmentTaxData{
+		Year:       year,
+		Assets:     assets,
+		Income:     SummarizeTaxIncome(byID, events, redemptions),
+		StockGains: SummarizeStockGains(redemptions),
+	}, nil
+}
+
+// taxAsset calcula o custo de aquisição em 31/12 do ano e do ano anterior. Posições usam o custo médio
+// dos lotes até a data; os demais investimentos, o principal ainda aplicado.
+func (s *Service) taxAsset(ctx context.Context, inv *Investment, year int) (*report.TaxAsset, error) {
+	asset := &report.TaxAsset{
+		InvestmentId: inv.Id,
+		Name:         inv.Name,
+		Ticker:       inv.Ticker,
+		Type:         string(inv.Type),
+	}
+
+	if inv.IsPosition() {
+		lots, err := s.Repository.GetLots(ctx, inv.Id, inv.UserId)
+		if err != nil {
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		asset.Quantity, asset.Cost = lotsCostAt(inv.Ticker, lots, yearEnd(year))
+		asset.PreviousQuantity, asset.PreviousCost = lotsCostAt(inv.Ticker, lots, yearEnd(year-1))
+		return asset, nil
+	}
+
+	if inv.AccruesDaily() {
+		current, err := s.calculateAccrual(ctx, inv, yearEnd(year), nil)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := s.calculateAccrual(ctx, inv, yearEnd(year-1), nil)
+		if err != nil {
+			return nil, err
+		}
+		asset.Cost, asset.PreviousCost = current.Principal, previous.Principal
+		return asset, nil
+	}
+
+	flows, err := s.cashFlows(ctx, inv, nil)
+	if err != nil {
+		return nil, err
+	}
+	principal := &Investment{Type: inv.Type}
+	asset.Cost = CalculateAccrual(principal, flows, nil, yearEnd(year)).Principal
+	asset.PreviousCost = CalculateAccrual(principal, flows, nil, yearEnd(year-1)).Principal
+	return asset, nil
+}
+
+// lotsCostAt retorna quantidade e custo médio total da posição considerando os lotes até a data
+func lotsCostAt(ticker string, lots []*Lot, at time.Time) (float64, float64) {
+	var until []*Lot
+	for _, l := range lots {
+		if !truncateDay(l.TradeDate).After(at) {
+			until = append(until, l)
+		}
+	}
+	if len(until) == 0 {
+		return 0, 0
+	}
+
+	position, err := CalculatePosition(ticker, until, 0)
+	if err != nil {
+		return 0, 0
+	}
+	return position.Quantity, position.CostBasis
+}
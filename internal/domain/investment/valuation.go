@@ -0,0 +1,52 @@
+package investment
+
+import (
+	"context"
+	"time"
+
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+)
+
+// PriceSource fornece a cotação mais recente de um ativo
+type PriceSource interface {
+	LatestPrice(ctx context.Context, symbol string) (float64, time.Time, error)
+}
+
+// RevaluePositions marca a mercado todas as posições com ticker usando a cotação mais recente.
+// Ativos sem cotação mantêm o último preço conhecido. Retorna quantas posições foram atualizadas.
+func (s *Service) RevaluePositions(ctx context.Context) (int, error) {
+	if s.Prices == nil {
+		return 0, nil
+	}
+
+	investments, err := s.Repository.ListPositions(ctx)
+	if err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+
+	byTicker := make(map[string][]*Investment)
+	for _, inv := range investments {
+		byTicker[inv.Ticker] = append(byTicker[inv.Ticker], inv)
+	}
+
+	updated := 0
+	for ticker, group := range byTicker {
+		price, _, err := s.Prices.LatestPrice(ctx, ticker)
+		if err != nil {
+			logger.Warn().Err(err).Str("ticker", ticker).Msg("Cotacao indisponivel, mantendo ultimo preco")
+			continue
+		}
+
+		for _, inv := range group {
+			inv.LastPrice = price
+			if _, err := s.refreshPosition(ctx, inv); err != nil {
+				logger.Error().Err(err).Str("investment_id", inv.Id.String()).Str("ticker", ticker).Msg("Falha ao reavaliar posicao")
+				continue
+			}
+			updated++
+		}
+	}
+
+	return updated, nil
+}
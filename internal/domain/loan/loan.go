@@ -0,0 +1,181 @@
+package loan
+
+import (
+	"math"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Kind é a finalidade do empréstimo ou financiamento
+type Kind string
+
+const (
+	KindMortgage Kind = "IMOVEL"
+	KindVehicle  Kind = "VEICULO"
+	KindPersonal Kind = "PESSOAL"
+	KindOther    Kind = "OUTRO"
+)
+
+func (k Kind) IsValid() bool {
+	switch k {
+	case KindMortgage, KindVehicle, KindPersonal, KindOther:
+		return true
+	}
+	return false
+}
+
+// System é o sistema de amortização: SAC tem amortização constante e parcelas decrescentes,
+// PRICE tem parcelas constantes com amortização crescente
+type System string
+
+const (
+	SystemSAC   System = "SAC"
+	SystemPrice System = "PRICE"
+)
+
+func (s System) IsValid() bool {
+	return s == SystemSAC || s == SystemPrice
+}
+
+// RatePeriod indica se a taxa de juros informada é mensal ou anual
+type RatePeriod string
+
+const (
+	RateMonthly RatePeriod = "MONTHLY"
+	RateYearly  RatePeriod = "YEARLY"
+)
+
+func (p RatePeriod) IsValid() bool {
+	return p == RateMonthly || p == RateYearly
+}
+
+type Status string
+
+const (
+	StatusActive  Status = "ACTIVE"
+	StatusPaidOff Status = "PAID_OFF"
+)
+
+// Loan é um empréstimo ou financiamento. O cronograma não é gravado: é reconstruído a partir das
+// condições do contrato e dos pagamentos, e OutstandingBalance guarda o saldo devedor para consultas.
+type Loan struct {
+	Id                 ulid.ULID  `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId             ulid.ULID  `gorm:"type:varchar(26);index:idx_loans_user_id;not null" json:"userId"`
+	AccountId          ulid.ULID  `gorm:"type:varchar(26);not null" json:"accountId"`
+	CategoryId         *ulid.ULID `gorm:"type:varchar(26)" json:"categoryId,omitempty"`
+	Name               string     `gorm:"type:varchar(100);not null" json:"name"`
+	Kind               Kind       `gorm:"type:varchar(10);not null" json:"kind"`
+	System             System     `gorm:"type:varchar(5);not null" json:"system"`
+	Principal          float64    `gorm:"type:decimal(15,2);not null" json:"principal"`
+	InterestRate       float64    `gorm:"type:decimal(9,4);not null;default:0" json:"interestRate"`
+	RatePeriod         RatePeriod `gorm:"type:varchar(10);not null" json:"ratePeriod"`
+	TermMonths         int        `gorm:"not null" json:"termMonths"`
+	FirstDueDate       time.Time  `gorm:"type:date;not null" json:"firstDueDate"`
+	OutstandingBalance float64    `gorm:"type:decimal(15,2);not null;default:0" json:"outstandingBalance"`
+	PaidInstallments   int        `gorm:"not null;default:0" json:"paidInstallments"`
+	Status             Status     `gorm:"type:varchar(10);not null;index:idx_loans_status" json:"status"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (Loan) TableName() string {
+	return "loans"
+}
+
+// MonthlyRate é a taxa mensal efetiva em fração; taxas anuais são convertidas por juros compostos
+func (l *Loan) MonthlyRate() float64 {
+	rate := l.InterestRate / 100
+	if l.RatePeriod == RateYearly {
+		return math.Pow(1+rate, 1.0/12) - 1
+	}
+	return rate
+}
+
+// PaymentKind diferencia o pagamento de uma parcela da amortização extraordinária
+type PaymentKind string
+
+const (
+	PaymentInstallment PaymentKind = "INSTALLMENT"
+	PaymentExtra       PaymentKind = "EXTRA"
+)
+
+// ExtraStrategy define o efeito da amortização extraordinária no restante do contrato
+type ExtraStrategy string
+
+const (
+	// ReduceTerm mantém a parcela e diminui o número de parcelas
+	ReduceTerm ExtraStrategy = "REDUCE_TERM"
+	// ReduceInstallment mantém o prazo e diminui o valor das parcelas
+	ReduceInstallment ExtraStrategy = "REDUCE_INSTALLMENT"
+)
+
+func (s ExtraStrategy) IsValid() bool {
+	return s == ReduceTerm || s == ReduceInstallment
+}
+
+// Payment é um pagamento do empréstimo, com a despesa lançada na conta. Parcelas guardam a divisão
+// entre juros e amortização; amortizações extraordinárias vão integralmente para o saldo devedor.
+type Payment struct {
+	Id            ulid.ULID     `gorm:"type:varchar(26);primaryKey" json:"id"`
+	LoanId        ulid.ULID     `gorm:"type:varchar(26);index:idx_loan_payments_loan_id;not null" json:"loanId"`
+	UserId        ulid.ULID     `gorm:"type:varchar(26);not null" json:"userId"`
+	TransactionId ulid.ULID     `gorm:"type:varchar(26);uniqueIndex:idx_loan_payments_transaction_id;not null" json:"transactionId"`
+	Kind          PaymentKind   `gorm:"type:varchar(12);not null" json:"kind"`
+	Number        int           `gorm:"not null;default:0" json:"number,omitempty"`
+	Amount        float64       `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Interest      float64       `gorm:"type:decimal(15,2);not null;default:0" json:"interest"`
+	Amortization  float64       `gorm:"type:decimal(15,2);not null" json:"amortization"`
+	Strategy      ExtraStrategy `gorm:"type:varchar(20)" json:"strategy,omitempty"`
+	Date          time.Time     `gorm:"type:date;not null" json:"date"`
+	CreatedAt     time.Time     `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (Payment) TableName() string {
+	return "loan_payments"
+}
+
+type CreateLoanRequest struct {
+	UserId       ulid.ULID
+	AccountId    ulid.ULID
+	CategoryId   *ulid.ULID
+	Name         string
+	Kind         Kind
+	System       System
+	Principal    float64
+	InterestRate float64
+	RatePeriod   RatePeriod
+	TermMonths   int
+	FirstDueDate time.Time
+}
+
+type UpdateLoanRequest struct {
+	Name       *string
+	AccountId  *ulid.ULID
+	CategoryId *ulid.ULID
+}
+
+type PaymentRequest struct {
+	LoanId    ulid.ULID
+	UserId    ulid.ULID
+	AccountId *ulid.ULID
+	Date      *time.Time
+	Amount    float64
+	Strategy  ExtraStrategy
+}
+
+// DebtSummary consolida as dívidas ativas do usuário
+type DebtSummary struct {
+	ActiveLoans         int     `json:"activeLoans"`
+	TotalOutstanding    float64 `json:"totalOutstanding"`
+	MonthlyPayment      float64 `json:"monthlyPayment"`
+	OverdueInstallments int     `json:"overdueInstallments"`
+}
+
+// UpcomingInstallment é uma parcela pendente de um empréstimo ativo, usada nas projeções de caixa
+type UpcomingInstallment struct {
+	LoanId      ulid.ULID   `json:"loanId"`
+	AccountId   ulid.ULID   `json:"accountId"`
+	LoanName    string      `json:"loanName"`
+	Installment Installment `json:"installment"`
+}
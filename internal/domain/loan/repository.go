@@ -0,0 +1,21 @@
+package loan
+
+import (
+	"context"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type LoanRepository interface {
+	Create(ctx context.Context, loan *Loan) error
+	Update(ctx context.Context, loan *Loan) error
+	Delete(ctx context.Context, loanID, userID ulid.ULID) error
+	GetByID(ctx context.Context, loanID, userID ulid.ULID) (*Loan, error)
+	ListByUser(ctx context.Context, userID ulid.ULID) ([]*Loan, error)
+	UpdateProgress(ctx context.Context, loanID ulid.ULID, outstanding float64, paidInstallments int, status Status) error
+	CreatePayment(ctx context.Context, payment *Payment) error
+	ListPayments(ctx context.Context, loanID, userID ulid.ULID) ([]*Payment, error)
+	GetPaymentByTransactionID(ctx context.Context, transactionID, userID ulid.ULID) (*Payment, error)
+	DeletePayment(ctx context.Context, paymentID ulid.ULID) error
+	DeletePaymentsByLoan(ctx context.Context, loanID, userID ulid.ULID) error
+}
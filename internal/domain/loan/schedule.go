@@ -0,0 +1,276 @@
+package loan
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type InstallmentStatus string
+
+const (
+	InstallmentPaid    InstallmentStatus = "PAID"
+	InstallmentPending InstallmentStatus = "PENDING"
+	InstallmentOverdue InstallmentStatus = "OVERDUE"
+)
+
+// Installment é uma parcela do cronograma; Balance é o saldo devedor depois dela
+type Installment struct {
+	Number        int               `json:"number"`
+	DueDate       time.Time         `json:"dueDate"`
+	Payment       float64           `json:"payment"`
+	Interest      float64           `json:"interest"`
+	Amortization  float64           `json:"amortization"`
+	Balance       float64           `json:"balance"`
+	Status        InstallmentStatus `json:"status"`
+	PaidAt        *time.Time        `json:"paidAt,omitempty"`
+	TransactionId *ulid.ULID        `json:"transactionId,omitempty"`
+}
+
+// Schedule é o cronograma do empréstimo: parcelas pagas, amortizações extraordinárias e a projeção
+// das parcelas restantes com as condições atuais
+type Schedule struct {
+	Installments          []Installment `json:"installments"`
+	ExtraPayments         []*Payment    `json:"extraPayments"`
+	Outstanding           float64       `json:"outstanding"`
+	PaidInstallments      int           `json:"paidInstallments"`
+	RemainingInstallments int           `json:"remainingInstallments"`
+	InterestPaid          float64       `json:"interestPaid"`
+	InterestRemaining     float64       `json:"interestRemaining"`
+	NextInstallment       *Installment  `json:"nextInstallment,omitempty"`
+}
+
+// ExtraPaymentSimulation compara o efeito de uma amortização extraordinária nas duas estratégias
+type ExtraPaymentSimulation struct {
+	Amount            float64          `json:"amount"`
+	Current           SimulationResult `json:"current"`
+	ReduceTerm        SimulationResult `json:"reduceTerm"`
+	ReduceInstallment SimulationResult `json:"reduceInstallment"`
+}
+
+type SimulationResult struct {
+	RemainingInstallments int        `json:"remainingInstallments"`
+	NextPayment           float64    `json:"nextPayment"`
+	InterestRemaining     float64    `json:"interestRemaining"`
+	LastDueDate           *time.Time `json:"lastDueDate,omitempty"`
+}
+
+// scheduleState é a situação do contrato depois de aplicar os pagamentos em ordem
+type scheduleState struct {
+	balance      float64
+	remaining    int
+	number       int
+	payment      float64
+	amortization float64
+}
+
+// BuildSchedule reconstrói o cronograma aplicando os pagamentos em ordem cronológica sobre as condições
+// do contrato e projeta as parcelas restantes. Parcelas vencidas antes de today ficam como OVERDUE.
+func BuildSchedule(l *Loan, payments []*Payment, today time.Time) *Schedule {
+	ordered := make([]*Payment, len(payments))
+	copy(ordered, payments)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Date.Equal(ordered[j].Date) {
+			return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+		}
+		return ordered[i].Date.Before(ordered[j].Date)
+	})
+
+	rate := l.MonthlyRate()
+	state := newScheduleState(l, rate)
+	result := &Schedule{Installments: []Installment{}, ExtraPayments: []*Payment{}}
+
+	for _, p := range ordered {
+		result.InterestPaid += p.Interest
+		switch p.Kind {
+		case PaymentInstallment:
+			state.balance = roundCents(state.balance - p.Amortization)
+			paidAt := p.Date
+			transactionID := p.TransactionId
+			result.Installments = append(result.Installments, Installment{
+				Number:        state.number,
+				DueDate:       dueDate(l.FirstDueDate, state.number),
+				Payment:       p.Amount,
+				Interest:      p.Interest,
+				Amortization:  p.Amortization,
+				Balance:       math.Max(state.balance, 0),
+				Status:        InstallmentPaid,
+				PaidAt:        &paidAt,
+				TransactionId: &transactionID,
+			})
+			state.number++
+			state.remaining--
+			result.PaidInstallments++
+		case PaymentExtra:
+			state.applyExtra(l.System, rate, p.Amortization, p.Strategy)
+			result.ExtraPayments = append(result.ExtraPayments, p)
+		}
+	}
+
+	today = truncateDay(today)
+	for _, installment := range state.project(l, rate) {
+		if installment.DueDate.Before(today) {
+			installment.Status = InstallmentOverdue
+		}
+		result.Installments = append(result.Installments, installment)
+		result.InterestRemaining += installment.Interest
+		result.RemainingInstallments++
+		if result.NextInstallment == nil {
+			next := installment
+			result.NextInstallment = &next
+		}
+	}
+
+	result.Outstanding = math.Max(state.balance, 0)
+	result.InterestPaid = roundCents(result.InterestPaid)
+	result.InterestRemaining = roundCents(result.InterestRemaining)
+	return result
+}
+
+// SimulateExtraPayment mostra como ficaria o contrato depois de amortizar o valor em cada estratégia
+func SimulateExtraPayment(l *Loan, payments []*Payment, amount float64, today time.Time) *ExtraPaymentSimulation {
+	simulate := func(strategy ExtraStrategy) SimulationResult {
+		extra := &Payment{Kind: PaymentExtra, Amount: amount, Amortization: amount, Strategy: strategy, Date: truncateDay(today), CreatedAt: time.Now()}
+		return summarize(BuildSchedule(l, append(append([]*Payment{}, payments...), extra), today))
+	}
+
+	return &ExtraPaymentSimulation{
+		Amount:            amount,
+		Current:           summarize(BuildSchedule(l, payments, today)),
+		ReduceTerm:        simulate(ReduceTerm),
+		ReduceInstallment: simulate(ReduceInstallment),
+	}
+}
+
+func summarize(s *Schedule) SimulationResult {
+	result := SimulationResult{
+		RemainingInstallments: s.RemainingInstallments,
+		InterestRemaining:     s.InterestRemaining,
+	}
+	if s.NextInstallment != nil {
+		result.NextPayment = s.NextInstallment.Payment
+		last := s.Installments[len(s.Installments)-1].DueDate
+		result.LastDueDate = &last
+	}
+	return result
+}
+
+func newScheduleState(l *Loan, rate float64) *scheduleState {
+	state := &scheduleState{balance: l.Principal, remaining: l.TermMonths, number: 1}
+	state.reprice(l.System, rate)
+	return state
+}
+
+// reprice recalcula a parcela (PRICE) ou a amortização (SAC) para o saldo e o prazo restantes
+func (s *scheduleState) reprice(system System, rate float64) {
+	if s.remaining <= 0 || s.balance <= 0 {
+		s.payment, s.amortization = 0, 0
+		return
+	}
+	if system == SystemSAC {
+		s.amortization = roundCents(s.balance / float64(s.remaining))
+		return
+	}
+	s.payment = pricePayment(s.balance, rate, s.remaining)
+}
+
+// applyExtra abate o valor do saldo devedor. Reduzindo o prazo, a parcela (PRICE) ou a amortização (SAC)
+// é mantida e o número de parcelas é recalculado; reduzindo a parcela, o prazo é mantido.
+func (s *scheduleState) applyExtra(system System, rate, amount float64, strategy ExtraStrategy) {
+	s.balance = roundCents(s.balance - amount)
+	if s.balance <= 0 {
+		s.balance, s.remaining = 0, 0
+		return
+	}
+
+	if strategy == ReduceInstallment {
+		s.reprice(system, rate)
+		return
+	}
+
+	if system == SystemSAC {
+		if s.amortization > 0 {
+			s.remaining = int(math.Ceil(s.balance/s.amortization - 1e-9))
+		}
+		return
+	}
+	s.remaining = priceTerm(s.balance, rate, s.payment)
+}
+
+// project calcula as parcelas restantes; a última absorve as diferenças de arredondamento
+func (s *scheduleState) project(l *Loan, rate float64) []Installment {
+	balance := s.balance
+	installments := make([]Installment, 0, s.remaining)
+	for i := 0; i < s.remaining && balance > 0; i++ {
+		number := s.number + i
+		interest := roundCents(balance * rate)
+
+		var amortization, payment float64
+		if l.System == SystemSAC {
+			amortization = s.amortization
+		} else {
+			amortization = roundCents(s.payment - interest)
+		}
+		if i == s.remaining-1 || amortization > balance {
+			amortization = balance
+		}
+		payment = roundCents(amortization + interest)
+		balance = roundCents(balance - amortization)
+
+		installments = append(installments, Installment{
+			Number:       number,
+			DueDate:      dueDate(l.FirstDueDate, number),
+			Payment:      payment,
+			Interest:     interest,
+			Amortization: amortization,
+			Balance:      balance,
+			Status:       InstallmentPending,
+		})
+	}
+	return installments
+}
+
+// pricePayment é a parcela constante da tabela Price
+func pricePayment(balance, rate float64, term int) float64 {
+	if rate == 0 {
+		return roundCents(balance / float64(term))
+	}
+	return roundCents(balance * rate / (1 - math.Pow(1+rate, -float64(term))))
+}
+
+// priceTerm é o número de parcelas necessário para quitar o saldo mantendo a parcela
+func priceTerm(balance, rate, payment float64) int {
+	if payment <= 0 {
+		return 0
+	}
+	if rate == 0 {
+		return int(math.Ceil(balance/payment - 1e-9))
+	}
+	ratio := 1 - balance*rate/payment
+	if ratio <= 0 {
+		return 0
+	}
+	return int(math.Ceil(-math.Log(ratio)/math.Log(1+rate) - 1e-9))
+}
+
+// dueDate é o vencimento da parcela, mantendo o dia do primeiro vencimento (ou o último dia do mês)
+func dueDate(first time.Time, number int) time.Time {
+	first = truncateDay(first)
+	target := time.Date(first.Year(), first.Month()+time.Month(number-1), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := target.AddDate(0, 1, -1).Day()
+	day := first.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(target.Year(), target.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,521 @@
+package loan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/category"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// defaultCategoryNames são as categorias padrão das parcelas quando o empréstimo não define uma
+var defaultCategoryNames = map[Kind]string{
+	KindMortgage: "Moradia",
+	KindVehicle:  "Transporte",
+	KindPersonal: "Contas",
+	KindOther:    "Contas",
+}
+
+type Service struct {
+	Repository         LoanRepository
+	AccountService     *account.Service
+	CategoryService    *category.Service
+	TransactionService transaction.TransactionHandler
+	shared.BaseService
+}
+
+func NewService(
+	repo LoanRepository,
+	accountService *account.Service,
+	categoryService *category.Service,
+	transactionService transaction.TransactionHandler,
+	userChecker *shared.UserCheckerService,
+) *Service {
+	return &Service{
+		Repository:         repo,
+		AccountService:     accountService,
+		CategoryService:    categoryService,
+		TransactionService: transactionService,
+		BaseService: shared.BaseService{
+			UserChecker: userChecker,
+		},
+	}
+}
+
+func (s *Service) CreateLoan(ctx context.Context, req CreateLoanRequest) (*Loan, error) {
+	if err := s.EnsureUserExists(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := validateCreateRequest(req); err != nil {
+		return nil, err
+	}
+	if err := s.validatePaymentAccount(ctx, req.AccountId, req.UserId); err != nil {
+		return nil, err
+	}
+	if req.CategoryId != nil && s.CategoryService != nil {
+		if err := s.CategoryService.ValidateAndEnsureExists(ctx, *req.CategoryId, req.UserId); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	loan := &Loan{
+		Id:                 pkg.GenerateULIDObject(),
+		UserId:             req.UserId,
+		AccountId:          req.AccountId,
+		CategoryId:         req.CategoryId,
+		Name:               strings.TrimSpace(req.Name),
+		Kind:               req.Kind,
+		System:             req.System,
+		Principal:          roundCents(req.Principal),
+		InterestRate:       req.InterestRate,
+		RatePeriod:         req.RatePeriod,
+		TermMonths:         req.TermMonths,
+		FirstDueDate:       truncateDay(req.FirstDueDate),
+		OutstandingBalance: roundCents(req.Principal),
+		Status:             StatusActive,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	if err := s.Repository.Create(ctx, loan); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return loan, nil
+}
+
+func (s *Service) UpdateLoan(ctx context.Context, loanID, userID ulid.ULID, req UpdateLoanRequest) (*Loan, error) {
+	loan, err := s.GetLoan(ctx, loanID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, appErrors.NewValidationError("name", "é obrigatório")
+		}
+		loan.Name = name
+	}
+	if req.AccountId != nil {
+		if err := s.validatePaymentAccount(ctx, *req.AccountId, userID); err != nil {
+			return nil, err
+		}
+		loan.AccountId = *req.AccountId
+	}
+	if req.CategoryId != nil {
+		if s.CategoryService != nil {
+			if err := s.CategoryService.ValidateAndEnsureExists(ctx, *req.CategoryId, userID); err != nil {
+				return nil, err
+			}
+		}
+		loan.CategoryId = req.CategoryId
+	}
+
+	loan.UpdatedAt = time.Now()
+	if err := s.Repository.Update(ctx, loan); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return loan, nil
+}
+
+// DeleteLoan remove o empréstimo e o histórico de pagamentos. As despesas lançadas nas contas são mantidas.
+func (s *Service) DeleteLoan(ctx context.Context, loanID, userID ulid.ULID) error {
+	if _, err := s.GetLoan(ctx, loanID, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeletePaymentsByLoan(ctx, loanID, userID); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	if err := s.Repository.Delete(ctx, loanID, userID); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) GetLoan(ctx context.Context, loanID, userID ulid.ULID) (*Loan, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	loan, err := s.Repository.GetByID(ctx, loanID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("emprestimo")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return loan, nil
+}
+
+func (s *Service) ListLoans(ctx context.Context, userID ulid.ULID) ([]*Loan, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	loans, err := s.Repository.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return loans, nil
+}
+
+func (s *Service) GetSchedule(ctx context.Context, loanID, userID ulid.ULID) (*Schedule, error) {
+	loan, err := s.GetLoan(ctx, loanID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.Repository.ListPayments(ctx, loan.Id, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return BuildSchedule(loan, payments, time.Now()), nil
+}
+
+// SimulateExtraPayment compara reduzir o prazo e reduzir a parcela antes de amortizar
+func (s *Service) SimulateExtraPayment(ctx context.Context, loanID, userID ulid.ULID, amount float64) (*ExtraPaymentSimulation, error) {
+	loan, err := s.GetLoan(ctx, loanID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.Repository.ListPayments(ctx, loan.Id, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	if err := validateExtraAmount(amount, BuildSchedule(loan, payments, time.Now()).Outstanding); err != nil {
+		return nil, err
+	}
+	return SimulateExtraPayment(loan, payments, roundCents(amount), time.Now()), nil
+}
+
+// PayInstallment paga a próxima parcela em aberto: lança a despesa na conta e registra
+// a divisão entre juros e amortização
+func (s *Service) PayInstallment(ctx context.Context, req PaymentRequest) (*Payment, *Schedule, error) {
+	loan, schedule, err := s.loadForPayment(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if schedule.NextInstallment == nil {
+		return nil, nil, appErrors.NewValidationError("loan", "emprestimo sem parcelas em aberto")
+	}
+
+	next := schedule.NextInstallment
+	payment := &Payment{
+		Kind:         PaymentInstallment,
+		Number:       next.Number,
+		Amount:       next.Payment,
+		Interest:     next.Interest,
+		Amortization: next.Amortization,
+	}
+	description := fmt.Sprintf("Parcela %d/%d - %s", next.Number, next.Number+schedule.RemainingInstallments-1, loan.Name)
+	return s.recordPayment(ctx, loan, req, payment, description)
+}
+
+// ExtraPayment amortiza o saldo devedor fora das parcelas, reduzindo o prazo ou o valor das parcelas
+func (s *Service) ExtraPayment(ctx context.Context, req PaymentRequest) (*Payment, *Schedule, error) {
+	if !req.Strategy.IsValid() {
+		return nil, nil, appErrors.NewValidationError("strategy", "deve ser REDUCE_TERM ou REDUCE_INSTALLMENT")
+	}
+
+	loan, schedule, err := s.loadForPayment(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateExtraAmount(req.Amount, schedule.Outstanding); err != nil {
+		return nil, nil, err
+	}
+
+	amount := roundCents(req.Amount)
+	payment := &Payment{
+		Kind:         PaymentExtra,
+		Amount:       amount,
+		Amortization: amount,
+		Strategy:     req.Strategy,
+	}
+	description := "Amortizacao extra - " + loan.Name
+	if amount >= schedule.Outstanding {
+		description = "Quitacao - " + loan.Name
+	}
+	return s.recordPayment(ctx, loan, req, payment, description)
+}
+
+// GetDebtSummary soma o saldo devedor e as próximas parcelas dos empréstimos ativos
+func (s *Service) GetDebtSummary(ctx context.Context, userID ulid.ULID) (*DebtSummary, error) {
+	loans, err := s.ListLoans(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &DebtSummary{}
+	now := time.Now()
+	for _, loan := range loans {
+		if loan.Status != StatusActive {
+			continue
+		}
+		payments, err := s.Repository.ListPayments(ctx, loan.Id, userID)
+		if err != nil {
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		schedule := BuildSchedule(loan, payments, now)
+
+		summary.ActiveLoans++
+		summary.TotalOutstanding += schedule.Outstanding
+		if schedule.NextInstallment != nil {
+			summary.MonthlyPayment += schedule.NextInstallment.Payment
+		}
+		for _, installment := range schedule.Installments {
+			if installment.Status == InstallmentOverdue {
+				summary.OverdueInstallments++
+			}
+		}
+	}
+
+	summary.TotalOutstanding = roundCents(summary.TotalOutstanding)
+	summary.MonthlyPayment = roundCents(summary.MonthlyPayment)
+	return summary, nil
+}
+
+// UpcomingInstallments lista as parcelas pendentes dos empréstimos ativos que vencem até a data
+// informada, incluindo as parcelas em atraso
+func (s *Service) UpcomingInstallments(ctx context.Context, userID ulid.ULID, until time.Time) ([]UpcomingInstallment, error) {
+	loans, err := s.ListLoans(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	until = truncateDay(until)
+	now := time.Now()
+	var upcoming []UpcomingInstallment
+	for _, loan := range loans {
+		if loan.Status != StatusActive {
+			continue
+		}
+		payments, err := s.Repository.ListPayments(ctx, loan.Id, userID)
+		if err != nil {
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		for _, installment := range BuildSchedule(loan, payments, now).Installments {
+			if installment.Status == InstallmentPaid || installment.DueDate.After(until) {
+				continue
+			}
+			upcoming = append(upcoming, UpcomingInstallment{
+				LoanId:      loan.Id,
+				AccountId:   loan.AccountId,
+				LoanName:    loan.Name,
+				Installment: installment,
+			})
+		}
+	}
+	return upcoming, nil
+}
+
+// IsLoanPayment indica se a movimentação é o pagamento de um empréstimo
+func (s *Service) IsLoanPayment(ctx context.Context, transactionID, userID ulid.ULID) (bool, error) {
+	_, err := s.Repository.GetPaymentByTransactionID(ctx, transactionID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, appErrors.NewDatabaseError(err)
+	}
+	return true, nil
+}
+
+// DeletePaymentByTransactionId desfaz o pagamento ligado a uma despesa excluída e recalcula o saldo devedor.
+// O estorno na conta é feito pela exclusão da própria despesa.
+func (s *Service) DeletePaymentByTransactionId(ctx context.Context, transactionID, userID ulid.ULID) error {
+	payment, err := s.Repository.GetPaymentByTransactionID(ctx, transactionID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+
+	if err := s.Repository.DeletePayment(ctx, payment.Id); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	loan, err := s.Repository.GetByID(ctx, payment.LoanId, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	_, err = s.refreshLoan(ctx, loan)
+	return err
+}
+
+func (s *Service) loadForPayment(ctx context.Context, req PaymentRequest) (*Loan, *Schedule, error) {
+	loan, err := s.GetLoan(ctx, req.LoanId, req.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if loan.Status == StatusPaidOff {
+		return nil, nil, appErrors.NewValidationError("loan", "emprestimo ja quitado")
+	}
+
+	payments, err := s.Repository.ListPayments(ctx, loan.Id, req.UserId)
+	if err != nil {
+		return nil, nil, appErrors.NewDatabaseError(err)
+	}
+	return loan, BuildSchedule(loan, payments, time.Now()), nil
+}
+
+// recordPayment lança a despesa pelo serviço de transações (saldo, orçamento e arredondamento)
+// e grava o pagamento; se a gravação falhar, a despesa é excluída
+func (s *Service) recordPayment(ctx context.Context, loan *Loan, req PaymentRequest, payment *Payment, description string) (*Payment, *Schedule, error) {
+	accountID := loan.AccountId
+	if req.AccountId != nil {
+		accountID = *req.AccountId
+	}
+	if err := s.validatePaymentAccount(ctx, accountID, req.UserId); err != nil {
+		return nil, nil, err
+	}
+
+	date := time.Now()
+	if req.Date != nil {
+		date = *req.Date
+	}
+	date = truncateDay(date)
+
+	categoryID, err := s.paymentCategoryID(ctx, loan)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := pkg.SetTimestamps()
+	expense := &transaction.Transaction{
+		Id:          pkg.GenerateULIDObject(),
+		UserId:      req.UserId,
+		AccountId:   accountID,
+		CategoryId:  &categoryID,
+		Type:        transaction.Expense,
+		Amount:      payment.Amount,
+		Description: description,
+		Date:        date,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.TransactionService.CreateTransaction(ctx, expense); err != nil {
+		return nil, nil, err
+	}
+
+	payment.Id = pkg.GenerateULIDObject()
+	payment.LoanId = loan.Id
+	payment.UserId = req.UserId
+	payment.TransactionId = expense.Id
+	payment.Date = date
+	payment.CreatedAt = now
+	if err := s.Repository.CreatePayment(ctx, payment); err != nil {
+		_ = s.TransactionService.DeleteTransaction(ctx, expense.Id, req.UserId)
+		return nil, nil, appErrors.NewDatabaseError(err)
+	}
+
+	schedule, err := s.refreshLoan(ctx, loan)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payment, schedule, nil
+}
+
+// refreshLoan reconstrói o cronograma e grava saldo devedor, parcelas pagas e situação
+func (s *Service) refreshLoan(ctx context.Context, loan *Loan) (*Schedule, error) {
+	payments, err := s.Repository.ListPayments(ctx, loan.Id, loan.UserId)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	schedule := BuildSchedule(loan, payments, time.Now())
+
+	status := StatusActive
+	if schedule.Outstanding <= 0 {
+		status = StatusPaidOff
+	}
+	if err := s.Repository.UpdateProgress(ctx, loan.Id, schedule.Outstanding, schedule.PaidInstallments, status); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	loan.OutstandingBalance = schedule.Outstanding
+	loan.PaidInstallments = schedule.PaidInstallments
+	loan.Status = status
+	return schedule, nil
+}
+
+func (s *Service) paymentCategoryID(ctx context.Context, loan *Loan) (ulid.ULID, error) {
+	if loan.CategoryId != nil {
+		return *loan.CategoryId, nil
+	}
+
+	categoryID := category.GenerateDeterministicID(loan.UserId.String(), defaultCategoryNames[loan.Kind])
+	if s.CategoryService == nil {
+		return categoryID, nil
+	}
+	if err := s.CategoryService.ValidateAndEnsureExists(ctx, categoryID, loan.UserId); err != nil {
+		return ulid.ULID{}, err
+	}
+	return s.CategoryService.ResolveCategoryID(ctx, categoryID, loan.UserId)
+}
+
+func (s *Service) validatePaymentAccount(ctx context.Context, accountID, userID ulid.ULID) error {
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, accountID, userID)
+	if err != nil {
+		return err
+	}
+	if accountEntity.Type == account.TypeCreditCard {
+		return appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+	}
+	return nil
+}
+
+func validateCreateRequest(req CreateLoanRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return appErrors.NewValidationError("name", "é obrigatório")
+	}
+	if !req.Kind.IsValid() {
+		return appErrors.NewValidationError("kind", "deve ser IMOVEL, VEICULO, PESSOAL ou OUTRO")
+	}
+	if !req.System.IsValid() {
+		return appErrors.NewValidationError("system", "deve ser SAC ou PRICE")
+	}
+	if !req.RatePeriod.IsValid() {
+		return appErrors.NewValidationError("rate_period", "deve ser MONTHLY ou YEARLY")
+	}
+	if req.Principal <= 0 {
+		return appErrors.NewValidationError("principal", "deve ser maior que zero")
+	}
+	if req.InterestRate < 0 {
+		return appErrors.NewValidationError("interest_rate", "nao pode ser negativa")
+	}
+	if req.TermMonths <= 0 || req.TermMonths > 600 {
+		return appErrors.NewValidationError("term_months", "deve estar entre 1 e 600")
+	}
+	if req.FirstDueDate.IsZero() {
+		return appErrors.NewValidationError("first_due_date", "é obrigatório")
+	}
+	return nil
+}
+
+func validateExtraAmount(amount, outstanding float64) error {
+	if amount <= 0 {
+		return appErrors.NewValidationError("amount", "deve ser maior que zero")
+	}
+	if roundCents(amount) > outstanding {
+		return appErrors.NewValidationError("amount", fmt.Sprintf("maior que o saldo devedor de %.2f", outstanding))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ErrSymbolNotFound indica que o provedor não possui cotação para o ativo
+var ErrSymbolNotFound = errors.New("ativo nao encontrado no provedor de cotacoes")
+
+// Quote é a cotação de fechamento de um ativo em uma data
+type Quote struct {
+	Symbol string    `json:"symbol"`
+	Price  float64   `json:"price"`
+	Date   time.Time `json:"date"`
+	Source string    `json:"source"`
+}
+
+// IndexPoint é o valor de um índice econômico em uma data.
+// CDI e SELIC são taxas diárias em %; IPCA é a variação mensal em %, datada no primeiro dia do mês;
+// IBOV é a pontuação de fechamento do Ibovespa.
+type IndexPoint struct {
+	Index string    `json:"index"`
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+}
+
+// PriceProvider é a fonte externa de cotações e séries de índices
+type PriceProvider interface {
+	Name() string
+	GetQuote(ctx context.Context, symbol string) (*Quote, error)
+	GetIndexSeries(ctx context.Context, index string, from, to time.Time) ([]IndexPoint, error)
+}
+
+// PriceHistory guarda as cotações obtidas para gráficos e reavaliações
+type PriceHistory struct {
+	Id        ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"id"`
+	Symbol    string    `gorm:"type:varchar(20);not null;index:idx_price_history_symbol_date,unique" json:"symbol"`
+	Date      time.Time `gorm:"type:date;not null;index:idx_price_history_symbol_date,unique" json:"date"`
+	Price     float64   `gorm:"type:decimal(18,8);not null" json:"price"`
+	Source    string    `gorm:"type:varchar(30);not null" json:"source"`
+	CreatedAt time.Time `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
+
+// IndexRate é um ponto da série histórica de um índice armazenado localmente
+type IndexRate struct {
+	Index     string    `gorm:"column:index_code;type:varchar(10);primaryKey" json:"index"`
+	Date      time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Value     float64   `gorm:"type:decimal(18,8);not null" json:"value"`
+	CreatedAt time.Time `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (IndexRate) TableName() string {
+	return "index_rates"
+}
+
+const (
+	IndexCDI   = "CDI"
+	IndexSelic = "SELIC"
+	IndexIPCA  = "IPCA"
+	IndexIBOV  = "IBOV"
+)
+
+// TrackedIndexes são os índices sincronizados pelo job de mercado
+var TrackedIndexes = []string{IndexCDI, IndexSelic, IndexIPCA, IndexIBOV}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,15 @@
+package market
+
+import (
+	"context"
+	"time"
+)
+
+type MarketRepository interface {
+	SavePrice(ctx context.Context, price *PriceHistory) error
+	GetLatestPrice(ctx context.Context, symbol string) (*PriceHistory, error)
+	GetPriceHistory(ctx context.Context, symbol string, from, to time.Time) ([]*PriceHistory, error)
+	SaveIndexRates(ctx context.Context, rates []*IndexRate) error
+	GetIndexRates(ctx context.Context, index string, from, to time.Time) ([]*IndexRate, error)
+	GetLatestIndexDate(ctx context.Context, index string) (*time.Time, error)
+}
@@ -0,0 +1,136 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// indexBackfill é o período buscado quando um índice ainda não tem série local
+const indexBackfill = 5 * 365 * 24 * time.Hour
+
+type Service struct {
+	Repository MarketRepository
+	Provider   PriceProvider
+}
+
+func NewService(repo MarketRepository, provider PriceProvider) *Service {
+	return &Service{
+		Repository: repo,
+		Provider:   provider,
+	}
+}
+
+// LatestPrice busca a cotação no provedor e guarda no histórico.
+// Sem provedor ou em caso de falha, usa a última cotação armazenada.
+func (s *Service) LatestPrice(ctx context.Context, symbol string) (float64, time.Time, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	if s.Provider != nil {
+		quote, err := s.Provider.GetQuote(ctx, symbol)
+		if err == nil && quote.Price > 0 {
+			if quote.Date.IsZero() {
+				quote.Date = time.Now()
+			}
+			entry := &PriceHistory{
+				Id:     pkg.GenerateULIDObject(),
+				Symbol: symbol,
+				Date:   truncateDay(quote.Date),
+				Price:  quote.Price,
+				Source: s.Provider.Name(),
+			}
+			if err := s.Repository.SavePrice(ctx, entry); err != nil {
+				return 0, time.Time{}, appErrors.NewDatabaseError(err)
+			}
+			return quote.Price, entry.Date, nil
+		}
+		if err != nil {
+			logger.Warn().Err(err).Str("symbol", symbol).Str("provider", s.Provider.Name()).Msg("Falha ao obter cotacao, usando ultimo preco armazenado")
+		}
+	}
+
+	latest, err := s.Repository.GetLatestPrice(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, time.Time{}, ErrSymbolNotFound
+		}
+		return 0, time.Time{}, appErrors.NewDatabaseError(err)
+	}
+	return latest.Price, latest.Date, nil
+}
+
+// GetPriceHistory retorna as cotações armazenadas de um ativo no período
+func (s *Service) GetPriceHistory(ctx context.Context, symbol string, from, to time.Time) ([]*PriceHistory, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, appErrors.NewValidationError("symbol", "é obrigatório")
+	}
+	if to.Before(from) {
+		return nil, appErrors.NewValidationError("from", "deve ser anterior a data final")
+	}
+
+	history, err := s.Repository.GetPriceHistory(ctx, symbol, truncateDay(from), truncateDay(to))
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return history, nil
+}
+
+// GetIndexRates retorna a série local de um índice no período
+func (s *Service) GetIndexRates(ctx context.Context, index string, from, to time.Time) ([]*IndexRate, error) {
+	rates, err := s.Repository.GetIndexRates(ctx, strings.ToUpper(index), truncateDay(from), truncateDay(to))
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return rates, nil
+}
+
+// SyncIndexes completa a série local dos índices acompanhados a partir do último ponto armazenado
+func (s *Service) SyncIndexes(ctx context.Context, now time.Time) error {
+	if s.Provider == nil {
+		return nil
+	}
+
+	to := truncateDay(now)
+	for _, index := range TrackedIndexes {
+		from := to.Add(-indexBackfill)
+		latest, err := s.Repository.GetLatestIndexDate(ctx, index)
+		if err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+		if latest != nil {
+			from = truncateDay(latest.AddDate(0, 0, 1))
+		}
+		if from.After(to) {
+			continue
+		}
+
+		points, err := s.Provider.GetIndexSeries(ctx, index, from, to)
+		if err != nil {
+			logger.Warn().Err(err).Str("index", index).Str("provider", s.Provider.Name()).Msg("Falha ao sincronizar serie de indice")
+			continue
+		}
+
+		rates := make([]*IndexRate, 0, len(points))
+		for _, p := range points {
+			rates = append(rates, &IndexRate{
+				Index: index,
+				Date:  truncateDay(p.Date),
+				Value: p.Value,
+			})
+		}
+		if err := s.Repository.SaveIndexRates(ctx, rates); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+
+		logger.Info().Str("index", index).Int("points", len(rates)).Msg("Serie de indice sincronizada")
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Fynance/internal/domain/shared"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Type string
+
+const (
+	TypeBudgetAlert      Type = shared.NotificationBudgetAlert
+	TypeBudgetExceeded   Type = shared.NotificationBudgetExceeded
+	TypeInvoiceDue       Type = shared.NotificationInvoiceDue
+	TypeBillDue          Type = shared.NotificationBillDue
+	TypeLoanDue          Type = shared.NotificationLoanDue
+	TypeGoalDeadline     Type = shared.NotificationGoalDeadline
+	TypeGoalMilestone    Type = shared.NotificationGoalMilestone
+	TypeSecurityLogin    Type = shared.NotificationSecurityLogin
+	TypeSecurityPassword Type = shared.NotificationSecurityPassword
+)
+
+// Types lista os tipos na ordem exibida nas preferências
+var Types = []Type{
+	TypeBudgetAlert,
+	TypeBudgetExceeded,
+	TypeInvoiceDue,
+	TypeBillDue,
+	TypeLoanDue,
+	TypeGoalDeadline,
+	TypeGoalMilestone,
+	TypeSecurityLogin,
+	TypeSecurityPassword,
+}
+
+func (t Type) IsValid() bool {
+	_, ok := templates[t]
+	return ok
+}
+
+// IsSecurity indica os eventos de segurança, que sempre são enviados por e-mail
+func (t Type) IsSecurity() bool {
+	return t == TypeSecurityLogin || t == TypeSecurityPassword
+}
+
+type Channel string
+
+const (
+	ChannelInApp Channel = "IN_APP"
+	ChannelEmail Channel = "EMAIL"
+	ChannelPush  Channel = "PUSH"
+)
+
+func (c Channel) IsValid() bool {
+	switch c {
+	case ChannelInApp, ChannelEmail, ChannelPush:
+		return true
+	}
+	return false
+}
+
+const (
+	LocalePtBR    = "pt-BR"
+	LocaleEn      = "en"
+	DefaultLocale = LocalePtBR
+)
+
+func IsValidLocale(locale string) bool {
+	return locale == LocalePtBR || locale == LocaleEn
+}
+
+// Notification é uma mensagem da caixa de entrada do usuário, já renderizada no idioma dele
+type Notification struct {
+	Id        ulid.ULID      `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId    ulid.ULID      `gorm:"type:varchar(26);index:idx_notifications_user_created,priority:1;not null" json:"userId"`
+	Type      Type           `gorm:"type:varchar(40);not null" json:"type"`
+	Title     string         `gorm:"type:varchar(200);not null" json:"title"`
+	Body      string         `gorm:"type:text;not null" json:"body"`
+	Data      map[string]any `gorm:"type:text;serializer:json" json:"data"`
+	ReadAt    *time.Time     `gorm:"type:timestamp" json:"readAt"`
+	CreatedAt time.Time      `gorm:"index:idx_notifications_user_created,priority:2;not null" json:"createdAt"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Preferences guarda o idioma das mensagens e os canais habilitados por tipo. Tipos ausentes em
+// Channels usam os canais padrão.
+type Preferences struct {
+	UserId    ulid.ULID          `gorm:"type:varchar(26);primaryKey" json:"userId"`
+	Locale    string             `gorm:"type:varchar(10);not null;default:'pt-BR'" json:"locale"`
+	Channels  map[Type][]Channel `gorm:"type:text;serializer:json;not null" json:"channels"`
+	CreatedAt time.Time          `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt time.Time          `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (Preferences) TableName() string {
+	return "notification_preferences"
+}
+
+// PushSubscription é a inscrição Web Push de um navegador, com as chaves usadas para cifrar as mensagens
+type PushSubscription struct {
+	Id        ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId    ulid.ULID `gorm:"type:varchar(26);index:idx_push_subscriptions_user;not null" json:"userId"`
+	Endpoint  string    `gorm:"type:varchar(1000);uniqueIndex:idx_push_subscriptions_endpoint;not null" json:"endpoint"`
+	P256dh    string    `gorm:"type:varchar(200);not null" json:"-"`
+	Auth      string    `gorm:"type:varchar(100);not null" json:"-"`
+	UserAgent string    `gorm:"type:varchar(255)" json:"userAgent"`
+	CreatedAt time.Time `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (PushSubscription) TableName() string {
+	return "push_subscriptions"
+}
+
+// EmailSender entrega mensagens por e-mail
+type EmailSender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// PushSender entrega mensagens Web Push assinadas com a chave VAPID do servidor
+type PushSender interface {
+	PublicKey() string
+	Send(ctx context.Context, subscription *PushSubscription, payload []byte) error
+}
+
+// ErrSubscriptionGone indica que o serviço de push descartou a inscrição e ela deve ser removida
+var ErrSubscriptionGone = errors.New("inscricao de push expirada ou cancelada")
+
+// defaultChannels são os canais usados enquanto o usuário não configura o tipo: caixa de entrada e push
+// para tudo, e-mail para vencimentos, orçamento estourado e segurança
+func defaultChannels(t Type) []Channel {
+	switch t {
+	case TypeBudgetExceeded, TypeInvoiceDue, TypeBillDue, TypeLoanDue, TypeSecurityLogin, TypeSecurityPassword:
+		return []Channel{ChannelInApp, ChannelEmail, ChannelPush}
+	default:
+		return []Channel{ChannelInApp, ChannelPush}
+	}
+}
+
+// ChannelsFor devolve os canais habilitados para o tipo
+func (p *Preferences) ChannelsFor(t Type) []Channel {
+	if channels, ok := p.Channels[t]; ok {
+		return channels
+	}
+	return defaultChannels(t)
+}
+
+// withDefaults preenche Channels com todos os tipos, para que a resposta mostre os canais efetivos
+func (p *Preferences) withDefaults() *Preferences {
+	channels := make(map[Type][]Channel, len(Types))
+	for _, t := range Types {
+		channels[t] = p.ChannelsFor(t)
+	}
+	p.Channels = channels
+	return p
+}
@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Repository interface {
+	Create(ctx context.Context, notification *Notification) error
+	List(ctx context.Context, userID ulid.ULID, unreadOnly bool, pagination *pkg.PaginationParams) ([]*Notification, int64, error)
+	CountUnread(ctx context.Context, userID ulid.ULID) (int64, error)
+	// SetReadAt marca a notificação como lida (readAt preenchido) ou não lida (nil)
+	SetReadAt(ctx context.Context, id, userID ulid.ULID, readAt *time.Time) error
+	MarkAllRead(ctx context.Context, userID ulid.ULID, readAt time.Time) (int64, error)
+	Delete(ctx context.Context, id, userID ulid.ULID) error
+
+	GetPreferences(ctx context.Context, userID ulid.ULID) (*Preferences, error)
+	SavePreferences(ctx context.Context, preferences *Preferences) error
+
+	// SavePushSubscription cria a inscrição ou atualiza as chaves de um endpoint já cadastrado
+	SavePushSubscription(ctx context.Context, subscription *PushSubscription) error
+	ListPushSubscriptions(ctx context.Context, userID ulid.ULID) ([]*PushSubscription, error)
+	DeletePushSubscription(ctx context.Context, userID ulid.ULID, endpoint string) error
+}
@@ -0,0 +1,405 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"Fynance/internal/domain/event"
+	"Fynance/internal/domain/plan"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/user"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// deliveryTimeout limita o envio por e-mail e push, que acontece fora da requisição que publicou o evento
+const deliveryTimeout = 30 * time.Second
+
+// UserGetter busca o destinatário para obter o e-mail e o plano
+type UserGetter interface {
+	GetByID(ctx context.Context, id ulid.ULID) (*user.User, error)
+}
+
+type Service struct {
+	Repository Repository
+	Users      UserGetter
+	// Email e Push são opcionais; sem eles as notificações ficam apenas na caixa de entrada
+	Email EmailSender
+	Push  PushSender
+	shared.BaseService
+
+	// deliveries acompanha os envios por e-mail e push em andamento, aguardados por Wait; é ponteiro
+	// porque os handlers HTTP guardam uma cópia do service
+	deliveries *sync.WaitGroup
+}
+
+var _ shared.Notifier = (*Service)(nil)
+
+func NewService(
+	repo Repository,
+	users UserGetter,
+	email EmailSender,
+	push PushSender,
+	userChecker *shared.UserCheckerService,
+) *Service {
+	return &Service{
+		Repository: repo,
+		Users:      users,
+		Email:      email,
+		Push:       push,
+		BaseService: shared.BaseService{
+			UserChecker: userChecker,
+		},
+		deliveries: &sync.WaitGroup{},
+	}
+}
+
+// HandleGoalMilestone avisa o usuário do marco atingido pela meta; o dado do evento preenche o template
+func (s *Service) HandleGoalMilestone(ctx context.Context, evt *event.Event) error {
+	var data map[string]any
+	if err := evt.Decode(&data); err != nil {
+		return err
+	}
+	return s.Notify(ctx, evt.UserId, shared.NotificationGoalMilestone, data)
+}
+
+// Notify renderiza a mensagem no idioma do usuário, grava na caixa de entrada e envia por e-mail e push
+// conforme as preferências. E-mail e push exigem plano com notificações, exceto eventos de segurança,
+// que sempre vão por e-mail. O envio começa só depois do commit da transação do ctx, então uma operação
+// desfeita não avisa o usuário. Falhas nesses canais são registradas no log e não afetam quem publicou.
+func (s *Service) Notify(ctx context.Context, userID ulid.ULID, notificationType string, data map[string]any) error {
+	t := Type(notificationType)
+	if !t.IsValid() {
+		return fmt.Errorf("tipo de notificacao desconhecido: %s", notificationType)
+	}
+
+	recipient, err := s.Users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	preferences, err := s.loadPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	title, body, err := render(t, preferences.Locale, data)
+	if err != nil {
+		return err
+	}
+	channels := allowedChannels(t, recipient.Plan, preferences)
+
+	if slices.Contains(channels, ChannelInApp) {
+		if err := s.Repository.Create(ctx, &Notification{
+			Id:        pkg.GenerateULIDObject(),
+			UserId:    userID,
+			Type:      t,
+			Title:     title,
+			Body:      body,
+			Data:      data,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+	}
+
+	sendEmail := slices.Contains(channels, ChannelEmail) && s.Email != nil && recipient.Email != ""
+	sendPush := slices.Contains(channels, ChannelPush) && s.Push != nil
+	if sendEmail || sendPush {
+		shared.AfterCommit(ctx, func() {
+			s.deliveries.Add(1)
+			go func() {
+				defer s.deliveries.Done()
+				// o ctx da transação já encerrada não serve para as consultas do envio
+				s.deliver(context.Background(), recipient, t, title, body, sendEmail, sendPush)
+			}()
+		})
+	}
+	return nil
+}
+
+// Wait aguarda os envios por e-mail e push em andamento, para que o desligamento não os interrompa
+func (s *Service) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// allowedChannels aplica a restrição do plano aos canais escolhidos pelo usuário
+func allowedChannels(t Type, userPlan user.Plan, preferences *Preferences) []Channel {
+	channels := preferences.ChannelsFor(t)
+	if t.IsSecurity() {
+		if !slices.Contains(channels, ChannelEmail) {
+			channels = append(slices.Clone(channels), ChannelEmail)
+		}
+		return channels
+	}
+	if plan.GetLimits(userPlan).HasNotifications {
+		return channels
+	}
+	if slices.Contains(channels, ChannelInApp) {
+		return []Channel{ChannelInApp}
+	}
+	return nil
+}
+
+func (s *Service) deliver(ctx context.Context, recipient *user.User, t Type, title, body string, sendEmail, sendPush bool) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	if sendEmail {
+		if err := s.Email.Send(ctx, recipient.Email, title, body); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("user_id", recipient.Id.String()).
+				Str("type", string(t)).
+				Msg("Erro ao enviar notificacao por e-mail")
+		}
+	}
+	if sendPush {
+		s.sendPush(ctx, recipient.Id, t, title, body)
+	}
+}
+
+func (s *Service) sendPush(ctx context.Context, userID ulid.ULID, t Type, title, body string) {
+	subscriptions, err := s.Repository.ListPushSubscriptions(ctx, userID)
+	if err != nil {
+		logger.Warn().Err(err).Str("user_id", userID.String()).Msg("Erro ao carregar inscricoes de push")
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]string{"type": string(t), "title": title, "body": body})
+	if err != nil {
+		return
+	}
+	for _, subscription := range subscriptions {
+		err := s.Push.Send(ctx, subscription, payload)
+		if errors.Is(err, ErrSubscriptionGone) {
+			if err := s.Repository.DeletePushSubscription(ctx, userID, subscription.Endpoint); err != nil {
+				logger.Warn().Err(err).Str("user_id", userID.String()).Msg("Erro ao remover inscricao de push expirada")
+			}
+			continue
+		}
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("user_id", userID.String()).
+				Str("type", string(t)).
+				Msg("Erro ao enviar notificacao push")
+		}
+	}
+}
+
+func (s *Service) ListNotifications(ctx context.Context, userID ulid.ULID, unreadOnly bool, pagination *pkg.PaginationParams) ([]*Notification, int64, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+
+	notifications, total, err := s.Repository.List(ctx, userID, unreadOnly, pagination)
+	if err != nil {
+		return nil, 0, appErrors.NewDatabaseError(err)
+	}
+	return notifications, total, nil
+}
+
+func (s *Service) CountUnread(ctx context.Context, userID ulid.ULID) (int64, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	count, err := s.Repository.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+	return count, nil
+}
+
+func (s *Service) MarkRead(ctx context.Context, id, userID ulid.ULID) error {
+	now := time.Now()
+	return s.setReadAt(ctx, id, userID, &now)
+}
+
+func (s *Service) MarkUnread(ctx context.Context, id, userID ulid.ULID) error {
+	return s.setReadAt(ctx, id, userID, nil)
+}
+
+func (s *Service) setReadAt(ctx context.Context, id, userID ulid.ULID, readAt *time.Time) error {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.SetReadAt(ctx, id, userID, readAt); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("notificacao")
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// MarkAllRead marca todas as notificações como lidas e devolve quantas foram alteradas
+func (s *Service) MarkAllRead(ctx context.Context, userID ulid.ULID) (int64, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	updated, err := s.Repository.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteNotification(ctx context.Context, id, userID ulid.ULID) error {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.Delete(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("notificacao")
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// GetPreferences devolve o idioma e os canais efetivos de cada tipo
+func (s *Service) GetPreferences(ctx context.Context, userID ulid.ULID) (*Preferences, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.loadPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return preferences.withDefaults(), nil
+}
+
+// UpdatePreferences altera o idioma e os canais dos tipos informados; os demais tipos não mudam.
+// Uma lista vazia desativa o tipo em todos os canais.
+func (s *Service) UpdatePreferences(ctx context.Context, userID ulid.ULID, locale *string, channels map[Type][]Channel) (*Preferences, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.loadPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if locale != nil {
+		if !IsValidLocale(*locale) {
+			return nil, appErrors.NewValidationError("locale", "deve ser pt-BR ou en")
+		}
+		preferences.Locale = *locale
+	}
+
+	for t, selected := range channels {
+		if !t.IsValid() {
+			return nil, appErrors.NewValidationError("channels", "tipo de notificacao invalido: "+string(t))
+		}
+		normalized := make([]Channel, 0, len(selected))
+		for _, channel := range selected {
+			if !channel.IsValid() {
+				return nil, appErrors.NewValidationError("channels", "canal invalido: "+string(channel))
+			}
+			if !slices.Contains(normalized, channel) {
+				normalized = append(normalized, channel)
+			}
+		}
+		preferences.Channels[t] = normalized
+	}
+
+	preferences.UpdatedAt = time.Now()
+	if err := s.Repository.SavePreferences(ctx, preferences); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return preferences.withDefaults(), nil
+}
+
+// PushPublicKey devolve a chave pública VAPID usada pelo navegador para criar a inscrição
+func (s *Service) PushPublicKey() (string, error) {
+	if s.Push == nil {
+		return "", appErrors.NewValidationError("push", "notificacoes push nao estao configuradas")
+	}
+	return s.Push.PublicKey(), nil
+}
+
+func (s *Service) Subscribe(ctx context.Context, subscription *PushSubscription) error {
+	if err := s.EnsureUserExists(ctx, subscription.UserId); err != nil {
+		return err
+	}
+	if s.Push == nil {
+		return appErrors.NewValidationError("push", "notificacoes push nao estao configuradas")
+	}
+	if subscription.Endpoint == "" {
+		return appErrors.NewValidationError("endpoint", "é obrigatório")
+	}
+	if subscription.P256dh == "" || subscription.Auth == "" {
+		return appErrors.NewValidationError("keys", "p256dh e auth sao obrigatorios")
+	}
+
+	subscription.Id = pkg.GenerateULIDObject()
+	subscription.CreatedAt = time.Now()
+	if err := s.Repository.SavePushSubscription(ctx, subscription); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) Unsubscribe(ctx context.Context, userID ulid.ULID, endpoint string) error {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeletePushSubscription(ctx, userID, endpoint); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("inscricao")
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) loadPreferences(ctx context.Context, userID ulid.ULID) (*Preferences, error) {
+	preferences, err := s.Repository.GetPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			now := time.Now()
+			return &Preferences{
+				UserId:    userID,
+				Locale:    DefaultLocale,
+				Channels:  map[Type][]Channel{},
+				CreatedAt: now,
+				UpdatedAt: now,
+			}, nil
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	if preferences.Channels == nil {
+		preferences.Channels = map[Type][]Channel{}
+	}
+	return preferences, nil
+}
@@ -0,0 +1,245 @@
+package notification
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// message é o texto de um tipo de notificação em um idioma
+type message struct {
+	Title string
+	Body  string
+}
+
+// templateSources são os textos por tipo e idioma. Os dados vêm do domínio que publica o evento:
+// valores em float64, datas em time.Time e daysBefore com a antecedência do lembrete.
+var templateSources = map[Type]map[string]message{
+	TypeBudgetAlert: {
+		LocalePtBR: {
+			Title: "Orçamento de {{.category}} em {{percent .percentage}}",
+			Body:  "Você já gastou {{money .spent}} de {{money .amount}} previstos para {{.category}} neste mês.",
+		},
+		LocaleEn: {
+			Title: "{{.category}} budget at {{percent .percentage}}",
+			Body:  "You have spent {{money .spent}} of the {{money .amount}} planned for {{.category}} this month.",
+		},
+	},
+	TypeBudgetExceeded: {
+		LocalePtBR: {
+			Title: "Orçamento de {{.category}} estourado",
+			Body:  "Os gastos com {{.category}} chegaram a {{money .spent}}, acima do limite de {{money .amount}}.",
+		},
+		LocaleEn: {
+			Title: "{{.category}} budget exceeded",
+			Body:  "Spending on {{.category}} reached {{money .spent}}, above the {{money .amount}} limit.",
+		},
+	},
+	TypeInvoiceDue: {
+		LocalePtBR: {
+			Title: "{{.title}} vence {{when .daysBefore}}",
+			Body:  "A fatura do cartão, de {{money .amount}}, vence em {{date .dueDate}}.",
+		},
+		LocaleEn: {
+			Title: "Credit card invoice due {{when .daysBefore}}",
+			Body:  "{{.title}}: {{money .amount}} due on {{date .dueDate}}.",
+		},
+	},
+	TypeBillDue: {
+		LocalePtBR: {
+			Title: "{{.title}} vence {{when .daysBefore}}",
+			Body:  "{{if .estimated}}Valor estimado de{{else}}Valor de{{end}} {{money .amount}}, com vencimento em {{date .dueDate}}.",
+		},
+		LocaleEn: {
+			Title: "{{.title}} is due {{when .daysBefore}}",
+			Body:  "{{if .estimated}}Estimated amount{{else}}Amount{{end}}: {{money .amount}}, due on {{date .dueDate}}.",
+		},
+	},
+	TypeLoanDue: {
+		LocalePtBR: {
+			Title: "{{.title}} vence {{when .daysBefore}}",
+			Body:  "O valor da parcela é {{money .amount}}, com vencimento em {{date .dueDate}}.",
+		},
+		LocaleEn: {
+			Title: "Loan installment due {{when .daysBefore}}",
+			Body:  "{{.title}}: {{money .amount}} due on {{date .dueDate}}.",
+		},
+	},
+	TypeGoalDeadline: {
+		LocalePtBR: {
+			Title: "Prazo da meta {{.goal}} termina {{when .daysBefore}}",
+			Body:  "A data limite para atingir a meta é {{date .dueDate}}.",
+		},
+		LocaleEn: {
+			Title: "{{.goal}} deadline is {{when .daysBefore}}",
+			Body:  "The deadline to reach this goal is {{date .dueDate}}.",
+		},
+	},
+	TypeGoalMilestone: {
+		LocalePtBR: {
+			Title: "Meta {{.goal}} atingiu {{.milestone}}%",
+			Body:  "Você já juntou {{money .currentAmount}} de {{money .targetAmount}}.",
+		},
+		LocaleEn: {
+			Title: "{{.goal}} reached {{.milestone}}%",
+			Body:  "You have saved {{money .currentAmount}} of {{money .targetAmount}}.",
+		},
+	},
+	TypeSecurityLogin: {
+		LocalePtBR: {
+			Title: "Novo acesso à sua conta",
+			Body:  `Sua conta foi acessada em {{datetime .at}}{{if eq .method "google"}} com o Google{{end}}. Se não foi você, altere sua senha.`,
+		},
+		LocaleEn: {
+			Title: "New sign-in to your account",
+			Body:  `Your account was accessed on {{datetime .at}}{{if eq .method "google"}} with Google{{end}}. If this wasn't you, change your password.`,
+		},
+	},
+	TypeSecurityPassword: {
+		LocalePtBR: {
+			Title: "Senha alterada",
+			Body:  "A senha da sua conta foi alterada em {{datetime .at}}. Se não foi você, entre em contato com o suporte.",
+		},
+		LocaleEn: {
+			Title: "Password changed",
+			Body:  "Your account password was changed on {{datetime .at}}. If this wasn't you, contact support.",
+		},
+	},
+}
+
+type compiledMessage struct {
+	title *template.Template
+	body  *template.Template
+}
+
+var templates = compileTemplates()
+
+func compileTemplates() map[Type]map[string]compiledMessage {
+	compiled := make(map[Type]map[string]compiledMessage, len(templateSources))
+	for t, locales := range templateSources {
+		compiled[t] = make(map[string]compiledMessage, len(locales))
+		for locale, msg := range locales {
+			funcs := templateFuncs(locale)
+			compiled[t][locale] = compiledMessage{
+				title: template.Must(template.New(string(t) + ".title").Funcs(funcs).Parse(msg.Title)),
+				body:  template.Must(template.New(string(t) + ".body").Funcs(funcs).Parse(msg.Body)),
+			}
+		}
+	}
+	return compiled
+}
+
+// render monta o título e o corpo no idioma pedido, usando pt-BR quando o idioma não tem tradução
+func render(t Type, locale string, data map[string]any) (string, string, error) {
+	locales, ok := templates[t]
+	if !ok {
+		return "", "", fmt.Errorf("tipo de notificacao desconhecido: %s", t)
+	}
+	msg, ok := locales[locale]
+	if !ok {
+		msg = locales[DefaultLocale]
+	}
+
+	var title, body strings.Builder
+	if err := msg.title.Execute(&title, data); err != nil {
+		return "", "", err
+	}
+	if err := msg.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	return title.String(), body.String(), nil
+}
+
+func templateFuncs(locale string) template.FuncMap {
+	return template.FuncMap{
+		"money": func(value any) string {
+			return formatMoney(toFloat(value), locale)
+		},
+		"percent": func(value any) string {
+			return fmt.Sprintf("%.0f%%", toFloat(value))
+		},
+		"date": func(value any) string {
+			if locale == LocaleEn {
+				return toTime(value).Format("Jan 2, 2006")
+			}
+			return toTime(value).Format("02/01/2006")
+		},
+		"datetime": func(value any) string {
+			if locale == LocaleEn {
+				return toTime(value).UTC().Format("Jan 2, 2006 15:04 UTC")
+			}
+			return toTime(value).UTC().Format("02/01/2006 15:04 UTC")
+		},
+		"when": func(value any) string {
+			days := int(toFloat(value))
+			if locale == LocaleEn {
+				switch days {
+				case 0:
+					return "today"
+				case 1:
+					return "tomorrow"
+				}
+				return fmt.Sprintf("in %d days", days)
+			}
+			switch days {
+			case 0:
+				return "hoje"
+			case 1:
+				return "amanhã"
+			}
+			return fmt.Sprintf("em %d dias", days)
+		},
+	}
+}
+
+// formatMoney formata o valor em reais no padrão do idioma: "R$ 1.234,56" ou "R$1,234.56"
+func formatMoney(amount float64, locale string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	integer := fmt.Sprintf("%d", cents/100)
+
+	thousands, decimal, prefix := ".", ",", "R$ "
+	if locale == LocaleEn {
+		thousands, decimal, prefix = ",", ".", "R$"
+	}
+
+	var groups []string
+	for len(integer) > 3 {
+		groups = append([]string{integer[len(integer)-3:]}, groups...)
+		integer = integer[:len(integer)-3]
+	}
+	groups = append([]string{integer}, groups...)
+	return fmt.Sprintf("%s%s%s%s%02d", sign, prefix, strings.Join(groups, thousands), decimal, cents%100)
+}
+
+func toFloat(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+func toTime(value any) time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		if parsed, err := time.Parse(time.RFC3339, v); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,168 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec calcula as ocorrências de um agendamento
+type Spec interface {
+	// Next devolve a primeira ocorrência depois de t
+	Next(t time.Time) time.Time
+}
+
+// ParseSpec interpreta um agendamento no formato cron de cinco campos (minuto, hora, dia do mês, mês e
+// dia da semana, com *, listas, intervalos e passos), nos atalhos @hourly, @daily, @weekly e @monthly
+// ou em "@every <duração>". O cron usa o fuso do horário recebido em Next.
+func ParseSpec(spec string) (Spec, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || interval < time.Second {
+			return nil, fmt.Errorf("intervalo invalido em %q", spec)
+		}
+		return everySpec(interval), nil
+	}
+
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("agendamento %q deve ter 5 campos", spec)
+	}
+
+	var (
+		cron cronSpec
+		err  error
+	)
+	if cron.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if cron.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if cron.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if cron.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if cron.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	// 7 também é domingo
+	if cron.dow&(1<<7) != 0 {
+		cron.dow |= 1
+	}
+	cron.domAny = fields[2] == "*"
+	cron.dowAny = fields[4] == "*"
+	return cron, nil
+}
+
+type everySpec time.Duration
+
+func (s everySpec) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// cronSpec guarda os valores aceitos de cada campo como bits
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// cronSearchLimit evita laço infinito em agendamentos que nunca ocorrem, como 30 de fevereiro
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+func (s cronSpec) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for next.Before(limit) {
+		if s.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !s.matchDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+// matchDay segue o cron: com dia do mês e dia da semana restritos, basta um dos dois coincidir
+func (s cronSpec) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func parseCronField(field string, lower, upper int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			value, err := strconv.Atoi(stepPart)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("passo invalido em %q", field)
+			}
+			step = value
+		}
+
+		start, end := lower, upper
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			first, last, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("valor invalido em %q", field)
+			}
+			if end, err = strconv.Atoi(last); err != nil {
+				return 0, fmt.Errorf("valor invalido em %q", field)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("valor invalido em %q", field)
+			}
+			start = value
+			if !hasStep {
+				end = value
+			}
+		}
+		if start < lower || end > upper || start > end {
+			return 0, fmt.Errorf("valor fora do intervalo %d-%d em %q", lower, upper, field)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HandlerFunc processa um job. O processamento é pelo menos uma vez: se o handler falhar ou o worker
+// cair antes de registrar o resultado, o job roda de novo, então o handler deve ser idempotente.
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// Definition registra um tipo de job na fila
+type Definition struct {
+	Type    string
+	Handler HandlerFunc
+	// MaxAttempts substitui o padrão da fila quando positivo
+	MaxAttempts int
+	// Timeout limita cada tentativa; zero usa defaultTimeout
+	Timeout time.Duration
+	// Schedule, quando informado, enfileira o job periodicamente no formato aceito por ParseSpec
+	Schedule string
+}
+
+// Handle cria a definição de um tipo de job cujo payload é decodificado em T. Um payload que não
+// decodifica leva o job direto para DEAD, já que nova tentativa não resolveria.
+func Handle[T any](jobType string, handler func(ctx context.Context, payload T) error) Definition {
+	return Definition{
+		Type: jobType,
+		Handler: func(ctx context.Context, job *Job) error {
+			var payload T
+			if len(job.Payload) > 0 {
+				if err := job.Decode(&payload); err != nil {
+					return Permanent(fmt.Errorf("payload invalido: %w", err))
+				}
+			}
+			return handler(ctx, payload)
+		},
+	}
+}
+
+// Every monta o agendamento que enfileira o job a cada intervalo
+func Every(interval time.Duration) string {
+	return "@every " + interval.String()
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marca a falha como definitiva: o job vai para DEAD sem novas tentativas
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,114 @@
+package queue
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Status string
+
+const (
+	// StatusPending aguarda RunAt para ser reservado por um worker
+	StatusPending Status = "PENDING"
+	// StatusRunning está reservado por LockedBy até LockedUntil; se o worker cair, o job volta à fila
+	// depois desse prazo
+	StatusRunning   Status = "RUNNING"
+	StatusSucceeded Status = "SUCCEEDED"
+	// StatusDead é a fila de mensagens mortas: esgotou as tentativas, falhou de forma permanente ou não
+	// tem handler registrado. Só sai daqui pelo reenvio manual.
+	StatusDead Status = "DEAD"
+)
+
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusRunning, StatusSucceeded, StatusDead:
+		return true
+	}
+	return false
+}
+
+// Job é uma unidade de trabalho assíncrona gravada no Postgres. Payload é o JSON do dado enfileirado e
+// é decodificado no tipo do handler. UniqueKey, quando informado, impede outro job ativo com a mesma chave.
+type Job struct {
+	Id          ulid.ULID       `gorm:"type:varchar(26);primaryKey" json:"id"`
+	Type        string          `gorm:"type:varchar(100);not null;index:idx_jobs_type" json:"type"`
+	Payload     json.RawMessage `gorm:"type:text;not null" json:"payload"`
+	Status      Status          `gorm:"type:varchar(20);not null;index:idx_jobs_due,priority:1" json:"status"`
+	Attempts    int             `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int             `gorm:"not null" json:"maxAttempts"`
+	RunAt       time.Time       `gorm:"type:timestamp;not null;index:idx_jobs_due,priority:2" json:"runAt"`
+	UniqueKey   *string         `gorm:"type:varchar(150);uniqueIndex:idx_jobs_unique_active,where:unique_key IS NOT NULL AND (status = 'PENDING' OR status = 'RUNNING')" json:"uniqueKey,omitempty"`
+	LockedBy    string          `gorm:"type:varchar(100)" json:"lockedBy,omitempty"`
+	LockedUntil *time.Time      `gorm:"type:timestamp" json:"lockedUntil,omitempty"`
+	LastError   string          `gorm:"type:text" json:"lastError,omitempty"`
+	StartedAt   *time.Time      `gorm:"type:timestamp" json:"startedAt,omitempty"`
+	FinishedAt  *time.Time      `gorm:"type:timestamp" json:"finishedAt,omitempty"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// Decode converte o payload no tipo do handler
+func (j *Job) Decode(target any) error {
+	return json.Unmarshal(j.Payload, target)
+}
+
+// Schedule enfileira um job do tipo JobType a cada ocorrência de Spec. NextRunAt é compartilhado pelas
+// instâncias da API, então cada ocorrência gera um único job.
+type Schedule struct {
+	Name      string          `gorm:"type:varchar(100);primaryKey" json:"name"`
+	JobType   string          `gorm:"type:varchar(100);not null" json:"jobType"`
+	Spec      string          `gorm:"type:varchar(100);not null" json:"spec"`
+	Payload   json.RawMessage `gorm:"type:text;not null" json:"payload"`
+	NextRunAt time.Time       `gorm:"type:timestamp;not null;index:idx_job_schedules_next_run" json:"nextRunAt"`
+	LastRunAt *time.Time      `gorm:"type:timestamp" json:"lastRunAt,omitempty"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (Schedule) TableName() string {
+	return "job_schedules"
+}
+
+// Filter restringe a listagem de jobs; campos vazios não filtram
+type Filter struct {
+	Status Status
+	Type   string
+}
+
+// StatusCount é o total de jobs de um tipo em um status
+type StatusCount struct {
+	Type   string `json:"type"`
+	Status Status `json:"status"`
+	Total  int64  `json:"total"`
+}
+
+// RetryPolicy define a espera entre as tentativas de um job, que dobra a cada falha a partir de
+// BaseDelay até MaxDelay. MaxAttempts é o padrão para os tipos que não definem o próprio.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    time.Hour,
+}
+
+// Backoff devolve a espera antes da próxima tentativa, depois de attempts tentativas sem sucesso
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return min(delay, p.MaxDelay)
+}
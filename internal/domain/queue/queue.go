@@ -0,0 +1,249 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"Fynance/internal/domain/shared"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ErrDuplicate indica que já existe um job ativo com a mesma UniqueKey
+var ErrDuplicate = errors.New("ja existe job ativo com a mesma chave")
+
+// Queue é a fila de jobs em segundo plano sobre o Postgres. Enqueue grava o job na transação do ctx e
+// os workers de todas as instâncias da API reservam os jobs com FOR UPDATE SKIP LOCKED, então cada job
+// roda em uma instância por vez. Os tipos de job e seus agendamentos são registrados com Register.
+type Queue struct {
+	Repository   Repository
+	Transactor   shared.Transactor
+	Retry        RetryPolicy
+	PollInterval time.Duration
+	// Concurrency é o número de jobs executados ao mesmo tempo por esta instância
+	Concurrency int
+	// WorkerID identifica as reservas desta instância
+	WorkerID string
+
+	mu          sync.RWMutex
+	definitions map[string]Definition
+
+	wake       chan struct{}
+	slots      chan struct{}
+	cancel     context.CancelFunc
+	cancelJobs context.CancelFunc
+	wg         sync.WaitGroup
+	started    bool
+}
+
+func NewQueue(repo Repository, transactor shared.Transactor) *Queue {
+	return &Queue{
+		Repository:   repo,
+		Transactor:   transactor,
+		Retry:        DefaultRetryPolicy,
+		PollInterval: time.Second,
+		Concurrency:  4,
+		WorkerID:     newWorkerID(),
+		definitions:  make(map[string]Definition),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+func newWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "worker"
+	}
+	suffix := pkg.GenerateULID()
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), suffix[len(suffix)-6:])
+}
+
+// Register adiciona os tipos de job à fila. Tipos repetidos ou agendamentos inválidos são rejeitados.
+func (q *Queue) Register(definitions ...Definition) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, definition := range definitions {
+		if definition.Type == "" || definition.Handler == nil {
+			return errors.New("job sem tipo ou handler")
+		}
+		if _, exists := q.definitions[definition.Type]; exists {
+			return fmt.Errorf("tipo de job %q ja registrado", definition.Type)
+		}
+		if definition.Schedule != "" {
+			if _, err := nextRun(definition.Schedule, time.Now()); err != nil {
+				return fmt.Errorf("agendamento do job %q: %w", definition.Type, err)
+			}
+		}
+		q.definitions[definition.Type] = definition
+	}
+	return nil
+}
+
+func (q *Queue) definition(jobType string) (Definition, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	definition, ok := q.definitions[jobType]
+	return definition, ok
+}
+
+// EnqueueOption ajusta o job antes de gravá-lo
+type EnqueueOption func(*Job)
+
+// RunAt agenda a primeira tentativa para a data
+func RunAt(at time.Time) EnqueueOption {
+	return func(job *Job) {
+		job.RunAt = at
+	}
+}
+
+// UniqueKey impede outro job ativo com a mesma chave; Enqueue devolve ErrDuplicate nesse caso
+func UniqueKey(key string) EnqueueOption {
+	return func(job *Job) {
+		job.UniqueKey = &key
+	}
+}
+
+// MaxAttempts substitui o limite de tentativas do tipo para este job
+func MaxAttempts(attempts int) EnqueueOption {
+	return func(job *Job) {
+		if attempts > 0 {
+			job.MaxAttempts = attempts
+		}
+	}
+}
+
+// Enqueue grava um job do tipo registrado com o payload em JSON. Dentro de uma transação o job só existe
+// se ela for confirmada, e os workers desta instância são acordados logo após o commit.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, opts ...EnqueueOption) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	job, err := q.newJob(jobType, data, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	created, err := q.Repository.Create(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrDuplicate
+	}
+	shared.AfterCommit(ctx, q.notify)
+	return job, nil
+}
+
+func (q *Queue) newJob(jobType string, payload json.RawMessage, now time.Time) (*Job, error) {
+	definition, ok := q.definition(jobType)
+	if !ok {
+		return nil, fmt.Errorf("tipo de job %q nao registrado", jobType)
+	}
+	maxAttempts := definition.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = q.Retry.MaxAttempts
+	}
+	return &Job{
+		Id:          pkg.GenerateULIDObject(),
+		Type:        jobType,
+		Payload:     payload,
+		Status:      StatusPending,
+		MaxAttempts: max(maxAttempts, 1),
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+func (q *Queue) ListJobs(ctx context.Context, filter Filter, pagination *pkg.PaginationParams) ([]*Job, int64, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, appErrors.NewValidationError("status", "status invalido")
+	}
+	jobs, total, err := q.Repository.List(ctx, filter, pagination)
+	if err != nil {
+		return nil, 0, appErrors.NewDatabaseError(err)
+	}
+	return jobs, total, nil
+}
+
+func (q *Queue) GetJob(ctx context.Context, id ulid.ULID) (*Job, error) {
+	job, err := q.Repository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("job")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return job, nil
+}
+
+// RetryJob devolve um job DEAD à fila para rodar agora, com as tentativas zeradas
+func (q *Queue) RetryJob(ctx context.Context, id ulid.ULID) (*Job, error) {
+	job, err := q.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != StatusDead {
+		return nil, appErrors.NewValidationError("status", "apenas jobs DEAD podem ser reenviados")
+	}
+
+	if err := q.Repository.Requeue(ctx, id, time.Now()); err != nil {
+		if shared.IsUniqueConstraintError(err) {
+			return nil, appErrors.NewConflictError("job ativo com a mesma chave")
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewValidationError("status", "apenas jobs DEAD podem ser reenviados")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	q.notify()
+	return q.GetJob(ctx, id)
+}
+
+// Stats conta os jobs por tipo e status
+func (q *Queue) Stats(ctx context.Context) ([]StatusCount, error) {
+	counts, err := q.Repository.CountByStatus(ctx)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return counts, nil
+}
+
+func (q *Queue) ListSchedules(ctx context.Context) ([]*Schedule, error) {
+	schedules, err := q.Repository.ListSchedules(ctx)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return schedules, nil
+}
+
+// Cleanup remove os jobs concluídos com sucesso há mais de retention; jobs DEAD são mantidos para análise
+func (q *Queue) Cleanup(ctx context.Context, now time.Time, retention time.Duration) (int64, error) {
+	return q.Repository.DeleteSucceededBefore(ctx, now.Add(-retention))
+}
+
+// nextRun devolve a próxima ocorrência do agendamento depois de now
+func nextRun(spec string, now time.Time) (time.Time, error) {
+	parsed, err := ParseSpec(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := parsed.Next(now)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("agendamento %q nunca ocorre", spec)
+	}
+	return next, nil
+}
@@ -0,0 +1,42 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Repository interface {
+	// Create grava o job e participa da transação aberta no ctx. Devolve false, sem erro, quando já
+	// existe um job ativo com a mesma UniqueKey.
+	Create(ctx context.Context, job *Job) (bool, error)
+	// Claim reserva até limit jobs vencidos para o worker, incluindo os RUNNING com reserva expirada,
+	// conta a tentativa e devolve os jobs atualizados. Jobs reservados por outra instância são pulados.
+	Claim(ctx context.Context, workerID string, now, leaseUntil time.Time, limit int) ([]*Job, error)
+	// ExtendLease prorroga a reserva enquanto o job roda; devolve gorm.ErrRecordNotFound se a reserva
+	// passou para outro worker
+	ExtendLease(ctx context.Context, job *Job, leaseUntil time.Time) error
+	// Finish grava o resultado da tentativa reservada em job.LockedBy e job.Attempts; devolve
+	// gorm.ErrRecordNotFound se a reserva passou para outro worker
+	Finish(ctx context.Context, job *Job) error
+	FindByID(ctx context.Context, id ulid.ULID) (*Job, error)
+	List(ctx context.Context, filter Filter, pagination *pkg.PaginationParams) ([]*Job, int64, error)
+	CountByStatus(ctx context.Context) ([]StatusCount, error)
+	// Requeue devolve um job DEAD à fila com as tentativas zeradas
+	Requeue(ctx context.Context, id ulid.ULID, runAt time.Time) error
+	// DeleteSucceededBefore remove os jobs concluídos com sucesso antes da data
+	DeleteSucceededBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// SaveSchedules cria ou atualiza os agendamentos; NextRunAt só é substituído quando Spec muda
+	SaveSchedules(ctx context.Context, schedules []*Schedule) error
+	// DeleteSchedulesExcept remove os agendamentos que não estão mais registrados
+	DeleteSchedulesExcept(ctx context.Context, names []string) error
+	// ClaimDueSchedules bloqueia os agendamentos vencidos até o fim da transação do ctx, pulando os
+	// bloqueados por outra instância
+	ClaimDueSchedules(ctx context.Context, now time.Time, limit int) ([]*Schedule, error)
+	UpdateSchedule(ctx context.Context, schedule *Schedule) error
+	ListSchedules(ctx context.Context) ([]*Schedule, error)
+}
@@ -0,0 +1,356 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// claimLease é a reserva de um job; enquanto ele roda, a reserva é renovada a cada leaseRenewal.
+	// Se a instância cair, outra retoma o job depois desse prazo.
+	claimLease   = time.Minute
+	leaseRenewal = 20 * time.Second
+	// defaultTimeout limita cada tentativa dos tipos que não definem Timeout
+	defaultTimeout = 10 * time.Minute
+	// scheduleBatchSize limita os agendamentos enfileirados de uma vez
+	scheduleBatchSize = 50
+	// errorLimit limita o trecho do erro guardado no job
+	errorLimit = 1024
+)
+
+// Start registra os agendamentos dos tipos de job e inicia os workers até Stop
+func (q *Queue) Start(ctx context.Context) error {
+	if err := q.syncSchedules(ctx, time.Now()); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.started {
+		return nil
+	}
+	q.started = true
+
+	concurrency := max(q.Concurrency, 1)
+	q.slots = make(chan struct{}, concurrency)
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	q.cancel = cancel
+	q.cancelJobs = cancelJobs
+	q.wg.Add(1)
+	go q.loop(loopCtx, jobsCtx)
+
+	logger.Info().
+		Str("worker_id", q.WorkerID).
+		Int("concurrency", concurrency).
+		Int("job_types", len(q.definitions)).
+		Msg("Fila de jobs iniciada")
+	return nil
+}
+
+// Stop para de reservar jobs e espera os que estão rodando até o prazo do ctx. Os que não terminarem a
+// tempo são cancelados e voltam à fila.
+func (q *Queue) Stop(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.started {
+		q.mu.Unlock()
+		return nil
+	}
+	q.cancel()
+	q.started = false
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancelJobs()
+		logger.Info().Msg("Fila de jobs parada")
+		return nil
+	case <-ctx.Done():
+		q.cancelJobs()
+		return ctx.Err()
+	}
+}
+
+// syncSchedules grava os agendamentos dos tipos registrados e remove os que saíram do código
+func (q *Queue) syncSchedules(ctx context.Context, now time.Time) error {
+	q.mu.RLock()
+	schedules := make([]*Schedule, 0, len(q.definitions))
+	names := make([]string, 0, len(q.definitions))
+	for _, definition := range q.definitions {
+		if definition.Schedule == "" {
+			continue
+		}
+		next, err := nextRun(definition.Schedule, now)
+		if err != nil {
+			q.mu.RUnlock()
+			return err
+		}
+		schedules = append(schedules, &Schedule{
+			Name:      definition.Type,
+			JobType:   definition.Type,
+			Spec:      definition.Schedule,
+			Payload:   json.RawMessage("{}"),
+			NextRunAt: next,
+			UpdatedAt: now,
+		})
+		names = append(names, definition.Type)
+	}
+	q.mu.RUnlock()
+
+	return shared.WithinTransaction(ctx, q.Transactor, func(ctx context.Context) error {
+		if len(schedules) > 0 {
+			if err := q.Repository.SaveSchedules(ctx, schedules); err != nil {
+				return err
+			}
+		}
+		return q.Repository.DeleteSchedulesExcept(ctx, names)
+	})
+}
+
+func (q *Queue) loop(ctx, jobsCtx context.Context) {
+	defer q.wg.Done()
+
+	interval := q.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		q.tick(ctx, jobsCtx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+func (q *Queue) tick(ctx, jobsCtx context.Context) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error().Err(fmt.Errorf("%v", rec)).Msg("Fila de jobs interrompida por panic")
+		}
+	}()
+
+	if err := q.enqueueDueSchedules(ctx, time.Now()); err != nil && ctx.Err() == nil {
+		logger.Error().Err(err).Msg("Falha ao enfileirar jobs agendados")
+	}
+	q.fill(ctx, jobsCtx)
+}
+
+// enqueueDueSchedules enfileira um job por agendamento vencido e avança NextRunAt na mesma transação.
+// Se a execução anterior ainda estiver ativa, a ocorrência é pulada.
+func (q *Queue) enqueueDueSchedules(ctx context.Context, now time.Time) error {
+	enqueued := false
+	err := shared.WithinTransaction(ctx, q.Transactor, func(ctx context.Context) error {
+		schedules, err := q.Repository.ClaimDueSchedules(ctx, now, scheduleBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, schedule := range schedules {
+			next, err := nextRun(schedule.Spec, now)
+			if err != nil {
+				logger.Error().Err(err).Str("schedule", schedule.Name).Msg("Agendamento de job invalido")
+				next = now.Add(time.Hour)
+			}
+			schedule.LastRunAt = &now
+			schedule.NextRunAt = next
+			if err := q.Repository.UpdateSchedule(ctx, schedule); err != nil {
+				return err
+			}
+
+			job, err := q.newJob(schedule.JobType, schedule.Payload, now)
+			if err != nil {
+				logger.Warn().Err(err).Str("schedule", schedule.Name).Msg("Agendamento sem job registrado")
+				continue
+			}
+			key := "schedule:" + schedule.Name
+			job.UniqueKey = &key
+			created, err := q.Repository.Create(ctx, job)
+			if err != nil {
+				return err
+			}
+			if !created {
+				logger.Debug().Str("schedule", schedule.Name).Msg("Execucao anterior ainda ativa, ocorrencia pulada")
+				continue
+			}
+			enqueued = true
+		}
+		return nil
+	})
+	if err == nil && enqueued {
+		q.notify()
+	}
+	return err
+}
+
+// fill reserva jobs para as vagas livres e executa cada um em uma goroutine
+func (q *Queue) fill(ctx, jobsCtx context.Context) {
+	free := cap(q.slots) - len(q.slots)
+	if free <= 0 || ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	jobs, err := q.Repository.Claim(ctx, q.WorkerID, now, now.Add(claimLease), free)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error().Err(err).Msg("Falha ao reservar jobs")
+		}
+		return
+	}
+
+	for _, job := range jobs {
+		q.slots <- struct{}{}
+		q.wg.Add(1)
+		go func(job *Job) {
+			defer q.wg.Done()
+			defer func() {
+				<-q.slots
+				q.notify()
+			}()
+			q.process(jobsCtx, job)
+		}(job)
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job *Job) {
+	definition, ok := q.definition(job.Type)
+	start := time.Now()
+
+	var err error
+	switch {
+	case !ok:
+		err = Permanent(fmt.Errorf("tipo de job %q nao registrado", job.Type))
+	case job.Attempts > job.MaxAttempts:
+		err = Permanent(errors.New("tentativas esgotadas; a ultima foi interrompida sem resultado"))
+	default:
+		err = q.run(ctx, definition, job)
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		job.Status = StatusSucceeded
+		job.FinishedAt = &now
+		job.LastError = ""
+	case ctx.Err() != nil:
+		// a instância está parando: o job volta para a fila sem esperar o backoff
+		job.Status = StatusPending
+		job.RunAt = now
+		job.LastError = truncate("interrompido no desligamento: "+err.Error(), errorLimit)
+	case isPermanent(err) || job.Attempts >= job.MaxAttempts:
+		job.Status = StatusDead
+		job.FinishedAt = &now
+		job.LastError = truncate(err.Error(), errorLimit)
+	default:
+		job.Status = StatusPending
+		job.RunAt = now.Add(q.Retry.Backoff(job.Attempts))
+		job.LastError = truncate(err.Error(), errorLimit)
+	}
+
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("job_id", job.Id.String()).
+			Str("job", job.Type).
+			Int("attempts", job.Attempts).
+			Str("status", string(job.Status)).
+			Msg("Falha ao executar job")
+	} else {
+		logger.Debug().
+			Str("job_id", job.Id.String()).
+			Str("job", job.Type).
+			Dur("duration", now.Sub(start)).
+			Msg("Job executado")
+	}
+
+	if finishErr := q.Repository.Finish(context.WithoutCancel(ctx), job); finishErr != nil {
+		event := logger.Error()
+		if errors.Is(finishErr, gorm.ErrRecordNotFound) {
+			event = logger.Warn()
+			finishErr = errors.New("reserva do job expirou e passou para outro worker")
+		}
+		event.Err(finishErr).Str("job_id", job.Id.String()).Msg("Erro ao registrar resultado do job")
+	}
+}
+
+func (q *Queue) run(ctx context.Context, definition Definition, job *Job) (err error) {
+	timeout := definition.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go q.keepLease(ctx, cancel, job, stop)
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic no handler: %v", rec)
+		}
+	}()
+	return definition.Handler(ctx, job)
+}
+
+// keepLease renova a reserva do job enquanto ele roda e cancela a execução se a reserva for perdida
+func (q *Queue) keepLease(ctx context.Context, cancel context.CancelFunc, job *Job, stop <-chan struct{}) {
+	ticker := time.NewTicker(leaseRenewal)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := q.Repository.ExtendLease(ctx, job, time.Now().Add(claimLease))
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Warn().Str("job_id", job.Id.String()).Msg("Reserva do job perdida, cancelando execucao")
+				cancel()
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				logger.Error().Err(err).Str("job_id", job.Id.String()).Msg("Erro ao renovar reserva do job")
+			}
+		}
+	}
+}
+
+// notify acorda o worker sem bloquear; avisos seguidos se acumulam numa única rodada
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
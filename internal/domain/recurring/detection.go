@@ -0,0 +1,348 @@
+package recurring
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type ChargeSource string
+
+const (
+	SourceTransaction ChargeSource = "TRANSACTION"
+	SourceCreditCard  ChargeSource = "CREDIT_CARD"
+)
+
+// Charge é uma cobrança do histórico analisada pelo detector. AccountId vem das transações e
+// CreditCardId das compras no cartão.
+type Charge struct {
+	Id           ulid.ULID
+	Source       ChargeSource
+	AccountId    *ulid.ULID
+	CreditCardId *ulid.ULID
+	CategoryId   *ulid.ULID
+	Description  string
+	Amount       float64
+	Date         time.Time
+}
+
+// Cadence é a periodicidade detectada, no mesmo formato de frequência e intervalo das recorrências
+type Cadence struct {
+	Frequency FrequencyType `json:"frequency"`
+	Interval  int           `json:"interval"`
+	Days      int           `json:"days"`
+	Label     string        `json:"label"`
+}
+
+// PriceChange registra uma mudança de valor entre duas cobranças seguidas
+type PriceChange struct {
+	Date          time.Time `json:"date"`
+	OldAmount     float64   `json:"oldAmount"`
+	NewAmount     float64   `json:"newAmount"`
+	ChangePercent float64   `json:"changePercent"`
+}
+
+// DetectedSubscription é um padrão de cobrança periódica encontrado no histórico. Key identifica o
+// padrão entre execuções do detector e é usada para convertê-lo em recorrência.
+type DetectedSubscription struct {
+	Key           string        `json:"key"`
+	Description   string        `json:"description"`
+	Source        ChargeSource  `json:"source"`
+	AccountId     *ulid.ULID    `json:"accountId,omitempty"`
+	CreditCardId  *ulid.ULID    `json:"creditCardId,omitempty"`
+	CategoryId    *ulid.ULID    `json:"categoryId,omitempty"`
+	Cadence       Cadence       `json:"cadence"`
+	Amount        float64       `json:"amount"`
+	AverageAmount float64       `json:"averageAmount"`
+	Occurrences   int           `json:"occurrences"`
+	FirstDate     time.Time     `json:"firstDate"`
+	LastDate      time.Time     `json:"lastDate"`
+	NextExpected  time.Time     `json:"nextExpected"`
+	Confidence    float64       `json:"confidence"`
+	PriceChanges  []PriceChange `json:"priceChanges"`
+
+	lastChargeId ulid.ULID
+}
+
+// cadences são as periodicidades reconhecidas, com a tolerância em dias para o intervalo entre cobranças
+var cadences = []struct {
+	cadence   Cadence
+	tolerance int
+}{
+	{Cadence{Frequency: FrequencyWeekly, Interval: 1, Days: 7, Label: "semanal"}, 1},
+	{Cadence{Frequency: FrequencyWeekly, Interval: 2, Days: 14, Label: "quinzenal"}, 2},
+	{Cadence{Frequency: FrequencyMonthly, Interval: 1, Days: 30, Label: "mensal"}, 4},
+	{Cadence{Frequency: FrequencyMonthly, Interval: 2, Days: 61, Label: "bimestral"}, 6},
+	{Cadence{Frequency: FrequencyMonthly, Interval: 3, Days: 91, Label: "trimestral"}, 8},
+	{Cadence{Frequency: FrequencyMonthly, Interval: 6, Days: 182, Label: "semestral"}, 12},
+	{Cadence{Frequency: FrequencyYearly, Interval: 1, Days: 365, Label: "anual"}, 15},
+}
+
+const (
+	// MinConfidence é a confiança mínima para um padrão ser reportado
+	MinConfidence = 0.5
+
+	// priceChangeThreshold ignora diferenças pequenas (arredondamentos, IOF) entre cobranças
+	priceChangeThreshold = 0.01
+
+	// maxPriceJump separa cobranças de valores muito diferentes que só coincidem na descrição
+	maxPriceJump = 0.5
+)
+
+// DetectSubscriptions agrupa as cobranças por descrição normalizada e origem e reporta os grupos com
+// intervalo regular e valor estável. Padrões cuja última cobrança ficou mais de dois períodos para trás
+// são considerados cancelados e não entram no resultado.
+func DetectSubscriptions(charges []Charge, now time.Time) []DetectedSubscription {
+	groups := make(map[string][]Charge)
+	for _, charge := range charges {
+		if charge.Amount <= 0 {
+			continue
+		}
+		normalized := NormalizeDescription(charge.Description)
+		if normalized == "" {
+			continue
+		}
+		key := patternKey(normalized, charge)
+		groups[key] = append(groups[key], charge)
+	}
+
+	now = truncateDay(now)
+	var detected []DetectedSubscription
+	for key, group := range groups {
+		subscription, ok := detectPattern(key, group)
+		if !ok {
+			continue
+		}
+		if now.Sub(subscription.LastDate) > time.Duration(2*subscription.Cadence.Days)*24*time.Hour {
+			continue
+		}
+		detected = append(detected, subscription)
+	}
+
+	sort.Slice(detected, func(i, j int) bool {
+		if detected[i].Confidence != detected[j].Confidence {
+			return detected[i].Confidence > detected[j].Confidence
+		}
+		return detected[i].Key < detected[j].Key
+	})
+	return detected
+}
+
+func detectPattern(key string, group []Charge) (DetectedSubscription, bool) {
+	sort.SliceStable(group, func(i, j int) bool { return group[i].Date.Before(group[j].Date) })
+	group = mergeSameDay(group)
+	if len(group) < 2 {
+		return DetectedSubscription{}, false
+	}
+
+	intervals := make([]int, 0, len(group)-1)
+	for i := 1; i < len(group); i++ {
+		intervals = append(intervals, int(math.Round(truncateDay(group[i].Date).Sub(truncateDay(group[i-1].Date)).Hours()/24)))
+	}
+
+	median := medianInt(intervals)
+	cadenceIndex := -1
+	for i, candidate := range cadences {
+		if abs(median-candidate.cadence.Days) <= candidate.tolerance {
+			cadenceIndex = i
+			break
+		}
+	}
+	if cadenceIndex < 0 {
+		return DetectedSubscription{}, false
+	}
+	cadence, tolerance := cadences[cadenceIndex].cadence, cadences[cadenceIndex].tolerance
+
+	// cobranças anuais aparecem uma vez por ano; as demais precisam de pelo menos três ocorrências
+	if cadence.Frequency != FrequencyYearly && len(group) < 3 {
+		return DetectedSubscription{}, false
+	}
+
+	regular := 0
+	for _, interval := range intervals {
+		if abs(interval-cadence.Days) <= tolerance {
+			regular++
+		}
+	}
+	intervalScore := float64(regular) / float64(len(intervals))
+
+	var changes []PriceChange
+	total := group[0].Amount
+	for i := 1; i < len(group); i++ {
+		previous, current := group[i-1].Amount, group[i].Amount
+		total += current
+		variation := (current - previous) / previous
+		if math.Abs(variation) > maxPriceJump {
+			return DetectedSubscription{}, false
+		}
+		if math.Abs(variation) > priceChangeThreshold {
+			changes = append(changes, PriceChange{
+				Date:          group[i].Date,
+				OldAmount:     previous,
+				NewAmount:     current,
+				ChangePercent: roundTo(variation*100, 2),
+			})
+		}
+	}
+	amountScore := 1 - float64(len(changes))/float64(len(intervals))
+	countScore := math.Min(float64(len(group))/6, 1)
+	if cadence.Frequency == FrequencyYearly {
+		countScore = math.Min(float64(len(group))/3, 1)
+	}
+
+	confidence := roundTo(0.5*intervalScore+0.3*amountScore+0.2*countScore, 2)
+	if intervalScore < 0.6 || confidence < MinConfidence {
+		return DetectedSubscription{}, false
+	}
+
+	first, last := group[0], group[len(group)-1]
+	if changes == nil {
+		changes = []PriceChange{}
+	}
+	return DetectedSubscription{
+		Key:           key,
+		Description:   displayDescription(last.Description),
+		Source:        last.Source,
+		AccountId:     last.AccountId,
+		CreditCardId:  last.CreditCardId,
+		CategoryId:    lastCategory(group),
+		Cadence:       cadence,
+		Amount:        last.Amount,
+		AverageAmount: roundTo(total/float64(len(group)), 2),
+		Occurrences:   len(group),
+		FirstDate:     truncateDay(first.Date),
+		LastDate:      truncateDay(last.Date),
+		NextExpected:  nextExpected(truncateDay(last.Date), cadence),
+		Confidence:    confidence,
+		PriceChanges:  changes,
+		lastChargeId:  last.Id,
+	}, true
+}
+
+// NormalizeDescription reduz a descrição às palavras, sem acentos, números e pontuação, para que
+// cobranças como "NETFLIX.COM 12/05" e "Netflix.com 13/06" caiam no mesmo grupo
+func NormalizeDescription(description string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(description) {
+		r = foldAccent(r)
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsDigit(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// displayDescription remove da descrição as datas e números de parcela ou documento, mantendo o texto original
+func displayDescription(description string) string {
+	words := strings.Fields(description)
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		if strings.IndexFunc(word, unicode.IsDigit) < 0 {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		return strings.TrimSpace(description)
+	}
+	return strings.Join(kept, " ")
+}
+
+// patternKey identifica o padrão pela descrição normalizada e pela conta ou cartão de origem
+func patternKey(normalized string, charge Charge) string {
+	origin := ""
+	switch {
+	case charge.CreditCardId != nil:
+		origin = "card:" + charge.CreditCardId.String()
+	case charge.AccountId != nil:
+		origin = "account:" + charge.AccountId.String()
+	}
+	sum := sha1.Sum([]byte(normalized + "|" + origin))
+	return hex.EncodeToString(sum[:8])
+}
+
+// mergeSameDay mantém uma cobrança por dia; lançamentos repetidos no mesmo dia não contam como
+// ocorrências separadas
+func mergeSameDay(group []Charge) []Charge {
+	merged := make([]Charge, 0, len(group))
+	for _, charge := range group {
+		if n := len(merged); n > 0 && truncateDay(merged[n-1].Date).Equal(truncateDay(charge.Date)) {
+			continue
+		}
+		merged = append(merged, charge)
+	}
+	return merged
+}
+
+func nextExpected(last time.Time, cadence Cadence) time.Time {
+	switch cadence.Frequency {
+	case FrequencyWeekly:
+		return last.AddDate(0, 0, 7*cadence.Interval)
+	case FrequencyYearly:
+		return last.AddDate(cadence.Interval, 0, 0)
+	default:
+		target := time.Date(last.Year(), last.Month()+time.Month(cadence.Interval), 1, 0, 0, 0, 0, time.UTC)
+		day := last.Day()
+		if lastDay := target.AddDate(0, 1, -1).Day(); day > lastDay {
+			day = lastDay
+		}
+		return time.Date(target.Year(), target.Month(), day, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func lastCategory(group []Charge) *ulid.ULID {
+	for i := len(group) - 1; i >= 0; i-- {
+		if group[i].CategoryId != nil {
+			return group[i].CategoryId
+		}
+	}
+	return nil
+}
+
+func foldAccent(r rune) rune {
+	switch r {
+	case 'á', 'à', 'â', 'ã', 'ä':
+		return 'a'
+	case 'é', 'è', 'ê', 'ë':
+		return 'e'
+	case 'í', 'ì', 'î', 'ï':
+		return 'i'
+	case 'ó', 'ò', 'ô', 'õ', 'ö':
+		return 'o'
+	case 'ú', 'ù', 'û', 'ü':
+		return 'u'
+	case 'ç':
+		return 'c'
+	}
+	return r
+}
+
+func medianInt(values []int) int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func roundTo(v float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(v*factor) / factor
+}
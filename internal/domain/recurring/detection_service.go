@@ -0,0 +1,210 @@
+package recurring
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/creditcard"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// detectionLookbackMonths cobre duas cobranças de uma assinatura anual
+	detectionLookbackMonths = 13
+
+	// historyPageSize é o tamanho da página usada para ler o histórico de despesas
+	historyPageSize = 1000
+
+	// generatedSuffix marca as transações lançadas pelas recorrências, que já estão cadastradas
+	generatedSuffix = "(recorrente)"
+)
+
+// CardChargeSource fornece as compras no cartão analisadas pelo detector de assinaturas
+type CardChargeSource interface {
+	ListChargesSince(ctx context.Context, userID ulid.ULID, since time.Time) ([]*creditcard.CreditCardTransaction, error)
+}
+
+// ConvertDetectedRequest permite ajustar a recorrência criada a partir de um padrão detectado; os
+// campos vazios usam os valores do padrão
+type ConvertDetectedRequest struct {
+	UserId     ulid.ULID
+	Key        string
+	AccountId  *ulid.ULID
+	CategoryId *ulid.ULID
+	Amount     *float64
+	StartDate  *time.Time
+}
+
+// DetectSubscriptions procura cobranças periódicas nas despesas e nas compras no cartão dos últimos
+// meses que ainda não estão cadastradas como recorrência
+func (s *Service) DetectSubscriptions(ctx context.Context, userID ulid.ULID) ([]DetectedSubscription, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	since := truncateDay(now).AddDate(0, -detectionLookbackMonths, 0)
+	charges, err := s.loadCharges(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	registered, err := s.registeredDescriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	detected := make([]DetectedSubscription, 0)
+	for _, subscription := range DetectSubscriptions(charges, now) {
+		if registered[NormalizeDescription(subscription.Description)] {
+			continue
+		}
+		if subscription.Source == SourceTransaction && s.LoanPayments != nil {
+			isLoan, err := s.LoanPayments.IsLoanPayment(ctx, subscription.lastChargeId, userID)
+			if err != nil {
+				return nil, err
+			}
+			if isLoan {
+				continue
+			}
+		}
+		detected = append(detected, subscription)
+	}
+	return detected, nil
+}
+
+// ConvertDetected cadastra o padrão detectado como recorrência de despesa, começando na próxima cobrança
+// esperada. Padrões do cartão não têm conta associada e servem de lembrete, a menos que a conta seja informada.
+func (s *Service) ConvertDetected(ctx context.Context, req *ConvertDetectedRequest) (*RecurringTransaction, error) {
+	detected, err := s.DetectSubscriptions(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pattern *DetectedSubscription
+	for i := range detected {
+		if detected[i].Key == req.Key {
+			pattern = &detected[i]
+			break
+		}
+	}
+	if pattern == nil {
+		return nil, appErrors.NewNotFoundError("assinatura detectada")
+	}
+
+	categoryID := pattern.CategoryId
+	if req.CategoryId != nil {
+		categoryID = req.CategoryId
+	}
+	if categoryID == nil {
+		return nil, appErrors.NewValidationError("category_id", "é obrigatório")
+	}
+
+	accountID := pattern.AccountId
+	if req.AccountId != nil {
+		accountID = req.AccountId
+	}
+
+	amount := pattern.Amount
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+
+	startDate := pattern.NextExpected
+	if req.StartDate != nil {
+		startDate = truncateDay(*req.StartDate)
+	}
+
+	create := &CreateRecurringRequest{
+		UserId:      req.UserId,
+		Type:        string(transaction.Expense),
+		CategoryId:  *categoryID,
+		AccountId:   accountID,
+		Amount:      amount,
+		Description: pattern.Description,
+		Frequency:   pattern.Cadence.Frequency,
+		Interval:    pattern.Cadence.Interval,
+		DayOfMonth:  startDate.Day(),
+		DayOfWeek:   int(startDate.Weekday()),
+		StartDate:   startDate,
+	}
+	return s.CreateRecurring(ctx, create)
+}
+
+func (s *Service) loadCharges(ctx context.Context, userID ulid.ULID, since time.Time) ([]Charge, error) {
+	expenseType := string(transaction.Expense)
+	filters := &transaction.TransactionFilters{Type: &expenseType, DateFrom: &since}
+
+	var charges []Charge
+	loaded := 0
+	for page := 1; ; page++ {
+		transactions, total, err := s.TransactionRepo.GetAll(ctx, userID, nil, filters, &pkg.PaginationParams{Page: page, Limit: historyPageSize})
+		if err != nil {
+			return nil, appErrors.NewDatabaseError(err)
+		}
+		loaded += len(transactions)
+		for _, tx := range transactions {
+			if tx.InvestmentId != nil || strings.HasSuffix(strings.TrimSpace(tx.Description), generatedSuffix) {
+				continue
+			}
+			accountID := tx.AccountId
+			charges = append(charges, Charge{
+				Id:          tx.Id,
+				Source:      SourceTransaction,
+				AccountId:   &accountID,
+				CategoryId:  tx.CategoryId,
+				Description: tx.Description,
+				Amount:      tx.Amount,
+				Date:        tx.Date,
+			})
+		}
+		if len(transactions) == 0 || int64(loaded) >= total {
+			break
+		}
+	}
+
+	if s.CardCharges == nil {
+		return charges, nil
+	}
+
+	cardCharges, err := s.CardCharges.ListChargesSince(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, purchase := range cardCharges {
+		// compras parceladas não são assinaturas e as recorrentes já estão cadastradas
+		if purchase.Installments > 1 || purchase.IsRecurring {
+			continue
+		}
+		cardID, categoryID := purchase.CreditCardId, purchase.CategoryId
+		charges = append(charges, Charge{
+			Id:           purchase.Id,
+			Source:       SourceCreditCard,
+			CreditCardId: &cardID,
+			CategoryId:   &categoryID,
+			Description:  purchase.Description,
+			Amount:       purchase.Amount,
+			Date:         purchase.Date,
+		})
+	}
+	return charges, nil
+}
+
+// registeredDescriptions devolve as descrições normalizadas das recorrências ativas do usuário
+func (s *Service) registeredDescriptions(ctx context.Context, userID ulid.ULID) (map[string]bool, error) {
+	active, err := s.activeRecurring(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	registered := make(map[string]bool, len(active))
+	for _, rec := range active {
+		registered[NormalizeDescription(rec.Description)] = true
+	}
+	return registered, nil
+}
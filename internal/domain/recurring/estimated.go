@@ -0,0 +1,68 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type OccurrenceStatus string
+
+const (
+	OccurrencePending   OccurrenceStatus = "PENDING"
+	OccurrenceConfirmed OccurrenceStatus = "CONFIRMED"
+	OccurrenceSkipped   OccurrenceStatus = "SKIPPED"
+)
+
+func (s OccurrenceStatus) IsValid() bool {
+	switch s {
+	case OccurrencePending, OccurrenceConfirmed, OccurrenceSkipped:
+		return true
+	}
+	return false
+}
+
+// estimateWindow é o número de confirmações usadas na média que atualiza a estimativa
+const estimateWindow = 3
+
+// EstimatedOccurrence é o vencimento de uma recorrência de valor estimado, aguardando a confirmação
+// do valor real. A transação só é lançada na confirmação.
+type EstimatedOccurrence struct {
+	Id              ulid.ULID        `gorm:"type:varchar(26);primaryKey" json:"id"`
+	RecurringId     ulid.ULID        `gorm:"type:varchar(26);uniqueIndex:idx_estimated_occurrences_due,priority:1;not null" json:"recurringId"`
+	UserId          ulid.ULID        `gorm:"type:varchar(26);index:idx_estimated_occurrences_user_id;not null" json:"userId"`
+	DueDate         time.Time        `gorm:"type:date;uniqueIndex:idx_estimated_occurrences_due,priority:2;not null" json:"dueDate"`
+	EstimatedAmount float64          `gorm:"type:decimal(15,2);not null" json:"estimatedAmount"`
+	ActualAmount    *float64         `gorm:"type:decimal(15,2)" json:"actualAmount,omitempty"`
+	Status          OccurrenceStatus `gorm:"type:varchar(10);not null;default:PENDING;index:idx_estimated_occurrences_status" json:"status"`
+	TransactionId   *ulid.ULID       `gorm:"type:varchar(26)" json:"transactionId,omitempty"`
+	ConfirmedAt     *time.Time       `gorm:"type:timestamp" json:"confirmedAt,omitempty"`
+	CreatedAt       time.Time        `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+
+	Description string `gorm:"-" json:"description,omitempty"`
+	Overdue     bool   `gorm:"-" json:"overdue"`
+}
+
+func (EstimatedOccurrence) TableName() string {
+	return "recurring_estimated_occurrences"
+}
+
+// OccurrenceFilters filtra as ocorrências estimadas; OverdueOnly lista só as pendentes já vencidas
+type OccurrenceFilters struct {
+	RecurringId *ulid.ULID
+	Status      *OccurrenceStatus
+	OverdueOnly bool
+}
+
+// rollingEstimate é a média dos valores confirmados mais recentes, arredondada em centavos
+func rollingEstimate(confirmed []float64) float64 {
+	if len(confirmed) > estimateWindow {
+		confirmed = confirmed[:estimateWindow]
+	}
+	total := 0.0
+	for _, amount := range confirmed {
+		total += amount
+	}
+	return roundTo(total/float64(len(confirmed)), 2)
+}
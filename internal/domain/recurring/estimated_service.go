@@ -0,0 +1,178 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ConfirmOccurrenceRequest informa o valor real de uma ocorrência estimada; conta e data são opcionais
+// e usam a conta da recorrência e o vencimento da ocorrência
+type ConfirmOccurrenceRequest struct {
+	OccurrenceId ulid.ULID
+	UserId       ulid.ULID
+	Amount       float64
+	AccountId    *ulid.ULID
+	Date         *time.Time
+}
+
+// ListOccurrences lista as ocorrências das recorrências de valor estimado, marcando as pendentes vencidas
+func (s *Service) ListOccurrences(ctx context.Context, userID ulid.ULID, filters OccurrenceFilters) ([]*EstimatedOccurrence, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if filters.Status != nil && !filters.Status.IsValid() {
+		return nil, appErrors.NewValidationError("status", "deve ser PENDING, CONFIRMED ou SKIPPED")
+	}
+
+	occurrences, err := s.Repository.ListOccurrences(ctx, userID, filters)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	today := truncateDay(time.Now())
+	for _, occurrence := range occurrences {
+		occurrence.Overdue = occurrence.Status == OccurrencePending && occurrence.DueDate.Before(today)
+	}
+	return occurrences, nil
+}
+
+// ConfirmOccurrence executa a ocorrência com o valor real e recalcula a estimativa da recorrência pela
+// média das últimas confirmações
+func (s *Service) ConfirmOccurrence(ctx context.Context, req *ConfirmOccurrenceRequest) (*EstimatedOccurrence, *transaction.Transaction, error) {
+	if req.Amount <= 0 {
+		return nil, nil, appErrors.NewValidationError("amount", "deve ser maior que zero")
+	}
+
+	occurrence, err := s.getPendingOccurrence(ctx, req.OccurrenceId, req.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rec, err := s.GetRecurringByID(ctx, occurrence.RecurringId, req.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountID := rec.AccountId
+	if req.AccountId != nil && rec.Kind.UsesAccount() {
+		accountID = req.AccountId
+	}
+
+	date := truncateDay(occurrence.DueDate)
+	if req.Date != nil {
+		date = truncateDay(*req.Date)
+	}
+
+	tx, err := s.execute(ctx, rec, accountID, req.Amount, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	amount := req.Amount
+	occurrence.Status = OccurrenceConfirmed
+	occurrence.ActualAmount = &amount
+	occurrence.ConfirmedAt = &now
+	occurrence.UpdatedAt = now
+	if tx != nil {
+		occurrence.TransactionId = &tx.Id
+	}
+	if err := s.Repository.UpdateOccurrence(ctx, occurrence); err != nil {
+		if tx != nil && s.TransactionService != nil {
+			_ = s.TransactionService.DeleteTransaction(ctx, tx.Id, req.UserId)
+		}
+		return nil, nil, appErrors.NewDatabaseError(err)
+	}
+
+	if err := s.refreshEstimate(ctx, rec); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("recurring_id", rec.Id.String()).
+			Msg("Erro ao atualizar estimativa da recorrencia")
+	}
+
+	occurrence.Description = rec.Description
+	return occurrence, tx, nil
+}
+
+// SkipOccurrence descarta uma ocorrência pendente sem lançar transação
+func (s *Service) SkipOccurrence(ctx context.Context, occurrenceID, userID ulid.ULID) (*EstimatedOccurrence, error) {
+	occurrence, err := s.getPendingOccurrence(ctx, occurrenceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrence.Status = OccurrenceSkipped
+	occurrence.UpdatedAt = time.Now()
+	if err := s.Repository.UpdateOccurrence(ctx, occurrence); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return occurrence, nil
+}
+
+// createPendingOccurrence registra o vencimento atual da recorrência como ocorrência a confirmar.
+// Um vencimento já registrado não é duplicado.
+func (s *Service) createPendingOccurrence(ctx context.Context, recurring *RecurringTransaction) error {
+	dueDate := truncateDay(recurring.NextDue)
+	if _, err := s.Repository.GetOccurrenceByDueDate(ctx, recurring.Id, dueDate); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	now := time.Now()
+	occurrence := &EstimatedOccurrence{
+		Id:              pkg.GenerateULIDObject(),
+		RecurringId:     recurring.Id,
+		UserId:          recurring.UserId,
+		DueDate:         dueDate,
+		EstimatedAmount: recurring.Amount,
+		Status:          OccurrencePending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.Repository.CreateOccurrence(ctx, occurrence); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) getPendingOccurrence(ctx context.Context, occurrenceID, userID ulid.ULID) (*EstimatedOccurrence, error) {
+	occurrence, err := s.Repository.GetOccurrenceByID(ctx, occurrenceID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("ocorrencia")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	if occurrence.Status != OccurrencePending {
+		return nil, appErrors.NewValidationError("status", "ocorrencia ja foi confirmada ou ignorada")
+	}
+	return occurrence, nil
+}
+
+// refreshEstimate atualiza o valor estimado da recorrência com a média móvel das confirmações
+func (s *Service) refreshEstimate(ctx context.Context, recurring *RecurringTransaction) error {
+	amounts, err := s.Repository.ListConfirmedAmounts(ctx, recurring.Id, estimateWindow)
+	if err != nil {
+		return err
+	}
+	if len(amounts) == 0 {
+		return nil
+	}
+
+	recurring.Amount = rollingEstimate(amounts)
+	recurring.UpdatedAt = time.Now()
+	return s.Repository.Update(ctx, recurring)
+}
@@ -0,0 +1,82 @@
+package recurring
+
+import (
+	"context"
+
+	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/creditcard"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Kind define o que a recorrência executa em cada vencimento
+type Kind string
+
+const (
+	KindTransaction Kind = "TRANSACTION"
+	KindTransfer    Kind = "TRANSFER"
+	KindGoal        Kind = "GOAL"
+	KindInvestment  Kind = "INVESTMENT"
+	KindCreditCard  Kind = "CREDIT_CARD"
+)
+
+func (k Kind) IsValid() bool {
+	switch k {
+	case KindTransaction, KindTransfer, KindGoal, KindInvestment, KindCreditCard:
+		return true
+	}
+	return false
+}
+
+// UsesCategory indica os tipos que lançam receita ou despesa na categoria da recorrência
+func (k Kind) UsesCategory() bool {
+	return k == KindTransaction || k == KindCreditCard
+}
+
+// UsesAccount indica os tipos que movimentam a conta da recorrência
+func (k Kind) UsesAccount() bool {
+	return k != KindCreditCard
+}
+
+// transactionType é o tipo de transação gravado nas recorrências que não são receita ou despesa
+func (k Kind) transactionType() string {
+	switch k {
+	case KindTransfer:
+		return "TRANSFER"
+	case KindGoal:
+		return "GOALS"
+	case KindInvestment:
+		return "INVESTMENT"
+	default:
+		return "EXPENSE"
+	}
+}
+
+// IsReminder indica a receita ou despesa sem conta associada, que apenas lembra o vencimento
+func (r *RecurringTransaction) IsReminder() bool {
+	return r.Kind == KindTransaction && r.AccountId == nil
+}
+
+// AccountTransferrer confere as contas do usuário e executa as transferências entre elas
+type AccountTransferrer interface {
+	GetAccountByID(ctx context.Context, accountID, userID ulid.ULID) (*account.Account, error)
+	Transfer(ctx context.Context, fromAccountID, toAccountID, userID ulid.ULID, amount float64) error
+}
+
+// GoalContributor executa os aportes em metas
+type GoalContributor interface {
+	CheckGoalBelongsToUser(ctx context.Context, goalID ulid.ULID, userID ulid.ULID) error
+	MakeContribution(ctx context.Context, goalID, accountID, userID ulid.ULID, amount float64, description string) error
+}
+
+// InvestmentContributor executa os aportes em investimentos
+type InvestmentContributor interface {
+	CheckAcceptsContributions(ctx context.Context, investmentID, userID ulid.ULID) error
+	MakeContribution(ctx context.Context, investmentID, accountID, userID ulid.ULID, amount float64, description string) error
+}
+
+// CardPurchaser lança as cobranças recorrentes no cartão de crédito
+type CardPurchaser interface {
+	GetCreditCardById(ctx context.Context, cardID, userID ulid.ULID) (*creditcard.CreditCard, error)
+	CreateTransaction(ctx context.Context, req *creditcard.CreateTransactionRequest) error
+}
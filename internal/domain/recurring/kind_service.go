@@ -0,0 +1,153 @@
+package recurring
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/creditcard"
+	"Fynance/internal/domain/transaction"
+	appErrors "Fynance/internal/errors"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// validateKind confere os campos exigidos pelo tipo da recorrência e a posse da meta, do investimento
+// ou do cartão. Nas recorrências que não são receita ou despesa, o tipo de transação é definido pelo Kind.
+func (s *Service) validateKind(ctx context.Context, req *CreateRecurringRequest) error {
+	if req.Kind == "" {
+		req.Kind = KindTransaction
+	}
+	if !req.Kind.IsValid() {
+		return appErrors.NewValidationError("kind", "deve ser TRANSACTION, TRANSFER, GOAL, INVESTMENT ou CREDIT_CARD")
+	}
+
+	if req.Kind == KindTransaction {
+		if req.Type != "RECEIPT" && req.Type != "EXPENSE" {
+			return appErrors.NewValidationError("type", "tipo invalido")
+		}
+	} else {
+		req.Type = req.Kind.transactionType()
+	}
+
+	if req.Kind.UsesCategory() && req.CategoryId == (ulid.ULID{}) {
+		return appErrors.NewValidationError("category_id", "é obrigatório")
+	}
+	if req.Kind.UsesCategory() && s.CategoryService != nil {
+		if err := s.CategoryService.ValidateAndEnsureExists(ctx, req.CategoryId, req.UserId); err != nil {
+			return err
+		}
+	}
+	if !req.Kind.UsesCategory() {
+		req.CategoryId = ulid.ULID{}
+	}
+
+	if req.Kind != KindTransaction && req.Kind.UsesAccount() && req.AccountId == nil {
+		return appErrors.NewValidationError("account_id", "é obrigatório")
+	}
+
+	switch req.Kind {
+	case KindTransfer:
+		if req.DestinationAccountId == nil {
+			return appErrors.NewValidationError("destination_account_id", "é obrigatório")
+		}
+		if *req.DestinationAccountId == *req.AccountId {
+			return appErrors.NewValidationError("destination_account_id", "deve ser diferente da conta de origem")
+		}
+		if s.Transfers == nil {
+			return appErrors.NewValidationError("kind", "transferencias recorrentes nao estao disponiveis")
+		}
+		for _, accountID := range []ulid.ULID{*req.AccountId, *req.DestinationAccountId} {
+			if _, err := s.Transfers.GetAccountByID(ctx, accountID, req.UserId); err != nil {
+				return err
+			}
+		}
+	case KindGoal:
+		if req.GoalId == nil {
+			return appErrors.NewValidationError("goal_id", "é obrigatório")
+		}
+		if s.GoalContributions == nil {
+			return appErrors.NewValidationError("kind", "aportes recorrentes em metas nao estao disponiveis")
+		}
+		if err := s.GoalContributions.CheckGoalBelongsToUser(ctx, *req.GoalId, req.UserId); err != nil {
+			return err
+		}
+	case KindInvestment:
+		if req.InvestmentId == nil {
+			return appErrors.NewValidationError("investment_id", "é obrigatório")
+		}
+		if s.InvestmentContributions == nil {
+			return appErrors.NewValidationError("kind", "aportes recorrentes em investimentos nao estao disponiveis")
+		}
+		if err := s.InvestmentContributions.CheckAcceptsContributions(ctx, *req.InvestmentId, req.UserId); err != nil {
+			return err
+		}
+	case KindCreditCard:
+		if req.CreditCardId == nil {
+			return appErrors.NewValidationError("credit_card_id", "é obrigatório")
+		}
+		if s.CardPurchases == nil {
+			return appErrors.NewValidationError("kind", "cobrancas recorrentes no cartao nao estao disponiveis")
+		}
+		if _, err := s.CardPurchases.GetCreditCardById(ctx, *req.CreditCardId, req.UserId); err != nil {
+			return err
+		}
+		req.AccountId = nil
+	}
+
+	if req.Kind != KindTransfer {
+		req.DestinationAccountId = nil
+	}
+	if req.Kind != KindGoal {
+		req.GoalId = nil
+	}
+	if req.Kind != KindInvestment {
+		req.InvestmentId = nil
+	}
+	if req.Kind != KindCreditCard {
+		req.CreditCardId = nil
+	}
+	return nil
+}
+
+// execute realiza uma ocorrência da recorrência pelo serviço dono do recurso. Só receitas e despesas
+// devolvem a transação lançada; os demais tipos registram a movimentação no próprio domínio.
+func (s *Service) execute(ctx context.Context, recurring *RecurringTransaction, accountID *ulid.ULID, amount float64, date time.Time) (*transaction.Transaction, error) {
+	if recurring.Kind.UsesAccount() && accountID == nil {
+		return nil, appErrors.NewValidationError("account_id", "é obrigatório")
+	}
+	description := recurring.Description + " " + generatedSuffix
+
+	switch recurring.Kind {
+	case KindTransfer:
+		if s.Transfers == nil || recurring.DestinationAccountId == nil {
+			return nil, appErrors.NewValidationError("kind", "transferencia recorrente sem conta de destino")
+		}
+		return nil, s.Transfers.Transfer(ctx, *accountID, *recurring.DestinationAccountId, recurring.UserId, amount)
+	case KindGoal:
+		if s.GoalContributions == nil || recurring.GoalId == nil {
+			return nil, appErrors.NewValidationError("kind", "aporte recorrente sem meta")
+		}
+		return nil, s.GoalContributions.MakeContribution(ctx, *recurring.GoalId, *accountID, recurring.UserId, amount, description)
+	case KindInvestment:
+		if s.InvestmentContributions == nil || recurring.InvestmentId == nil {
+			return nil, appErrors.NewValidationError("kind", "aporte recorrente sem investimento")
+		}
+		return nil, s.InvestmentContributions.MakeContribution(ctx, *recurring.InvestmentId, *accountID, recurring.UserId, amount, description)
+	case KindCreditCard:
+		if s.CardPurchases == nil || recurring.CreditCardId == nil {
+			return nil, appErrors.NewValidationError("kind", "cobranca recorrente sem cartao")
+		}
+		return nil, s.CardPurchases.CreateTransaction(ctx, &creditcard.CreateTransactionRequest{
+			CreditCardId: *recurring.CreditCardId,
+			UserId:       recurring.UserId,
+			CategoryId:   recurring.CategoryId,
+			Amount:       amount,
+			Description:  description,
+			Date:         date,
+			Installments: 1,
+			IsRecurring:  true,
+		})
+	default:
+		return s.postTransaction(ctx, recurring, *accountID, amount, date)
+	}
+}
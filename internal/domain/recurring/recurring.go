@@ -25,6 +25,28 @@ type RecurringTransaction struct {
 	IsActive      bool          `gorm:"not null;default:true;index:idx_recurring_active" json:"isActive"`
 	CreatedAt     time.Time     `gorm:"autoCreateTime;not null" json:"createdAt"`
 	UpdatedAt     time.Time     `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+
+	// Rule é a regra RRULE da recorrência; vazia nas recorrências antigas, que usam Frequency e os dias
+	Interval              int                   `gorm:"not null;default:1" json:"interval"`
+	Rule                  string                `gorm:"type:varchar(255)" json:"rule,omitempty"`
+	BusinessDayAdjustment BusinessDayAdjustment `gorm:"type:varchar(10);not null;default:NONE" json:"businessDayAdjustment"`
+
+	// SeriesDate e SeriesIndex guardam a ocorrência de NextDue pela regra, antes do ajuste de dia útil, e
+	// o número dela na série; o próximo vencimento é calculado a partir daí, sem refazer o histórico
+	SeriesDate  *time.Time `gorm:"type:date" json:"-"`
+	SeriesIndex int        `gorm:"not null;default:0" json:"-"`
+
+	// IsEstimated indica conta de valor variável: cada vencimento gera uma ocorrência pendente, confirmada
+	// com o valor real, e Amount passa a ser a estimativa pela média das últimas confirmações
+	IsEstimated bool `gorm:"not null;default:false" json:"isEstimated"`
+
+	// Kind define a execução: receita ou despesa na conta, transferência para DestinationAccountId,
+	// aporte na meta ou no investimento a partir da conta, ou cobrança no cartão
+	Kind                 Kind       `gorm:"type:varchar(15);not null;default:TRANSACTION" json:"kind"`
+	DestinationAccountId *ulid.ULID `gorm:"type:varchar(26)" json:"destinationAccountId,omitempty"`
+	GoalId               *ulid.ULID `gorm:"type:varchar(26);index:idx_recurring_goal_id" json:"goalId,omitempty"`
+	InvestmentId         *ulid.ULID `gorm:"type:varchar(26);index:idx_recurring_investment_id" json:"investmentId,omitempty"`
+	CreditCardId         *ulid.ULID `gorm:"type:varchar(26);index:idx_recurring_credit_card_id" json:"creditCardId,omitempty"`
 }
 
 func (RecurringTransaction) TableName() string {
@@ -47,3 +69,108 @@ func (f FrequencyType) IsValid() bool {
 	}
 	return false
 }
+
+// Recurrence devolve a regra da recorrência. Recorrências sem RRULE têm a regra montada a partir da
+// frequência, do intervalo e do dia do mês ou da semana.
+func (r *RecurringTransaction) Recurrence() (*Rule, error) {
+	if r.Rule != "" {
+		return ParseRule(r.Rule)
+	}
+
+	rule := &Rule{Frequency: r.Frequency, Interval: r.Interval}
+	if rule.Interval < 1 {
+		rule.Interval = 1
+	}
+	switch r.Frequency {
+	case FrequencyWeekly:
+		rule.ByDay = []WeekdayNum{{Weekday: time.Weekday(r.DayOfWeek)}}
+	case FrequencyMonthly:
+		if r.DayOfMonth > 0 {
+			rule.ByMonthDay = []int{r.DayOfMonth}
+		}
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Position devolve a posição na série do vencimento atual; zero quando ainda não foi calculada
+func (r *RecurringTransaction) Position() SeriesPosition {
+	if r.SeriesIndex <= 0 || r.SeriesDate == nil {
+		return SeriesPosition{}
+	}
+	return SeriesPosition{Date: *r.SeriesDate, Index: r.SeriesIndex}
+}
+
+// SetNextDue agenda o vencimento e guarda a posição dele na série
+func (r *RecurringTransaction) SetNextDue(due time.Time, position SeriesPosition) {
+	r.NextDue = due
+	r.SeriesIndex = position.Index
+	r.SeriesDate = nil
+	if !position.IsZero() {
+		date := position.Date
+		r.SeriesDate = &date
+	}
+}
+
+// NextOccurrence devolve a primeira ocorrência, já ajustada para dia útil, posterior a after
+// (ou a primeira da série quando after é nil) e a posição dela na série. Quando o vencimento atual
+// não é posterior a after, a busca parte dele. Retorna false quando a série terminou.
+func (r *RecurringTransaction) NextOccurrence(after *time.Time) (time.Time, SeriesPosition, bool, error) {
+	rule, err := r.Recurrence()
+	if err != nil {
+		return time.Time{}, SeriesPosition{}, false, err
+	}
+
+	// o ajuste para dia útil preserva a ordem, então as ocorrências antes do vencimento atual
+	// não passam de after
+	var from SeriesPosition
+	if after != nil && !truncateDay(r.NextDue).After(truncateDay(*after)) {
+		from = r.Position()
+	}
+
+	var (
+		next     time.Time
+		position SeriesPosition
+		found    bool
+	)
+	rule.IterateFrom(r.StartDate, from, func(n int, date time.Time) bool {
+		if r.EndDate != nil && date.After(truncateDay(*r.EndDate)) {
+			return false
+		}
+		adjusted := r.BusinessDayAdjustment.Apply(date)
+		if after == nil || adjusted.After(truncateDay(*after)) {
+			next, position, found = adjusted, SeriesPosition{Date: date, Index: n}, true
+			return false
+		}
+		return true
+	})
+	return next, position, found, nil
+}
+
+// OccurrencesBetween lista as ocorrências ajustadas para dia útil no intervalo [from, to], a partir do
+// vencimento atual; as anteriores a ele já foram processadas
+func (r *RecurringTransaction) OccurrencesBetween(from, to time.Time) ([]time.Time, error) {
+	rule, err := r.Recurrence()
+	if err != nil {
+		return nil, err
+	}
+
+	from, to = truncateDay(from), truncateDay(to)
+	// o ajuste para dia útil desloca a data em poucos dias; a folga evita perder ocorrências na borda
+	limit := to.AddDate(0, 0, 7)
+
+	var dates []time.Time
+	rule.IterateFrom(r.StartDate, r.Position(), func(_ int, date time.Time) bool {
+		if date.After(limit) || (r.EndDate != nil && date.After(truncateDay(*r.EndDate))) {
+			return false
+		}
+		adjusted := r.BusinessDayAdjustment.Apply(date)
+		if !adjusted.Before(from) && !adjusted.After(to) {
+			dates = append(dates, adjusted)
+		}
+		return true
+	})
+	return dates, nil
+}
@@ -17,5 +17,13 @@ type RecurringRepository interface {
 	GetByUserID(ctx context.Context, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*RecurringTransaction, int64, error)
 	GetActiveByUserID(ctx context.Context, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*RecurringTransaction, int64, error)
 	GetDueTransactions(ctx context.Context, date time.Time, pagination *pkg.PaginationParams) ([]*RecurringTransaction, int64, error)
-	UpdateLastProcessed(ctx context.Context, recurringID ulid.ULID, processedDate, nextDue time.Time) error
+	UpdateLastProcessed(ctx context.Context, recurringID ulid.ULID, processedDate, nextDue time.Time, position SeriesPosition) error
+
+	CreateOccurrence(ctx context.Context, occurrence *EstimatedOccurrence) error
+	UpdateOccurrence(ctx context.Context, occurrence *EstimatedOccurrence) error
+	GetOccurrenceByID(ctx context.Context, occurrenceID, userID ulid.ULID) (*EstimatedOccurrence, error)
+	GetOccurrenceByDueDate(ctx context.Context, recurringID ulid.ULID, dueDate time.Time) (*EstimatedOccurrence, error)
+	ListOccurrences(ctx context.Context, userID ulid.ULID, filters OccurrenceFilters) ([]*EstimatedOccurrence, error)
+	ListConfirmedAmounts(ctx context.Context, recurringID ulid.ULID, limit int) ([]float64, error)
+	DeleteOccurrencesByRecurring(ctx context.Context, recurringID, userID ulid.ULID) error
 }
@@ -0,0 +1,589 @@
+package recurring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"Fynance/internal/pkg"
+)
+
+// BusinessDayAdjustment define o que fazer quando a ocorrência cai em fim de semana ou feriado nacional
+type BusinessDayAdjustment string
+
+const (
+	AdjustNone     BusinessDayAdjustment = "NONE"
+	AdjustPrevious BusinessDayAdjustment = "PREVIOUS"
+	AdjustNext     BusinessDayAdjustment = "NEXT"
+)
+
+func (a BusinessDayAdjustment) IsValid() bool {
+	switch a {
+	case AdjustNone, AdjustPrevious, AdjustNext:
+		return true
+	}
+	return false
+}
+
+// Apply move a data para o dia útil anterior ou seguinte, conforme o ajuste
+func (a BusinessDayAdjustment) Apply(t time.Time) time.Time {
+	switch a {
+	case AdjustPrevious:
+		return pkg.PreviousBusinessDay(t)
+	case AdjustNext:
+		return pkg.NextBusinessDay(t)
+	}
+	return t
+}
+
+// WeekdayNum é um item de BYDAY: o dia da semana e, em regras mensais ou anuais, a posição no mês
+// (2MO é a segunda segunda-feira, -1FR a última sexta-feira; 0 indica todas)
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func (w WeekdayNum) String() string {
+	if w.Ordinal == 0 {
+		return weekdayNames[w.Weekday]
+	}
+	return strconv.Itoa(w.Ordinal) + weekdayNames[w.Weekday]
+}
+
+// Rule é uma regra de recorrência no formato RRULE da RFC 5545 (FREQ, INTERVAL, COUNT, UNTIL, BYDAY,
+// BYMONTHDAY, BYMONTH e BYSETPOS). O início da série é a data de início da recorrência. Dias do mês
+// inexistentes (31 em abril) caem no último dia do mês; BYMONTHDAY=-1 é o último dia do mês.
+type Rule struct {
+	Frequency  FrequencyType
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+}
+
+// maxEmptyPeriods limita a busca quando a regra não gera ocorrências por muitos períodos seguidos
+const maxEmptyPeriods = 1000
+
+// ParseRule interpreta uma RRULE, com ou sem o prefixo "RRULE:"
+func ParseRule(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.ToUpper(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("regra vazia")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("parte invalida: %s", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%s repetido", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Frequency = FrequencyType(val)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(val)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(val)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(val)
+			for _, m := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			rule.BySetPos, err = parseIntList(val)
+		case "WKST":
+			if val != "MO" {
+				err = errors.New("apenas WKST=MO e suportado")
+			}
+		default:
+			err = errors.New("parte nao suportada")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Validate verifica as combinações aceitas pela RFC 5545 dentro do subconjunto suportado
+func (r *Rule) Validate() error {
+	if !r.Frequency.IsValid() {
+		return errors.New("FREQ deve ser DAILY, WEEKLY, MONTHLY ou YEARLY")
+	}
+	if r.Interval < 1 || r.Interval > 1000 {
+		return errors.New("INTERVAL deve estar entre 1 e 1000")
+	}
+	if r.Count < 0 {
+		return errors.New("COUNT nao pode ser negativo")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("COUNT e UNTIL nao podem ser usados juntos")
+	}
+	for _, d := range r.ByDay {
+		if d.Ordinal == 0 {
+			continue
+		}
+		if r.Frequency != FrequencyMonthly && r.Frequency != FrequencyYearly {
+			return errors.New("BYDAY com posicao so e aceito em regras mensais ou anuais")
+		}
+		if d.Ordinal < -5 || d.Ordinal > 5 {
+			return errors.New("posicao do BYDAY deve estar entre -5 e 5")
+		}
+	}
+	if len(r.ByMonthDay) > 0 && r.Frequency == FrequencyWeekly {
+		return errors.New("BYMONTHDAY nao e aceito em regras semanais")
+	}
+	for _, d := range r.ByMonthDay {
+		if d == 0 || d < -31 || d > 31 {
+			return errors.New("BYMONTHDAY deve estar entre 1 e 31 ou entre -31 e -1")
+		}
+	}
+	for _, m := range r.ByMonth {
+		if m < time.January || m > time.December {
+			return errors.New("BYMONTH deve estar entre 1 e 12")
+		}
+	}
+	for _, p := range r.BySetPos {
+		if p == 0 || p < -366 || p > 366 {
+			return errors.New("BYSETPOS deve estar entre 1 e 366 ou entre -366 e -1")
+		}
+	}
+	if len(r.BySetPos) > 0 && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByMonth) == 0 {
+		return errors.New("BYSETPOS exige BYDAY, BYMONTHDAY ou BYMONTH")
+	}
+	return nil
+}
+
+// String devolve a regra no formato RRULE, sem o prefixo
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]int, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = int(m)
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	return strings.Join(parts, ";")
+}
+
+// SeriesPosition identifica uma ocorrência da série: a data gerada pela regra, antes do ajuste de dia
+// útil, e o número dela a partir de 1. O valor zero indica o início da série.
+type SeriesPosition struct {
+	Date  time.Time
+	Index int
+}
+
+// IsZero indica que a posição não foi calculada
+func (p SeriesPosition) IsZero() bool {
+	return p.Index <= 0
+}
+
+// Iterate percorre as ocorrências a partir de start em ordem, numeradas a partir de 1, até COUNT,
+// UNTIL ou até fn devolver false
+func (r *Rule) Iterate(start time.Time, fn func(n int, date time.Time) bool) {
+	r.IterateFrom(start, SeriesPosition{}, fn)
+}
+
+// IterateFrom percorre as ocorrências da série iniciada em start a partir da posição from, inclusive,
+// mantendo a numeração. A busca começa no período de from, então o custo não cresce com o histórico.
+func (r *Rule) IterateFrom(start time.Time, from SeriesPosition, fn func(n int, date time.Time) bool) {
+	start = truncateDay(start)
+	first := start
+	n := 0
+	period := 0
+	if !from.IsZero() && !truncateDay(from.Date).Before(start) {
+		first = truncateDay(from.Date)
+		n = from.Index - 1
+		period = r.periodOf(start, first)
+	}
+	if r.Count > 0 && n >= r.Count {
+		return
+	}
+
+	empty := 0
+	for ; empty < maxEmptyPeriods; period += r.Interval {
+		candidates := r.expand(start, period)
+		if len(candidates) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, candidate := range candidates {
+			if candidate.Before(first) {
+				continue
+			}
+			if r.Until != nil && candidate.After(truncateDay(*r.Until)) {
+				return
+			}
+			n++
+			if !fn(n, candidate) {
+				return
+			}
+			if r.Count > 0 && n >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// periodOf devolve o primeiro período da série, múltiplo de INTERVAL, que contém a data
+func (r *Rule) periodOf(start, date time.Time) int {
+	var units int
+	switch r.Frequency {
+	case FrequencyDaily:
+		units = int(date.Sub(start).Hours() / 24)
+	case FrequencyWeekly:
+		startWeek := start.AddDate(0, 0, -weekdayOffset(start.Weekday()))
+		dateWeek := date.AddDate(0, 0, -weekdayOffset(date.Weekday()))
+		units = int(dateWeek.Sub(startWeek).Hours()/24) / 7
+	case FrequencyMonthly:
+		units = (date.Year()-start.Year())*12 + int(date.Month()) - int(start.Month())
+	case FrequencyYearly:
+		units = date.Year() - start.Year()
+	}
+	if units <= 0 {
+		return 0
+	}
+	return units - units%r.Interval
+}
+
+// expand gera as datas candidatas do período de número period (em unidades de FREQ desde o início)
+func (r *Rule) expand(start time.Time, period int) []time.Time {
+	var candidates []time.Time
+
+	switch r.Frequency {
+	case FrequencyDaily:
+		day := start.AddDate(0, 0, period)
+		if r.matchesDay(day) {
+			candidates = []time.Time{day}
+		}
+
+	case FrequencyWeekly:
+		weekStart := start.AddDate(0, 0, -weekdayOffset(start.Weekday())+7*period)
+		if len(r.ByDay) == 0 {
+			candidates = []time.Time{weekStart.AddDate(0, 0, weekdayOffset(start.Weekday()))}
+		}
+		for _, d := range r.ByDay {
+			candidates = append(candidates, weekStart.AddDate(0, 0, weekdayOffset(d.Weekday)))
+		}
+		candidates = filterMonths(candidates, r.ByMonth)
+
+	case FrequencyMonthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(period), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByMonth) == 0 || containsMonth(r.ByMonth, month.Month()) {
+			candidates = r.expandMonth(month, start)
+		}
+
+	case FrequencyYearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.expandMonth(time.Date(start.Year()+period, m, 1, 0, 0, 0, 0, time.UTC), start)...)
+		}
+	}
+
+	candidates = sortUnique(candidates)
+	return applySetPos(candidates, r.BySetPos)
+}
+
+// expandMonth gera os dias do mês que atendem BYMONTHDAY e BYDAY; sem nenhum dos dois, usa o dia do início
+func (r *Rule) expandMonth(month, start time.Time) []time.Time {
+	lastDay := daysIn(month)
+
+	var monthDays []int
+	for _, md := range r.ByMonthDay {
+		day := md
+		if md < 0 {
+			day = lastDay + md + 1
+		}
+		if day < 1 {
+			continue
+		}
+		if day > lastDay {
+			day = lastDay
+		}
+		monthDays = append(monthDays, day)
+	}
+
+	var weekDays []int
+	for _, d := range r.ByDay {
+		weekDays = append(weekDays, weekdaysInMonth(month, d)...)
+	}
+
+	var days []int
+	switch {
+	case len(r.ByMonthDay) > 0 && len(r.ByDay) > 0:
+		for _, day := range monthDays {
+			if containsInt(weekDays, day) {
+				days = append(days, day)
+			}
+		}
+	case len(r.ByMonthDay) > 0:
+		days = monthDays
+	case len(r.ByDay) > 0:
+		days = weekDays
+	default:
+		day := start.Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		days = []int{day}
+	}
+
+	dates := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		dates = append(dates, time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC))
+	}
+	return dates
+}
+
+// matchesDay aplica BYDAY, BYMONTHDAY e BYMONTH como filtros nas regras diárias
+func (r *Rule) matchesDay(day time.Time) bool {
+	if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, day.Month()) {
+		return false
+	}
+	if len(r.ByDay) > 0 {
+		found := false
+		for _, d := range r.ByDay {
+			if d.Weekday == day.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.ByMonthDay) > 0 {
+		lastDay := daysIn(day)
+		found := false
+		for _, md := range r.ByMonthDay {
+			if md == day.Day() || (md < 0 && lastDay+md+1 == day.Day()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// weekdaysInMonth devolve os dias do mês que caem no dia da semana, todos ou só o da posição indicada
+func weekdaysInMonth(month time.Time, d WeekdayNum) []int {
+	lastDay := daysIn(month)
+	first := (int(d.Weekday)-int(month.Weekday())+7)%7 + 1
+
+	var days []int
+	for day := first; day <= lastDay; day += 7 {
+		days = append(days, day)
+	}
+	if d.Ordinal == 0 {
+		return days
+	}
+
+	index := d.Ordinal - 1
+	if d.Ordinal < 0 {
+		index = len(days) + d.Ordinal
+	}
+	if index < 0 || index >= len(days) {
+		return nil
+	}
+	return []int{days[index]}
+}
+
+func applySetPos(candidates []time.Time, positions []int) []time.Time {
+	if len(positions) == 0 || len(candidates) == 0 {
+		return candidates
+	}
+
+	var selected []time.Time
+	for _, p := range positions {
+		index := p - 1
+		if p < 0 {
+			index = len(candidates) + p
+		}
+		if index >= 0 && index < len(candidates) {
+			selected = append(selected, candidates[index])
+		}
+	}
+	return sortUnique(selected)
+}
+
+func sortUnique(dates []time.Time) []time.Time {
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	unique := dates[:0]
+	for i, d := range dates {
+		if i == 0 || !d.Equal(dates[i-1]) {
+			unique = append(unique, d)
+		}
+	}
+	return unique
+}
+
+func filterMonths(dates []time.Time, months []time.Month) []time.Time {
+	if len(months) == 0 {
+		return dates
+	}
+	filtered := dates[:0]
+	for _, d := range dates {
+		if containsMonth(months, d.Month()) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// weekdayOffset é a distância do dia da semana até a segunda-feira (WKST=MO)
+func weekdayOffset(w time.Weekday) int {
+	return (int(w) - int(time.Monday) + 7) % 7
+}
+
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func containsMonth(months []time.Month, m time.Month) bool {
+	for _, month := range months {
+		if month == m {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return truncateDay(t), nil
+		}
+	}
+	return time.Time{}, errors.New("data invalida, use AAAAMMDD")
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("dia invalido: %s", item)
+		}
+		code := item[len(item)-2:]
+		weekday, ok := weekdayCodes[code]
+		if !ok {
+			return nil, fmt.Errorf("dia invalido: %s", item)
+		}
+		ordinal := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			parsed, err := strconv.Atoi(prefix)
+			if err != nil || parsed == 0 {
+				return nil, fmt.Errorf("posicao invalida: %s", item)
+			}
+			ordinal = parsed
+		}
+		days = append(days, WeekdayNum{Ordinal: ordinal, Weekday: weekday})
+	}
+	return days, nil
+}
+
+func parseIntList(value string) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		parsed, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("numero invalido: %s", item)
+		}
+		values = append(values, parsed)
+	}
+	return values, nil
+}
+
+func joinInts(values []int) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = strconv.Itoa(v)
+	}
+	return strings.Join(items, ",")
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -2,6 +2,7 @@ package recurring
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,11 +15,24 @@ import (
 	"github.com/oklog/ulid/v2"
 )
 
+// activePageSize é o tamanho da página usada para ler todas as recorrências ativas do usuário
+const activePageSize = 1000
+
 type Service struct {
 	Repository         RecurringRepository
 	TransactionRepo    transaction.TransactionRepository
 	CategoryService    *category.Service
 	TransactionService transaction.TransactionHandler
+	// CardCharges é opcional e inclui as compras no cartão na detecção de assinaturas
+	CardCharges CardChargeSource
+	// LoanPayments é opcional e exclui as parcelas de empréstimos da detecção de assinaturas
+	LoanPayments shared.LoanPaymentHandler
+	// Transfers, GoalContributions, InvestmentContributions e CardPurchases são opcionais e executam
+	// as recorrências de transferência, aporte em meta, aporte em investimento e cobrança no cartão
+	Transfers               AccountTransferrer
+	GoalContributions       GoalContributor
+	InvestmentContributions InvestmentContributor
+	CardPurchases           CardPurchaser
 	shared.BaseService
 }
 
@@ -50,25 +64,35 @@ func (s *Service) CreateRecurring(ctx context.Context, req *CreateRecurringReque
 	}
 
 	now := time.Now()
-	nextDue := s.calculateNextDue(req.StartDate, req.Frequency, req.DayOfMonth, req.DayOfWeek)
-
 	recurring := &RecurringTransaction{
-		Id:          pkg.GenerateULIDObject(),
-		UserId:      req.UserId,
-		Type:        req.Type,
-		CategoryId:  req.CategoryId,
-		AccountId:   req.AccountId,
-		Amount:      req.Amount,
-		Description: strings.TrimSpace(req.Description),
-		Frequency:   req.Frequency,
-		DayOfMonth:  req.DayOfMonth,
-		DayOfWeek:   req.DayOfWeek,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-		NextDue:     nextDue,
-		IsActive:    true,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		Id:                    pkg.GenerateULIDObject(),
+		UserId:                req.UserId,
+		Type:                  req.Type,
+		CategoryId:            req.CategoryId,
+		AccountId:             req.AccountId,
+		Amount:                req.Amount,
+		Description:           strings.TrimSpace(req.Description),
+		Frequency:             req.Frequency,
+		DayOfMonth:            req.DayOfMonth,
+		DayOfWeek:             req.DayOfWeek,
+		Interval:              req.Interval,
+		Rule:                  req.Rule,
+		BusinessDayAdjustment: req.BusinessDayAdjustment,
+		IsEstimated:           req.IsEstimated,
+		Kind:                  req.Kind,
+		DestinationAccountId:  req.DestinationAccountId,
+		GoalId:                req.GoalId,
+		InvestmentId:          req.InvestmentId,
+		CreditCardId:          req.CreditCardId,
+		StartDate:             req.StartDate,
+		EndDate:               req.EndDate,
+		IsActive:              true,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+
+	if err := s.applyRule(recurring, nil); err != nil {
+		return nil, err
 	}
 
 	if err := s.Repository.Create(ctx, recurring); err != nil {
@@ -99,12 +123,31 @@ func (s *Service) UpdateRecurring(ctx context.Context, recurringID, userID ulid.
 		recurring.IsActive = *req.IsActive
 	}
 
+	if req.IsEstimated != nil {
+		recurring.IsEstimated = *req.IsEstimated
+	}
+
 	if req.EndDate != nil {
 		recurring.EndDate = req.EndDate
 	}
 
+	if req.Rule != nil || req.BusinessDayAdjustment != nil {
+		if req.Rule != nil {
+			recurring.Rule = strings.TrimSpace(*req.Rule)
+		}
+		if req.BusinessDayAdjustment != nil {
+			if !req.BusinessDayAdjustment.IsValid() {
+				return appErrors.NewValidationError("business_day_adjustment", "deve ser NONE, PREVIOUS ou NEXT")
+			}
+			recurring.BusinessDayAdjustment = *req.BusinessDayAdjustment
+		}
+		if err := s.applyRule(recurring, recurring.LastProcessed); err != nil {
+			return err
+		}
+	}
+
 	if req.NextDue != nil {
-		recurring.NextDue = *req.NextDue
+		recurring.SetNextDue(*req.NextDue, SeriesPosition{})
 	}
 
 	recurring.UpdatedAt = time.Now()
@@ -118,6 +161,10 @@ func (s *Service) DeleteRecurring(ctx context.Context, recurringID, userID ulid.
 		return err
 	}
 
+	if err := s.Repository.DeleteOccurrencesByRecurring(ctx, recurringID, userID); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
 	return s.Repository.Delete(ctx, recurringID, userID)
 }
 
@@ -142,12 +189,22 @@ func (s *Service) ListRecurring(ctx context.Context, userID ulid.ULID, paginatio
 	return s.Repository.GetByUserID(ctx, userID, pagination)
 }
 
+// ProcessDueTransactions lança as transações das recorrências vencidas. As de valor estimado geram
+// uma ocorrência pendente de confirmação em vez da transação.
 func (s *Service) ProcessDueTransactions(ctx context.Context) error {
 	today := time.Now().Truncate(24 * time.Hour)
 
-	dueTransactions, _, err := s.Repository.GetDueTransactions(ctx, today, nil)
-	if err != nil {
-		return err
+	// carrega todas as páginas antes de processar, pois o processamento tira a recorrência da consulta
+	var dueTransactions []*RecurringTransaction
+	for page := 1; ; page++ {
+		items, total, err := s.Repository.GetDueTransactions(ctx, today, &pkg.PaginationParams{Page: page, Limit: activePageSize})
+		if err != nil {
+			return err
+		}
+		dueTransactions = append(dueTransactions, items...)
+		if len(items) == 0 || int64(len(dueTransactions)) >= total {
+			break
+		}
 	}
 
 	for _, recurring := range dueTransactions {
@@ -179,9 +236,8 @@ func (s *Service) ProcessRecurringManually(ctx context.Context, recurringID, use
 		return nil, err
 	}
 
-	nextDue := s.calculateNextDue(date, recurring.Frequency, recurring.DayOfMonth, recurring.DayOfWeek)
-	if err := s.Repository.UpdateLastProcessed(ctx, recurring.Id, date, nextDue); err != nil {
-		return nil, appErrors.NewDatabaseError(err)
+	if err := s.advance(ctx, recurring, date); err != nil {
+		return nil, err
 	}
 
 	return tx, nil
@@ -192,18 +248,39 @@ func (s *Service) validateCreateRequest(ctx context.Context, req *CreateRecurrin
 		return appErrors.NewValidationError("amount", "deve ser maior que zero")
 	}
 
+	if req.BusinessDayAdjustment == "" {
+		req.BusinessDayAdjustment = AdjustNone
+	}
+	if !req.BusinessDayAdjustment.IsValid() {
+		return appErrors.NewValidationError("business_day_adjustment", "deve ser NONE, PREVIOUS ou NEXT")
+	}
+
+	if req.Rule != "" {
+		rule, err := ParseRule(req.Rule)
+		if err != nil {
+			return appErrors.NewValidationError("rule", err.Error())
+		}
+		req.Frequency = rule.Frequency
+		req.Interval = rule.Interval
+	}
+
 	if !req.Frequency.IsValid() {
 		return appErrors.NewValidationError("frequency", "frequencia invalida")
 	}
 
-	if req.Type != "RECEIPT" && req.Type != "EXPENSE" {
-		return appErrors.NewValidationError("type", "tipo invalido")
+	if req.Interval == 0 {
+		req.Interval = 1
+	}
+	if req.Interval < 1 || req.Interval > 1000 {
+		return appErrors.NewValidationError("interval", "deve estar entre 1 e 1000")
 	}
 
-	if s.CategoryService != nil {
-		if err := s.CategoryService.ValidateAndEnsureExists(ctx, req.CategoryId, req.UserId); err != nil {
-			return err
-		}
+	if err := s.validateKind(ctx, req); err != nil {
+		return err
+	}
+
+	if req.Rule != "" {
+		return nil
 	}
 
 	if req.Frequency == FrequencyMonthly && (req.DayOfMonth < 1 || req.DayOfMonth > 31) {
@@ -222,7 +299,11 @@ func (s *Service) validateManualProcess(recurring *RecurringTransaction, process
 		return appErrors.NewValidationError("recurring", "transacao recorrente esta pausada")
 	}
 
-	if recurring.AccountId == nil {
+	if recurring.IsEstimated {
+		return appErrors.NewValidationError("recurring", "recorrencia com valor estimado deve ser lancada pela confirmacao da ocorrencia")
+	}
+
+	if recurring.IsReminder() {
 		return appErrors.NewValidationError("account_id", "transacao recorrente nao possui conta associada")
 	}
 
@@ -254,7 +335,14 @@ func (s *Service) processRecurring(ctx context.Context, recurring *RecurringTran
 		return nil
 	}
 
-	if recurring.AccountId == nil {
+	if recurring.IsEstimated {
+		if err := s.createPendingOccurrence(ctx, recurring); err != nil {
+			return err
+		}
+		return s.advance(ctx, recurring, today)
+	}
+
+	if recurring.IsReminder() {
 		return nil
 	}
 
@@ -263,22 +351,24 @@ func (s *Service) processRecurring(ctx context.Context, recurring *RecurringTran
 		return err
 	}
 
-	nextDue := s.calculateNextDue(today, recurring.Frequency, recurring.DayOfMonth, recurring.DayOfWeek)
-	_ = s.Repository.UpdateLastProcessed(ctx, recurring.Id, today, nextDue)
-
-	return nil
+	return s.advance(ctx, recurring, today)
 }
 
 func (s *Service) createTransactionFromRecurring(ctx context.Context, recurring *RecurringTransaction, date time.Time) (*transaction.Transaction, error) {
+	return s.execute(ctx, recurring, recurring.AccountId, recurring.Amount, date)
+}
+
+// postTransaction lança a transação de uma ocorrência da recorrência na conta informada
+func (s *Service) postTransaction(ctx context.Context, recurring *RecurringTransaction, accountID ulid.ULID, amount float64, date time.Time) (*transaction.Transaction, error) {
 	categoryID := &recurring.CategoryId
 	tx := &transaction.Transaction{
 		Id:          pkg.GenerateULIDObject(),
 		UserId:      recurring.UserId,
-		AccountId:   *recurring.AccountId,
+		AccountId:   accountID,
 		Type:        transaction.Types(recurring.Type),
 		CategoryId:  categoryID,
-		Amount:      recurring.Amount,
-		Description: recurring.Description + " (recorrente)",
+		Amount:      amount,
+		Description: recurring.Description + " " + generatedSuffix,
 		Date:        date,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
@@ -297,36 +387,169 @@ func (s *Service) createTransactionFromRecurring(ctx context.Context, recurring
 	return tx, nil
 }
 
-func (s *Service) calculateNextDue(from time.Time, frequency FrequencyType, dayOfMonth, dayOfWeek int) time.Time {
-	switch frequency {
-	case FrequencyDaily:
-		return from.AddDate(0, 0, 1)
+// UpcomingOccurrences lista as ocorrências das recorrências ativas até a data informada. Uma recorrência
+// com vencimento pendente entra uma vez na data original, marcada como atrasada, pois o processamento
+// gera uma única transação e avança para a próxima ocorrência. As ocorrências estimadas ainda não
+// confirmadas entram com o valor estimado no vencimento.
+func (s *Service) UpcomingOccurrences(ctx context.Context, userID ulid.ULID, until time.Time) ([]Occurrence, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
 
-	case FrequencyWeekly:
-		daysUntil := (dayOfWeek - int(from.Weekday()) + 7) % 7
-		if daysUntil == 0 {
-			daysUntil = 7
+	active, err := s.activeRecurring(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	today := truncateDay(time.Now())
+	occurrences, pending, err := s.pendingOccurrences(ctx, userID, active, until)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range active {
+		nextDue := truncateDay(rec.NextDue)
+		if nextDue.After(truncateDay(until)) {
+			continue
 		}
-		return from.AddDate(0, 0, daysUntil)
-
-	case FrequencyMonthly:
-		nextMonth := from.AddDate(0, 1, 0)
-		year, month, _ := nextMonth.Date()
-		lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
-		day := dayOfMonth
-		if day > lastDay {
-			day = lastDay
+		if nextDue.Before(today) && !pending[occurrenceKey(rec.Id, nextDue)] {
+			occurrences = append(occurrences, Occurrence{Recurring: rec, Date: nextDue, Amount: rec.Amount, Estimated: rec.IsEstimated, Overdue: true})
 		}
-		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 
-	case FrequencyYearly:
-		return from.AddDate(1, 0, 0)
+		dates, err := rec.OccurrencesBetween(today, until)
+		if err != nil {
+			return nil, appErrors.NewValidationError("rule", err.Error())
+		}
+		for _, date := range dates {
+			if date.Before(nextDue) || pending[occurrenceKey(rec.Id, date)] {
+				continue
+			}
+			occurrences = append(occurrences, Occurrence{Recurring: rec, Date: date, Amount: rec.Amount, Estimated: rec.IsEstimated})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Date.Before(occurrences[j].Date)
+	})
+	return occurrences, nil
+}
+
+// pendingOccurrences devolve as ocorrências estimadas pendentes até a data informada e o conjunto de
+// vencimentos já registrados, para não duplicá-los na projeção das recorrências
+func (s *Service) pendingOccurrences(ctx context.Context, userID ulid.ULID, active []*RecurringTransaction, until time.Time) ([]Occurrence, map[string]bool, error) {
+	byID := make(map[ulid.ULID]*RecurringTransaction, len(active))
+	for _, rec := range active {
+		byID[rec.Id] = rec
+	}
 
-	default:
-		return from.AddDate(0, 1, 0)
+	status := OccurrencePending
+	pending, err := s.Repository.ListOccurrences(ctx, userID, OccurrenceFilters{Status: &status})
+	if err != nil {
+		return nil, nil, appErrors.NewDatabaseError(err)
+	}
+
+	today := truncateDay(time.Now())
+	var occurrences []Occurrence
+	registered := make(map[string]bool, len(pending))
+	for _, item := range pending {
+		rec, ok := byID[item.RecurringId]
+		dueDate := truncateDay(item.DueDate)
+		if !ok || dueDate.After(truncateDay(until)) {
+			continue
+		}
+		occurrenceID := item.Id
+		registered[occurrenceKey(rec.Id, dueDate)] = true
+		occurrences = append(occurrences, Occurrence{
+			Recurring:    rec,
+			OccurrenceId: &occurrenceID,
+			Date:         dueDate,
+			Amount:       item.EstimatedAmount,
+			Estimated:    true,
+			Overdue:      dueDate.Before(today),
+		})
+	}
+	return occurrences, registered, nil
+}
+
+func occurrenceKey(recurringID ulid.ULID, date time.Time) string {
+	return recurringID.String() + "|" + date.Format("2006-01-02")
+}
+
+// activeRecurring carrega todas as recorrências ativas do usuário, página a página
+func (s *Service) activeRecurring(ctx context.Context, userID ulid.ULID) ([]*RecurringTransaction, error) {
+	var all []*RecurringTransaction
+	for page := 1; ; page++ {
+		items, total, err := s.Repository.GetActiveByUserID(ctx, userID, &pkg.PaginationParams{Page: page, Limit: activePageSize})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) == 0 || int64(len(all)) >= total {
+			return all, nil
+		}
 	}
 }
 
+// applyRule normaliza a regra da recorrência no formato RRULE e calcula o próximo vencimento
+// depois de after (ou a primeira ocorrência da série)
+func (s *Service) applyRule(recurring *RecurringTransaction, after *time.Time) error {
+	rule, err := recurring.Recurrence()
+	if err != nil {
+		return appErrors.NewValidationError("rule", err.Error())
+	}
+	recurring.Rule = rule.String()
+	recurring.Frequency = rule.Frequency
+	recurring.Interval = rule.Interval
+
+	// a regra pode ter mudado: a posição guardada não vale mais
+	recurring.SetNextDue(recurring.NextDue, SeriesPosition{})
+	nextDue, position, ok, err := recurring.NextOccurrence(after)
+	if err != nil {
+		return appErrors.NewValidationError("rule", err.Error())
+	}
+	if !ok {
+		return appErrors.NewValidationError("rule", "regra nao gera ocorrencias a partir da data de inicio")
+	}
+	recurring.SetNextDue(nextDue, position)
+	return nil
+}
+
+// advance registra o processamento e agenda a próxima ocorrência. Quando a série termina (COUNT,
+// UNTIL ou data de fim), a recorrência é desativada.
+func (s *Service) advance(ctx context.Context, recurring *RecurringTransaction, date time.Time) error {
+	nextDue, position, ok, err := recurring.NextOccurrence(&date)
+	if err != nil {
+		return appErrors.NewValidationError("rule", err.Error())
+	}
+
+	if !ok {
+		recurring.LastProcessed = &date
+		recurring.SetNextDue(date, SeriesPosition{})
+		recurring.IsActive = false
+		recurring.UpdatedAt = time.Now()
+		if err := s.Repository.Update(ctx, recurring); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+		return nil
+	}
+
+	if err := s.Repository.UpdateLastProcessed(ctx, recurring.Id, date, nextDue, position); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// Occurrence é uma ocorrência prevista de uma recorrência ativa. OccurrenceId identifica a ocorrência
+// estimada pendente de confirmação, quando já registrada.
+type Occurrence struct {
+	Recurring    *RecurringTransaction `json:"recurring"`
+	OccurrenceId *ulid.ULID            `json:"occurrenceId,omitempty"`
+	Date         time.Time             `json:"date"`
+	Amount       float64               `json:"amount"`
+	Estimated    bool                  `json:"estimated"`
+	Overdue      bool                  `json:"overdue"`
+}
+
 type CreateRecurringRequest struct {
 	UserId      ulid.ULID
 	Type        string
@@ -339,6 +562,19 @@ type CreateRecurringRequest struct {
 	DayOfWeek   int
 	StartDate   time.Time
 	EndDate     *time.Time
+
+	// Interval repete a cada N períodos; Rule (RRULE) substitui Frequency, Interval e os dias quando informada
+	Interval              int
+	Rule                  string
+	BusinessDayAdjustment BusinessDayAdjustment
+	IsEstimated           bool
+
+	// Kind vazio equivale a TRANSACTION; os identificadores são exigidos conforme o tipo
+	Kind                 Kind
+	DestinationAccountId *ulid.ULID
+	GoalId               *ulid.ULID
+	InvestmentId         *ulid.ULID
+	CreditCardId         *ulid.ULID
 }
 
 type UpdateRecurringRequest struct {
@@ -347,4 +583,8 @@ type UpdateRecurringRequest struct {
 	IsActive    *bool
 	EndDate     *time.Time
 	NextDue     *time.Time
+
+	Rule                  *string
+	BusinessDayAdjustment *BusinessDayAdjustment
+	IsEstimated           *bool
 }
@@ -80,3 +80,11 @@ type CategoryReport struct {
 	Transactions []TransactionItem `json:"transactions"`
 	MonthlyTrend []MonthSummary    `json:"monthlyTrend"`
 }
+
+type CategoryMonthAmount struct {
+	CategoryId   ulid.ULID `json:"categoryId"`
+	CategoryName string    `json:"categoryName"`
+	Month        int       `json:"month"`
+	Year         int       `json:"year"`
+	Amount       float64   `json:"amount"`
+}
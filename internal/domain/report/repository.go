@@ -10,4 +10,5 @@ type ReportRepository interface {
 	GetMonthlyReport(userID ulid.ULID, month, year int) (*MonthlyReport, error)
 	GetYearlyReport(userID ulid.ULID, year int) (*YearlyReport, error)
 	GetCategoryReport(userID ulid.ULID, categoryID ulid.ULID, startDate, endDate time.Time) (*CategoryReport, error)
+	GetCategoryMonthlyAmounts(userID ulid.ULID, txType string, startDate, endDate time.Time) ([]CategoryMonthAmount, error)
 }
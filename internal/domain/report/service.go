@@ -10,9 +10,15 @@ import (
 	"github.com/oklog/ulid/v2"
 )
 
+// InvestmentTaxSource fornece os dados de investimentos da declaração anual
+type InvestmentTaxSource interface {
+	GetTaxData(ctx context.Context, userID ulid.ULID, year int) (*InvestmentTaxData, error)
+}
+
 type Service struct {
 	Repository  ReportRepository
 	UserService *user.Service
+	Investments InvestmentTaxSource
 }
 
 func (s *Service) GetMonthlyReport(ctx context.Context, userID ulid.ULID, month, year int) (*MonthlyReport, error) {
@@ -55,6 +61,36 @@ func (s *Service) GetCategoryReport(ctx context.Context, userID, categoryID ulid
 	return s.Repository.GetCategoryReport(userID, categoryID, startDate, endDate)
 }
 
+// GetTaxReport monta o relatório auxiliar do IRPF do ano: bens e direitos, rendimentos de investimentos,
+// apuração mensal de renda variável e despesas dedutíveis identificadas pela categoria
+func (s *Service) GetTaxReport(ctx context.Context, userID ulid.ULID, year int) (*TaxReport, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if year < 2000 || year > 2100 {
+		return nil, appErrors.NewValidationError("year", "ano invalido")
+	}
+
+	if s.Investments == nil {
+		return nil, appErrors.ErrInternalServer
+	}
+
+	data, err := s.Investments.GetTaxData(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(1, 0, 0).Add(-time.Second)
+	expenses, err := s.Repository.GetCategoryMonthlyAmounts(userID, "EXPENSE", startDate, endDate)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	return BuildTaxReport(userID, data, expenses), nil
+}
+
 func (s *Service) ensureUserExists(ctx context.Context, userID ulid.ULID) error {
 	if s.UserService == nil {
 		return appErrors.ErrInternalServer
@@ -0,0 +1,280 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// TaxIncomeKind é a ficha da declaração em que o rendimento é informado
+type TaxIncomeKind string
+
+const (
+	// TaxIncomeExempt são os rendimentos isentos e não tributáveis
+	TaxIncomeExempt TaxIncomeKind = "ISENTO"
+	// TaxIncomeExclusive são os rendimentos sujeitos à tributação exclusiva/definitiva
+	TaxIncomeExclusive TaxIncomeKind = "EXCLUSIVO"
+)
+
+// Origem de rendimentos que não vêm de proventos
+const (
+	TaxSourceRedemption = "RESGATE"
+	TaxSourceSale       = "VENDA"
+)
+
+// TaxAsset é um bem na ficha Bens e Direitos, pelo custo de aquisição em 31/12 do ano anterior e do ano
+type TaxAsset struct {
+	InvestmentId     ulid.ULID `json:"investmentId"`
+	Name             string    `json:"name"`
+	Ticker           string    `json:"ticker,omitempty"`
+	Type             string    `json:"type"`
+	Quantity         float64   `json:"quantity,omitempty"`
+	PreviousQuantity float64   `json:"previousQuantity,omitempty"`
+	Cost             float64   `json:"cost"`
+	PreviousCost     float64   `json:"previousCost"`
+}
+
+// TaxIncome soma no ano os rendimentos de um investimento por origem (tipo do provento, resgate ou venda)
+type TaxIncome struct {
+	Kind         TaxIncomeKind `json:"kind"`
+	Source       string        `json:"source"`
+	InvestmentId ulid.ULID     `json:"investmentId"`
+	Name         string        `json:"name"`
+	Ticker       string        `json:"ticker,omitempty"`
+	Type         string        `json:"type"`
+	Gross        float64       `json:"gross"`
+	WithheldTax  float64       `json:"withheldTax"`
+	Net          float64       `json:"net"`
+}
+
+// TaxStockGain é a apuração mensal de renda variável de um tipo de ativo. SwingGain e DayTradeGain
+// podem ser negativos (prejuízo a compensar); Exempt indica vendas dentro do limite mensal de isenção.
+type TaxStockGain struct {
+	Month        int     `json:"month"`
+	Type         string  `json:"type"`
+	Sales        float64 `json:"sales"`
+	SwingGain    float64 `json:"swingGain"`
+	DayTradeGain float64 `json:"dayTradeGain"`
+	Exempt       bool    `json:"exempt"`
+	TaxDue       float64 `json:"taxDue"`
+}
+
+// InvestmentTaxData são os dados de investimentos da declaração anual
+type InvestmentTaxData struct {
+	Year       int            `json:"year"`
+	Assets     []TaxAsset     `json:"assets"`
+	Income     []TaxIncome    `json:"income"`
+	StockGains []TaxStockGain `json:"stockGains"`
+}
+
+// EducationDeductionLimit é o limite anual de dedução de despesas com instrução por pessoa
+const EducationDeductionLimit = 3561.50
+
+// DeductionKind é o tipo de despesa dedutível na declaração
+type DeductionKind string
+
+const (
+	DeductionHealth    DeductionKind = "SAUDE"
+	DeductionEducation DeductionKind = "EDUCACAO"
+)
+
+// Palavras das categorias identificadas como dedutíveis. Farmácia, livros, material escolar e cursos livres
+// não são dedutíveis e ficam de fora mesmo dentro dos grupos Saúde e Educação.
+var (
+	healthKeywords    = []string{"saude", "consulta", "exame", "medic", "hospital", "dentist", "odonto", "psicolog", "fisioterap", "clinica", "laboratorio"}
+	educationKeywords = []string{"educacao", "escola", "colegio", "faculdade", "universidade", "creche", "pos-graduacao", "mensalidade escolar"}
+	excludedKeywords  = []string{"farmacia", "livro", "material", "curso"}
+
+	accentReplacer = strings.NewReplacer("á", "a", "à", "a", "ã", "a", "â", "a", "é", "e", "ê", "e", "í", "i", "ó", "o", "õ", "o", "ô", "o", "ú", "u", "ç", "c")
+)
+
+// DeductibleExpense soma as despesas do ano de uma categoria identificada como dedutível
+type DeductibleExpense struct {
+	Kind         DeductionKind `json:"kind"`
+	CategoryId   ulid.ULID     `json:"categoryId"`
+	CategoryName string        `json:"categoryName"`
+	Amount       float64       `json:"amount"`
+}
+
+// TaxReport reúne os dados para a declaração anual do IRPF
+type TaxReport struct {
+	UserId                  ulid.ULID           `json:"userId"`
+	Year                    int                 `json:"year"`
+	Assets                  []TaxAsset          `json:"assets"`
+	ExemptIncome            []TaxIncome         `json:"exemptIncome"`
+	ExclusiveIncome         []TaxIncome         `json:"exclusiveIncome"`
+	StockGains              []TaxStockGain      `json:"stockGains"`
+	DeductibleExpenses      []DeductibleExpense `json:"deductibleExpenses"`
+	TotalAssets             float64             `json:"totalAssets"`
+	TotalExemptIncome       float64             `json:"totalExemptIncome"`
+	TotalExclusiveIncome    float64             `json:"totalExclusiveIncome"`
+	TotalHealthExpenses     float64             `json:"totalHealthExpenses"`
+	TotalEducationExpenses  float64             `json:"totalEducationExpenses"`
+	EducationLimitPerPerson float64             `json:"educationLimitPerPerson"`
+}
+
+// ClassifyDeduction identifica pelo nome da categoria se a despesa é dedutível
+func ClassifyDeduction(categoryName string) (DeductionKind, bool) {
+	name := accentReplacer.Replace(strings.ToLower(categoryName))
+	for _, keyword := range excludedKeywords {
+		if strings.Contains(name, keyword) {
+			return "", false
+		}
+	}
+	for _, keyword := range healthKeywords {
+		if strings.Contains(name, keyword) {
+			return DeductionHealth, true
+		}
+	}
+	for _, keyword := range educationKeywords {
+		if strings.Contains(name, keyword) {
+			return DeductionEducation, true
+		}
+	}
+	return "", false
+}
+
+// BuildTaxReport junta os dados de investimentos às despesas por categoria do ano
+func BuildTaxReport(userID ulid.ULID, data *InvestmentTaxData, expenses []CategoryMonthAmount) *TaxReport {
+	result := &TaxReport{
+		UserId:                  userID,
+		Year:                    data.Year,
+		Assets:                  data.Assets,
+		ExemptIncome:            []TaxIncome{},
+		ExclusiveIncome:         []TaxIncome{},
+		StockGains:              data.StockGains,
+		DeductibleExpenses:      []DeductibleExpense{},
+		EducationLimitPerPerson: EducationDeductionLimit,
+	}
+
+	for _, asset := range data.Assets {
+		result.TotalAssets += asset.Cost
+	}
+	for _, income := range data.Income {
+		if income.Kind == TaxIncomeExempt {
+			result.ExemptIncome = append(result.ExemptIncome, income)
+			result.TotalExemptIncome += income.Net
+		} else {
+			result.ExclusiveIncome = append(result.ExclusiveIncome, income)
+			result.TotalExclusiveIncome += income.Net
+		}
+	}
+
+	byCategory := map[ulid.ULID]*DeductibleExpense{}
+	for _, e := range expenses {
+		kind, ok := ClassifyDeduction(e.CategoryName)
+		if !ok {
+			continue
+		}
+		item, found := byCategory[e.CategoryId]
+		if !found {
+			item = &DeductibleExpense{Kind: kind, CategoryId: e.CategoryId, CategoryName: e.CategoryName}
+			byCategory[e.CategoryId] = item
+		}
+		item.Amount += e.Amount
+	}
+	for _, item := range byCategory {
+		item.Amount = roundCents(item.Amount)
+		result.DeductibleExpenses = append(result.DeductibleExpenses, *item)
+		if item.Kind == DeductionHealth {
+			result.TotalHealthExpenses += item.Amount
+		} else {
+			result.TotalEducationExpenses += item.Amount
+		}
+	}
+	sort.Slice(result.DeductibleExpenses, func(i, j int) bool {
+		a, b := result.DeductibleExpenses[i], result.DeductibleExpenses[j]
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		return a.Amount > b.Amount
+	})
+
+	result.TotalAssets = roundCents(result.TotalAssets)
+	result.TotalExemptIncome = roundCents(result.TotalExemptIncome)
+	result.TotalExclusiveIncome = roundCents(result.TotalExclusiveIncome)
+	result.TotalHealthExpenses = roundCents(result.TotalHealthExpenses)
+	result.TotalEducationExpenses = roundCents(result.TotalEducationExpenses)
+	return result
+}
+
+// WriteCSV exporta o relatório em seções separadas por ponto e vírgula, com vírgula decimal,
+// para abrir direto em planilhas em português
+func (r *TaxReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	rows := [][]string{
+		{fmt.Sprintf("Relatorio IRPF %d", r.Year)},
+		{},
+		{"Bens e Direitos"},
+		{"Tipo", "Nome", "Ticker", "Quantidade", fmt.Sprintf("Situacao em 31/12/%d", r.Year-1), fmt.Sprintf("Situacao em 31/12/%d", r.Year)},
+	}
+	for _, a := range r.Assets {
+		rows = append(rows, []string{string(a.Type), a.Name, a.Ticker, formatQuantity(a.Quantity), formatAmount(a.PreviousCost), formatAmount(a.Cost)})
+	}
+	rows = append(rows, []string{"Total", "", "", "", "", formatAmount(r.TotalAssets)}, []string{})
+
+	incomeHeader := []string{"Origem", "Nome", "Ticker", "Tipo", "Bruto", "Imposto retido", "Liquido"}
+	rows = append(rows, []string{"Rendimentos isentos e nao tributaveis"}, incomeHeader)
+	for _, i := range r.ExemptIncome {
+		rows = append(rows, incomeRow(i))
+	}
+	rows = append(rows, []string{"Total", "", "", "", "", "", formatAmount(r.TotalExemptIncome)}, []string{})
+
+	rows = append(rows, []string{"Rendimentos sujeitos a tributacao exclusiva"}, incomeHeader)
+	for _, i := range r.ExclusiveIncome {
+		rows = append(rows, incomeRow(i))
+	}
+	rows = append(rows, []string{"Total", "", "", "", "", "", formatAmount(r.TotalExclusiveIncome)}, []string{})
+
+	rows = append(rows, []string{"Renda variavel"}, []string{"Mes", "Tipo", "Vendas", "Ganho operacoes comuns", "Ganho day-trade", "Isento", "Imposto devido"})
+	for _, g := range r.StockGains {
+		exempt := "Nao"
+		if g.Exempt {
+			exempt = "Sim"
+		}
+		rows = append(rows, []string{strconv.Itoa(g.Month), string(g.Type), formatAmount(g.Sales), formatAmount(g.SwingGain), formatAmount(g.DayTradeGain), exempt, formatAmount(g.TaxDue)})
+	}
+	rows = append(rows, []string{})
+
+	rows = append(rows, []string{"Pagamentos dedutiveis"}, []string{"Tipo", "Categoria", "Valor"})
+	for _, e := range r.DeductibleExpenses {
+		rows = append(rows, []string{string(e.Kind), e.CategoryName, formatAmount(e.Amount)})
+	}
+	rows = append(rows,
+		[]string{"Total saude", "", formatAmount(r.TotalHealthExpenses)},
+		[]string{"Total educacao", "", formatAmount(r.TotalEducationExpenses)},
+		[]string{"Limite educacao por pessoa", "", formatAmount(r.EducationLimitPerPerson)},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func incomeRow(i TaxIncome) []string {
+	return []string{i.Source, i.Name, i.Ticker, string(i.Type), formatAmount(i.Gross), formatAmount(i.WithheldTax), formatAmount(i.Net)}
+}
+
+func formatAmount(v float64) string {
+	return strings.Replace(strconv.FormatFloat(v, 'f', 2, 64), ".", ",", 1)
+}
+
+func formatQuantity(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", ",", 1)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
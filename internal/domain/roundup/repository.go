@@ -0,0 +1,24 @@
+package roundup
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type RoundUpRepository interface {
+	CreateRule(ctx context.Context, rule *Rule) error
+	UpdateRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, ruleID, userID ulid.ULID) error
+	GetRuleByID(ctx context.Context, ruleID, userID ulid.ULID) (*Rule, error)
+	GetRuleByAccount(ctx context.Context, accountID, userID ulid.ULID) (*Rule, error)
+	ListRules(ctx context.Context, userID ulid.ULID) ([]*Rule, error)
+	GetDueRules(ctx context.Context, now time.Time, limit int) ([]*Rule, error)
+	CreateEntry(ctx context.Context, entry *Entry) error
+	DeletePendingEntryByTransaction(ctx context.Context, transactionID, userID ulid.ULID) error
+	DeletePendingEntriesByRule(ctx context.Context, ruleID ulid.ULID) error
+	GetPendingEntries(ctx context.Context, ruleID ulid.ULID) ([]*Entry, error)
+	MarkEntriesSwept(ctx context.Context, entryIDs []ulid.ULID, sweptAt time.Time) error
+	GetMonthlySummary(ctx context.Context, userID ulid.ULID, start, end time.Time) ([]MonthlySummary, error)
+}
@@ -0,0 +1,82 @@
+package roundup
+
+import (
+	"math"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Rule configura o arredondamento das despesas de uma conta para uma meta
+type Rule struct {
+	Id          ulid.ULID      `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId      ulid.ULID      `gorm:"type:varchar(26);index:idx_roundup_rules_user_account,unique;not null" json:"userId"`
+	AccountId   ulid.ULID      `gorm:"type:varchar(26);index:idx_roundup_rules_user_account,unique;not null" json:"accountId"`
+	GoalId      ulid.ULID      `gorm:"type:varchar(26);index:idx_roundup_rules_goal_id;not null" json:"goalId"`
+	Increment   int            `gorm:"not null" json:"increment"`
+	Frequency   SweepFrequency `gorm:"type:varchar(20);not null" json:"frequency"`
+	IsActive    bool           `gorm:"not null;default:true" json:"isActive"`
+	NextSweepAt time.Time      `gorm:"not null;index:idx_roundup_rules_next_sweep" json:"nextSweepAt"`
+	LastSweepAt *time.Time     `json:"lastSweepAt,omitempty"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (Rule) TableName() string {
+	return "roundup_rules"
+}
+
+// Entry é a diferença acumulada pelo arredondamento de uma despesa
+type Entry struct {
+	Id            ulid.ULID   `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId        ulid.ULID   `gorm:"type:varchar(26);index:idx_roundup_entries_user_id;not null" json:"userId"`
+	RuleId        ulid.ULID   `gorm:"type:varchar(26);index:idx_roundup_entries_rule_id;not null" json:"ruleId"`
+	AccountId     ulid.ULID   `gorm:"type:varchar(26);not null" json:"accountId"`
+	GoalId        ulid.ULID   `gorm:"type:varchar(26);not null" json:"goalId"`
+	TransactionId ulid.ULID   `gorm:"type:varchar(26);uniqueIndex:idx_roundup_entries_transaction_id;not null" json:"transactionId"`
+	ExpenseAmount float64     `gorm:"type:decimal(15,2);not null" json:"expenseAmount"`
+	Amount        float64     `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Status        EntryStatus `gorm:"type:varchar(20);not null;index:idx_roundup_entries_status" json:"status"`
+	Date          time.Time   `gorm:"type:date;not null" json:"date"`
+	SweptAt       *time.Time  `json:"sweptAt,omitempty"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (Entry) TableName() string {
+	return "roundup_entries"
+}
+
+// MonthlySummary é o total economizado por arredondamento em um mês
+type MonthlySummary struct {
+	Year     int     `json:"year"`
+	Month    int     `json:"month"`
+	Expenses int     `json:"expenses"`
+	Swept    float64 `json:"swept"`
+	Pending  float64 `json:"pending"`
+	Total    float64 `json:"total"`
+}
+
+// RoundUp retorna quanto falta para a despesa chegar ao próximo múltiplo do incremento.
+// Valores já múltiplos do incremento não geram arredondamento.
+func RoundUp(amount float64, increment int) float64 {
+	if increment <= 0 {
+		return 0
+	}
+
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	step := int64(increment) * 100
+	remainder := cents % step
+	if remainder == 0 {
+		return 0
+	}
+	return float64(step-remainder) / 100
+}
+
+// NextSweep calcula a próxima varredura a partir de uma data
+func NextSweep(from time.Time, frequency SweepFrequency) time.Time {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	if frequency == SweepWeekly {
+		return day.AddDate(0, 0, 7)
+	}
+	return day.AddDate(0, 0, 1)
+}
@@ -0,0 +1,35 @@
+package roundup
+
+type SweepFrequency string
+
+const (
+	SweepDaily  SweepFrequency = "DAILY"
+	SweepWeekly SweepFrequency = "WEEKLY"
+)
+
+func (f SweepFrequency) IsValid() bool {
+	switch f {
+	case SweepDaily, SweepWeekly:
+		return true
+	}
+	return false
+}
+
+type EntryStatus string
+
+const (
+	EntryPending EntryStatus = "PENDING"
+	EntrySwept   EntryStatus = "SWEPT"
+)
+
+// Increments são os valores aceitos para o arredondamento (R$1, R$5 e R$10)
+var Increments = []int{1, 5, 10}
+
+func IsValidIncrement(increment int) bool {
+	for _, i := range Increments {
+		if i == increment {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,364 @@
+package roundup
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/goal"
+	"Fynance/internal/domain/shared"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+const dueRulesBatchSize = 100
+
+type Service struct {
+	Repository     RoundUpRepository
+	AccountService account.AccountServiceInterface
+	GoalService    *goal.Service
+	shared.BaseService
+}
+
+var _ shared.ExpenseRoundUpRecorder = (*Service)(nil)
+
+func NewService(repo RoundUpRepository, accountService account.AccountServiceInterface, goalService *goal.Service, userChecker *shared.UserCheckerService) *Service {
+	return &Service{
+		Repository:     repo,
+		AccountService: accountService,
+		GoalService:    goalService,
+		BaseService: shared.BaseService{
+			UserChecker: userChecker,
+		},
+	}
+}
+
+type RuleRequest struct {
+	UserId    ulid.ULID
+	AccountId ulid.ULID
+	GoalId    ulid.ULID
+	Increment int
+	Frequency SweepFrequency
+}
+
+type RuleUpdateRequest struct {
+	Id        ulid.ULID
+	UserId    ulid.ULID
+	GoalId    *ulid.ULID
+	Increment *int
+	Frequency *SweepFrequency
+	IsActive  *bool
+}
+
+func (s *Service) CreateRule(ctx context.Context, req *RuleRequest) (*Rule, error) {
+	if err := s.EnsureUserExists(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := validateRule(req.Increment, req.Frequency); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateAccount(ctx, req.AccountId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateGoal(ctx, req.GoalId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Repository.GetRuleByAccount(ctx, req.AccountId, req.UserId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	if existing != nil {
+		return nil, appErrors.NewConflictError("regra de arredondamento para esta conta")
+	}
+
+	now := time.Now()
+	rule := &Rule{
+		Id:          pkg.GenerateULIDObject(),
+		UserId:      req.UserId,
+		AccountId:   req.AccountId,
+		GoalId:      req.GoalId,
+		Increment:   req.Increment,
+		Frequency:   req.Frequency,
+		IsActive:    true,
+		NextSweepAt: NextSweep(now, req.Frequency),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.Repository.CreateRule(ctx, rule); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return rule, nil
+}
+
+func (s *Service) UpdateRule(ctx context.Context, req *RuleUpdateRequest) (*Rule, error) {
+	rule, err := s.GetRule(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GoalId != nil {
+		if err := s.validateGoal(ctx, *req.GoalId, req.UserId); err != nil {
+			return nil, err
+		}
+		rule.GoalId = *req.GoalId
+	}
+	if req.Increment != nil {
+		rule.Increment = *req.Increment
+	}
+	if req.Frequency != nil && *req.Frequency != rule.Frequency {
+		rule.Frequency = *req.Frequency
+		rule.NextSweepAt = NextSweep(time.Now(), rule.Frequency)
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := validateRule(rule.Increment, rule.Frequency); err != nil {
+		return nil, err
+	}
+
+	rule.UpdatedAt = time.Now()
+	if err := s.Repository.UpdateRule(ctx, rule); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return rule, nil
+}
+
+// DeleteRule remove a regra e descarta o valor ainda não varrido para a meta
+func (s *Service) DeleteRule(ctx context.Context, ruleID, userID ulid.ULID) error {
+	if _, err := s.GetRule(ctx, ruleID, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeletePendingEntriesByRule(ctx, ruleID); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	if err := s.Repository.DeleteRule(ctx, ruleID, userID); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) GetRule(ctx context.Context, ruleID, userID ulid.ULID) (*Rule, error) {
+	rule, err := s.Repository.GetRuleByID(ctx, ruleID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("regra de arredondamento")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return rule, nil
+}
+
+func (s *Service) ListRules(ctx context.Context, userID ulid.ULID) ([]*Rule, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.Repository.ListRules(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return rules, nil
+}
+
+// RecordExpense acumula o arredondamento de uma despesa quando a conta tem regra ativa
+func (s *Service) RecordExpense(ctx context.Context, transactionID, accountID, userID ulid.ULID, amount float64, date time.Time) error {
+	rule, err := s.Repository.GetRuleByAccount(ctx, accountID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	if rule == nil || !rule.IsActive {
+		return nil
+	}
+
+	diff := RoundUp(amount, rule.Increment)
+	if diff <= 0 {
+		return nil
+	}
+
+	entry := &Entry{
+		Id:            pkg.GenerateULIDObject(),
+		UserId:        userID,
+		RuleId:        rule.Id,
+		AccountId:     accountID,
+		GoalId:        rule.GoalId,
+		TransactionId: transactionID,
+		ExpenseAmount: math.Abs(amount),
+		Amount:        diff,
+		Status:        EntryPending,
+		Date:          date,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := s.Repository.CreateEntry(ctx, entry); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// DiscardExpense remove o arredondamento ainda não varrido de uma despesa excluída
+func (s *Service) DiscardExpense(ctx context.Context, transactionID, userID ulid.ULID) error {
+	if err := s.Repository.DeletePendingEntryByTransaction(ctx, transactionID, userID); err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// Sweep transfere imediatamente para a meta o valor acumulado da regra
+func (s *Service) Sweep(ctx context.Context, ruleID, userID ulid.ULID) (float64, error) {
+	rule, err := s.GetRule(ctx, ruleID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return s.sweepRule(ctx, rule, time.Now())
+}
+
+// ProcessDueSweeps varre para as metas os valores acumulados das regras vencidas
+func (s *Service) ProcessDueSweeps(ctx context.Context, now time.Time) error {
+	rules, err := s.Repository.GetDueRules(ctx, now, dueRulesBatchSize)
+	if err != nil {
+		return appErrors.NewDatabaseError(err)
+	}
+
+	for _, rule := range rules {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		amount, err := s.sweepRule(ctx, rule, now)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("rule_id", rule.Id.String()).
+				Str("user_id", rule.UserId.String()).
+				Msg("Arredondamento nao varrido, valor mantido para a proxima execucao")
+		} else if amount > 0 {
+			logger.Info().
+				Str("rule_id", rule.Id.String()).
+				Str("goal_id", rule.GoalId.String()).
+				Float64("amount", amount).
+				Msg("Arredondamento varrido para a meta")
+		}
+
+		rule.NextSweepAt = NextSweep(now, rule.Frequency)
+		rule.UpdatedAt = time.Now()
+		if err := s.Repository.UpdateRule(ctx, rule); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
+	}
+
+	return nil
+}
+
+// GetMonthlySummary retorna quanto foi economizado por arredondamento nos últimos meses
+func (s *Service) GetMonthlySummary(ctx context.Context, userID ulid.ULID, months int, now time.Time) ([]MonthlySummary, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if months <= 0 {
+		months = 12
+	}
+
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	start := end.AddDate(0, -months, 0)
+
+	rows, err := s.Repository.GetMonthlySummary(ctx, userID, start, end)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	byMonth := make(map[string]MonthlySummary, len(rows))
+	for _, row := range rows {
+		byMonth[time.Date(row.Year, time.Month(row.Month), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")] = row
+	}
+
+	summary := make([]MonthlySummary, 0, months)
+	for cursor := start; cursor.Before(end); cursor = cursor.AddDate(0, 1, 0) {
+		row, ok := byMonth[cursor.Format("2006-01")]
+		if !ok {
+			row = MonthlySummary{Year: cursor.Year(), Month: int(cursor.Month())}
+		}
+		row.Total = math.Round((row.Swept+row.Pending)*100) / 100
+		summary = append(summary, row)
+	}
+	return summary, nil
+}
+
+func (s *Service) sweepRule(ctx context.Context, rule *Rule, now time.Time) (float64, error) {
+	entries, err := s.Repository.GetPendingEntries(ctx, rule.Id)
+	if err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+
+	total := 0.0
+	ids := make([]ulid.ULID, 0, len(entries))
+	for _, entry := range entries {
+		total += entry.Amount
+		ids = append(ids, entry.Id)
+	}
+	total = math.Round(total*100) / 100
+
+	if total <= 0 {
+		return 0, nil
+	}
+
+	if err := s.GoalService.MakeContribution(ctx, rule.GoalId, rule.AccountId, rule.UserId, total, "Arredondamento"); err != nil {
+		return 0, err
+	}
+
+	if err := s.Repository.MarkEntriesSwept(ctx, ids, now); err != nil {
+		return 0, appErrors.NewDatabaseError(err)
+	}
+
+	rule.LastSweepAt = &now
+	return total, nil
+}
+
+func (s *Service) validateAccount(ctx context.Context, accountID, userID ulid.ULID) error {
+	accountEntity, err := s.AccountService.GetAccountByID(ctx, accountID, userID)
+	if err != nil {
+		return err
+	}
+	if accountEntity.Type == account.TypeCreditCard {
+		return appErrors.NewValidationError("account_id", "conta nao pode ser cartao de credito")
+	}
+	return nil
+}
+
+func (s *Service) validateGoal(ctx context.Context, goalID, userID ulid.ULID) error {
+	g, err := s.GoalService.GetGoalByID(ctx, goalID, userID)
+	if err != nil {
+		return err
+	}
+	if g.Status != goal.Active {
+		return appErrors.NewValidationError("goal_id", "meta nao esta ativa")
+	}
+	return nil
+}
+
+func validateRule(increment int, frequency SweepFrequency) error {
+	if !IsValidIncrement(increment) {
+		return appErrors.NewValidationError("increment", "deve ser 1, 5 ou 10")
+	}
+	if !frequency.IsValid() {
+		return appErrors.NewValidationError("frequency", "deve ser DAILY ou WEEKLY")
+	}
+	return nil
+}
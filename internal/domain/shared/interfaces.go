@@ -29,11 +29,6 @@ type AccountService interface {
 	BalanceUpdater
 }
 
-type BudgetUpdater interface {
-	UpdateSpent(ctx context.Context, categoryID, userID ulid.ULID, amount float64) error
-	UpdateSpentWithDate(ctx context.Context, categoryID, userID ulid.ULID, amount float64, transactionDate time.Time) error
-}
-
 type TransactionCreator interface {
 	CreateTransaction(ctx context.Context, transaction interface{}) error
 }
@@ -54,3 +49,60 @@ type GoalContributionDeleter interface {
 type InvestmentTransactionDeleter interface {
 	DeleteInvestmentTransactionByTransactionId(ctx context.Context, transactionID, userID ulid.ULID) error
 }
+
+type AchievementEvaluator interface {
+	EvaluateAchievements(ctx context.Context, userID ulid.ULID) error
+}
+
+type GoalInvestmentSyncer interface {
+	SyncInvestmentBackedAmount(ctx context.Context, goalID, userID ulid.ULID) error
+}
+
+type ExpenseRoundUpRecorder interface {
+	RecordExpense(ctx context.Context, transactionID, accountID, userID ulid.ULID, amount float64, date time.Time) error
+	DiscardExpense(ctx context.Context, transactionID, userID ulid.ULID) error
+}
+
+type LoanPaymentHandler interface {
+	IsLoanPayment(ctx context.Context, transactionID, userID ulid.ULID) (bool, error)
+	DeletePaymentByTransactionId(ctx context.Context, transactionID, userID ulid.ULID) error
+}
+
+// Tipos de notificação publicados pelos domínios; os textos de cada tipo ficam nos templates do
+// pacote notification
+const (
+	NotificationBudgetAlert      = "BUDGET_ALERT"
+	NotificationBudgetExceeded   = "BUDGET_EXCEEDED"
+	NotificationInvoiceDue       = "INVOICE_DUE"
+	NotificationBillDue          = "BILL_DUE"
+	NotificationLoanDue          = "LOAN_DUE"
+	NotificationGoalDeadline     = "GOAL_DEADLINE"
+	NotificationGoalMilestone    = "GOAL_MILESTONE"
+	NotificationSecurityLogin    = "SECURITY_LOGIN"
+	NotificationSecurityPassword = "SECURITY_PASSWORD_CHANGED"
+)
+
+// Notifier publica uma notificação ao usuário pelos canais que ele habilitou; data preenche o template do tipo
+type Notifier interface {
+	Notify(ctx context.Context, userID ulid.ULID, notificationType string, data map[string]any) error
+}
+
+// Eventos do domínio publicados no barramento de eventos. Os de integração também são entregues aos
+// webhooks dos usuários; transaction.updated, goal.milestone e investment.created são apenas internos.
+const (
+	EventTransactionCreated = "transaction.created"
+	EventTransactionUpdated = "transaction.updated"
+	EventTransactionDeleted = "transaction.deleted"
+	EventInvoiceClosed      = "invoice.closed"
+	EventInvoicePaid        = "invoice.paid"
+	EventBudgetExceeded     = "budget.exceeded"
+	EventGoalCompleted      = "goal.completed"
+	EventGoalMilestone      = "goal.milestone"
+	EventInvestmentCreated  = "investment.created"
+)
+
+// EventPublisher publica um evento do usuário aos assinantes do tipo; data é serializado em JSON. Dentro de
+// uma transação aberta pelo Transactor o evento só é confirmado junto com ela.
+type EventPublisher interface {
+	Publish(ctx context.Context, userID ulid.ULID, eventType string, data any) error
+}
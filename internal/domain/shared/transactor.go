@@ -0,0 +1,56 @@
+package shared
+
+import (
+	"context"
+	"sync"
+)
+
+// Transactor executa fn numa transação do banco. Os repositórios chamados com o ctx recebido participam
+// da mesma transação; chamadas aninhadas reaproveitam a transação já aberta.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// WithinTransaction usa o transactor quando configurado; sem ele fn roda direto, sem transação
+func WithinTransaction(ctx context.Context, transactor Transactor, fn func(ctx context.Context) error) error {
+	if transactor == nil {
+		return fn(ctx)
+	}
+	return transactor.WithinTransaction(ctx, fn)
+}
+
+type commitHooksKey struct{}
+
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// WithCommitHooks prepara o ctx de uma transação para receber ações pós-commit; run deve ser chamado
+// pelo Transactor somente depois do commit
+func WithCommitHooks(ctx context.Context) (context.Context, func()) {
+	hooks := &commitHooks{}
+	run := func() {
+		hooks.mu.Lock()
+		fns := hooks.fns
+		hooks.fns = nil
+		hooks.mu.Unlock()
+		for _, fn := range fns {
+			fn()
+		}
+	}
+	return context.WithValue(ctx, commitHooksKey{}, hooks), run
+}
+
+// AfterCommit agenda fn para depois do commit da transação do ctx, ou executa na hora fora de transação.
+// Se a transação for desfeita, fn não é executada.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks)
+	if !ok {
+		fn()
+		return
+	}
+	hooks.mu.Lock()
+	hooks.fns = append(hooks.fns, fn)
+	hooks.mu.Unlock()
+}
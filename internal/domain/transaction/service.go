@@ -9,7 +9,6 @@ import (
 	"Fynance/internal/domain/category"
 	"Fynance/internal/domain/shared"
 	appErrors "Fynance/internal/errors"
-	"Fynance/internal/logger"
 	"Fynance/internal/pkg"
 
 	"github.com/oklog/ulid/v2"
@@ -20,9 +19,17 @@ type Service struct {
 	Repository        TransactionRepository
 	CategoryService   category.CategoryServiceInterface
 	AccountService    account.AccountServiceInterface
-	BudgetService     shared.BudgetUpdater
 	GoalService       shared.GoalContributionDeleter
 	InvestmentService shared.InvestmentTransactionDeleter
+	// RoundUpService é opcional e acumula o arredondamento das despesas
+	RoundUpService shared.ExpenseRoundUpRecorder
+	// LoanService é opcional e mantém os pagamentos de empréstimos ligados às despesas
+	LoanService shared.LoanPaymentHandler
+	// Events é opcional e publica a criação, a alteração e a exclusão de transações; orçamentos e webhooks
+	// acompanham as transações por esses eventos
+	Events shared.EventPublisher
+	// Transactor é opcional e grava a transação, o saldo da conta e o evento de forma atômica
+	Transactor shared.Transactor
 	shared.BaseService
 }
 
@@ -32,7 +39,6 @@ func NewService(
 	repo TransactionRepository,
 	categoryService category.CategoryServiceInterface,
 	accountService account.AccountServiceInterface,
-	budgetService shared.BudgetUpdater,
 	goalService shared.GoalContributionDeleter,
 	investmentService shared.InvestmentTransactionDeleter,
 	userChecker *shared.UserCheckerService,
@@ -41,7 +47,6 @@ func NewService(
 		Repository:        repo,
 		CategoryService:   categoryService,
 		AccountService:    accountService,
-		BudgetService:     budgetService,
 		GoalService:       goalService,
 		InvestmentService: investmentService,
 		BaseService: shared.BaseService{
@@ -68,30 +73,29 @@ func (s *Service) CreateTransaction(ctx context.Context, transaction *Transactio
 		return err
 	}
 
-	if accountEntity.Type == account.TypeCreditCard {
-		s.initTransaction(transaction)
-		if err := s.Repository.Create(ctx, transaction); err != nil {
-			return appErrors.NewDatabaseError(err)
+	if accountEntity.Type != account.TypeCreditCard {
+		if err := s.validateBalance(transaction, accountEntity); err != nil {
+			return err
 		}
-		return nil
-	}
-
-	if err := s.validateBalance(transaction, accountEntity); err != nil {
-		return err
 	}
 
 	s.initTransaction(transaction)
-	if err := s.Repository.Create(ctx, transaction); err != nil {
-		return appErrors.NewDatabaseError(err)
-	}
-
-	if err := s.updateAccountBalance(ctx, transaction, accountEntity); err != nil {
-		return err
-	}
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.Repository.Create(ctx, transaction); err != nil {
+			return appErrors.NewDatabaseError(err)
+		}
 
-	s.updateBudgetIfExpense(ctx, transaction)
+		if accountEntity.Type != account.TypeCreditCard {
+			if err := s.updateAccountBalance(ctx, transaction, accountEntity); err != nil {
+				return err
+			}
+			if err := s.recordRoundUpIfExpense(ctx, transaction); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return s.publish(ctx, transaction.UserId, shared.EventTransactionCreated, transaction)
+	})
 }
 
 func (s *Service) UpdateTransaction(ctx context.Context, transaction *Transaction) error {
@@ -104,6 +108,24 @@ func (s *Service) UpdateTransaction(ctx context.Context, transaction *Transactio
 		return err
 	}
 
+	if storedTransaction.Type == Tax && storedTransaction.InvestmentId != nil {
+		return appErrors.NewValidationError("transaction", "imposto retido nao pode ser alterado")
+	}
+
+	if storedTransaction.Type == Receipt && storedTransaction.InvestmentId != nil {
+		return appErrors.NewValidationError("transaction", "provento nao pode ser alterado, exclua e registre novamente")
+	}
+
+	if storedTransaction.Type == Expense && s.LoanService != nil {
+		isLoanPayment, err := s.LoanService.IsLoanPayment(ctx, storedTransaction.Id, storedTransaction.UserId)
+		if err != nil {
+			return err
+		}
+		if isLoanPayment {
+			return appErrors.NewValidationError("transaction", "parcela de emprestimo nao pode ser alterada, exclua e registre novamente")
+		}
+	}
+
 	accountEntity, err := s.AccountService.GetAccountByID(ctx, transaction.AccountId, transaction.UserId)
 	if err != nil {
 		return err
@@ -128,30 +150,31 @@ func (s *Service) UpdateTransaction(ctx context.Context, transaction *Transactio
 		return err
 	}
 
-	if err := s.processBalanceUpdate(ctx, storedTransaction, transaction, oldAccountEntity, accountEntity); err != nil {
-		return err
-	}
-
-	oldCategoryId := storedTransaction.CategoryId
-	oldDate := storedTransaction.Date
-	oldType := storedTransaction.Type
-	oldAmount := storedTransaction.Amount
+	previous := *storedTransaction
+	accountChanged := storedTransaction.AccountId != transaction.AccountId
 
-	s.applyTransactionUpdate(storedTransaction, transaction)
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if err := s.processBalanceUpdate(ctx, storedTransaction, transaction, oldAccountEntity, accountEntity); err != nil {
+			return err
+		}
 
-	if err := s.Repository.Update(ctx, storedTransaction); err != nil {
-		return err
-	}
+		s.applyTransactionUpdate(storedTransaction, transaction)
 
-	if storedTransaction.AccountId != transaction.AccountId {
-		if err := s.updateAccountBalance(ctx, transaction, accountEntity); err != nil {
+		if err := s.Repository.Update(ctx, storedTransaction); err != nil {
 			return err
 		}
-	}
 
-	s.updateBudgetOnChange(ctx, transaction.UserId, oldCategoryId, oldDate, oldType, oldAmount, transaction)
+		if accountChanged {
+			if err := s.updateAccountBalance(ctx, transaction, accountEntity); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return s.publish(ctx, transaction.UserId, shared.EventTransactionUpdated, Update{
+			Previous: &previous,
+			Current:  storedTransaction,
+		})
+	})
 }
 
 func (s *Service) DeleteTransaction(ctx context.Context, transactionID ulid.ULID, userID ulid.ULID) error {
@@ -159,42 +182,70 @@ func (s *Service) DeleteTransaction(ctx context.Context, transactionID ulid.ULID
 	if err != nil {
 		return err
 	}
+
+	if transactionEntity.Type == Tax && transactionEntity.InvestmentId != nil {
+		return appErrors.NewValidationError("transaction", "imposto retido e removido junto com o resgate")
+	}
+
 	accountEntity, err := s.AccountService.GetAccountByID(ctx, transactionEntity.AccountId, userID)
 	if err != nil {
 		appErr, isAppErr := appErrors.AsAppError(err)
 		if !isAppErr || appErr.Code != "NOT_FOUND" {
 			return err
 		}
-	} else {
-		if err := s.revertAccountBalance(ctx, transactionEntity, accountEntity); err != nil {
+		accountEntity = nil
+	}
+
+	return shared.WithinTransaction(ctx, s.Transactor, func(ctx context.Context) error {
+		if accountEntity != nil {
+			if err := s.revertAccountBalance(ctx, transactionEntity, accountEntity); err != nil {
+				return err
+			}
+		}
+
+		if err := s.removeLinkedRecords(ctx, transactionEntity); err != nil {
+			return err
+		}
+
+		if err := s.Repository.Delete(ctx, transactionID); err != nil {
+			return err
+		}
+
+		return s.publish(ctx, userID, shared.EventTransactionDeleted, transactionEntity)
+	})
+}
+
+// removeLinkedRecords desfaz os registros de outros domínios ligados à transação excluída; roda na
+// transação da exclusão, então uma falha mantém a transação e os registros ligados
+func (s *Service) removeLinkedRecords(ctx context.Context, transactionEntity *Transaction) error {
+	transactionID := transactionEntity.Id
+	userID := transactionEntity.UserId
+
+	if transactionEntity.Type == Expense && s.RoundUpService != nil {
+		if err := s.RoundUpService.DiscardExpense(ctx, transactionID, userID); err != nil {
 			return err
 		}
 	}
 
-	s.revertBudgetIfExpense(ctx, transactionEntity)
+	if transactionEntity.Type == Expense && s.LoanService != nil {
+		if err := s.LoanService.DeletePaymentByTransactionId(ctx, transactionID, userID); err != nil {
+			return err
+		}
+	}
 
 	if transactionEntity.Type == Goals && s.GoalService != nil {
 		if err := s.GoalService.DeleteContributionByTransactionId(ctx, transactionID, userID); err != nil {
-			logger.Warn().
-				Err(err).
-				Str("transaction_id", transactionID.String()).
-				Str("user_id", userID.String()).
-				Msg("failed to delete goal contribution by transaction id")
+			return err
 		}
 	}
 
-	if (transactionEntity.Type == Investment || transactionEntity.Type == Withdraw) &&
+	if (transactionEntity.Type == Investment || transactionEntity.Type == Withdraw || transactionEntity.Type == Receipt) &&
 		transactionEntity.InvestmentId != nil && s.InvestmentService != nil {
 		if err := s.InvestmentService.DeleteInvestmentTransactionByTransactionId(ctx, transactionID, userID); err != nil {
-			logger.Warn().
-				Err(err).
-				Str("transaction_id", transactionID.String()).
-				Str("user_id", userID.String()).
-				Msg("failed to delete investment transaction by transaction id")
+			return err
 		}
 	}
-
-	return s.Repository.Delete(ctx, transactionID)
+	return nil
 }
 
 func (s *Service) GetTransactionByID(ctx context.Context, transactionID ulid.ULID, userID ulid.ULID) (*Transaction, error) {
@@ -432,84 +483,22 @@ func (s *Service) revertAccountBalance(ctx context.Context, transaction *Transac
 	return s.AccountService.UpdateBalance(ctx, transaction.AccountId, transaction.UserId, amount)
 }
 
-func (s *Service) updateBudgetIfExpense(ctx context.Context, transaction *Transaction) {
-	if transaction.Type != Expense || s.BudgetService == nil || transaction.CategoryId == nil {
-		return
-	}
-
-	spentAmount := transaction.Amount
-	if spentAmount < 0 {
-		spentAmount = -spentAmount
-	}
-
-	if err := s.BudgetService.UpdateSpentWithDate(ctx, *transaction.CategoryId, transaction.UserId, spentAmount, transaction.Date); err != nil {
-		logger.Error().
-			Err(err).
-			Str("category_id", transaction.CategoryId.String()).
-			Str("user_id", transaction.UserId.String()).
-			Float64("amount", spentAmount).
-			Msg("error updating budget spent")
-	}
-}
-
-func (s *Service) revertBudgetIfExpense(ctx context.Context, transaction *Transaction) {
-	if transaction.Type != Expense || s.BudgetService == nil || transaction.CategoryId == nil {
-		return
-	}
-
-	spentAmount := transaction.Amount
-	if spentAmount < 0 {
-		spentAmount = -spentAmount
-	}
-
-	if err := s.BudgetService.UpdateSpentWithDate(ctx, *transaction.CategoryId, transaction.UserId, -spentAmount, transaction.Date); err != nil {
-		logger.Warn().
-			Err(err).
-			Str("category_id", transaction.CategoryId.String()).
-			Str("user_id", transaction.UserId.String()).
-			Float64("amount", -spentAmount).
-			Msg("failed to revert budget spent")
+func (s *Service) recordRoundUpIfExpense(ctx context.Context, transaction *Transaction) error {
+	if transaction.Type != Expense || s.RoundUpService == nil {
+		return nil
 	}
+	return s.RoundUpService.RecordExpense(ctx, transaction.Id, transaction.AccountId, transaction.UserId, transaction.Amount, transaction.Date)
 }
 
-func (s *Service) updateBudgetOnChange(ctx context.Context, userID ulid.ULID, oldCategoryId *ulid.ULID, oldDate time.Time, oldType Types, oldAmount float64, newTx *Transaction) {
-	if s.BudgetService == nil {
-		return
-	}
-
-	if oldType == Expense && oldCategoryId != nil {
-		oldSpentAmount := oldAmount
-		if oldSpentAmount < 0 {
-			oldSpentAmount = -oldSpentAmount
-		}
-		if err := s.BudgetService.UpdateSpentWithDate(ctx, *oldCategoryId, userID, -oldSpentAmount, oldDate); err != nil {
-			logger.Warn().
-				Err(err).
-				Str("category_id", oldCategoryId.String()).
-				Str("user_id", userID.String()).
-				Float64("amount", -oldSpentAmount).
-				Msg("failed to revert old budget spent on transaction update")
-		}
+// publish grava o evento na transação do ctx; a falha desfaz a operação para o evento não se perder
+func (s *Service) publish(ctx context.Context, userID ulid.ULID, eventType string, data any) error {
+	if s.Events == nil {
+		return nil
 	}
-
-	if newTx.Type == Expense && newTx.CategoryId != nil {
-		newSpentAmount := newTx.Amount
-		if newSpentAmount < 0 {
-			newSpentAmount = -newSpentAmount
-		}
-		transactionDate := newTx.Date
-		if transactionDate.IsZero() {
-			transactionDate = time.Now()
-		}
-		if err := s.BudgetService.UpdateSpentWithDate(ctx, *newTx.CategoryId, userID, newSpentAmount, transactionDate); err != nil {
-			logger.Warn().
-				Err(err).
-				Str("category_id", newTx.CategoryId.String()).
-				Str("user_id", userID.String()).
-				Float64("amount", newSpentAmount).
-				Msg("failed to update new budget spent on transaction update")
-		}
+	if err := s.Events.Publish(ctx, userID, eventType, data); err != nil {
+		return appErrors.NewDatabaseError(err)
 	}
+	return nil
 }
 
 func (s *Service) initTransaction(transaction *Transaction) {
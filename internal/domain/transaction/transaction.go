@@ -24,3 +24,9 @@ type Transaction struct {
 func (Transaction) TableName() string {
 	return "transactions"
 }
+
+// Update é o dado do evento transaction.updated, com a transação antes e depois da alteração
+type Update struct {
+	Previous *Transaction `json:"previous"`
+	Current  *Transaction `json:"current"`
+}
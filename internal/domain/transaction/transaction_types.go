@@ -9,4 +9,5 @@ const (
 	Goals      Types = "GOALS"
 	Investment Types = "INVESTMENT"
 	Withdraw   Types = "WITHDRAW"
+	Tax        Types = "TAX"
 )
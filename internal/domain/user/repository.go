@@ -3,6 +3,8 @@ package user
 import (
 	"context"
 
+	"Fynance/internal/pkg"
+
 	"github.com/oklog/ulid/v2"
 )
 
@@ -13,4 +15,5 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id ulid.ULID) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetPlan(ctx context.Context, id ulid.ULID) (Plan, error)
+	ListIDs(ctx context.Context, pagination *pkg.PaginationParams) ([]ulid.ULID, int64, error)
 }
@@ -6,6 +6,7 @@ import (
 
 	"Fynance/internal/domain/shared"
 	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
 	"Fynance/internal/pkg"
 
 	"github.com/oklog/ulid/v2"
@@ -14,6 +15,8 @@ import (
 
 type Service struct {
 	Repository UserRepository
+	// Notifier é opcional e avisa o usuário quando a senha é alterada
+	Notifier shared.Notifier
 }
 
 var _ shared.UserChecker = (*Service)(nil)
@@ -66,6 +69,11 @@ func (s *Service) GetPlan(ctx context.Context, id ulid.ULID) (Plan, error) {
 	return plan, nil
 }
 
+// ListUserIDs percorre os usuários cadastrados, página a página, para os jobs que atendem todos eles
+func (s *Service) ListUserIDs(ctx context.Context, pagination *pkg.PaginationParams) ([]ulid.ULID, int64, error) {
+	return s.Repository.ListIDs(ctx, pagination)
+}
+
 func (s *Service) UpdatePlan(ctx context.Context, userID ulid.ULID, newPlan Plan) error {
 	if !newPlan.IsValid() {
 		return appErrors.NewValidationError("plan", "plano invalido")
@@ -130,7 +138,17 @@ func (s *Service) UpdatePassword(ctx context.Context, userID ulid.ULID, currentP
 	user.Password = string(hashedPassword)
 	user.UpdatedAt = pkg.SetTimestamps()
 
-	return s.Repository.Update(ctx, user)
+	if err := s.Repository.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if s.Notifier != nil {
+		data := map[string]any{"at": user.UpdatedAt}
+		if err := s.Notifier.Notify(ctx, userID, shared.NotificationSecurityPassword, data); err != nil {
+			logger.Warn().Err(err).Str("user_id", userID.String()).Msg("Erro ao notificar alteracao de senha")
+		}
+	}
+	return nil
 }
 
 type UserServiceAdapter struct {
@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type Repository interface {
+	CreateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	UpdateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	// DeleteEndpoint remove o endpoint e o histórico de entregas dele
+	DeleteEndpoint(ctx context.Context, id, userID ulid.ULID) error
+	GetEndpoint(ctx context.Context, id, userID ulid.ULID) (*Endpoint, error)
+	ListEndpoints(ctx context.Context, userID ulid.ULID) ([]*Endpoint, error)
+	CountEndpoints(ctx context.Context, userID ulid.ULID) (int64, error)
+
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	// CreateDeliveries grava as entregas de um evento de uma vez; entregas de um evento que o endpoint
+	// já recebeu são ignoradas, então republicar o mesmo evento não duplica o envio
+	CreateDeliveries(ctx context.Context, deliveries []*Delivery) error
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, id, endpointID, userID ulid.ULID) (*Delivery, error)
+	// ListDeliveries lista as entregas do endpoint da mais recente para a mais antiga; status vazio não filtra
+	ListDeliveries(ctx context.Context, endpointID, userID ulid.ULID, status DeliveryStatus, pagination *pkg.PaginationParams) ([]*Delivery, int64, error)
+	// ClaimDueDeliveries reserva até limit entregas pendentes com próxima tentativa até now, das mais
+	// antigas, adiando a próxima tentativa para leaseUntil. Execuções concorrentes do job não reservam
+	// a mesma entrega, e uma entrega reservada por um processo que caiu volta depois do prazo.
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Delivery, error)
+}
@@ -0,0 +1,488 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/event"
+	"Fynance/internal/domain/shared"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxEndpoints limita os webhooks cadastrados por usuário
+	MaxEndpoints = 10
+	// claimLease reserva as entregas de um lote enquanto o job faz as tentativas; se o processo cair
+	// antes do resultado, a entrega volta para a fila depois desse prazo
+	claimLease = 5 * time.Minute
+	// dueBatchSize limita as entregas reservadas de cada vez, para que o lote termine dentro de
+	// claimLease mesmo com todos os endpoints esgotando o timeout do envio
+	dueBatchSize = 20
+	// responseBodyLimit limita o trecho da resposta guardado no histórico
+	responseBodyLimit = 1024
+)
+
+type Service struct {
+	Repository Repository
+	Sender     Sender
+	Retry      RetryPolicy
+	// AllowInsecureURLs aceita endpoints http, para receptores locais em desenvolvimento e testes
+	AllowInsecureURLs bool
+	shared.BaseService
+}
+
+var _ shared.EventPublisher = (*Service)(nil)
+
+func NewService(repo Repository, sender Sender, userChecker *shared.UserCheckerService) *Service {
+	return &Service{
+		Repository: repo,
+		Sender:     sender,
+		Retry:      DefaultRetryPolicy,
+		BaseService: shared.BaseService{
+			UserChecker: userChecker,
+		},
+	}
+}
+
+// EndpointUpdate traz os campos alterados de um endpoint; campos nil não mudam
+type EndpointUpdate struct {
+	URL         *string
+	Description *string
+	Events      []string
+	Active      *bool
+}
+
+// CreateEndpoint cadastra o endpoint ativo e gera o segredo de assinatura, devolvido apenas aqui
+func (s *Service) CreateEndpoint(ctx context.Context, userID ulid.ULID, rawURL, description string, events []string) (*Endpoint, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	endpointURL, err := s.validateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeEvents(events)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.Repository.CountEndpoints(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	if count >= MaxEndpoints {
+		return nil, appErrors.NewValidationError("webhook", fmt.Sprintf("limite de %d webhooks por usuario atingido", MaxEndpoints))
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, appErrors.ErrInternalServer.WithError(err)
+	}
+
+	now := time.Now()
+	endpoint := &Endpoint{
+		Id:          pkg.GenerateULIDObject(),
+		UserId:      userID,
+		URL:         endpointURL,
+		Description: strings.TrimSpace(description),
+		Secret:      secret,
+		Events:      normalized,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.Repository.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return endpoint, nil
+}
+
+func (s *Service) ListEndpoints(ctx context.Context, userID ulid.ULID) ([]*Endpoint, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	endpoints, err := s.Repository.ListEndpoints(ctx, userID)
+	if err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return endpoints, nil
+}
+
+func (s *Service) GetEndpoint(ctx context.Context, id, userID ulid.ULID) (*Endpoint, error) {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.Repository.GetEndpoint(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("webhook")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return endpoint, nil
+}
+
+func (s *Service) UpdateEndpoint(ctx context.Context, id, userID ulid.ULID, update EndpointUpdate) (*Endpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		endpointURL, err := s.validateURL(*update.URL)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.URL = endpointURL
+	}
+	if update.Description != nil {
+		endpoint.Description = strings.TrimSpace(*update.Description)
+	}
+	if update.Events != nil {
+		normalized, err := normalizeEvents(update.Events)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Events = normalized
+	}
+	if update.Active != nil {
+		endpoint.Active = *update.Active
+	}
+
+	endpoint.UpdatedAt = time.Now()
+	if err := s.Repository.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return endpoint, nil
+}
+
+func (s *Service) DeleteEndpoint(ctx context.Context, id, userID ulid.ULID) error {
+	if err := s.EnsureUserExists(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeleteEndpoint(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("webhook")
+		}
+		return appErrors.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// RotateSecret troca o segredo de assinatura; entregas feitas a partir daqui usam o novo segredo
+func (s *Service) RotateSecret(ctx context.Context, id, userID ulid.ULID) (*Endpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, appErrors.ErrInternalServer.WithError(err)
+	}
+	endpoint.Secret = secret
+	endpoint.UpdatedAt = time.Now()
+	if err := s.Repository.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	return endpoint, nil
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, endpointID, userID ulid.ULID, status DeliveryStatus, pagination *pkg.PaginationParams) ([]*Delivery, int64, error) {
+	if _, err := s.GetEndpoint(ctx, endpointID, userID); err != nil {
+		return nil, 0, err
+	}
+	if status != "" && !status.IsValid() {
+		return nil, 0, appErrors.NewValidationError("status", "deve ser PENDING, SUCCEEDED ou FAILED")
+	}
+
+	deliveries, total, err := s.Repository.ListDeliveries(ctx, endpointID, userID, status, pagination)
+	if err != nil {
+		return nil, 0, appErrors.NewDatabaseError(err)
+	}
+	return deliveries, total, nil
+}
+
+// Publish registra uma entrega para cada endpoint ativo inscrito no evento. As entregas nascem vencidas e
+// o job de entregas faz a primeira tentativa e as seguintes.
+func (s *Service) Publish(ctx context.Context, userID ulid.ULID, eventType string, data any) error {
+	return s.publish(ctx, pkg.GenerateULIDObject(), userID, eventType, time.Now(), data)
+}
+
+// HandleEvent entrega aos webhooks um evento do barramento do domínio. O id do evento é mantido, então o
+// receptor reconhece o mesmo evento mesmo que o barramento o entregue mais de uma vez.
+func (s *Service) HandleEvent(ctx context.Context, evt *event.Event) error {
+	return s.publish(ctx, evt.Id, evt.UserId, evt.Type, evt.OccurredAt, evt.Payload)
+}
+
+func (s *Service) publish(ctx context.Context, eventID, userID ulid.ULID, eventType string, occurredAt time.Time, data any) error {
+	endpoints, err := s.Repository.ListEndpoints(ctx, userID)
+	if err != nil {
+		return err
+	}
+	endpoints = slices.DeleteFunc(endpoints, func(endpoint *Endpoint) bool {
+		return !endpoint.Subscribes(eventType)
+	})
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(Event{Id: eventID, Type: eventType, CreatedAt: occurredAt.UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]*Delivery, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		delivery := newDelivery(endpoint, eventID, eventType, payload, now)
+		delivery.NextAttemptAt = &now
+		deliveries = append(deliveries, delivery)
+	}
+	return s.Repository.CreateDeliveries(ctx, deliveries)
+}
+
+// Ping envia um evento de teste ao endpoint e devolve o resultado, sem novas tentativas em caso de falha
+func (s *Service) Ping(ctx context.Context, id, userID ulid.ULID) (*Delivery, error) {
+	endpoint, err := s.GetEndpoint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	eventID := pkg.GenerateULIDObject()
+	payload, err := json.Marshal(Event{
+		Id:        eventID,
+		Type:      EventPing,
+		CreatedAt: now.UTC(),
+		Data:      map[string]any{"endpointId": endpoint.Id, "events": endpoint.Events},
+	})
+	if err != nil {
+		return nil, appErrors.ErrInternalServer.WithError(err)
+	}
+
+	delivery := newDelivery(endpoint, eventID, EventPing, payload, now)
+	if err := s.Repository.CreateDelivery(ctx, delivery); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	s.attempt(ctx, endpoint, delivery, false)
+	return delivery, nil
+}
+
+// Redeliver reenvia o corpo original de uma entrega como uma nova entrega, com uma única tentativa
+// feita na hora. O histórico da entrega original é mantido.
+func (s *Service) Redeliver(ctx context.Context, endpointID, deliveryID, userID ulid.ULID) (*Delivery, error) {
+	endpoint, err := s.GetEndpoint(ctx, endpointID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.Repository.GetDelivery(ctx, deliveryID, endpointID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("entrega")
+		}
+		return nil, appErrors.NewDatabaseError(err)
+	}
+
+	delivery := newDelivery(endpoint, original.EventId, original.EventType, original.Payload, time.Now())
+	delivery.RedeliveryOf = &original.Id
+	if err := s.Repository.CreateDelivery(ctx, delivery); err != nil {
+		return nil, appErrors.NewDatabaseError(err)
+	}
+	s.attempt(ctx, endpoint, delivery, false)
+	return delivery, nil
+}
+
+// ProcessDueDeliveries faz as tentativas das entregas pendentes cujo prazo chegou, reservando um lote
+// de cada vez até não restar entrega vencida. Entregas de endpoints desativados ou removidos são
+// encerradas como falha.
+func (s *Service) ProcessDueDeliveries(ctx context.Context, now time.Time) error {
+	endpoints := make(map[ulid.ULID]*Endpoint)
+	for {
+		deliveries, err := s.Repository.ClaimDueDeliveries(ctx, now, time.Now().Add(claimLease), dueBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			endpoint, ok := endpoints[delivery.EndpointId]
+			if !ok {
+				endpoint, err = s.Repository.GetEndpoint(ctx, delivery.EndpointId, delivery.UserId)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				endpoints[delivery.EndpointId] = endpoint
+			}
+			if endpoint == nil || !endpoint.Active {
+				delivery.Status = DeliveryFailed
+				delivery.NextAttemptAt = nil
+				delivery.Error = "webhook desativado"
+				if endpoint == nil {
+					delivery.Error = "webhook removido"
+				}
+				if err := s.Repository.UpdateDelivery(ctx, delivery); err != nil {
+					return err
+				}
+				continue
+			}
+			s.attempt(ctx, endpoint, delivery, true)
+		}
+
+		if len(deliveries) < dueBatchSize {
+			return nil
+		}
+	}
+}
+
+// attempt envia a entrega assinada e grava o resultado. Com retry, uma falha agenda a próxima tentativa
+// pelo backoff até esgotar a política; sem retry, a falha encerra a entrega.
+func (s *Service) attempt(ctx context.Context, endpoint *Endpoint, delivery *Delivery, retry bool) {
+	start := time.Now()
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		HeaderEvent:     delivery.EventType,
+		HeaderDelivery:  delivery.Id.String(),
+		HeaderSignature: Sign(endpoint.Secret, start, delivery.Payload),
+	}
+	response, err := s.Sender.Send(ctx, endpoint.URL, headers, delivery.Payload)
+	finished := time.Now()
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &finished
+	delivery.DurationMs = finished.Sub(start).Milliseconds()
+	delivery.ResponseStatus = 0
+	delivery.ResponseBody = ""
+	delivery.Error = ""
+	if response != nil {
+		delivery.ResponseStatus = response.StatusCode
+		delivery.ResponseBody = truncate(response.Body, responseBodyLimit)
+	}
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case response == nil || response.StatusCode < 200 || response.StatusCode >= 300:
+		delivery.Error = fmt.Sprintf("endpoint respondeu com status %d", delivery.ResponseStatus)
+	}
+
+	switch {
+	case delivery.Error == "":
+		delivery.Status = DeliverySucceeded
+		delivery.DeliveredAt = &finished
+		delivery.NextAttemptAt = nil
+	case retry && delivery.Attempts < s.Retry.MaxAttempts:
+		next := finished.Add(s.Retry.Backoff(delivery.Attempts))
+		delivery.Status = DeliveryPending
+		delivery.NextAttemptAt = &next
+	default:
+		delivery.Status = DeliveryFailed
+		delivery.NextAttemptAt = nil
+	}
+
+	if err := s.Repository.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Error().
+			Err(err).
+			Str("delivery_id", delivery.Id.String()).
+			Msg("Erro ao registrar resultado da entrega de webhook")
+	}
+	if delivery.Status != DeliverySucceeded {
+		logger.Warn().
+			Str("delivery_id", delivery.Id.String()).
+			Str("endpoint_id", endpoint.Id.String()).
+			Str("event", delivery.EventType).
+			Int("attempts", delivery.Attempts).
+			Str("error", delivery.Error).
+			Msg("Falha na entrega de webhook")
+	}
+}
+
+func newDelivery(endpoint *Endpoint, eventID ulid.ULID, eventType string, payload []byte, now time.Time) *Delivery {
+	return &Delivery{
+		Id:         pkg.GenerateULIDObject(),
+		EndpointId: endpoint.Id,
+		UserId:     endpoint.UserId,
+		EventId:    eventID,
+		EventType:  eventType,
+		Payload:    payload,
+		Status:     DeliveryPending,
+		CreatedAt:  now,
+	}
+}
+
+func (s *Service) validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", appErrors.NewValidationError("url", "é obrigatória")
+	}
+	if len(raw) > 500 {
+		return "", appErrors.NewValidationError("url", "deve ter no maximo 500 caracteres")
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return "", appErrors.NewValidationError("url", "url invalida")
+	}
+	if parsed.User != nil {
+		return "", appErrors.NewValidationError("url", "nao pode conter usuario ou senha")
+	}
+	switch {
+	case parsed.Scheme == "https":
+	case parsed.Scheme == "http" && s.AllowInsecureURLs:
+	default:
+		return "", appErrors.NewValidationError("url", "deve usar https")
+	}
+	return parsed.String(), nil
+}
+
+func normalizeEvents(events []string) ([]string, error) {
+	normalized := make([]string, 0, len(events))
+	for _, eventType := range events {
+		eventType = strings.TrimSpace(eventType)
+		if !IsValidEvent(eventType) {
+			return nil, appErrors.NewValidationError("events", "evento invalido: "+eventType)
+		}
+		if !slices.Contains(normalized, eventType) {
+			normalized = append(normalized, eventType)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, appErrors.NewValidationError("events", "informe ao menos um evento")
+	}
+	return normalized, nil
+}
+
+func generateSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(raw), nil
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return strings.ToValidUTF8(value[:limit], "")
+}
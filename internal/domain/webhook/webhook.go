@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"time"
+
+	"Fynance/internal/domain/shared"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// EventPing é enviado só pelo teste manual do endpoint e não entra na lista de inscrições
+const EventPing = "ping"
+
+// EventTypes lista os eventos em que um endpoint pode se inscrever
+var EventTypes = []string{
+	shared.EventTransactionCreated,
+	shared.EventTransactionDeleted,
+	shared.EventInvoiceClosed,
+	shared.EventInvoicePaid,
+	shared.EventBudgetExceeded,
+	shared.EventGoalCompleted,
+}
+
+func IsValidEvent(eventType string) bool {
+	return slices.Contains(EventTypes, eventType)
+}
+
+// Cabeçalhos enviados em cada entrega
+const (
+	HeaderEvent     = "X-Fynance-Event"
+	HeaderDelivery  = "X-Fynance-Delivery"
+	HeaderSignature = "X-Fynance-Signature"
+)
+
+// Endpoint é uma URL do usuário que recebe os eventos em que se inscreveu. Secret assina o corpo de cada entrega
+// e só é exibido na criação e na rotação.
+type Endpoint struct {
+	Id          ulid.ULID `gorm:"type:varchar(26);primaryKey" json:"id"`
+	UserId      ulid.ULID `gorm:"type:varchar(26);index:idx_webhook_endpoints_user;not null" json:"userId"`
+	URL         string    `gorm:"type:varchar(500);not null" json:"url"`
+	Description string    `gorm:"type:varchar(255)" json:"description"`
+	Secret      string    `gorm:"type:varchar(100);not null" json:"-"`
+	Events      []string  `gorm:"type:text;serializer:json;not null" json:"events"`
+	Active      bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;not null" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime;not null" json:"updatedAt"`
+}
+
+func (Endpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// Subscribes indica se o endpoint recebe o tipo de evento
+func (e *Endpoint) Subscribes(eventType string) bool {
+	return e.Active && slices.Contains(e.Events, eventType)
+}
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending aguarda a primeira tentativa ou uma nova tentativa em NextAttemptAt
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliverySucceeded DeliveryStatus = "SUCCEEDED"
+	// DeliveryFailed esgotou as tentativas; pode ser reenviada manualmente
+	DeliveryFailed DeliveryStatus = "FAILED"
+)
+
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryPending, DeliverySucceeded, DeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// Delivery é o registro de envio de um evento a um endpoint, com o resultado da última tentativa.
+// Payload guarda o corpo exato enviado, para que o reenvio seja idêntico ao original. Cada evento gera
+// no máximo uma entrega por endpoint; só os reenvios manuais repetem o par endpoint e evento.
+type Delivery struct {
+	Id             ulid.ULID       `gorm:"type:varchar(26);primaryKey" json:"id"`
+	EndpointId     ulid.ULID       `gorm:"type:varchar(26);index:idx_webhook_deliveries_endpoint;uniqueIndex:idx_webhook_deliveries_event,priority:1,where:redelivery_of IS NULL;not null" json:"endpointId"`
+	UserId         ulid.ULID       `gorm:"type:varchar(26);not null" json:"userId"`
+	EventId        ulid.ULID       `gorm:"type:varchar(26);uniqueIndex:idx_webhook_deliveries_event,priority:2;not null" json:"eventId"`
+	EventType      string          `gorm:"type:varchar(50);not null" json:"eventType"`
+	Payload        json.RawMessage `gorm:"type:text;not null" json:"payload"`
+	Status         DeliveryStatus  `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts       int             `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time      `gorm:"type:timestamp;index:idx_webhook_deliveries_due,priority:2" json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time      `gorm:"type:timestamp" json:"lastAttemptAt"`
+	ResponseStatus int             `gorm:"not null;default:0" json:"responseStatus"`
+	ResponseBody   string          `gorm:"type:text" json:"responseBody"`
+	Error          string          `gorm:"type:text" json:"error"`
+	DurationMs     int64           `gorm:"not null;default:0" json:"durationMs"`
+	RedeliveryOf   *ulid.ULID      `gorm:"type:varchar(26)" json:"redeliveryOf"`
+	DeliveredAt    *time.Time      `gorm:"type:timestamp" json:"deliveredAt"`
+	CreatedAt      time.Time       `gorm:"autoCreateTime;not null" json:"createdAt"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// Event é o corpo JSON enviado aos endpoints; Id se repete nas entregas do mesmo evento e permite
+// ao receptor descartar duplicatas
+type Event struct {
+	Id        ulid.ULID `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Response é o resultado HTTP de uma tentativa de entrega
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// Sender faz a requisição POST ao endpoint. Erros representam falhas de rede ou de destino
+// bloqueado; respostas fora de 2xx voltam em Response.
+type Sender interface {
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (*Response, error)
+}
+
+// Sign calcula a assinatura enviada em X-Fynance-Signature: "t=<unix>,v1=<hex>", onde v1 é o
+// HMAC-SHA256 de "<unix>.<corpo>" com o segredo do endpoint. O timestamp permite ao receptor
+// recusar entregas antigas.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RetryPolicy define quantas vezes uma entrega é tentada e o intervalo entre as tentativas, que dobra
+// a cada falha a partir de BaseDelay até MaxDelay
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 8,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    6 * time.Hour,
+}
+
+// Backoff devolve a espera antes da próxima tentativa, depois de attempts tentativas sem sucesso
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return min(delay, p.MaxDelay)
+}
@@ -3,18 +3,27 @@ package fx
 import (
 	"Fynance/config"
 	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/achievement"
 	"Fynance/internal/domain/auth"
 	"Fynance/internal/domain/budget"
+	"Fynance/internal/domain/calendar"
 	"Fynance/internal/domain/category"
 	"Fynance/internal/domain/creditcard"
 	"Fynance/internal/domain/dashboard"
+	"Fynance/internal/domain/event"
+	"Fynance/internal/domain/forecast"
 	"Fynance/internal/domain/goal"
 	"Fynance/internal/domain/investment"
+	"Fynance/internal/domain/loan"
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/notification"
 	"Fynance/internal/domain/recurring"
 	"Fynance/internal/domain/report"
+	"Fynance/internal/domain/roundup"
 	"Fynance/internal/domain/shared"
 	"Fynance/internal/domain/transaction"
 	"Fynance/internal/domain/user"
+	"Fynance/internal/domain/webhook"
 	"Fynance/internal/infrastructure"
 	"Fynance/internal/logger"
 
@@ -62,10 +71,67 @@ var DomainModule = fx.Module("domain",
 
 		// CreditCard service
 		newCreditCardService,
+
+		// Achievement service
+		newAchievementService,
+
+		// RoundUp service
+		newRoundUpService,
+
+		// Market service
+		newMarketService,
+
+		// Loan service
+		newLoanService,
+
+		// Forecast service
+		newForecastService,
+
+		// Calendar service
+		newCalendarService,
+
+		// Notification service
+		newNotificationService,
+
+		// Webhook service
+		newWebhookService,
+
+		// Barramento de eventos do domínio
+		newEventBus,
 	),
 	fx.Invoke(
 		// Atualizar GoalService com TransactionService após ambos serem criados
 		updateGoalServiceWithTransactionService,
+
+		// Conectar metas aos investimentos que as lastreiam
+		updateGoalServiceWithInvestmentService,
+
+		// Acumular arredondamento das despesas criadas
+		updateTransactionServiceWithRoundUpService,
+
+		// Manter os pagamentos de empréstimos ao excluir despesas
+		updateTransactionServiceWithLoanService,
+
+		// Ampliar a detecção de assinaturas com o cartão e os empréstimos
+		updateRecurringServiceWithDetectionSources,
+
+		// Executar transferências, aportes e cobranças no cartão recorrentes pelos domínios donos
+		updateRecurringServiceWithExecutors,
+
+		// Marcar posições a mercado e acumular rendimento da renda fixa
+		updateInvestmentServiceWithMarketService,
+
+		// Publicar alertas, lembretes e eventos de segurança nas notificações
+		updateServicesWithNotificationService,
+
+		// Publicar os eventos do domínio no barramento, gravados na mesma transação da mudança
+		updateServicesWithEventBus,
+
+		// Inscrever orçamentos, conquistas, notificações e webhooks nos eventos do domínio
+		subscribeEventHandlers,
+
+		// Aguardar os envios de notificações em andamento no desligamento
+		waitNotificationDeliveries,
 	),
 )
 
@@ -78,6 +144,118 @@ func updateGoalServiceWithTransactionService(
 	goalSvc.TransactionService = transactionSvc
 }
 
+// updateGoalServiceWithInvestmentService conecta metas e investimentos nos dois sentidos:
+// a meta aplica aportes nos investimentos e o investimento recalcula a meta quando o saldo muda
+func updateGoalServiceWithInvestmentService(
+	goalSvc *goal.Service,
+	investmentSvc *investment.Service,
+) {
+	goalSvc.InvestmentService = investmentSvc
+	investmentSvc.GoalSync = goalSvc
+}
+
+// updateTransactionServiceWithRoundUpService conecta as despesas ao arredondamento para metas
+func updateTransactionServiceWithRoundUpService(
+	transactionSvc *transaction.Service,
+	roundUpSvc *roundup.Service,
+) {
+	transactionSvc.RoundUpService = roundUpSvc
+}
+
+// updateTransactionServiceWithLoanService desfaz o pagamento do empréstimo quando a despesa é excluída
+func updateTransactionServiceWithLoanService(
+	transactionSvc *transaction.Service,
+	loanSvc *loan.Service,
+) {
+	transactionSvc.LoanService = loanSvc
+}
+
+// updateRecurringServiceWithDetectionSources inclui as compras no cartão na detecção de assinaturas
+// e descarta as parcelas de empréstimos, que já têm cronograma próprio
+func updateRecurringServiceWithDetectionSources(
+	recurringSvc *recurring.Service,
+	creditCardSvc creditcard.Service,
+	loanSvc *loan.Service,
+) {
+	recurringSvc.CardCharges = &creditCardSvc
+	recurringSvc.LoanPayments = loanSvc
+}
+
+// updateRecurringServiceWithExecutors conecta as recorrências de transferência, aporte em meta,
+// aporte em investimento e cobrança no cartão aos serviços que executam cada movimentação
+func updateRecurringServiceWithExecutors(
+	recurringSvc *recurring.Service,
+	accountSvc *account.Service,
+	goalSvc *goal.Service,
+	investmentSvc *investment.Service,
+	creditCardSvc creditcard.Service,
+) {
+	recurringSvc.Transfers = accountSvc
+	recurringSvc.GoalContributions = goalSvc
+	recurringSvc.InvestmentContributions = investmentSvc
+	recurringSvc.CardPurchases = &creditCardSvc
+}
+
+// updateInvestmentServiceWithMarketService conecta as posições às cotações e a renda fixa às séries de índices
+func updateInvestmentServiceWithMarketService(
+	investmentSvc *investment.Service,
+	marketSvc *market.Service,
+) {
+	investmentSvc.Prices = marketSvc
+	investmentSvc.Indexes = marketSvc
+	investmentSvc.PriceHistory = marketSvc
+}
+
+// updateServicesWithNotificationService conecta os domínios que publicam notificações: alertas de
+// orçamento, lembretes de vencimento e eventos de segurança. Os marcos de metas chegam pelo barramento.
+func updateServicesWithNotificationService(
+	notificationSvc *notification.Service,
+	budgetSvc *budget.Service,
+	calendarSvc *calendar.Service,
+	authSvc *auth.Service,
+	userSvc *user.Service,
+) {
+	budgetSvc.Notifier = notificationSvc
+	calendarSvc.Notifier = notificationSvc
+	authSvc.Notifier = notificationSvc
+	userSvc.Notifier = notificationSvc
+}
+
+// updateServicesWithEventBus conecta os domínios que publicam eventos ao barramento e ao transactor.
+// O cartão de crédito recebe os dois na construção, porque o service é fornecido por valor.
+func updateServicesWithEventBus(
+	bus *event.Bus,
+	transactor shared.Transactor,
+	transactionSvc *transaction.Service,
+	budgetSvc *budget.Service,
+	goalSvc *goal.Service,
+	investmentSvc *investment.Service,
+) {
+	transactionSvc.Events = bus
+	transactionSvc.Transactor = transactor
+	budgetSvc.Events = bus
+	budgetSvc.Transactor = transactor
+	goalSvc.Events = bus
+	goalSvc.Transactor = transactor
+	investmentSvc.Events = bus
+}
+
+// subscribeEventHandlers registra os assinantes do barramento. Os nomes identificam as entregas gravadas
+// na outbox e não devem mudar.
+func subscribeEventHandlers(
+	bus *event.Bus,
+	budgetSvc *budget.Service,
+	achievementSvc *achievement.Service,
+	notificationSvc *notification.Service,
+	webhookSvc *webhook.Service,
+) {
+	bus.Subscribe("budget_spending", budgetSvc.HandleTransactionEvent, budget.TransactionEvents...)
+	bus.Subscribe("goal_milestone_achievements", achievementSvc.HandleEvent, shared.EventGoalMilestone)
+	bus.Subscribe("achievements", achievementSvc.HandleEvent, achievement.AchievementEvents...)
+	bus.Subscribe("goal_milestone_notifications", notificationSvc.HandleGoalMilestone, shared.EventGoalMilestone)
+	bus.Subscribe("webhooks", webhookSvc.HandleEvent, webhook.EventTypes...)
+}
+
 func newUserService(repo *infrastructure.UserRepository) *user.Service {
 	return user.NewService(repo)
 }
@@ -138,18 +316,23 @@ func newAuthService(
 func newBudgetService(
 	repo *infrastructure.BudgetRepository,
 	categorySvc *category.Service,
+	reportRepo *infrastructure.ReportRepository,
 	userChecker *shared.UserCheckerService,
 ) *budget.Service {
-	return budget.NewService(repo, categorySvc, userChecker)
+	return budget.NewService(repo, categorySvc, reportRepo, userChecker)
 }
 
 func newInvestmentService(
 	repo *infrastructure.InvestmentRepository,
 	transactionRepo *infrastructure.TransactionRepository,
 	accountSvc *account.Service,
+	categorySvc *category.Service,
 	userChecker *shared.UserCheckerService,
+	transactor shared.Transactor,
 ) *investment.Service {
-	return investment.NewService(repo, transactionRepo, accountSvc, userChecker)
+	svc := investment.NewService(repo, transactionRepo, accountSvc, categorySvc, userChecker)
+	svc.Transactor = transactor
+	return svc
 }
 
 func newGoalService(
@@ -165,7 +348,6 @@ func newTransactionService(
 	repo *infrastructure.TransactionRepository,
 	categorySvc *category.Service,
 	accountSvc *account.Service,
-	budgetSvc *budget.Service,
 	goalSvc *goal.Service,
 	investmentSvc *investment.Service,
 	userChecker *shared.UserCheckerService,
@@ -174,7 +356,6 @@ func newTransactionService(
 		repo,
 		categorySvc,
 		accountSvc,
-		budgetSvc,
 		goalSvc,
 		investmentSvc,
 		userChecker,
@@ -200,10 +381,12 @@ func newDashboardService(repo *infrastructure.DashboardRepository) dashboard.Ser
 func newReportService(
 	repo *infrastructure.ReportRepository,
 	userSvc *user.Service,
+	investmentSvc *investment.Service,
 ) report.Service {
 	return report.Service{
 		Repository:  repo,
 		UserService: userSvc,
+		Investments: investmentSvc,
 	}
 }
 
@@ -211,10 +394,116 @@ func newCreditCardService(
 	repo *infrastructure.CreditCardRepository,
 	accountSvc *account.Service,
 	userSvc *user.Service,
+	bus *event.Bus,
+	transactor shared.Transactor,
 ) creditcard.Service {
 	return creditcard.Service{
 		Repository:     repo,
 		AccountService: accountSvc,
 		UserService:    userSvc,
+		Events:         bus,
+		Transactor:     transactor,
+	}
+}
+
+func newAchievementService(
+	repo *infrastructure.AchievementRepository,
+	userChecker *shared.UserCheckerService,
+) *achievement.Service {
+	return achievement.NewService(repo, userChecker)
+}
+
+func newRoundUpService(
+	repo *infrastructure.RoundUpRepository,
+	accountSvc *account.Service,
+	goalSvc *goal.Service,
+	userChecker *shared.UserCheckerService,
+) *roundup.Service {
+	return roundup.NewService(repo, accountSvc, goalSvc, userChecker)
+}
+
+func newMarketService(
+	repo *infrastructure.MarketRepository,
+	provider market.PriceProvider,
+) *market.Service {
+	return market.NewService(repo, provider)
+}
+
+func newLoanService(
+	repo *infrastructure.LoanRepository,
+	accountSvc *account.Service,
+	categorySvc *category.Service,
+	transactionSvc *transaction.Service,
+	userChecker *shared.UserCheckerService,
+) *loan.Service {
+	return loan.NewService(repo, accountSvc, categorySvc, transactionSvc, userChecker)
+}
+
+func newForecastService(
+	accountSvc *account.Service,
+	recurringSvc *recurring.Service,
+	creditCardSvc creditcard.Service,
+	goalSvc *goal.Service,
+	loanSvc *loan.Service,
+	userChecker *shared.UserCheckerService,
+) *forecast.Service {
+	return forecast.NewService(accountSvc, recurringSvc, &creditCardSvc, goalSvc, loanSvc, userChecker)
+}
+
+func newCalendarService(
+	repo *infrastructure.CalendarRepository,
+	recurringSvc *recurring.Service,
+	creditCardSvc creditcard.Service,
+	loanSvc *loan.Service,
+	goalSvc *goal.Service,
+	userSvc *user.Service,
+	userChecker *shared.UserCheckerService,
+) *calendar.Service {
+	return calendar.NewService(repo, recurringSvc, &creditCardSvc, loanSvc, goalSvc, userSvc, userChecker)
+}
+
+// waitNotificationDeliveries registra a espera dos envios de notificações no desligamento. Registrado
+// antes do servidor e do despacho de eventos, roda depois que eles param e não geram novos envios.
+func waitNotificationDeliveries(lc fx.Lifecycle, notificationSvc *notification.Service) {
+	lc.Append(fx.Hook{
+		OnStop: notificationSvc.Wait,
+	})
+}
+
+func newNotificationService(
+	repo *infrastructure.NotificationRepository,
+	userSvc *user.Service,
+	emailSender notification.EmailSender,
+	pushSender notification.PushSender,
+	userChecker *shared.UserCheckerService,
+) *notification.Service {
+	return notification.NewService(repo, userSvc, emailSender, pushSender, userChecker)
+}
+
+func newWebhookService(
+	cfg *config.Config,
+	repo *infrastructure.WebhookRepository,
+	sender webhook.Sender,
+	userChecker *shared.UserCheckerService,
+) *webhook.Service {
+	svc := webhook.NewService(repo, sender, userChecker)
+	svc.Retry = webhook.RetryPolicy{
+		MaxAttempts: cfg.Webhook.MaxAttempts,
+		BaseDelay:   cfg.Webhook.RetryBaseDelay,
+		MaxDelay:    cfg.Webhook.RetryMaxDelay,
+	}
+	svc.AllowInsecureURLs = cfg.Webhook.AllowInsecure
+	return svc
+}
+
+func newEventBus(cfg *config.Config, repo *infrastructure.EventRepository, transactor shared.Transactor) *event.Bus {
+	bus := event.NewBus(repo)
+	bus.Transactor = transactor
+	bus.PollInterval = cfg.Events.PollInterval
+	bus.Retry = event.RetryPolicy{
+		MaxAttempts: cfg.Events.MaxAttempts,
+		BaseDelay:   cfg.Events.RetryBaseDelay,
+		MaxDelay:    cfg.Events.RetryMaxDelay,
 	}
+	return bus
 }
@@ -2,7 +2,12 @@ package fx
 
 import (
 	"Fynance/config"
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/notification"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/webhook"
 	"Fynance/internal/infrastructure"
+	"Fynance/internal/logger"
 
 	"go.uber.org/fx"
 	"gorm.io/gorm"
@@ -23,6 +28,20 @@ var InfrastructureModule = fx.Module("infrastructure",
 		newReportRepository,
 		newCreditCardRepository,
 		newResourceCounter,
+		newAchievementRepository,
+		newRoundUpRepository,
+		newLoanRepository,
+		newMarketRepository,
+		newCalendarRepository,
+		newNotificationRepository,
+		newWebhookRepository,
+		newEventRepository,
+		newQueueRepository,
+		newTransactor,
+		newPriceProvider,
+		newEmailSender,
+		newPushSender,
+		newWebhookSender,
 	),
 )
 
@@ -77,3 +96,117 @@ func newCreditCardRepository(db *gorm.DB) *infrastructure.CreditCardRepository {
 func newResourceCounter(db *gorm.DB) *infrastructure.ResourceCounter {
 	return &infrastructure.ResourceCounter{DB: db}
 }
+
+func newAchievementRepository(db *gorm.DB) *infrastructure.AchievementRepository {
+	return &infrastructure.AchievementRepository{DB: db}
+}
+
+func newRoundUpRepository(db *gorm.DB) *infrastructure.RoundUpRepository {
+	return &infrastructure.RoundUpRepository{DB: db}
+}
+
+func newLoanRepository(db *gorm.DB) *infrastructure.LoanRepository {
+	return &infrastructure.LoanRepository{DB: db}
+}
+
+func newCalendarRepository(db *gorm.DB) *infrastructure.CalendarRepository {
+	return &infrastructure.CalendarRepository{DB: db}
+}
+
+func newNotificationRepository(db *gorm.DB) *infrastructure.NotificationRepository {
+	return &infrastructure.NotificationRepository{DB: db}
+}
+
+func newWebhookRepository(db *gorm.DB) *infrastructure.WebhookRepository {
+	return &infrastructure.WebhookRepository{DB: db}
+}
+
+func newEventRepository(db *gorm.DB) *infrastructure.EventRepository {
+	return &infrastructure.EventRepository{DB: db}
+}
+
+func newQueueRepository(db *gorm.DB) *infrastructure.QueueRepository {
+	return &infrastructure.QueueRepository{DB: db}
+}
+
+func newTransactor(db *gorm.DB) shared.Transactor {
+	return &infrastructure.GormTransactor{DB: db}
+}
+
+func newMarketRepository(db *gorm.DB) *infrastructure.MarketRepository {
+	return &infrastructure.MarketRepository{DB: db}
+}
+
+// newPriceProvider escolhe a fonte de cotações pelo MARKET_PROVIDER.
+// Retorna nil quando desativado; o serviço passa a usar apenas os preços armazenados.
+func newPriceProvider(cfg *config.Config) market.PriceProvider {
+	switch cfg.Market.Provider {
+	case "file":
+		return infrastructure.NewFilePriceProvider(cfg.Market.DataDir)
+	case "http":
+		if cfg.Market.HTTPURL == "" {
+			logger.Warn().Msg("MARKET_HTTP_URL nao definido, provedor de cotacoes desativado")
+			return nil
+		}
+		return infrastructure.NewHTTPPriceProvider(cfg.Market.HTTPURL, cfg.Market.HTTPToken, cfg.Market.HTTPTimeout)
+	case "":
+		return nil
+	default:
+		logger.Warn().Str("provider", cfg.Market.Provider).Msg("MARKET_PROVIDER desconhecido, provedor de cotacoes desativado")
+		return nil
+	}
+}
+
+// newEmailSender habilita o envio de e-mails quando SMTP_HOST está definido.
+// Retorna nil quando desativado; as notificações seguem para a caixa de entrada e o push.
+func newEmailSender(cfg *config.Config) notification.EmailSender {
+	notify := cfg.Notification
+	if notify.SMTPHost == "" {
+		return nil
+	}
+	switch notify.SMTPTLSMode {
+	case infrastructure.SMTPTLSModeStartTLS, infrastructure.SMTPTLSModeTLS, infrastructure.SMTPTLSModeNone:
+	default:
+		logger.Warn().Str("mode", notify.SMTPTLSMode).Msg("SMTP_TLS_MODE desconhecido, envio de e-mails desativado")
+		return nil
+	}
+	return infrastructure.NewSMTPEmailSender(
+		notify.SMTPHost,
+		notify.SMTPPort,
+		notify.SMTPUsername,
+		notify.SMTPPassword,
+		notify.SMTPFrom,
+		notify.SMTPTLSMode,
+		notify.SMTPTimeout,
+	)
+}
+
+// newPushSender habilita o Web Push quando VAPID_PRIVATE_KEY está definida.
+// Retorna nil quando desativado ou com chaves inválidas.
+func newPushSender(cfg *config.Config) notification.PushSender {
+	notify := cfg.Notification
+	if notify.VAPIDPrivateKey == "" {
+		return nil
+	}
+	sender, err := infrastructure.NewWebPushSender(
+		notify.VAPIDPublicKey,
+		notify.VAPIDPrivateKey,
+		notify.VAPIDSubject,
+		notify.PushTTL,
+		notify.PushTimeout,
+	)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Chaves VAPID invalidas, notificacoes push desativadas")
+		return nil
+	}
+	return sender
+}
+
+// newWebhookSender cria o cliente HTTP das entregas de webhooks. Com WEBHOOK_ALLOW_INSECURE os destinos
+// em rede privada são aceitos, o que permite testar com receptores locais.
+func newWebhookSender(cfg *config.Config) webhook.Sender {
+	if cfg.Webhook.AllowInsecure {
+		logger.Warn().Msg("WEBHOOK_ALLOW_INSECURE ativo: webhooks aceitam http e destinos em rede privada")
+	}
+	return infrastructure.NewHTTPWebhookSender(cfg.Webhook.Timeout, cfg.Webhook.AllowInsecure)
+}
@@ -0,0 +1,208 @@
+package fx
+
+import (
+	"context"
+	"time"
+
+	"Fynance/config"
+	"Fynance/internal/domain/calendar"
+	"Fynance/internal/domain/creditcard"
+	"Fynance/internal/domain/event"
+	"Fynance/internal/domain/goal"
+	"Fynance/internal/domain/investment"
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/queue"
+	"Fynance/internal/domain/recurring"
+	"Fynance/internal/domain/roundup"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/webhook"
+	"Fynance/internal/infrastructure"
+	"Fynance/internal/logger"
+
+	"go.uber.org/fx"
+)
+
+// JobsModule fornece a fila de jobs em segundo plano e registra os jobs do domínio. Cada construtor
+// anotado com asJob entra no grupo "jobs" e é registrado na fila antes do início dos workers.
+var JobsModule = fx.Module("jobs",
+	fx.Provide(
+		newQueue,
+		asJob(newRecurringJob),
+		asJob(newGoalJob),
+		asJob(newRoundUpJob),
+		asJob(newMarketJob),
+		asJob(newInvestmentIncomeJob),
+		asJob(newReminderJob),
+		asJob(newInvoiceJob),
+		asJob(newWebhookJob),
+		asJob(newEventCleanupJob),
+		asJob(newQueueCleanupJob),
+	),
+	fx.Invoke(
+		registerJobs,
+		startQueue,
+		startEventBus,
+	),
+)
+
+// asJob anota o construtor de uma queue.Definition para registrá-la na fila
+func asJob(constructor any) any {
+	return fx.Annotate(constructor, fx.ResultTags(`group:"jobs"`))
+}
+
+func newQueue(cfg *config.Config, repo *infrastructure.QueueRepository, transactor shared.Transactor) *queue.Queue {
+	q := queue.NewQueue(repo, transactor)
+	q.PollInterval = cfg.Queue.PollInterval
+	q.Concurrency = cfg.Queue.Concurrency
+	q.Retry = queue.RetryPolicy{
+		MaxAttempts: cfg.Queue.MaxAttempts,
+		BaseDelay:   cfg.Queue.RetryBaseDelay,
+		MaxDelay:    cfg.Queue.RetryMaxDelay,
+	}
+	return q
+}
+
+type jobDefinitions struct {
+	fx.In
+
+	Jobs []queue.Definition `group:"jobs"`
+}
+
+func registerJobs(q *queue.Queue, params jobDefinitions) error {
+	return q.Register(params.Jobs...)
+}
+
+// every agenda o job a cada intervalo; intervalo não positivo deixa o job sem agendamento
+func every(job queue.Definition, interval time.Duration) queue.Definition {
+	if interval > 0 {
+		job.Schedule = queue.Every(interval)
+	}
+	return job
+}
+
+func newRecurringJob(cfg *config.Config, recurringSvc *recurring.Service) queue.Definition {
+	return every(queue.Handle("recurring_transactions", func(ctx context.Context, _ struct{}) error {
+		return recurringSvc.ProcessDueTransactions(ctx)
+	}), cfg.Jobs.Interval)
+}
+
+func newGoalJob(cfg *config.Config, goalSvc *goal.Service) queue.Definition {
+	return every(queue.Handle("goal_contribution_schedules", func(ctx context.Context, _ struct{}) error {
+		return goalSvc.ProcessDueSchedules(ctx, time.Now())
+	}), cfg.Jobs.Interval)
+}
+
+func newRoundUpJob(cfg *config.Config, roundUpSvc *roundup.Service) queue.Definition {
+	return every(queue.Handle("roundup_sweeps", func(ctx context.Context, _ struct{}) error {
+		return roundUpSvc.ProcessDueSweeps(ctx, time.Now())
+	}), cfg.Jobs.Interval)
+}
+
+func newMarketJob(cfg *config.Config, marketSvc *market.Service, investmentSvc *investment.Service) queue.Definition {
+	return every(queue.Handle("market_revaluation", func(ctx context.Context, _ struct{}) error {
+		if err := marketSvc.SyncIndexes(ctx, time.Now()); err != nil {
+			return err
+		}
+		updated, err := investmentSvc.RevaluePositions(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Info().Int("positions", updated).Msg("Posicoes reavaliadas a mercado")
+
+		accrued, err := investmentSvc.AccrueFixedIncome(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		logger.Info().Int("investments", accrued).Msg("Rendimento de renda fixa atualizado")
+		return nil
+	}), cfg.Market.RevalueInterval)
+}
+
+func newInvestmentIncomeJob(cfg *config.Config, investmentSvc *investment.Service) queue.Definition {
+	return every(queue.Handle("investment_income", func(ctx context.Context, _ struct{}) error {
+		return investmentSvc.ProcessDueIncome(ctx, time.Now())
+	}), cfg.Jobs.Interval)
+}
+
+func newReminderJob(cfg *config.Config, calendarSvc *calendar.Service) queue.Definition {
+	return every(queue.Handle("due_reminders", func(ctx context.Context, _ struct{}) error {
+		return calendarSvc.ProcessReminders(ctx, time.Now())
+	}), cfg.Jobs.Interval)
+}
+
+func newInvoiceJob(cfg *config.Config, creditCardSvc creditcard.Service) queue.Definition {
+	return every(queue.Handle("invoice_closing", func(ctx context.Context, _ struct{}) error {
+		closed, err := creditCardSvc.CloseDueInvoices(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if closed > 0 {
+			logger.Info().Int("invoices", closed).Msg("Faturas fechadas")
+		}
+		return nil
+	}), cfg.Jobs.Interval)
+}
+
+func newWebhookJob(cfg *config.Config, webhookSvc *webhook.Service) queue.Definition {
+	return every(queue.Handle("webhook_deliveries", func(ctx context.Context, _ struct{}) error {
+		return webhookSvc.ProcessDueDeliveries(ctx, time.Now())
+	}), cfg.Webhook.RetryInterval)
+}
+
+func newEventCleanupJob(cfg *config.Config, bus *event.Bus) queue.Definition {
+	return every(queue.Handle("domain_events_cleanup", func(ctx context.Context, _ struct{}) error {
+		deleted, err := bus.Cleanup(ctx, time.Now(), cfg.Events.Retention)
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			logger.Info().Int64("events", deleted).Msg("Eventos processados removidos da outbox")
+		}
+		return nil
+	}), cfg.Jobs.Interval)
+}
+
+func newQueueCleanupJob(cfg *config.Config, q *queue.Queue) queue.Definition {
+	return every(queue.Handle("jobs_cleanup", func(ctx context.Context, _ struct{}) error {
+		deleted, err := q.Cleanup(ctx, time.Now(), cfg.Queue.Retention)
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			logger.Info().Int64("jobs", deleted).Msg("Jobs concluidos removidos da fila")
+		}
+		return nil
+	}), cfg.Jobs.Interval)
+}
+
+// startQueue inicia os workers da fila. Com JOBS_ENABLED=false a instância continua enfileirando jobs,
+// mas só as instâncias com workers os executam.
+func startQueue(lc fx.Lifecycle, cfg *config.Config, q *queue.Queue) {
+	if !cfg.Jobs.Enabled {
+		logger.Info().Msg("Workers da fila de jobs desabilitados (JOBS_ENABLED=false)")
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return q.Start(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			return q.Stop(ctx)
+		},
+	})
+}
+
+// startEventBus despacha os eventos do domínio mesmo com JOBS_ENABLED=false, porque orçamentos,
+// conquistas e webhooks dependem deles para refletir as operações dos usuários
+func startEventBus(lc fx.Lifecycle, bus *event.Bus) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			bus.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return bus.Stop(ctx)
+		},
+	})
+}
@@ -10,4 +10,5 @@ var AppModule = fx.Options(
 	MiddlewareModule,
 	RoutesModule,
 	ServerModule,
+	JobsModule,
 )
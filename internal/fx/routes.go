@@ -4,17 +4,26 @@ import (
 	"time"
 
 	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/achievement"
 	"Fynance/internal/domain/auth"
 	"Fynance/internal/domain/budget"
+	"Fynance/internal/domain/calendar"
 	"Fynance/internal/domain/creditcard"
 	"Fynance/internal/domain/dashboard"
+	"Fynance/internal/domain/forecast"
 	"Fynance/internal/domain/goal"
 	"Fynance/internal/domain/healthscore"
 	"Fynance/internal/domain/investment"
+	"Fynance/internal/domain/loan"
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/notification"
+	"Fynance/internal/domain/queue"
 	"Fynance/internal/domain/recurring"
 	"Fynance/internal/domain/report"
+	"Fynance/internal/domain/roundup"
 	"Fynance/internal/domain/transaction"
 	"Fynance/internal/domain/user"
+	"Fynance/internal/domain/webhook"
 	"Fynance/internal/infrastructure"
 	"Fynance/internal/middleware"
 	"Fynance/internal/routes"
@@ -45,6 +54,15 @@ func newHandler(
 	recurringSvc *recurring.Service,
 	reportSvc report.Service,
 	creditCardSvc creditcard.Service,
+	achievementSvc *achievement.Service,
+	roundUpSvc *roundup.Service,
+	marketSvc *market.Service,
+	loanSvc *loan.Service,
+	forecastSvc *forecast.Service,
+	calendarSvc *calendar.Service,
+	notificationSvc *notification.Service,
+	webhookSvc *webhook.Service,
+	jobQueue *queue.Queue,
 	accountRepo *infrastructure.AccountRepository,
 	transactionRepo *infrastructure.TransactionRepository,
 	goalRepo *infrastructure.GoalRepository,
@@ -55,18 +73,27 @@ func newHandler(
 	categoryRepo *infrastructure.TransactionCategoryRepository,
 ) *routes.Handler {
 	return &routes.Handler{
-		UserService:        *userSvc,
-		JwtService:         jwtSvc,
-		AuthService:        *authSvc,
-		GoalService:        *goalSvc,
-		TransactionService: *transactionSvc,
-		InvestmentService:  *investmentSvc,
-		AccountService:     *accountSvc,
-		BudgetService:      *budgetSvc,
-		DashboardService:   dashboardSvc,
-		RecurringService:   *recurringSvc,
-		ReportService:      reportSvc,
-		CreditCardService:  creditCardSvc,
+		UserService:         *userSvc,
+		JwtService:          jwtSvc,
+		AuthService:         *authSvc,
+		GoalService:         *goalSvc,
+		TransactionService:  *transactionSvc,
+		InvestmentService:   *investmentSvc,
+		AccountService:      *accountSvc,
+		BudgetService:       *budgetSvc,
+		DashboardService:    dashboardSvc,
+		RecurringService:    *recurringSvc,
+		ReportService:       reportSvc,
+		CreditCardService:   creditCardSvc,
+		AchievementService:  *achievementSvc,
+		RoundUpService:      *roundUpSvc,
+		MarketService:       *marketSvc,
+		LoanService:         *loanSvc,
+		ForecastService:     *forecastSvc,
+		CalendarService:     *calendarSvc,
+		NotificationService: *notificationSvc,
+		WebhookService:      *webhookSvc,
+		Queue:               jobQueue,
 
 		AccountRepository:     accountRepo,
 		TransactionRepository: transactionRepo,
@@ -83,8 +110,10 @@ func newAuthRateLimiter() *middleware.RateLimiter {
 	return middleware.NewRateLimiter(100, time.Minute)
 }
 
-func newHealthScoreService() *healthscore.Service {
-	return healthscore.NewService()
+func newHealthScoreService(loanSvc *loan.Service) *healthscore.Service {
+	svc := healthscore.NewService()
+	svc.Debts = loanSvc
+	return svc
 }
 
 func newHealthScoreHandler(svc *healthscore.Service) *routes.HealthScoreHandler {
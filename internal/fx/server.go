@@ -55,6 +55,7 @@ func setupRoutes(
 		public.POST("/auth/login", handler.Authenticate)
 		public.POST("/auth/register", handler.Registration)
 		public.POST("/auth/google", handler.GoogleAuth)
+		public.GET("/calendar/:token", handler.GetCalendarFeed)
 	}
 
 	private := router.Group("/api")
@@ -83,6 +84,12 @@ func setupRoutes(
 			goals.POST("/:id/withdraw", handler.WithdrawFromGoal)
 			goals.GET("/:id/contributions", handler.GetGoalContributions)
 			goals.GET("/:id/progress", handler.GetGoalProgress)
+			goals.GET("/:id/schedule", handler.GetGoalSchedule)
+			goals.PUT("/:id/schedule", handler.SetGoalSchedule)
+			goals.DELETE("/:id/schedule", handler.DeleteGoalSchedule)
+			goals.GET("/:id/investments", handler.ListGoalInvestments)
+			goals.POST("/:id/investments", handler.LinkGoalInvestment)
+			goals.DELETE("/:id/investments/:investment_id", handler.UnlinkGoalInvestment)
 			goals.DELETE("/contributions/:contribution_id", handler.DeleteContribution)
 		}
 
@@ -111,10 +118,44 @@ func setupRoutes(
 			investments.POST("/:id/contribution", handler.MakeContribution)
 			investments.POST("/:id/withdraw", handler.MakeWithdraw)
 			investments.GET("/:id/return", handler.GetInvestmentReturn)
+			investments.GET("/:id/position", handler.GetInvestmentPosition)
+			investments.GET("/:id/yield", handler.GetInvestmentYield)
+			investments.GET("/:id/performance", handler.GetInvestmentPerformance)
+			investments.POST("/:id/buy", handler.BuyInvestmentLot)
+			investments.POST("/:id/sell", handler.SellInvestmentLot)
+			investments.POST("/:id/income", handler.RecordInvestmentIncome)
+			investments.GET("/:id/income", handler.GetInvestmentIncome)
 			investments.DELETE("/:id", handler.DeleteInvestment)
 			investments.PATCH("/:id", handler.UpdateInvestment)
 		}
 
+		portfolio := private.Group("/portfolio")
+		{
+			portfolio.GET("/performance", handler.GetPortfolioPerformance)
+		}
+
+		allocation := private.Group("/allocation")
+		{
+			allocation.GET("", handler.GetAllocation)
+			allocation.GET("/targets", handler.GetAllocationTargets)
+			allocation.PUT("/targets", handler.SetAllocationTargets)
+		}
+
+		brokerageNotes := private.Group("/brokerage-notes")
+		{
+			brokerageNotes.GET("", handler.ListBrokerageNotes)
+			brokerageNotes.POST("/preview", handler.PreviewBrokerageNotes)
+			brokerageNotes.POST("/import", handler.ImportBrokerageNotes)
+		}
+
+		income := private.Group("/income")
+		{
+			income.GET("/monthly", handler.GetMonthlyIncome)
+			income.GET("/calendar", handler.GetIncomeCalendar)
+			income.POST("/:id/receive", handler.ReceiveInvestmentIncome)
+			income.DELETE("/:id", handler.DeleteInvestmentIncome)
+		}
+
 		accounts := private.Group("/accounts")
 		{
 			accounts.POST("", middleware.CheckResourceLimit("accounts", resourceCounter, userSvc), handler.CreateAccount)
@@ -131,6 +172,9 @@ func setupRoutes(
 			budgets.POST("", middleware.CheckResourceLimit("budgets", resourceCounter, userSvc), handler.CreateBudget)
 			budgets.GET("", handler.ListBudgets)
 			budgets.GET("/summary", handler.GetBudgetSummary)
+			budgets.GET("/templates", handler.ListBudgetTemplates)
+			budgets.POST("/templates/preview", handler.PreviewBudgetTemplate)
+			budgets.POST("/templates/apply", handler.ApplyBudgetTemplate)
 			budgets.GET("/:id", handler.GetBudget)
 			budgets.GET("/:id/status", handler.GetBudgetStatus)
 			budgets.PATCH("/:id", handler.UpdateBudget)
@@ -141,6 +185,11 @@ func setupRoutes(
 		{
 			recurring.POST("", middleware.CheckResourceLimit("recurring", resourceCounter, userSvc), handler.CreateRecurring)
 			recurring.GET("", handler.ListRecurrings)
+			recurring.GET("/detected", handler.ListDetectedSubscriptions)
+			recurring.POST("/detected/:key/convert", middleware.CheckResourceLimit("recurring", resourceCounter, userSvc), handler.ConvertDetectedSubscription)
+			recurring.GET("/occurrences", handler.ListRecurringOccurrences)
+			recurring.POST("/occurrences/:occurrenceId/confirm", handler.ConfirmRecurringOccurrence)
+			recurring.POST("/occurrences/:occurrenceId/skip", handler.SkipRecurringOccurrence)
 			recurring.GET("/:id", handler.GetRecurring)
 			recurring.PATCH("/:id", handler.UpdateRecurring)
 			recurring.DELETE("/:id", handler.DeleteRecurring)
@@ -156,6 +205,8 @@ func setupRoutes(
 			reports.GET("/period", handler.GetPeriodReport)
 			reports.GET("/yearly", handler.GetYearlyReport)
 			reports.GET("/category/:category_id", handler.GetCategoryReport)
+			reports.GET("/tax", handler.GetTaxReport)
+			reports.GET("/tax/export", middleware.RequireFeature("export"), handler.ExportTaxReport)
 		}
 
 		creditCards := private.Group("/credit-cards")
@@ -174,6 +225,89 @@ func setupRoutes(
 		}
 
 		private.GET("/health-score", healthScoreHandler.GetHealthScore)
+		private.GET("/achievements", handler.GetAchievements)
+
+		roundups := private.Group("/roundups")
+		{
+			roundups.GET("", handler.ListRoundUpRules)
+			roundups.POST("", handler.CreateRoundUpRule)
+			roundups.GET("/summary", handler.GetRoundUpSummary)
+			roundups.PATCH("/:id", handler.UpdateRoundUpRule)
+			roundups.DELETE("/:id", handler.DeleteRoundUpRule)
+			roundups.POST("/:id/sweep", handler.SweepRoundUpRule)
+		}
+
+		loans := private.Group("/loans")
+		{
+			loans.GET("", handler.ListLoans)
+			loans.POST("", handler.CreateLoan)
+			loans.GET("/:id", handler.GetLoan)
+			loans.PATCH("/:id", handler.UpdateLoan)
+			loans.DELETE("/:id", handler.DeleteLoan)
+			loans.GET("/:id/schedule", handler.GetLoanSchedule)
+			loans.POST("/:id/pay", handler.PayLoanInstallment)
+			loans.POST("/:id/extra-payment", handler.CreateLoanExtraPayment)
+			loans.GET("/:id/extra-payment/simulate", handler.SimulateLoanExtraPayment)
+		}
+
+		private.GET("/forecast", handler.GetForecast)
+
+		upcoming := private.Group("/upcoming")
+		{
+			upcoming.GET("", handler.GetUpcoming)
+			upcoming.GET("/settings", handler.GetReminderSettings)
+			upcoming.PUT("/settings", handler.UpdateReminderSettings)
+			upcoming.POST("/feed-token", handler.RotateCalendarFeedToken)
+			upcoming.DELETE("/feed-token", handler.RevokeCalendarFeedToken)
+		}
+
+		notifications := private.Group("/notifications")
+		{
+			notifications.GET("", handler.ListNotifications)
+			notifications.GET("/unread-count", handler.GetUnreadNotificationCount)
+			notifications.POST("/read-all", handler.MarkAllNotificationsRead)
+			notifications.GET("/preferences", handler.GetNotificationPreferences)
+			notifications.PUT("/preferences", handler.UpdateNotificationPreferences)
+			notifications.GET("/push/public-key", handler.GetPushPublicKey)
+			notifications.POST("/push/subscriptions", handler.SubscribePush)
+			notifications.DELETE("/push/subscriptions", handler.UnsubscribePush)
+			notifications.POST("/:id/read", handler.MarkNotificationRead)
+			notifications.POST("/:id/unread", handler.MarkNotificationUnread)
+			notifications.DELETE("/:id", handler.DeleteNotification)
+		}
+
+		webhooks := private.Group("/webhooks")
+		{
+			webhooks.GET("", handler.ListWebhooks)
+			webhooks.POST("", handler.CreateWebhook)
+			webhooks.GET("/events", handler.ListWebhookEvents)
+			webhooks.GET("/:id", handler.GetWebhook)
+			webhooks.PUT("/:id", handler.UpdateWebhook)
+			webhooks.DELETE("/:id", handler.DeleteWebhook)
+			webhooks.POST("/:id/rotate-secret", handler.RotateWebhookSecret)
+			webhooks.POST("/:id/ping", handler.PingWebhook)
+			webhooks.GET("/:id/deliveries", handler.ListWebhookDeliveries)
+			webhooks.POST("/:id/deliveries/:deliveryId/redeliver", handler.RedeliverWebhook)
+		}
+
+		market := private.Group("/market")
+		{
+			market.GET("/prices/:symbol/history", handler.GetPriceHistory)
+		}
+	}
+
+	admin := router.Group("/api/admin")
+	admin.Use(middleware.RateLimit(authRateLimiter))
+	admin.Use(middleware.RequireAdminToken(cfg.Admin.Token))
+	{
+		jobs := admin.Group("/jobs")
+		{
+			jobs.GET("", handler.ListJobs)
+			jobs.GET("/stats", handler.GetJobStats)
+			jobs.GET("/schedules", handler.ListJobSchedules)
+			jobs.GET("/:id", handler.GetJob)
+			jobs.POST("/:id/retry", handler.RetryJob)
+		}
 	}
 
 	serverAddr := ":" + cfg.Server.Port
@@ -96,21 +96,21 @@ func toDBAccount(a *account.Account) *accountDB {
 
 func (r *AccountRepository) Create(ctx context.Context, a *account.Account) error {
 	adb := toDBAccount(a)
-	return r.DB.WithContext(ctx).Table("accounts").Create(adb).Error
+	return conn(ctx, r.DB).Table("accounts").Create(adb).Error
 }
 
 func (r *AccountRepository) Update(ctx context.Context, a *account.Account) error {
 	adb := toDBAccount(a)
-	return r.DB.WithContext(ctx).Model(&accountDB{}).Where("id = ? AND user_id = ?", adb.Id, adb.UserId).Updates(adb).Error
+	return conn(ctx, r.DB).Model(&accountDB{}).Where("id = ? AND user_id = ?", adb.Id, adb.UserId).Updates(adb).Error
 }
 
 func (r *AccountRepository) Delete(ctx context.Context, accountID, userID ulid.ULID) error {
-	return r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", accountID.String(), userID.String()).Delete(&accountDB{}).Error
+	return conn(ctx, r.DB).Where("id = ? AND user_id = ?", accountID.String(), userID.String()).Delete(&accountDB{}).Error
 }
 
 func (r *AccountRepository) GetByID(ctx context.Context, accountID, userID ulid.ULID) (*account.Account, error) {
 	var adb accountDB
-	err := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", accountID.String(), userID.String()).First(&adb).Error
+	err := conn(ctx, r.DB).Where("id = ? AND user_id = ?", accountID.String(), userID.String()).First(&adb).Error
 	if err != nil {
 		return nil, err
 	}
@@ -118,17 +118,17 @@ func (r *AccountRepository) GetByID(ctx context.Context, accountID, userID ulid.
 }
 
 func (r *AccountRepository) GetByUserID(ctx context.Context, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*account.Account, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("accounts").Where("user_id = ? AND type != ?", userID.String(), string(account.TypeCreditCard))
+	baseQuery := conn(ctx, r.DB).Table("accounts").Where("user_id = ? AND type != ?", userID.String(), string(account.TypeCreditCard))
 	return pkg.Paginate(baseQuery, pagination, "created_at DESC", toDomainAccount)
 }
 
 func (r *AccountRepository) GetActiveByUserID(ctx context.Context, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*account.Account, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("accounts").Where("user_id = ? AND is_active = ? AND type != ?", userID.String(), true, string(account.TypeCreditCard))
+	baseQuery := conn(ctx, r.DB).Table("accounts").Where("user_id = ? AND is_active = ? AND type != ?", userID.String(), true, string(account.TypeCreditCard))
 	return pkg.Paginate(baseQuery, pagination, "created_at DESC", toDomainAccount)
 }
 
 func (r *AccountRepository) GetByUserIDWithFilters(ctx context.Context, userID ulid.ULID, accountType *string, search *string, pagination *pkg.PaginationParams) ([]*account.Account, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("accounts").Where("user_id = ? AND type != ?", userID.String(), string(account.TypeCreditCard))
+	baseQuery := conn(ctx, r.DB).Table("accounts").Where("user_id = ? AND type != ?", userID.String(), string(account.TypeCreditCard))
 
 	if accountType != nil && *accountType != "" && *accountType != "ALL" {
 		baseQuery = baseQuery.Where("type = ?", *accountType)
@@ -143,7 +143,7 @@ func (r *AccountRepository) GetByUserIDWithFilters(ctx context.Context, userID u
 
 func (r *AccountRepository) GetByCreditCardID(ctx context.Context, creditCardID, userID ulid.ULID) (*account.Account, error) {
 	var adb accountDB
-	err := r.DB.WithContext(ctx).Where("credit_card_id = ? AND user_id = ?", creditCardID.String(), userID.String()).First(&adb).Error
+	err := conn(ctx, r.DB).Where("credit_card_id = ? AND user_id = ?", creditCardID.String(), userID.String()).First(&adb).Error
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +151,7 @@ func (r *AccountRepository) GetByCreditCardID(ctx context.Context, creditCardID,
 }
 
 func (r *AccountRepository) UpdateBalance(ctx context.Context, accountID ulid.ULID, amount float64) error {
-	return r.DB.WithContext(ctx).Model(&accountDB{}).Where("id = ?", accountID.String()).
+	return conn(ctx, r.DB).Model(&accountDB{}).Where("id = ?", accountID.String()).
 		UpdateColumn("balance", gorm.Expr("balance + ?", amount)).
 		UpdateColumn("updated_at", time.Now()).Error
 }
@@ -177,7 +177,7 @@ func (r *AccountRepository) RollbackTx(tx interface{}) error {
 
 func (r *AccountRepository) GetTotalBalance(ctx context.Context, userID ulid.ULID) (float64, error) {
 	var total float64
-	err := r.DB.WithContext(ctx).Model(&accountDB{}).
+	err := conn(ctx, r.DB).Model(&accountDB{}).
 		Where("user_id = ? AND is_active = ? AND include_in_total = ?", userID.String(), true, true).
 		Select("COALESCE(SUM(balance), 0)").Scan(&total).Error
 	return total, err
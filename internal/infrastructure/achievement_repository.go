@@ -0,0 +1,166 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/achievement"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AchievementRepository struct {
+	DB *gorm.DB
+}
+
+var _ achievement.AchievementRepository = (*AchievementRepository)(nil)
+
+type userAchievementDB struct {
+	Id          string    `gorm:"type:varchar(26);primaryKey"`
+	UserId      string    `gorm:"type:varchar(26);not null"`
+	Code        string    `gorm:"type:varchar(50);not null"`
+	ReferenceId string    `gorm:"type:varchar(26);not null"`
+	XP          int       `gorm:"column:xp;not null"`
+	UnlockedAt  time.Time `gorm:"not null"`
+}
+
+func (userAchievementDB) TableName() string {
+	return "user_achievements"
+}
+
+func toDomainUserAchievement(adb *userAchievementDB) (*achievement.UserAchievement, error) {
+	id, err := pkg.ParseULID(adb.Id)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := pkg.ParseULID(adb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &achievement.UserAchievement{
+		Id:          id,
+		UserId:      uid,
+		Code:        adb.Code,
+		ReferenceId: adb.ReferenceId,
+		XP:          adb.XP,
+		UnlockedAt:  adb.UnlockedAt,
+	}, nil
+}
+
+func (r *AchievementRepository) Create(ctx context.Context, a *achievement.UserAchievement) (bool, error) {
+	adb := &userAchievementDB{
+		Id:          a.Id.String(),
+		UserId:      a.UserId.String(),
+		Code:        a.Code,
+		ReferenceId: a.ReferenceId,
+		XP:          a.XP,
+		UnlockedAt:  a.UnlockedAt,
+	}
+	result := conn(ctx, r.DB).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(adb)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *AchievementRepository) ListByUser(ctx context.Context, userID ulid.ULID) ([]*achievement.UserAchievement, error) {
+	var rows []userAchievementDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ?", userID.String()).
+		Order("unlocked_at DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]*achievement.UserAchievement, 0, len(rows))
+	for i := range rows {
+		a, err := toDomainUserAchievement(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func (r *AchievementRepository) GetCounters(ctx context.Context, userID ulid.ULID) (map[achievement.Metric]float64, error) {
+	var result struct {
+		Transactions       int64
+		Investments        int64
+		GoalsCompleted     int64
+		InvoicesPaidOnTime int64
+	}
+
+	err := conn(ctx, r.DB).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM transactions WHERE user_id = @user) AS transactions,
+			(SELECT COUNT(*) FROM investments WHERE user_id = @user) AS investments,
+			(SELECT COUNT(*) FROM goals WHERE user_id = @user AND status = 'COMPLETED') AS goals_completed,
+			(SELECT COUNT(*) FROM invoices WHERE user_id = @user AND status = 'PAID'
+				AND paid_at IS NOT NULL AND paid_at::date <= due_date) AS invoices_paid_on_time
+	`, map[string]interface{}{"user": userID.String()}).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return map[achievement.Metric]float64{
+		achievement.MetricTransactions:       float64(result.Transactions),
+		achievement.MetricInvestments:        float64(result.Investments),
+		achievement.MetricGoalsCompleted:     float64(result.GoalsCompleted),
+		achievement.MetricInvoicesPaidOnTime: float64(result.InvoicesPaidOnTime),
+	}, nil
+}
+
+func (r *AchievementRepository) GetGoalProgress(ctx context.Context, userID ulid.ULID) (map[string]float64, error) {
+	var rows []struct {
+		Id       string
+		Progress float64
+	}
+
+	err := conn(ctx, r.DB).Table("goals").
+		Select("id, CASE WHEN target_amount > 0 THEN current_amount / target_amount * 100 ELSE 0 END AS progress").
+		Where("user_id = ? AND status <> ?", userID.String(), "CANCELLED").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		progress[row.Id] = row.Progress
+	}
+	return progress, nil
+}
+
+func (r *AchievementRepository) GetBudgetMonths(ctx context.Context, userID ulid.ULID, before time.Time) ([]achievement.BudgetMonth, error) {
+	var rows []achievement.BudgetMonth
+
+	err := conn(ctx, r.DB).Table("budgets").
+		Select("month, year, COUNT(*) AS budgets, SUM(CASE WHEN spent > amount THEN 1 ELSE 0 END) AS exceeded").
+		Where("user_id = ? AND (year < ? OR (year = ? AND month < ?))", userID.String(), before.Year(), before.Year(), int(before.Month())).
+		Group("year, month").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *AchievementRepository) GetExpenseDays(ctx context.Context, userID ulid.ULID, since time.Time) ([]time.Time, error) {
+	var days []time.Time
+
+	err := conn(ctx, r.DB).Table("transactions").
+		Distinct("date").
+		Where("user_id = ? AND type = ? AND date >= ?", userID.String(), "EXPENSE", since).
+		Order("date DESC").
+		Pluck("date", &days).Error
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
@@ -84,7 +84,7 @@ func toDBBudget(b *budget.Budget) *budgetDB {
 
 func (r *BudgetRepository) Create(ctx context.Context, b *budget.Budget) error {
 	bdb := toDBBudget(b)
-	result := r.DB.WithContext(ctx).Table("budgets").Create(&bdb)
+	result := conn(ctx, r.DB).Table("budgets").Create(&bdb)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -93,11 +93,11 @@ func (r *BudgetRepository) Create(ctx context.Context, b *budget.Budget) error {
 
 func (r *BudgetRepository) Update(ctx context.Context, b *budget.Budget) error {
 	bdb := toDBBudget(b)
-	return r.DB.WithContext(ctx).Model(&budgetDB{}).Where("id = ? AND user_id = ?", bdb.Id, bdb.UserId).Updates(bdb).Error
+	return conn(ctx, r.DB).Model(&budgetDB{}).Where("id = ? AND user_id = ?", bdb.Id, bdb.UserId).Updates(bdb).Error
 }
 
 func (r *BudgetRepository) Delete(ctx context.Context, budgetID, userID ulid.ULID) error {
-	result := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", budgetID.String(), userID.String()).Delete(&budgetDB{})
+	result := conn(ctx, r.DB).Where("id = ? AND user_id = ?", budgetID.String(), userID.String()).Delete(&budgetDB{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -113,7 +113,7 @@ func (r *BudgetRepository) GetByID(ctx context.Context, budgetID, userID ulid.UL
 		CategoryName string `gorm:"->;column:category_name"`
 	}
 	var bdb budgetDBWithCategory
-	query := r.DB.WithContext(ctx).
+	query := conn(ctx, r.DB).
 		Table("budgets b").
 		Select("b.id, b.user_id, b.category_id, b.amount, b.spent, b.month, b.year, b.alert_at, b.is_recurring, b.created_at, b.updated_at, c.name as category_name").
 		Joins("LEFT JOIN categories c ON b.category_id = c.id").
@@ -157,7 +157,7 @@ func (r *BudgetRepository) GetByUserID(ctx context.Context, userID ulid.ULID, mo
 		CategoryName string    `gorm:"column:category_name"`
 	}
 
-	baseQuery := r.DB.WithContext(ctx).
+	baseQuery := conn(ctx, r.DB).
 		Table("budgets b").
 		Select("b.id, b.user_id, b.category_id, b.amount, b.spent, b.month, b.year, b.alert_at, b.is_recurring, b.created_at, b.updated_at, c.name as category_name").
 		Joins("LEFT JOIN categories c ON b.category_id = c.id AND c.user_id = b.user_id").
@@ -168,7 +168,7 @@ func (r *BudgetRepository) GetByUserID(ctx context.Context, userID ulid.ULID, mo
 		baseQuery = baseQuery.Where("c.name ILIKE ?", searchPattern)
 	}
 
-	countQuery := r.DB.WithContext(ctx).
+	countQuery := conn(ctx, r.DB).
 		Table("budgets").
 		Where("user_id = ?", userID.String())
 
@@ -229,7 +229,7 @@ func (r *BudgetRepository) GetByUserID(ctx context.Context, userID ulid.ULID, mo
 
 func (r *BudgetRepository) GetByCategoryID(ctx context.Context, categoryID, userID ulid.ULID, month, year int) (*budget.Budget, error) {
 	var bdb budgetDB
-	err := r.DB.WithContext(ctx).
+	err := conn(ctx, r.DB).
 		Table("budgets").
 		Where("category_id = ? AND user_id = ? AND month = ? AND year = ?", categoryID.String(), userID.String(), month, year).
 		First(&bdb).Error
@@ -243,8 +243,29 @@ func (r *BudgetRepository) GetByCategoryID(ctx context.Context, categoryID, user
 	return b, nil
 }
 
+func (r *BudgetRepository) ListByPeriod(ctx context.Context, userID ulid.ULID, month, year int) ([]*budget.Budget, error) {
+	var rows []budgetDB
+	err := conn(ctx, r.DB).
+		Table("budgets").
+		Where("user_id = ? AND month = ? AND year = ?", userID.String(), month, year).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := make([]*budget.Budget, 0, len(rows))
+	for i := range rows {
+		b, err := toDomainBudget(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, nil
+}
+
 func (r *BudgetRepository) UpdateSpent(ctx context.Context, budgetID ulid.ULID, amount float64) error {
-	return r.DB.WithContext(ctx).Model(&budgetDB{}).Where("id = ?", budgetID.String()).
+	return conn(ctx, r.DB).Model(&budgetDB{}).Where("id = ?", budgetID.String()).
 		UpdateColumn("spent", gorm.Expr("spent + ?", amount)).
 		UpdateColumn("updated_at", time.Now()).Error
 }
@@ -255,8 +276,8 @@ func (r *BudgetRepository) GetRecurring(ctx context.Context, userID ulid.ULID, p
 	}
 	pagination.Normalize()
 
-	countQuery := r.DB.WithContext(ctx).Table("budgets").Where("user_id = ? AND is_recurring = ?", userID.String(), true)
-	dataQuery := r.DB.WithContext(ctx).
+	countQuery := conn(ctx, r.DB).Table("budgets").Where("user_id = ? AND is_recurring = ?", userID.String(), true)
+	dataQuery := conn(ctx, r.DB).
 		Table("budgets b").
 		Select("b.id, b.user_id, b.category_id, b.amount, b.spent, b.month, b.year, b.alert_at, b.is_recurring, b.created_at, b.updated_at, c.name as category_name").
 		Joins("LEFT JOIN categories c ON b.category_id = c.id").
@@ -304,7 +325,7 @@ func (r *BudgetRepository) GetSummary(ctx context.Context, userID ulid.ULID, mon
 		TotalSpent  float64
 	}
 
-	err := r.DB.WithContext(ctx).Model(&budgetDB{}).
+	err := conn(ctx, r.DB).Model(&budgetDB{}).
 		Where("user_id = ? AND month = ? AND year = ?", userID.String(), month, year).
 		Select("COALESCE(SUM(amount), 0) as total_budget, COALESCE(SUM(spent), 0) as total_spent").
 		Scan(&result).Error
@@ -326,3 +347,9 @@ func (r *BudgetRepository) GetSummary(ctx context.Context, userID ulid.ULID, mon
 		Percentage:     percentage,
 	}, nil
 }
+
+func (r *BudgetRepository) CountByUserID(ctx context.Context, userID ulid.ULID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.DB).Model(&budgetDB{}).Where("user_id = ?", userID.String()).Count(&count).Error
+	return count, err
+}
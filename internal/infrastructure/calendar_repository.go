@@ -0,0 +1,124 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/calendar"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepository struct {
+	DB *gorm.DB
+}
+
+var _ calendar.Repository = (*CalendarRepository)(nil)
+
+type reminderSettingsDB struct {
+	UserId    string    `gorm:"type:varchar(26);primaryKey"`
+	Enabled   bool      `gorm:"not null;default:true"`
+	LeadDays  []int     `gorm:"type:varchar(100);serializer:json;not null"`
+	FeedToken *string   `gorm:"type:varchar(64)"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (reminderSettingsDB) TableName() string {
+	return "reminder_settings"
+}
+
+type reminderLogDB struct {
+	Id       string    `gorm:"type:varchar(26);primaryKey"`
+	UserId   string    `gorm:"type:varchar(26);not null"`
+	ItemKey  string    `gorm:"type:varchar(120);not null"`
+	LeadDays int       `gorm:"not null"`
+	DueDate  time.Time `gorm:"type:date;not null"`
+	SentAt   time.Time `gorm:"not null"`
+}
+
+func (reminderLogDB) TableName() string {
+	return "reminder_logs"
+}
+
+func toDomainReminderSettings(sdb *reminderSettingsDB) (*calendar.ReminderSettings, error) {
+	userID, err := pkg.ParseULID(sdb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	leadDays := sdb.LeadDays
+	if leadDays == nil {
+		leadDays = []int{}
+	}
+	return &calendar.ReminderSettings{
+		UserId:      userID,
+		Enabled:     sdb.Enabled,
+		LeadDays:    leadDays,
+		FeedToken:   sdb.FeedToken,
+		FeedEnabled: sdb.FeedToken != nil,
+		CreatedAt:   sdb.CreatedAt,
+		UpdatedAt:   sdb.UpdatedAt,
+	}, nil
+}
+
+func (r *CalendarRepository) GetSettings(ctx context.Context, userID ulid.ULID) (*calendar.ReminderSettings, error) {
+	var row reminderSettingsDB
+	if err := conn(ctx, r.DB).Where("user_id = ?", userID.String()).First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainReminderSettings(&row)
+}
+
+func (r *CalendarRepository) GetSettingsByFeedToken(ctx context.Context, token string) (*calendar.ReminderSettings, error) {
+	var row reminderSettingsDB
+	if err := conn(ctx, r.DB).Where("feed_token = ?", token).First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainReminderSettings(&row)
+}
+
+// SaveSettings cria ou substitui a configuração de lembretes do usuário
+func (r *CalendarRepository) SaveSettings(ctx context.Context, settings *calendar.ReminderSettings) error {
+	leadDays := settings.LeadDays
+	if leadDays == nil {
+		leadDays = []int{}
+	}
+	row := &reminderSettingsDB{
+		UserId:    settings.UserId.String(),
+		Enabled:   settings.Enabled,
+		LeadDays:  leadDays,
+		FeedToken: settings.FeedToken,
+		CreatedAt: settings.CreatedAt,
+		UpdatedAt: settings.UpdatedAt,
+	}
+	return conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "lead_days", "feed_token", "updated_at"}),
+	}).Create(row).Error
+}
+
+func (r *CalendarRepository) CreateReminderLog(ctx context.Context, log *calendar.ReminderLog) (bool, error) {
+	row := &reminderLogDB{
+		Id:       log.Id.String(),
+		UserId:   log.UserId.String(),
+		ItemKey:  log.ItemKey,
+		LeadDays: log.LeadDays,
+		DueDate:  log.DueDate,
+		SentAt:   log.SentAt,
+	}
+	result := conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "item_key"}, {Name: "lead_days"}},
+		DoNothing: true,
+	}).Create(row)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *CalendarRepository) DeleteReminderLog(ctx context.Context, logID ulid.ULID) error {
+	return conn(ctx, r.DB).Where("id = ?", logID.String()).Delete(&reminderLogDB{}).Error
+}
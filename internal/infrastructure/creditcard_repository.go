@@ -244,21 +244,21 @@ func toDBCreditCardTransaction(t *creditcard.CreditCardTransaction) *creditCardT
 
 func (r *CreditCardRepository) CreateCreditCard(ctx context.Context, card *creditcard.CreditCard) error {
 	ccdb := toDBCreditCard(card)
-	return r.DB.WithContext(ctx).Table("credit_cards").Create(ccdb).Error
+	return conn(ctx, r.DB).Table("credit_cards").Create(ccdb).Error
 }
 
 func (r *CreditCardRepository) UpdateCreditCard(ctx context.Context, card *creditcard.CreditCard) error {
 	ccdb := toDBCreditCard(card)
-	return r.DB.WithContext(ctx).Model(&creditCardDB{}).Where("id = ? AND user_id = ?", ccdb.Id, ccdb.UserId).Updates(ccdb).Error
+	return conn(ctx, r.DB).Model(&creditCardDB{}).Where("id = ? AND user_id = ?", ccdb.Id, ccdb.UserId).Updates(ccdb).Error
 }
 
 func (r *CreditCardRepository) DeleteCreditCard(ctx context.Context, cardID, userID ulid.ULID) error {
-	return r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", cardID.String(), userID.String()).Delete(&creditCardDB{}).Error
+	return conn(ctx, r.DB).Where("id = ? AND user_id = ?", cardID.String(), userID.String()).Delete(&creditCardDB{}).Error
 }
 
 func (r *CreditCardRepository) GetCreditCardById(ctx context.Context, cardID, userID ulid.ULID) (*creditcard.CreditCard, error) {
 	var ccdb creditCardDB
-	err := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", cardID.String(), userID.String()).First(&ccdb).Error
+	err := conn(ctx, r.DB).Where("id = ? AND user_id = ?", cardID.String(), userID.String()).First(&ccdb).Error
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +271,7 @@ func (r *CreditCardRepository) GetCreditCardsByUserId(ctx context.Context, userI
 	}
 	pagination.Normalize()
 
-	baseQuery := r.DB.WithContext(ctx).Table("credit_cards").Where("user_id = ?", userID.String())
+	baseQuery := conn(ctx, r.DB).Table("credit_cards").Where("user_id = ?", userID.String())
 
 	var total int64
 	if err := baseQuery.Count(&total).Error; err != nil {
@@ -300,7 +300,7 @@ func (r *CreditCardRepository) GetCreditCardsByUserId(ctx context.Context, userI
 
 func (r *CreditCardRepository) GetCreditCardByAccountId(ctx context.Context, accountID, userID ulid.ULID) (*creditcard.CreditCard, error) {
 	var ccdb creditCardDB
-	err := r.DB.WithContext(ctx).Where("account_id = ? AND user_id = ?", accountID.String(), userID.String()).First(&ccdb).Error
+	err := conn(ctx, r.DB).Where("account_id = ? AND user_id = ?", accountID.String(), userID.String()).First(&ccdb).Error
 	if err != nil {
 		return nil, err
 	}
@@ -308,24 +308,24 @@ func (r *CreditCardRepository) GetCreditCardByAccountId(ctx context.Context, acc
 }
 
 func (r *CreditCardRepository) UpdateAvailableLimit(ctx context.Context, cardID ulid.ULID, amount float64) error {
-	return r.DB.WithContext(ctx).Model(&creditCardDB{}).
+	return conn(ctx, r.DB).Model(&creditCardDB{}).
 		Where("id = ?", cardID.String()).
 		Update("available_limit", gorm.Expr("available_limit + ?", amount)).Error
 }
 
 func (r *CreditCardRepository) CreateInvoice(ctx context.Context, invoice *creditcard.Invoice) error {
 	idb := toDBInvoice(invoice)
-	return r.DB.WithContext(ctx).Table("invoices").Create(idb).Error
+	return conn(ctx, r.DB).Table("invoices").Create(idb).Error
 }
 
 func (r *CreditCardRepository) UpdateInvoice(ctx context.Context, invoice *creditcard.Invoice) error {
 	idb := toDBInvoice(invoice)
-	return r.DB.WithContext(ctx).Model(&invoiceDB{}).Where("id = ? AND user_id = ?", idb.Id, idb.UserId).Updates(idb).Error
+	return conn(ctx, r.DB).Model(&invoiceDB{}).Where("id = ? AND user_id = ?", idb.Id, idb.UserId).Updates(idb).Error
 }
 
 func (r *CreditCardRepository) GetInvoiceById(ctx context.Context, invoiceID, userID ulid.ULID) (*creditcard.Invoice, error) {
 	var idb invoiceDB
-	err := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", invoiceID.String(), userID.String()).First(&idb).Error
+	err := conn(ctx, r.DB).Where("id = ? AND user_id = ?", invoiceID.String(), userID.String()).First(&idb).Error
 	if err != nil {
 		return nil, err
 	}
@@ -338,7 +338,7 @@ func (r *CreditCardRepository) GetInvoicesByCreditCardId(ctx context.Context, ca
 	}
 	pagination.Normalize()
 
-	baseQuery := r.DB.WithContext(ctx).Table("invoices").Where("credit_card_id = ? AND user_id = ?", cardID.String(), userID.String())
+	baseQuery := conn(ctx, r.DB).Table("invoices").Where("credit_card_id = ? AND user_id = ?", cardID.String(), userID.String())
 
 	var total int64
 	if err := baseQuery.Count(&total).Error; err != nil {
@@ -371,7 +371,7 @@ func (r *CreditCardRepository) GetCurrentInvoice(ctx context.Context, cardID, us
 	currentYear := now.Year()
 
 	var idb invoiceDB
-	err := r.DB.WithContext(ctx).
+	err := conn(ctx, r.DB).
 		Where("credit_card_id = ? AND user_id = ? AND reference_month = ? AND reference_year = ? AND status = ?",
 			cardID.String(), userID.String(), currentMonth, currentYear, string(creditcard.InvoiceOpen)).
 		First(&idb).Error
@@ -386,7 +386,7 @@ func (r *CreditCardRepository) GetCurrentInvoice(ctx context.Context, cardID, us
 
 func (r *CreditCardRepository) GetInvoiceByReference(ctx context.Context, cardID ulid.ULID, month, year int) (*creditcard.Invoice, error) {
 	var idb invoiceDB
-	err := r.DB.WithContext(ctx).
+	err := conn(ctx, r.DB).
 		Where("credit_card_id = ? AND reference_month = ? AND reference_year = ?",
 			cardID.String(), month, year).
 		First(&idb).Error
@@ -399,9 +399,52 @@ func (r *CreditCardRepository) GetInvoiceByReference(ctx context.Context, cardID
 	return toDomainInvoice(&idb)
 }
 
+func (r *CreditCardRepository) GetUnpaidInvoicesByUserId(ctx context.Context, userID ulid.ULID) ([]*creditcard.Invoice, error) {
+	var rows []invoiceDB
+	err := conn(ctx, r.DB).
+		Where("user_id = ? AND status <> ?", userID.String(), string(creditcard.InvoicePaid)).
+		Order("due_date ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*creditcard.Invoice, 0, len(rows))
+	for i := range rows {
+		invoice, err := toDomainInvoice(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, nil
+}
+
+// GetOpenInvoicesClosingBy lista as faturas ainda abertas cuja data de fechamento é até a data informada
+func (r *CreditCardRepository) GetOpenInvoicesClosingBy(ctx context.Context, date time.Time) ([]*creditcard.Invoice, error) {
+	var rows []invoiceDB
+	err := conn(ctx, r.DB).
+		Where("status = ? AND closing_date <= ?", string(creditcard.InvoiceOpen), date).
+		Order("closing_date ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*creditcard.Invoice, 0, len(rows))
+	for i := range rows {
+		invoice, err := toDomainInvoice(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, nil
+}
+
 func (r *CreditCardRepository) CreateTransaction(ctx context.Context, transaction *creditcard.CreditCardTransaction) error {
 	tdb := toDBCreditCardTransaction(transaction)
-	return r.DB.WithContext(ctx).Table("credit_card_transactions").Create(tdb).Error
+	return conn(ctx, r.DB).Table("credit_card_transactions").Create(tdb).Error
 }
 
 func (r *CreditCardRepository) GetTransactionsByInvoice(ctx context.Context, invoiceID, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*creditcard.CreditCardTransaction, int64, error) {
@@ -410,8 +453,8 @@ func (r *CreditCardRepository) GetTransactionsByInvoice(ctx context.Context, inv
 	}
 	pagination.Normalize()
 
-	countQuery := r.DB.WithContext(ctx).Table("credit_card_transactions t").Where("t.invoice_id = ? AND t.user_id = ?", invoiceID.String(), userID.String())
-	dataQuery := r.DB.WithContext(ctx).Table("credit_card_transactions t").
+	countQuery := conn(ctx, r.DB).Table("credit_card_transactions t").Where("t.invoice_id = ? AND t.user_id = ?", invoiceID.String(), userID.String())
+	dataQuery := conn(ctx, r.DB).Table("credit_card_transactions t").
 		Select("t.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.invoice_id = ? AND t.user_id = ?", invoiceID.String(), userID.String())
@@ -447,8 +490,8 @@ func (r *CreditCardRepository) GetTransactionsByCreditCard(ctx context.Context,
 	}
 	pagination.Normalize()
 
-	countQuery := r.DB.WithContext(ctx).Table("credit_card_transactions t").Where("t.credit_card_id = ? AND t.user_id = ?", cardID.String(), userID.String())
-	dataQuery := r.DB.WithContext(ctx).Table("credit_card_transactions t").
+	countQuery := conn(ctx, r.DB).Table("credit_card_transactions t").Where("t.credit_card_id = ? AND t.user_id = ?", cardID.String(), userID.String())
+	dataQuery := conn(ctx, r.DB).Table("credit_card_transactions t").
 		Select("t.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.credit_card_id = ? AND t.user_id = ?", cardID.String(), userID.String())
@@ -477,3 +520,54 @@ func (r *CreditCardRepository) GetTransactionsByCreditCard(ctx context.Context,
 	}
 	return transactions, total, nil
 }
+
+func (r *CreditCardRepository) GetTransactionsByUserSince(ctx context.Context, userID ulid.ULID, since time.Time) ([]*creditcard.CreditCardTransaction, error) {
+	var rows []creditCardTransactionDB
+	err := conn(ctx, r.DB).Table("credit_card_transactions").
+		Where("user_id = ? AND date >= ?", userID.String(), since).
+		Order("date ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*creditcard.CreditCardTransaction, 0, len(rows))
+	for i := range rows {
+		transaction, err := toDomainCreditCardTransaction(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+func (r *CreditCardRepository) GetInstallmentTransactionsByInvoices(ctx context.Context, userID ulid.ULID, invoiceIDs []ulid.ULID) ([]*creditcard.CreditCardTransaction, error) {
+	if len(invoiceIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(invoiceIDs))
+	for _, id := range invoiceIDs {
+		ids = append(ids, id.String())
+	}
+
+	var rows []creditCardTransactionDB
+	err := conn(ctx, r.DB).Table("credit_card_transactions").
+		Where("user_id = ? AND invoice_id IN ? AND installments > 1", userID.String(), ids).
+		Order("date ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*creditcard.CreditCardTransaction, 0, len(rows))
+	for i := range rows {
+		transaction, err := toDomainCreditCardTransaction(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
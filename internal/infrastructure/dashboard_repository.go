@@ -24,7 +24,7 @@ func (r *DashboardRepository) GetFinancialSummary(ctx context.Context, userID ul
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0)
 
-	incomeQuery := r.DB.WithContext(ctx).Table("transactions").
+	incomeQuery := conn(ctx, r.DB).Table("transactions").
 		Where("user_id = ? AND type = ? AND date >= ? AND date < ?", userID.String(), "RECEIPT", startDate, endDate)
 	if accountID != nil {
 		incomeQuery = incomeQuery.Where("account_id = ?", accountID.String())
@@ -35,7 +35,7 @@ func (r *DashboardRepository) GetFinancialSummary(ctx context.Context, userID ul
 		return nil, appErrors.NewDatabaseError(err)
 	}
 
-	expenseQuery := r.DB.WithContext(ctx).Table("transactions").
+	expenseQuery := conn(ctx, r.DB).Table("transactions").
 		Where("user_id = ? AND type = ? AND date >= ? AND date < ?", userID.String(), "EXPENSE", startDate, endDate)
 	if accountID != nil {
 		expenseQuery = expenseQuery.Where("account_id = ?", accountID.String())
@@ -47,7 +47,7 @@ func (r *DashboardRepository) GetFinancialSummary(ctx context.Context, userID ul
 	}
 
 	var totalBalance float64
-	balanceQuery := r.DB.WithContext(ctx).Table("accounts").
+	balanceQuery := conn(ctx, r.DB).Table("accounts").
 		Where("user_id = ? AND include_in_total = ? AND is_active = ?", userID.String(), true, true)
 	if accountID != nil {
 		balanceQuery = balanceQuery.Where("id = ?", accountID.String())
@@ -57,7 +57,7 @@ func (r *DashboardRepository) GetFinancialSummary(ctx context.Context, userID ul
 	}
 
 	var totalInvestments float64
-	if err := r.DB.WithContext(ctx).Table("investments").
+	if err := conn(ctx, r.DB).Table("investments").
 		Where("user_id = ?", userID.String()).
 		Select("COALESCE(SUM(current_balance), 0)").
 		Scan(&totalInvestments).Error; err != nil {
@@ -65,13 +65,21 @@ func (r *DashboardRepository) GetFinancialSummary(ctx context.Context, userID ul
 	}
 
 	var totalGoals float64
-	if err := r.DB.WithContext(ctx).Table("goals").
+	if err := conn(ctx, r.DB).Table("goals").
 		Where("user_id = ? AND status = ?", userID.String(), "ACTIVE").
 		Select("COALESCE(SUM(current_amount), 0)").
 		Scan(&totalGoals).Error; err != nil {
 		totalGoals = 0
 	}
 
+	var totalDebt float64
+	if err := conn(ctx, r.DB).Table("loans").
+		Where("user_id = ? AND status = ?", userID.String(), "ACTIVE").
+		Select("COALESCE(SUM(outstanding_balance), 0)").
+		Scan(&totalDebt).Error; err != nil {
+		totalDebt = 0
+	}
+
 	return &dashboard.FinancialSummary{
 		TotalBalance:     totalBalance,
 		MonthIncome:      monthIncome,
@@ -79,6 +87,8 @@ func (r *DashboardRepository) GetFinancialSummary(ctx context.Context, userID ul
 		MonthBalance:     monthIncome - monthExpenses,
 		TotalInvestments: totalInvestments,
 		TotalGoals:       totalGoals,
+		TotalDebt:        totalDebt,
+		NetWorth:         totalBalance + totalInvestments - totalDebt,
 	}, nil
 }
 
@@ -91,7 +101,7 @@ func (r *DashboardRepository) GetMonthlyTrend(ctx context.Context, userID ulid.U
 		startDate := time.Date(targetDate.Year(), targetDate.Month(), 1, 0, 0, 0, 0, time.UTC)
 		endDate := startDate.AddDate(0, 1, 0)
 
-		incomeQuery := r.DB.WithContext(ctx).Table("transactions").
+		incomeQuery := conn(ctx, r.DB).Table("transactions").
 			Where("user_id = ? AND type = ? AND date >= ? AND date < ?", userID.String(), "RECEIPT", startDate, endDate)
 		if accountID != nil {
 			incomeQuery = incomeQuery.Where("account_id = ?", accountID.String())
@@ -102,7 +112,7 @@ func (r *DashboardRepository) GetMonthlyTrend(ctx context.Context, userID ulid.U
 			return nil, appErrors.NewDatabaseError(err)
 		}
 
-		expenseQuery := r.DB.WithContext(ctx).Table("transactions").
+		expenseQuery := conn(ctx, r.DB).Table("transactions").
 			Where("user_id = ? AND type = ? AND date >= ? AND date < ?", userID.String(), "EXPENSE", startDate, endDate)
 		if accountID != nil {
 			expenseQuery = expenseQuery.Where("account_id = ?", accountID.String())
@@ -135,7 +145,7 @@ func (r *DashboardRepository) GetExpensesByCategory(ctx context.Context, userID
 		Amount     float64 `gorm:"column:amount"`
 	}
 
-	query := r.DB.WithContext(ctx).Table("transactions t").
+	query := conn(ctx, r.DB).Table("transactions t").
 		Select("t.category_id, c.name, SUM(ABS(t.amount)) as amount").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.user_id = ? AND t.type = ? AND t.date >= ? AND t.date < ?", userID.String(), "EXPENSE", startDate, endDate)
@@ -209,7 +219,7 @@ func (r *DashboardRepository) GetRecentTransactions(ctx context.Context, userID
 		AccountId    string    `gorm:"column:account_id"`
 	}
 
-	query := r.DB.WithContext(ctx).Table("transactions t").
+	query := conn(ctx, r.DB).Table("transactions t").
 		Select("t.id, t.type, t.amount, t.description, t.category_id, c.name as category_name, t.date, t.account_id").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.user_id = ?", userID.String())
@@ -263,7 +273,7 @@ func (r *DashboardRepository) GetActiveGoals(ctx context.Context, userID ulid.UL
 	}
 
 	var results []goalResult
-	if err := r.DB.WithContext(ctx).Table("goals").
+	if err := conn(ctx, r.DB).Table("goals").
 		Select("id, name, target_amount, current_amount, status").
 		Where("user_id = ? AND status = ?", userID.String(), "ACTIVE").
 		Order("created_at DESC").
@@ -305,7 +315,7 @@ func (r *DashboardRepository) GetBudgetStatus(ctx context.Context, userID ulid.U
 	}
 
 	var budgets []budgetResult
-	if err := r.DB.WithContext(ctx).Table("budgets b").
+	if err := conn(ctx, r.DB).Table("budgets b").
 		Select("b.category_id, c.name, b.amount").
 		Joins("LEFT JOIN categories c ON b.category_id = c.id").
 		Where("b.user_id = ? AND b.month = ? AND b.year = ?", userID.String(), month, year).
@@ -319,7 +329,7 @@ func (r *DashboardRepository) GetBudgetStatus(ctx context.Context, userID ulid.U
 		if err != nil {
 			continue
 		}
-		spentQuery := r.DB.WithContext(ctx).Table("transactions").
+		spentQuery := conn(ctx, r.DB).Table("transactions").
 			Where("user_id = ? AND category_id = ? AND type = ? AND date >= ? AND date < ?",
 				userID.String(), b.CategoryId, "EXPENSE", startDate, endDate)
 		if accountID != nil {
@@ -366,7 +376,7 @@ func (r *DashboardRepository) GetAccountsSummary(ctx context.Context, userID uli
 	}
 
 	var results []accountResult
-	if err := r.DB.WithContext(ctx).Table("accounts").
+	if err := conn(ctx, r.DB).Table("accounts").
 		Select("id, name, type, balance, color").
 		Where("user_id = ? AND is_active = ? AND type != ?", userID.String(), true, "CREDIT_CARD").
 		Order("name ASC").
@@ -403,7 +413,7 @@ func (r *DashboardRepository) GetUserCategories(ctx context.Context, userID ulid
 	}
 
 	var results []categoryResult
-	if err := r.DB.WithContext(ctx).Table("categories").
+	if err := conn(ctx, r.DB).Table("categories").
 		Where("user_id = ?", userID.String()).
 		Order("name ASC").
 		Scan(&results).Error; err != nil {
@@ -447,7 +457,7 @@ func (r *DashboardRepository) GetMonthExpenses(ctx context.Context, userID ulid.
 		Date         time.Time `gorm:"column:date"`
 	}
 
-	query := r.DB.WithContext(ctx).Table("transactions t").
+	query := conn(ctx, r.DB).Table("transactions t").
 		Select("t.id, t.type, t.amount, t.description, t.category_id, c.name as category_name, t.date").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.user_id = ? AND t.type = ? AND t.date >= ? AND t.date < ?", userID.String(), "EXPENSE", startDate, endDate)
@@ -3,13 +3,22 @@ package infrastructure
 import (
 	"Fynance/config"
 	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/achievement"
 	"Fynance/internal/domain/budget"
+	"Fynance/internal/domain/calendar"
 	"Fynance/internal/domain/creditcard"
+	"Fynance/internal/domain/event"
 	"Fynance/internal/domain/goal"
 	"Fynance/internal/domain/investment"
+	"Fynance/internal/domain/loan"
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/notification"
+	"Fynance/internal/domain/queue"
 	"Fynance/internal/domain/recurring"
+	"Fynance/internal/domain/roundup"
 	"Fynance/internal/domain/transaction"
 	"Fynance/internal/domain/user"
+	"Fynance/internal/domain/webhook"
 	"Fynance/internal/logger"
 
 	"gorm.io/driver/postgres"
@@ -66,15 +75,41 @@ func runMigrations(db *gorm.DB) error {
 		&user.User{},
 		&goal.Goal{},
 		&goal.Contribution{},
+		&goal.ContributionSchedule{},
 		&transaction.Transaction{},
 		&transaction.Category{},
 		&investment.Investment{},
+		&investment.Lot{},
+		&investment.Redemption{},
+		&investment.IncomeEvent{},
+		&investment.AllocationTarget{},
+		&investment.BrokerageNote{},
 		&account.Account{},
 		&budget.Budget{},
 		&recurring.RecurringTransaction{},
+		&recurring.EstimatedOccurrence{},
 		&creditcard.CreditCard{},
 		&creditcard.Invoice{},
 		&creditcard.CreditCardTransaction{},
+		&achievement.UserAchievement{},
+		&roundup.Rule{},
+		&roundup.Entry{},
+		&loan.Loan{},
+		&loan.Payment{},
+		&market.PriceHistory{},
+		&market.IndexRate{},
+		&calendar.ReminderSettings{},
+		&calendar.ReminderLog{},
+		&notification.Notification{},
+		&notification.Preferences{},
+		&notification.PushSubscription{},
+		&webhook.Endpoint{},
+		&webhook.Delivery{},
+		&event.Event{},
+		&event.Delivery{},
+		&event.ProcessedDelivery{},
+		&queue.Job{},
+		&queue.Schedule{},
 	}
 
 	for _, entity := range entities {
@@ -194,24 +229,76 @@ func getEntityName(entity interface{}) string {
 		return "Goal"
 	case *goal.Contribution:
 		return "GoalContribution"
+	case *goal.ContributionSchedule:
+		return "GoalContributionSchedule"
 	case *transaction.Transaction:
 		return "Transaction"
 	case *transaction.Category:
 		return "Category"
 	case *investment.Investment:
 		return "Investment"
+	case *investment.Lot:
+		return "InvestmentLot"
+	case *investment.Redemption:
+		return "InvestmentRedemption"
+	case *investment.IncomeEvent:
+		return "InvestmentIncomeEvent"
+	case *investment.AllocationTarget:
+		return "InvestmentAllocationTarget"
+	case *investment.BrokerageNote:
+		return "BrokerageNote"
 	case *account.Account:
 		return "Account"
 	case *budget.Budget:
 		return "Budget"
 	case *recurring.RecurringTransaction:
 		return "RecurringTransaction"
+	case *recurring.EstimatedOccurrence:
+		return "EstimatedOccurrence"
 	case *creditcard.CreditCard:
 		return "CreditCard"
 	case *creditcard.Invoice:
 		return "Invoice"
 	case *creditcard.CreditCardTransaction:
 		return "CreditCardTransaction"
+	case *achievement.UserAchievement:
+		return "UserAchievement"
+	case *roundup.Rule:
+		return "RoundUpRule"
+	case *roundup.Entry:
+		return "RoundUpEntry"
+	case *loan.Loan:
+		return "Loan"
+	case *loan.Payment:
+		return "LoanPayment"
+	case *market.PriceHistory:
+		return "PriceHistory"
+	case *market.IndexRate:
+		return "IndexRate"
+	case *calendar.ReminderSettings:
+		return "ReminderSettings"
+	case *calendar.ReminderLog:
+		return "ReminderLog"
+	case *notification.Notification:
+		return "Notification"
+	case *notification.Preferences:
+		return "NotificationPreferences"
+	case *notification.PushSubscription:
+		return "PushSubscription"
+	case *webhook.Endpoint:
+		return "WebhookEndpoint"
+	case *webhook.Delivery:
+		return "WebhookDelivery"
+	case *event.Event:
+		return "DomainEvent"
+	case *event.Delivery:
+		return "DomainEventDelivery"
+	case *event.ProcessedDelivery:
+		return "DomainEventProcessedDelivery"
+	case *queue.Job:
+		return "Job"
+	case *queue.Schedule:
+		return "JobSchedule"
 	default:
 		return "Unknown"
 	}
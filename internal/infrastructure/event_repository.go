@@ -0,0 +1,246 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/event"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventRepository struct {
+	DB *gorm.DB
+}
+
+var _ event.Repository = (*EventRepository)(nil)
+
+type domainEventDB struct {
+	Id         string    `gorm:"type:varchar(26);primaryKey"`
+	UserId     string    `gorm:"type:varchar(26);not null"`
+	Type       string    `gorm:"type:varchar(50);not null"`
+	Payload    string    `gorm:"type:text;not null"`
+	OccurredAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (domainEventDB) TableName() string {
+	return "domain_events"
+}
+
+type domainEventDeliveryDB struct {
+	Id            string     `gorm:"type:varchar(26);primaryKey"`
+	EventId       string     `gorm:"type:varchar(26);not null"`
+	Subscriber    string     `gorm:"type:varchar(50);not null"`
+	Status        string     `gorm:"type:varchar(20);not null"`
+	Attempts      int        `gorm:"not null"`
+	NextAttemptAt time.Time  `gorm:"type:timestamp;not null"`
+	LastError     string     `gorm:"type:text"`
+	ProcessedAt   *time.Time `gorm:"type:timestamp"`
+	CreatedAt     time.Time  `gorm:"not null"`
+}
+
+func (domainEventDeliveryDB) TableName() string {
+	return "domain_event_deliveries"
+}
+
+type domainEventProcessedDeliveryDB struct {
+	DeliveryId  string    `gorm:"type:varchar(26);primaryKey"`
+	ProcessedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (domainEventProcessedDeliveryDB) TableName() string {
+	return "domain_event_processed_deliveries"
+}
+
+func toDomainEvent(edb *domainEventDB) (*event.Event, error) {
+	id, err := pkg.ParseULID(edb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(edb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &event.Event{
+		Id:         id,
+		UserId:     userID,
+		Type:       edb.Type,
+		Payload:    []byte(edb.Payload),
+		OccurredAt: edb.OccurredAt,
+	}, nil
+}
+
+func toDomainEventDelivery(ddb *domainEventDeliveryDB) (*event.Delivery, error) {
+	id, err := pkg.ParseULID(ddb.Id)
+	if err != nil {
+		return nil, err
+	}
+	eventID, err := pkg.ParseULID(ddb.EventId)
+	if err != nil {
+		return nil, err
+	}
+	return &event.Delivery{
+		Id:            id,
+		EventId:       eventID,
+		Subscriber:    ddb.Subscriber,
+		Status:        event.DeliveryStatus(ddb.Status),
+		Attempts:      ddb.Attempts,
+		NextAttemptAt: ddb.NextAttemptAt,
+		LastError:     ddb.LastError,
+		ProcessedAt:   ddb.ProcessedAt,
+		CreatedAt:     ddb.CreatedAt,
+	}, nil
+}
+
+func toDomainEventDeliveryDB(delivery *event.Delivery) *domainEventDeliveryDB {
+	return &domainEventDeliveryDB{
+		Id:            delivery.Id.String(),
+		EventId:       delivery.EventId.String(),
+		Subscriber:    delivery.Subscriber,
+		Status:        string(delivery.Status),
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastError:     delivery.LastError,
+		ProcessedAt:   delivery.ProcessedAt,
+		CreatedAt:     delivery.CreatedAt,
+	}
+}
+
+func (r *EventRepository) Create(ctx context.Context, evt *event.Event, deliveries []*event.Delivery) error {
+	rows := make([]*domainEventDeliveryDB, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		rows = append(rows, toDomainEventDeliveryDB(delivery))
+	}
+
+	return conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&domainEventDB{
+			Id:         evt.Id.String(),
+			UserId:     evt.UserId.String(),
+			Type:       evt.Type,
+			Payload:    string(evt.Payload),
+			OccurredAt: evt.OccurredAt,
+		}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *EventRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*event.Delivery, error) {
+	var rows []domainEventDeliveryDB
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("status = ? AND next_attempt_at <= ?", string(event.DeliveryPending), now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.Id)
+		}
+		return tx.Model(&domainEventDeliveryDB{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	eventIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		eventIDs = append(eventIDs, row.EventId)
+	}
+	var eventRows []domainEventDB
+	if err := conn(ctx, r.DB).Where("id IN ?", eventIDs).Find(&eventRows).Error; err != nil {
+		return nil, err
+	}
+	events := make(map[string]*event.Event, len(eventRows))
+	for i := range eventRows {
+		evt, err := toDomainEvent(&eventRows[i])
+		if err != nil {
+			return nil, err
+		}
+		events[eventRows[i].Id] = evt
+	}
+
+	deliveries := make([]*event.Delivery, 0, len(rows))
+	for i := range rows {
+		evt, ok := events[rows[i].EventId]
+		if !ok {
+			continue
+		}
+		delivery, err := toDomainEventDelivery(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		delivery.NextAttemptAt = leaseUntil
+		delivery.Event = evt
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *EventRepository) MarkProcessed(ctx context.Context, deliveryID ulid.ULID, processedAt time.Time) (bool, error) {
+	result := conn(ctx, r.DB).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domainEventProcessedDeliveryDB{DeliveryId: deliveryID.String(), ProcessedAt: processedAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateDelivery restringe a atualização à tentativa reservada até leaseUntil, como o claimedBy da fila:
+// um despacho cuja reserva expirou não sobrescreve o resultado de quem retomou a entrega
+func (r *EventRepository) UpdateDelivery(ctx context.Context, delivery *event.Delivery, leaseUntil time.Time) error {
+	result := conn(ctx, r.DB).
+		Where("id = ? AND status = ? AND next_attempt_at = ? AND attempts = ?",
+			delivery.Id.String(), string(event.DeliveryPending), leaseUntil, delivery.Attempts-1).
+		Select("status", "attempts", "next_attempt_at", "last_error", "processed_at").
+		Updates(toDomainEventDeliveryDB(delivery))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *EventRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("occurred_at < ?", before).
+			Where("NOT EXISTS (SELECT 1 FROM domain_event_deliveries d WHERE d.event_id = domain_events.id AND d.status <> ?)",
+				string(event.DeliveryProcessed)).
+			Delete(&domainEventDB{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		if err := tx.
+			Where("NOT EXISTS (SELECT 1 FROM domain_events e WHERE e.id = domain_event_deliveries.event_id)").
+			Delete(&domainEventDeliveryDB{}).Error; err != nil {
+			return err
+		}
+		return tx.
+			Where("NOT EXISTS (SELECT 1 FROM domain_event_deliveries d WHERE d.id = domain_event_processed_deliveries.delivery_id)").
+			Delete(&domainEventProcessedDeliveryDB{}).Error
+	})
+	return deleted, err
+}
@@ -29,6 +29,11 @@ type goalDB struct {
 	Status        goal.GoalStatus `gorm:"not null"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+
+	SuggestedAmount *float64
+	LastMilestone   int
+
+	CompletedAt *time.Time
 }
 
 func toDomainGoal(gdb *goalDB) (*goal.Goal, error) {
@@ -51,6 +56,11 @@ func toDomainGoal(gdb *goalDB) (*goal.Goal, error) {
 		Status:        gdb.Status,
 		CreatedAt:     gdb.CreatedAt,
 		UpdatedAt:     gdb.UpdatedAt,
+
+		SuggestedAmount: gdb.SuggestedAmount,
+		LastMilestone:   gdb.LastMilestone,
+
+		CompletedAt: gdb.CompletedAt,
 	}, nil
 }
 
@@ -66,16 +76,21 @@ func toDBGoal(g *goal.Goal) *goalDB {
 		Status:        g.Status,
 		CreatedAt:     g.CreatedAt,
 		UpdatedAt:     g.UpdatedAt,
+
+		SuggestedAmount: g.SuggestedAmount,
+		LastMilestone:   g.LastMilestone,
+
+		CompletedAt: g.CompletedAt,
 	}
 }
 
 func (r *GoalRepository) Create(ctx context.Context, g *goal.Goal) error {
 	gdb := toDBGoal(g)
-	return r.DB.WithContext(ctx).Table("goals").Create(&gdb).Error
+	return conn(ctx, r.DB).Table("goals").Create(&gdb).Error
 }
 
 func (r *GoalRepository) Delete(ctx context.Context, id ulid.ULID) error {
-	result := r.DB.WithContext(ctx).Table("goals").Where("id = ?", id.String()).Delete(&goalDB{})
+	result := conn(ctx, r.DB).Table("goals").Where("id = ?", id.String()).Delete(&goalDB{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -87,7 +102,7 @@ func (r *GoalRepository) Delete(ctx context.Context, id ulid.ULID) error {
 
 func (r *GoalRepository) GetByID(ctx context.Context, id ulid.ULID) (*goal.Goal, error) {
 	var gdb goalDB
-	if err := r.DB.WithContext(ctx).Table("goals").Where("id = ?", id.String()).First(&gdb).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("goals").Where("id = ?", id.String()).First(&gdb).Error; err != nil {
 		return nil, err
 	}
 	return toDomainGoal(&gdb)
@@ -95,14 +110,14 @@ func (r *GoalRepository) GetByID(ctx context.Context, id ulid.ULID) (*goal.Goal,
 
 func (r *GoalRepository) GetByIDAndUser(ctx context.Context, id, userID ulid.ULID) (*goal.Goal, error) {
 	var gdb goalDB
-	if err := r.DB.WithContext(ctx).Table("goals").Where("id = ? AND user_id = ?", id.String(), userID.String()).First(&gdb).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("goals").Where("id = ? AND user_id = ?", id.String(), userID.String()).First(&gdb).Error; err != nil {
 		return nil, err
 	}
 	return toDomainGoal(&gdb)
 }
 
 func (r *GoalRepository) GetByUserID(ctx context.Context, userID ulid.ULID, filters *goal.GoalFilters, pagination *pkg.PaginationParams) ([]*goal.Goal, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("goals").Where("user_id = ?", userID.String())
+	baseQuery := conn(ctx, r.DB).Table("goals").Where("user_id = ?", userID.String())
 
 	if filters != nil && filters.Status != nil {
 		baseQuery = baseQuery.Where("status = ?", string(*filters.Status))
@@ -112,22 +127,22 @@ func (r *GoalRepository) GetByUserID(ctx context.Context, userID ulid.ULID, filt
 }
 
 func (r *GoalRepository) List(ctx context.Context, pagination *pkg.PaginationParams) ([]*goal.Goal, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("goals")
+	baseQuery := conn(ctx, r.DB).Table("goals")
 	return pkg.Paginate(baseQuery, pagination, "created_at DESC", toDomainGoal)
 }
 
 func (r *GoalRepository) Update(ctx context.Context, g *goal.Goal) error {
 	gdb := toDBGoal(g)
-	return r.DB.WithContext(ctx).Table("goals").Where("id = ?", gdb.Id).Updates(&gdb).Error
+	return conn(ctx, r.DB).Table("goals").Where("id = ?", gdb.Id).Updates(&gdb).Error
 }
 
 func (r *GoalRepository) UpdateFields(ctx context.Context, id ulid.ULID, fields map[string]interface{}) error {
-	return r.DB.WithContext(ctx).Table("goals").Where("id = ?", id.String()).Updates(fields).Error
+	return conn(ctx, r.DB).Table("goals").Where("id = ?", id.String()).Updates(fields).Error
 }
 
 func (r *GoalRepository) CheckGoalBelongsToUser(ctx context.Context, goalID ulid.ULID, userID ulid.ULID) (bool, error) {
 	var count int64
-	if err := r.DB.WithContext(ctx).Table("goals").Where("id = ? AND user_id = ?", goalID.String(), userID.String()).Count(&count).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("goals").Where("id = ? AND user_id = ?", goalID.String(), userID.String()).Count(&count).Error; err != nil {
 		return false, err
 	}
 	return count > 0, nil
@@ -139,6 +154,7 @@ type contributionDB struct {
 	UserId        string    `gorm:"type:varchar(26);index;not null"`
 	AccountId     string    `gorm:"type:varchar(26);index;not null"`
 	TransactionId *string   `gorm:"type:varchar(26);index"`
+	InvestmentId  *string   `gorm:"type:varchar(26);index"`
 	Type          string    `gorm:"type:varchar(20);not null"`
 	Amount        float64   `gorm:"type:decimal(15,2);not null"`
 	Description   string    `gorm:"type:varchar(255)"`
@@ -171,12 +187,21 @@ func toDomainContribution(cdb *contributionDB) (*goal.Contribution, error) {
 		}
 	}
 
+	var investmentID *ulid.ULID
+	if cdb.InvestmentId != nil && *cdb.InvestmentId != "" {
+		iid, err := pkg.ParseULID(*cdb.InvestmentId)
+		if err == nil {
+			investmentID = &iid
+		}
+	}
+
 	return &goal.Contribution{
 		Id:            id,
 		GoalId:        gid,
 		UserId:        uid,
 		AccountId:     aid,
 		TransactionId: transactionID,
+		InvestmentId:  investmentID,
 		Type:          goal.ContributionType(cdb.Type),
 		Amount:        cdb.Amount,
 		Description:   cdb.Description,
@@ -190,12 +215,18 @@ func toDBContribution(c *goal.Contribution) *contributionDB {
 		s := c.TransactionId.String()
 		transactionID = &s
 	}
+	var investmentID *string
+	if c.InvestmentId != nil {
+		s := c.InvestmentId.String()
+		investmentID = &s
+	}
 	return &contributionDB{
 		Id:            c.Id.String(),
 		GoalId:        c.GoalId.String(),
 		UserId:        c.UserId.String(),
 		AccountId:     c.AccountId.String(),
 		TransactionId: transactionID,
+		InvestmentId:  investmentID,
 		Type:          string(c.Type),
 		Amount:        c.Amount,
 		Description:   c.Description,
@@ -205,12 +236,12 @@ func toDBContribution(c *goal.Contribution) *contributionDB {
 
 func (r *GoalRepository) CreateContribution(ctx context.Context, c *goal.Contribution) error {
 	cdb := toDBContribution(c)
-	return r.DB.WithContext(ctx).Table("goal_contributions").Create(&cdb).Error
+	return conn(ctx, r.DB).Table("goal_contributions").Create(&cdb).Error
 }
 
 func (r *GoalRepository) GetContributionsByGoalID(ctx context.Context, goalId ulid.ULID, userId ulid.ULID) ([]*goal.Contribution, error) {
 	var rows []contributionDB
-	if err := r.DB.WithContext(ctx).Table("goal_contributions").
+	if err := conn(ctx, r.DB).Table("goal_contributions").
 		Where("goal_id = ? AND user_id = ?", goalId.String(), userId.String()).
 		Order("created_at DESC").
 		Find(&rows).Error; err != nil {
@@ -229,7 +260,7 @@ func (r *GoalRepository) GetContributionsByGoalID(ctx context.Context, goalId ul
 
 func (r *GoalRepository) GetContributionByID(ctx context.Context, contributionId ulid.ULID, userId ulid.ULID) (*goal.Contribution, error) {
 	var cdb contributionDB
-	if err := r.DB.WithContext(ctx).Table("goal_contributions").
+	if err := conn(ctx, r.DB).Table("goal_contributions").
 		Where("id = ? AND user_id = ?", contributionId.String(), userId.String()).
 		First(&cdb).Error; err != nil {
 		return nil, err
@@ -239,7 +270,7 @@ func (r *GoalRepository) GetContributionByID(ctx context.Context, contributionId
 
 func (r *GoalRepository) GetContributionByTransactionID(ctx context.Context, transactionId ulid.ULID, userId ulid.ULID) (*goal.Contribution, error) {
 	var cdb contributionDB
-	if err := r.DB.WithContext(ctx).Table("goal_contributions").
+	if err := conn(ctx, r.DB).Table("goal_contributions").
 		Where("transaction_id = ? AND user_id = ?", transactionId.String(), userId.String()).
 		First(&cdb).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -251,7 +282,7 @@ func (r *GoalRepository) GetContributionByTransactionID(ctx context.Context, tra
 }
 
 func (r *GoalRepository) DeleteContribution(ctx context.Context, contributionId ulid.ULID) error {
-	result := r.DB.WithContext(ctx).Table("goal_contributions").
+	result := conn(ctx, r.DB).Table("goal_contributions").
 		Where("id = ?", contributionId.String()).
 		Delete(&contributionDB{})
 	if result.Error != nil {
@@ -264,7 +295,7 @@ func (r *GoalRepository) DeleteContribution(ctx context.Context, contributionId
 }
 
 func (r *GoalRepository) UpdateCurrentAmount(ctx context.Context, goalId ulid.ULID, amount float64) error {
-	return r.DB.WithContext(ctx).Table("goals").
+	return conn(ctx, r.DB).Table("goals").
 		Where("id = ?", goalId.String()).
 		Updates(map[string]interface{}{
 			"current_amount": amount,
@@ -273,7 +304,7 @@ func (r *GoalRepository) UpdateCurrentAmount(ctx context.Context, goalId ulid.UL
 }
 
 func (r *GoalRepository) UpdateCurrentAmountAtomic(ctx context.Context, goalId ulid.ULID, delta float64) error {
-	result := r.DB.WithContext(ctx).Table("goals").Where("id = ?", goalId.String()).
+	result := conn(ctx, r.DB).Table("goals").Where("id = ?", goalId.String()).
 		UpdateColumn("current_amount", gorm.Expr("current_amount + ?", delta)).
 		UpdateColumn("updated_at", time.Now())
 	if result.Error != nil {
@@ -284,3 +315,168 @@ func (r *GoalRepository) UpdateCurrentAmountAtomic(ctx context.Context, goalId u
 	}
 	return nil
 }
+
+type scheduleDB struct {
+	Id           string     `gorm:"type:varchar(26);primaryKey"`
+	GoalId       string     `gorm:"type:varchar(26);not null"`
+	UserId       string     `gorm:"type:varchar(26);not null"`
+	AccountId    string     `gorm:"type:varchar(26);not null"`
+	Amount       float64    `gorm:"type:decimal(15,2);not null"`
+	UseSuggested bool       `gorm:"not null"`
+	Frequency    string     `gorm:"type:varchar(20);not null"`
+	DayOfMonth   int        `gorm:"column:day_of_month"`
+	DayOfWeek    int        `gorm:"column:day_of_week"`
+	NextRunAt    time.Time  `gorm:"not null"`
+	LastRunAt    *time.Time `gorm:"column:last_run_at"`
+	LastStatus   string     `gorm:"type:varchar(20)"`
+	LastMessage  string     `gorm:"type:varchar(255)"`
+	SkippedRuns  int        `gorm:"not null"`
+	IsActive     bool       `gorm:"not null"`
+	CreatedAt    time.Time  `gorm:"not null"`
+	UpdatedAt    time.Time  `gorm:"not null"`
+}
+
+func (scheduleDB) TableName() string {
+	return "goal_contribution_schedules"
+}
+
+func toDomainSchedule(sdb *scheduleDB) (*goal.ContributionSchedule, error) {
+	id, err := pkg.ParseULID(sdb.Id)
+	if err != nil {
+		return nil, err
+	}
+	gid, err := pkg.ParseULID(sdb.GoalId)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := pkg.ParseULID(sdb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	aid, err := pkg.ParseULID(sdb.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &goal.ContributionSchedule{
+		Id:           id,
+		GoalId:       gid,
+		UserId:       uid,
+		AccountId:    aid,
+		Amount:       sdb.Amount,
+		UseSuggested: sdb.UseSuggested,
+		Frequency:    goal.ScheduleFrequency(sdb.Frequency),
+		DayOfMonth:   sdb.DayOfMonth,
+		DayOfWeek:    sdb.DayOfWeek,
+		NextRunAt:    sdb.NextRunAt,
+		LastRunAt:    sdb.LastRunAt,
+		LastStatus:   goal.ScheduleRunStatus(sdb.LastStatus),
+		LastMessage:  sdb.LastMessage,
+		SkippedRuns:  sdb.SkippedRuns,
+		IsActive:     sdb.IsActive,
+		CreatedAt:    sdb.CreatedAt,
+		UpdatedAt:    sdb.UpdatedAt,
+	}, nil
+}
+
+func toDBSchedule(s *goal.ContributionSchedule) *scheduleDB {
+	return &scheduleDB{
+		Id:           s.Id.String(),
+		GoalId:       s.GoalId.String(),
+		UserId:       s.UserId.String(),
+		AccountId:    s.AccountId.String(),
+		Amount:       s.Amount,
+		UseSuggested: s.UseSuggested,
+		Frequency:    string(s.Frequency),
+		DayOfMonth:   s.DayOfMonth,
+		DayOfWeek:    s.DayOfWeek,
+		NextRunAt:    s.NextRunAt,
+		LastRunAt:    s.LastRunAt,
+		LastStatus:   string(s.LastStatus),
+		LastMessage:  s.LastMessage,
+		SkippedRuns:  s.SkippedRuns,
+		IsActive:     s.IsActive,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func (r *GoalRepository) CreateSchedule(ctx context.Context, s *goal.ContributionSchedule) error {
+	sdb := toDBSchedule(s)
+	return conn(ctx, r.DB).Create(sdb).Error
+}
+
+func (r *GoalRepository) UpdateSchedule(ctx context.Context, s *goal.ContributionSchedule) error {
+	sdb := toDBSchedule(s)
+	return conn(ctx, r.DB).Model(&scheduleDB{}).
+		Where("id = ? AND user_id = ?", sdb.Id, sdb.UserId).
+		Select("*").
+		Omit("id", "goal_id", "user_id", "created_at").
+		Updates(sdb).Error
+}
+
+func (r *GoalRepository) GetScheduleByGoalID(ctx context.Context, goalId ulid.ULID, userId ulid.ULID) (*goal.ContributionSchedule, error) {
+	var sdb scheduleDB
+	if err := conn(ctx, r.DB).
+		Where("goal_id = ? AND user_id = ?", goalId.String(), userId.String()).
+		First(&sdb).Error; err != nil {
+		return nil, err
+	}
+	return toDomainSchedule(&sdb)
+}
+
+func (r *GoalRepository) DeleteSchedule(ctx context.Context, goalId ulid.ULID, userId ulid.ULID) error {
+	result := conn(ctx, r.DB).
+		Where("goal_id = ? AND user_id = ?", goalId.String(), userId.String()).
+		Delete(&scheduleDB{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GoalRepository) GetDueSchedules(ctx context.Context, date time.Time, limit int) ([]*goal.ContributionSchedule, error) {
+	var rows []scheduleDB
+	query := conn(ctx, r.DB).
+		Where("is_active = ? AND next_run_at <= ?", true, date).
+		Order("next_run_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]*goal.ContributionSchedule, 0, len(rows))
+	for i := range rows {
+		s, err := toDomainSchedule(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (r *GoalRepository) GetActiveSchedulesByUserID(ctx context.Context, userId ulid.ULID) ([]*goal.ContributionSchedule, error) {
+	var rows []scheduleDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ? AND is_active = ?", userId.String(), true).
+		Order("next_run_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]*goal.ContributionSchedule, 0, len(rows))
+	for i := range rows {
+		s, err := toDomainSchedule(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
@@ -18,14 +18,22 @@ type InvestmentRepository struct {
 var _ investment.InvestmentRepository = (*InvestmentRepository)(nil)
 
 type investmentDB struct {
-	Id              string    `gorm:"type:varchar(26);primaryKey"`
-	UserId          string    `gorm:"type:varchar(26);index;not null"`
-	Type            string    `gorm:"type:varchar(20);not null"`
-	Name            string    `gorm:"size:100;not null"`
-	CurrentBalance  float64   `gorm:"not null;default:0"`
-	ReturnBalance   float64   `gorm:"not null;default:0"`
-	ReturnRate      float64   `gorm:"default:0"`
+	Id              string  `gorm:"type:varchar(26);primaryKey"`
+	UserId          string  `gorm:"type:varchar(26);index;not null"`
+	Type            string  `gorm:"type:varchar(20);not null"`
+	Name            string  `gorm:"size:100;not null"`
+	Ticker          string  `gorm:"size:20"`
+	Quantity        float64 `gorm:"not null;default:0"`
+	LastPrice       float64 `gorm:"not null;default:0"`
+	CurrentBalance  float64 `gorm:"not null;default:0"`
+	ReturnBalance   float64 `gorm:"not null;default:0"`
+	ReturnRate      float64 `gorm:"default:0"`
+	Indexer         string  `gorm:"size:20"`
+	IndexerRate     float64 `gorm:"not null;default:0"`
+	MaturityDate    *time.Time
+	AssetClass      string    `gorm:"size:40"`
 	ApplicationDate time.Time `gorm:"not null"`
+	GoalId          *string   `gorm:"type:varchar(26)"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -39,30 +47,59 @@ func toDomainInvestment(idb *investmentDB) (*investment.Investment, error) {
 	if err != nil {
 		return nil, err
 	}
+	var goalID *ulid.ULID
+	if idb.GoalId != nil && *idb.GoalId != "" {
+		gid, err := pkg.ParseULID(*idb.GoalId)
+		if err == nil {
+			goalID = &gid
+		}
+	}
+
 	return &investment.Investment{
 		Id:              id,
 		UserId:          uid,
 		Type:            investment.Types(idb.Type),
 		Name:            idb.Name,
+		Ticker:          idb.Ticker,
+		Quantity:        idb.Quantity,
+		LastPrice:       idb.LastPrice,
 		CurrentBalance:  idb.CurrentBalance,
 		ReturnBalance:   idb.ReturnBalance,
 		ReturnRate:      idb.ReturnRate,
+		Indexer:         investment.Indexer(idb.Indexer),
+		IndexerRate:     idb.IndexerRate,
+		MaturityDate:    idb.MaturityDate,
+		AssetClass:      idb.AssetClass,
 		ApplicationDate: idb.ApplicationDate,
+		GoalId:          goalID,
 		CreatedAt:       idb.CreatedAt,
 		UpdatedAt:       idb.UpdatedAt,
 	}, nil
 }
 
 func toDBInvestment(inv *investment.Investment) *investmentDB {
+	var goalID *string
+	if inv.GoalId != nil {
+		s := inv.GoalId.String()
+		goalID = &s
+	}
 	return &investmentDB{
 		Id:              inv.Id.String(),
 		UserId:          inv.UserId.String(),
 		Type:            string(inv.Type),
 		Name:            inv.Name,
+		Ticker:          inv.Ticker,
+		Quantity:        inv.Quantity,
+		LastPrice:       inv.LastPrice,
 		CurrentBalance:  inv.CurrentBalance,
 		ReturnBalance:   inv.ReturnBalance,
 		ReturnRate:      inv.ReturnRate,
+		Indexer:         string(inv.Indexer),
+		IndexerRate:     inv.IndexerRate,
+		MaturityDate:    inv.MaturityDate,
+		AssetClass:      inv.AssetClass,
 		ApplicationDate: inv.ApplicationDate,
+		GoalId:          goalID,
 		CreatedAt:       inv.CreatedAt,
 		UpdatedAt:       inv.UpdatedAt,
 	}
@@ -70,11 +107,11 @@ func toDBInvestment(inv *investment.Investment) *investmentDB {
 
 func (r *InvestmentRepository) Create(ctx context.Context, inv *investment.Investment) error {
 	idb := toDBInvestment(inv)
-	return r.DB.WithContext(ctx).Table("investments").Create(idb).Error
+	return conn(ctx, r.DB).Table("investments").Create(idb).Error
 }
 
 func (r *InvestmentRepository) List(ctx context.Context, userId ulid.ULID, filters *investment.InvestmentFilters, pagination *pkg.PaginationParams) ([]*investment.Investment, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("investments").Where("user_id = ?", userId.String())
+	baseQuery := conn(ctx, r.DB).Table("investments").Where("user_id = ?", userId.String())
 
 	if filters != nil && filters.Type != nil && *filters.Type != "" && *filters.Type != "ALL" {
 		baseQuery = baseQuery.Where("type = ?", *filters.Type)
@@ -85,11 +122,11 @@ func (r *InvestmentRepository) List(ctx context.Context, userId ulid.ULID, filte
 
 func (r *InvestmentRepository) Update(ctx context.Context, inv *investment.Investment) error {
 	idb := toDBInvestment(inv)
-	return r.DB.WithContext(ctx).Table("investments").Where("id = ?", idb.Id).Updates(idb).Error
+	return conn(ctx, r.DB).Table("investments").Where("id = ?", idb.Id).Updates(idb).Error
 }
 
 func (r *InvestmentRepository) Delete(ctx context.Context, id ulid.ULID, userId ulid.ULID) error {
-	result := r.DB.WithContext(ctx).Table("investments").Where("id = ? AND user_id = ?", id.String(), userId.String()).
+	result := conn(ctx, r.DB).Table("investments").Where("id = ? AND user_id = ?", id.String(), userId.String()).
 		Delete(&investmentDB{})
 	if result.Error != nil {
 		return result.Error
@@ -102,7 +139,7 @@ func (r *InvestmentRepository) Delete(ctx context.Context, id ulid.ULID, userId
 
 func (r *InvestmentRepository) GetInvestmentByID(ctx context.Context, id ulid.ULID, userId ulid.ULID) (*investment.Investment, error) {
 	var row investmentDB
-	err := r.DB.WithContext(ctx).Table("investments").Where("id = ? AND user_id = ?", id.String(), userId.String()).
+	err := conn(ctx, r.DB).Table("investments").Where("id = ? AND user_id = ?", id.String(), userId.String()).
 		First(&row).Error
 	if err != nil {
 		return nil, err
@@ -111,13 +148,13 @@ func (r *InvestmentRepository) GetInvestmentByID(ctx context.Context, id ulid.UL
 }
 
 func (r *InvestmentRepository) GetByUserID(ctx context.Context, userId ulid.ULID, pagination *pkg.PaginationParams) ([]*investment.Investment, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("investments").Where("user_id = ?", userId.String())
+	baseQuery := conn(ctx, r.DB).Table("investments").Where("user_id = ?", userId.String())
 	return pkg.Paginate(baseQuery, pagination, "application_date DESC", toDomainInvestment)
 }
 
 func (r *InvestmentRepository) GetTotalBalance(ctx context.Context, userId ulid.ULID) (float64, error) {
 	var total float64
-	err := r.DB.WithContext(ctx).Table("investments").
+	err := conn(ctx, r.DB).Table("investments").
 		Where("user_id = ?", userId.String()).
 		Select("COALESCE(SUM(current_balance), 0)").
 		Scan(&total).Error
@@ -125,12 +162,12 @@ func (r *InvestmentRepository) GetTotalBalance(ctx context.Context, userId ulid.
 }
 
 func (r *InvestmentRepository) GetByType(ctx context.Context, userId ulid.ULID, investmentType investment.Types, pagination *pkg.PaginationParams) ([]*investment.Investment, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("investments").Where("user_id = ? AND type = ?", userId.String(), string(investmentType))
+	baseQuery := conn(ctx, r.DB).Table("investments").Where("user_id = ? AND type = ?", userId.String(), string(investmentType))
 	return pkg.Paginate(baseQuery, pagination, "application_date DESC", toDomainInvestment)
 }
 
 func (r *InvestmentRepository) UpdateBalanceAtomic(ctx context.Context, investmentID ulid.ULID, delta float64) error {
-	result := r.DB.WithContext(ctx).Table("investments").Where("id = ?", investmentID.String()).
+	result := conn(ctx, r.DB).Table("investments").Where("id = ?", investmentID.String()).
 		UpdateColumn("current_balance", gorm.Expr("current_balance + ?", delta)).
 		UpdateColumn("updated_at", time.Now())
 	if result.Error != nil {
@@ -141,3 +178,741 @@ func (r *InvestmentRepository) UpdateBalanceAtomic(ctx context.Context, investme
 	}
 	return nil
 }
+
+func (r *InvestmentRepository) ListPositions(ctx context.Context) ([]*investment.Investment, error) {
+	var rows []investmentDB
+	if err := conn(ctx, r.DB).Table("investments").
+		Where("ticker <> ''").
+		Order("ticker ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]*investment.Investment, 0, len(rows))
+	for i := range rows {
+		inv, err := toDomainInvestment(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, inv)
+	}
+	return out, nil
+}
+
+func (r *InvestmentRepository) ListAccruing(ctx context.Context) ([]*investment.Investment, error) {
+	var rows []investmentDB
+	if err := conn(ctx, r.DB).Table("investments").
+		Where("indexer <> ''").
+		Order("created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]*investment.Investment, 0, len(rows))
+	for i := range rows {
+		inv, err := toDomainInvestment(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, inv)
+	}
+	return out, nil
+}
+
+func (r *InvestmentRepository) GetByGoalID(ctx context.Context, goalID ulid.ULID, userId ulid.ULID) ([]*investment.Investment, error) {
+	var rows []investmentDB
+	if err := conn(ctx, r.DB).Table("investments").
+		Where("goal_id = ? AND user_id = ?", goalID.String(), userId.String()).
+		Order("created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]*investment.Investment, 0, len(rows))
+	for i := range rows {
+		inv, err := toDomainInvestment(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, inv)
+	}
+	return out, nil
+}
+
+func (r *InvestmentRepository) SetGoal(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID, goalID *ulid.ULID) error {
+	var value interface{}
+	if goalID != nil {
+		value = goalID.String()
+	}
+	result := conn(ctx, r.DB).Table("investments").
+		Where("id = ? AND user_id = ?", investmentID.String(), userId.String()).
+		Updates(map[string]interface{}{
+			"goal_id":    value,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *InvestmentRepository) UpdateFields(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID, fields map[string]interface{}) error {
+	result := conn(ctx, r.DB).Table("investments").
+		Where("id = ? AND user_id = ?", investmentID.String(), userId.String()).
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+type lotDB struct {
+	Id            string    `gorm:"type:varchar(26);primaryKey"`
+	InvestmentId  string    `gorm:"type:varchar(26);index;not null"`
+	UserId        string    `gorm:"type:varchar(26);index;not null"`
+	AccountId     string    `gorm:"type:varchar(26);not null"`
+	TransactionId *string   `gorm:"type:varchar(26);index"`
+	Side          string    `gorm:"type:varchar(4);not null"`
+	Quantity      float64   `gorm:"not null"`
+	UnitPrice     float64   `gorm:"not null"`
+	Fees          float64   `gorm:"not null;default:0"`
+	TradeDate     time.Time `gorm:"type:date;not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (lotDB) TableName() string {
+	return "investment_lots"
+}
+
+func toDomainLot(ldb *lotDB) (*investment.Lot, error) {
+	id, err := pkg.ParseULID(ldb.Id)
+	if err != nil {
+		return nil, err
+	}
+	investmentID, err := pkg.ParseULID(ldb.InvestmentId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(ldb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := pkg.ParseULID(ldb.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	var transactionID *ulid.ULID
+	if ldb.TransactionId != nil && *ldb.TransactionId != "" {
+		tid, err := pkg.ParseULID(*ldb.TransactionId)
+		if err == nil {
+			transactionID = &tid
+		}
+	}
+
+	return &investment.Lot{
+		Id:            id,
+		InvestmentId:  investmentID,
+		UserId:        userID,
+		AccountId:     accountID,
+		TransactionId: transactionID,
+		Side:          investment.LotSide(ldb.Side),
+		Quantity:      ldb.Quantity,
+		UnitPrice:     ldb.UnitPrice,
+		Fees:          ldb.Fees,
+		TradeDate:     ldb.TradeDate,
+		CreatedAt:     ldb.CreatedAt,
+	}, nil
+}
+
+func (r *InvestmentRepository) CreateLot(ctx context.Context, lot *investment.Lot) error {
+	var transactionID *string
+	if lot.TransactionId != nil {
+		s := lot.TransactionId.String()
+		transactionID = &s
+	}
+	ldb := &lotDB{
+		Id:            lot.Id.String(),
+		InvestmentId:  lot.InvestmentId.String(),
+		UserId:        lot.UserId.String(),
+		AccountId:     lot.AccountId.String(),
+		TransactionId: transactionID,
+		Side:          string(lot.Side),
+		Quantity:      lot.Quantity,
+		UnitPrice:     lot.UnitPrice,
+		Fees:          lot.Fees,
+		TradeDate:     lot.TradeDate,
+		CreatedAt:     lot.CreatedAt,
+	}
+	return conn(ctx, r.DB).Create(ldb).Error
+}
+
+func (r *InvestmentRepository) GetLots(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) ([]*investment.Lot, error) {
+	var rows []lotDB
+	if err := conn(ctx, r.DB).
+		Where("investment_id = ? AND user_id = ?", investmentID.String(), userId.String()).
+		Order("trade_date ASC, created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	lots := make([]*investment.Lot, 0, len(rows))
+	for i := range rows {
+		lot, err := toDomainLot(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	return lots, nil
+}
+
+func (r *InvestmentRepository) GetLotByTransactionID(ctx context.Context, transactionID ulid.ULID, userId ulid.ULID) (*investment.Lot, error) {
+	var row lotDB
+	if err := conn(ctx, r.DB).
+		Where("transaction_id = ? AND user_id = ?", transactionID.String(), userId.String()).
+		First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainLot(&row)
+}
+
+func (r *InvestmentRepository) DeleteLot(ctx context.Context, lotID ulid.ULID) error {
+	return conn(ctx, r.DB).Where("id = ?", lotID.String()).Delete(&lotDB{}).Error
+}
+
+func (r *InvestmentRepository) DeleteLotsByInvestment(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("investment_id = ? AND user_id = ?", investmentID.String(), userId.String()).
+		Delete(&lotDB{}).Error
+}
+
+func (r *InvestmentRepository) SumMonthlySales(ctx context.Context, userId ulid.ULID, investmentType investment.Types, from, to time.Time) (float64, error) {
+	var total float64
+	err := conn(ctx, r.DB).
+		Table("investment_lots AS l").
+		Joins("JOIN investments AS i ON i.id = l.investment_id").
+		Where("l.user_id = ? AND l.side = ? AND i.type = ? AND l.trade_date >= ? AND l.trade_date < ?",
+			userId.String(), string(investment.LotSell), string(investmentType), from, to).
+		Select("COALESCE(SUM(l.quantity * l.unit_price), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+type redemptionDB struct {
+	Id                     string    `gorm:"type:varchar(26);primaryKey"`
+	UserId                 string    `gorm:"type:varchar(26);not null"`
+	InvestmentId           string    `gorm:"type:varchar(26);not null"`
+	TransactionId          string    `gorm:"type:varchar(26);not null"`
+	IncomeTaxTransactionId *string   `gorm:"type:varchar(26)"`
+	IOFTransactionId       *string   `gorm:"column:iof_transaction_id;type:varchar(26)"`
+	Kind                   string    `gorm:"type:varchar(10);not null"`
+	InvestmentType         string    `gorm:"type:varchar(20);not null"`
+	Gross                  float64   `gorm:"not null"`
+	CostBasis              float64   `gorm:"not null"`
+	Gain                   float64   `gorm:"not null"`
+	DayTradeGain           float64   `gorm:"not null;default:0"`
+	IOF                    float64   `gorm:"column:iof;not null;default:0"`
+	IncomeTax              float64   `gorm:"not null;default:0"`
+	Net                    float64   `gorm:"not null"`
+	Exempt                 bool      `gorm:"not null;default:false"`
+	Date                   time.Time `gorm:"type:date;not null"`
+	CreatedAt              time.Time `gorm:"not null"`
+}
+
+func (redemptionDB) TableName() string {
+	return "investment_redemptions"
+}
+
+func toDomainRedemption(rdb *redemptionDB) (*investment.Redemption, error) {
+	id, err := pkg.ParseULID(rdb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(rdb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	investmentID, err := pkg.ParseULID(rdb.InvestmentId)
+	if err != nil {
+		return nil, err
+	}
+	transactionID, err := pkg.ParseULID(rdb.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &investment.Redemption{
+		Id:                     id,
+		UserId:                 userID,
+		InvestmentId:           investmentID,
+		TransactionId:          transactionID,
+		IncomeTaxTransactionId: parseOptionalULID(rdb.IncomeTaxTransactionId),
+		IOFTransactionId:       parseOptionalULID(rdb.IOFTransactionId),
+		Kind:                   investment.RedemptionKind(rdb.Kind),
+		InvestmentType:         investment.Types(rdb.InvestmentType),
+		Gross:                  rdb.Gross,
+		CostBasis:              rdb.CostBasis,
+		Gain:                   rdb.Gain,
+		DayTradeGain:           rdb.DayTradeGain,
+		IOF:                    rdb.IOF,
+		IncomeTax:              rdb.IncomeTax,
+		Net:                    rdb.Net,
+		Exempt:                 rdb.Exempt,
+		Date:                   rdb.Date,
+		CreatedAt:              rdb.CreatedAt,
+	}, nil
+}
+
+func parseOptionalULID(value *string) *ulid.ULID {
+	if value == nil || *value == "" {
+		return nil
+	}
+	id, err := pkg.ParseULID(*value)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+func optionalULIDString(id *ulid.ULID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func (r *InvestmentRepository) CreateRedemption(ctx context.Context, redemption *investment.Redemption) error {
+	rdb := &redemptionDB{
+		Id:                     redemption.Id.String(),
+		UserId:                 redemption.UserId.String(),
+		InvestmentId:           redemption.InvestmentId.String(),
+		TransactionId:          redemption.TransactionId.String(),
+		IncomeTaxTransactionId: optionalULIDString(redemption.IncomeTaxTransactionId),
+		IOFTransactionId:       optionalULIDString(redemption.IOFTransactionId),
+		Kind:                   string(redemption.Kind),
+		InvestmentType:         string(redemption.InvestmentType),
+		Gross:                  redemption.Gross,
+		CostBasis:              redemption.CostBasis,
+		Gain:                   redemption.Gain,
+		DayTradeGain:           redemption.DayTradeGain,
+		IOF:                    redemption.IOF,
+		IncomeTax:              redemption.IncomeTax,
+		Net:                    redemption.Net,
+		Exempt:                 redemption.Exempt,
+		Date:                   redemption.Date,
+		CreatedAt:              time.Now(),
+	}
+	return conn(ctx, r.DB).Create(rdb).Error
+}
+
+func (r *InvestmentRepository) GetRedemptionByTransactionID(ctx context.Context, transactionID ulid.ULID, userId ulid.ULID) (*investment.Redemption, error) {
+	var row redemptionDB
+	if err := conn(ctx, r.DB).
+		Where("transaction_id = ? AND user_id = ?", transactionID.String(), userId.String()).
+		First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainRedemption(&row)
+}
+
+func (r *InvestmentRepository) ListUserRedemptions(ctx context.Context, userId ulid.ULID, from, to time.Time) ([]*investment.Redemption, error) {
+	var rows []redemptionDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ? AND date >= ? AND date < ?", userId.String(), from, to).
+		Order("date ASC, created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	redemptions := make([]*investment.Redemption, 0, len(rows))
+	for i := range rows {
+		redemption, err := toDomainRedemption(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, redemption)
+	}
+	return redemptions, nil
+}
+
+func (r *InvestmentRepository) DeleteRedemption(ctx context.Context, redemptionID ulid.ULID) error {
+	return conn(ctx, r.DB).Where("id = ?", redemptionID.String()).Delete(&redemptionDB{}).Error
+}
+
+func (r *InvestmentRepository) DeleteRedemptionsByInvestment(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("investment_id = ? AND user_id = ?", investmentID.String(), userId.String()).
+		Delete(&redemptionDB{}).Error
+}
+
+type incomeEventDB struct {
+	Id            string     `gorm:"type:varchar(26);primaryKey"`
+	UserId        string     `gorm:"type:varchar(26);not null"`
+	InvestmentId  string     `gorm:"type:varchar(26);not null"`
+	AccountId     string     `gorm:"type:varchar(26);not null"`
+	TransactionId *string    `gorm:"type:varchar(26)"`
+	Type          string     `gorm:"type:varchar(20);not null"`
+	Status        string     `gorm:"type:varchar(10);not null"`
+	AmountPerUnit float64    `gorm:"not null;default:0"`
+	GrossAmount   float64    `gorm:"not null"`
+	WithheldTax   float64    `gorm:"not null;default:0"`
+	NetAmount     float64    `gorm:"not null"`
+	ExDate        *time.Time `gorm:"type:date"`
+	PaymentDate   time.Time  `gorm:"type:date;not null"`
+	Description   string     `gorm:"type:varchar(255)"`
+	CreatedAt     time.Time  `gorm:"not null"`
+	UpdatedAt     time.Time  `gorm:"not null"`
+}
+
+func (incomeEventDB) TableName() string {
+	return "investment_income_events"
+}
+
+func toDomainIncomeEvent(edb *incomeEventDB) (*investment.IncomeEvent, error) {
+	id, err := pkg.ParseULID(edb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(edb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	investmentID, err := pkg.ParseULID(edb.InvestmentId)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := pkg.ParseULID(edb.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &investment.IncomeEvent{
+		Id:            id,
+		UserId:        userID,
+		InvestmentId:  investmentID,
+		AccountId:     accountID,
+		TransactionId: parseOptionalULID(edb.TransactionId),
+		Type:          investment.IncomeType(edb.Type),
+		Status:        investment.IncomeStatus(edb.Status),
+		AmountPerUnit: edb.AmountPerUnit,
+		GrossAmount:   edb.GrossAmount,
+		WithheldTax:   edb.WithheldTax,
+		NetAmount:     edb.NetAmount,
+		ExDate:        edb.ExDate,
+		PaymentDate:   edb.PaymentDate,
+		Description:   edb.Description,
+		CreatedAt:     edb.CreatedAt,
+		UpdatedAt:     edb.UpdatedAt,
+	}, nil
+}
+
+func toIncomeEventDB(event *investment.IncomeEvent) *incomeEventDB {
+	return &incomeEventDB{
+		Id:            event.Id.String(),
+		UserId:        event.UserId.String(),
+		InvestmentId:  event.InvestmentId.String(),
+		AccountId:     event.AccountId.String(),
+		TransactionId: optionalULIDString(event.TransactionId),
+		Type:          string(event.Type),
+		Status:        string(event.Status),
+		AmountPerUnit: event.AmountPerUnit,
+		GrossAmount:   event.GrossAmount,
+		WithheldTax:   event.WithheldTax,
+		NetAmount:     event.NetAmount,
+		ExDate:        event.ExDate,
+		PaymentDate:   event.PaymentDate,
+		Description:   event.Description,
+		CreatedAt:     event.CreatedAt,
+		UpdatedAt:     event.UpdatedAt,
+	}
+}
+
+func toDomainIncomeEvents(rows []incomeEventDB) ([]*investment.IncomeEvent, error) {
+	events := make([]*investment.IncomeEvent, 0, len(rows))
+	for i := range rows {
+		event, err := toDomainIncomeEvent(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *InvestmentRepository) CreateIncomeEvent(ctx context.Context, event *investment.IncomeEvent) error {
+	return conn(ctx, r.DB).Create(toIncomeEventDB(event)).Error
+}
+
+func (r *InvestmentRepository) UpdateIncomeEvent(ctx context.Context, event *investment.IncomeEvent) error {
+	return conn(ctx, r.DB).
+		Model(&incomeEventDB{}).
+		Where("id = ?", event.Id.String()).
+		Updates(map[string]interface{}{
+			"status":         string(event.Status),
+			"transaction_id": optionalULIDString(event.TransactionId),
+			"payment_date":   event.PaymentDate,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+func (r *InvestmentRepository) GetIncomeEvent(ctx context.Context, eventID ulid.ULID, userId ulid.ULID) (*investment.IncomeEvent, error) {
+	var row incomeEventDB
+	if err := conn(ctx, r.DB).
+		Where("id = ? AND user_id = ?", eventID.String(), userId.String()).
+		First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainIncomeEvent(&row)
+}
+
+func (r *InvestmentRepository) GetIncomeEventByTransactionID(ctx context.Context, transactionID ulid.ULID, userId ulid.ULID) (*investment.IncomeEvent, error) {
+	var row incomeEventDB
+	if err := conn(ctx, r.DB).
+		Where("transaction_id = ? AND user_id = ?", transactionID.String(), userId.String()).
+		First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainIncomeEvent(&row)
+}
+
+func (r *InvestmentRepository) ListIncomeEvents(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) ([]*investment.IncomeEvent, error) {
+	var rows []incomeEventDB
+	if err := conn(ctx, r.DB).
+		Where("investment_id = ? AND user_id = ?", investmentID.String(), userId.String()).
+		Order("payment_date DESC, created_at DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toDomainIncomeEvents(rows)
+}
+
+func (r *InvestmentRepository) ListUserIncomeEvents(ctx context.Context, userId ulid.ULID, status investment.IncomeStatus, from, to time.Time) ([]*investment.IncomeEvent, error) {
+	var rows []incomeEventDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ? AND status = ? AND payment_date >= ? AND payment_date < ?", userId.String(), string(status), from, to).
+		Order("payment_date ASC, created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toDomainIncomeEvents(rows)
+}
+
+func (r *InvestmentRepository) ListDueIncomeEvents(ctx context.Context, until time.Time) ([]*investment.IncomeEvent, error) {
+	var rows []incomeEventDB
+	if err := conn(ctx, r.DB).
+		Where("status = ? AND payment_date <= ?", string(investment.IncomeAnnounced), until).
+		Order("payment_date ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toDomainIncomeEvents(rows)
+}
+
+func (r *InvestmentRepository) DeleteIncomeEvent(ctx context.Context, eventID ulid.ULID) error {
+	return conn(ctx, r.DB).Where("id = ?", eventID.String()).Delete(&incomeEventDB{}).Error
+}
+
+func (r *InvestmentRepository) DeleteIncomeEventsByInvestment(ctx context.Context, investmentID ulid.ULID, userId ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("investment_id = ? AND user_id = ?", investmentID.String(), userId.String()).
+		Delete(&incomeEventDB{}).Error
+}
+
+type allocationTargetDB struct {
+	Id        string    `gorm:"type:varchar(26);primaryKey"`
+	UserId    string    `gorm:"type:varchar(26);not null"`
+	Class     string    `gorm:"type:varchar(40);not null"`
+	Weight    float64   `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (allocationTargetDB) TableName() string {
+	return "investment_allocation_targets"
+}
+
+func toDomainAllocationTarget(adb *allocationTargetDB) (*investment.AllocationTarget, error) {
+	id, err := pkg.ParseULID(adb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(adb.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &investment.AllocationTarget{
+		Id:        id,
+		UserId:    userID,
+		Class:     adb.Class,
+		Weight:    adb.Weight,
+		CreatedAt: adb.CreatedAt,
+		UpdatedAt: adb.UpdatedAt,
+	}, nil
+}
+
+func (r *InvestmentRepository) ListAllocationTargets(ctx context.Context, userId ulid.ULID) ([]*investment.AllocationTarget, error) {
+	var rows []allocationTargetDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ?", userId.String()).
+		Order("weight DESC, class ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	targets := make([]*investment.AllocationTarget, 0, len(rows))
+	for i := range rows {
+		target, err := toDomainAllocationTarget(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func (r *InvestmentRepository) ReplaceAllocationTargets(ctx context.Context, userId ulid.ULID, targets []*investment.AllocationTarget) error {
+	return conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId.String()).Delete(&allocationTargetDB{}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, target := range targets {
+			target.CreatedAt = now
+			target.UpdatedAt = now
+			row := &allocationTargetDB{
+				Id:        target.Id.String(),
+				UserId:    target.UserId.String(),
+				Class:     target.Class,
+				Weight:    target.Weight,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := tx.Create(row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type brokerageNoteDB struct {
+	Id             string    `gorm:"type:varchar(26);primaryKey"`
+	UserId         string    `gorm:"type:varchar(26);not null"`
+	AccountId      string    `gorm:"type:varchar(26);not null"`
+	Number         string    `gorm:"type:varchar(30);not null"`
+	TradeDate      time.Time `gorm:"type:date;not null"`
+	Status         string    `gorm:"type:varchar(10);not null"`
+	Trades         int       `gorm:"not null"`
+	ImportedTrades int       `gorm:"not null;default:0"`
+	GrossBuys      float64   `gorm:"not null;default:0"`
+	GrossSells     float64   `gorm:"not null;default:0"`
+	Fees           float64   `gorm:"not null;default:0"`
+	IRRF           float64   `gorm:"column:irrf;not null;default:0"`
+	CreatedAt      time.Time `gorm:"not null"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
+
+func (brokerageNoteDB) TableName() string {
+	return "brokerage_notes"
+}
+
+func toDomainBrokerageNote(ndb *brokerageNoteDB) (*investment.BrokerageNote, error) {
+	id, err := pkg.ParseULID(ndb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(ndb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := pkg.ParseULID(ndb.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &investment.BrokerageNote{
+		Id:             id,
+		UserId:         userID,
+		AccountId:      accountID,
+		Number:         ndb.Number,
+		TradeDate:      ndb.TradeDate,
+		Status:         investment.NoteStatus(ndb.Status),
+		Trades:         ndb.Trades,
+		ImportedTrades: ndb.ImportedTrades,
+		GrossBuys:      ndb.GrossBuys,
+		GrossSells:     ndb.GrossSells,
+		Fees:           ndb.Fees,
+		IRRF:           ndb.IRRF,
+		CreatedAt:      ndb.CreatedAt,
+		UpdatedAt:      ndb.UpdatedAt,
+	}, nil
+}
+
+func (r *InvestmentRepository) CreateBrokerageNote(ctx context.Context, note *investment.BrokerageNote) error {
+	return conn(ctx, r.DB).Create(&brokerageNoteDB{
+		Id:             note.Id.String(),
+		UserId:         note.UserId.String(),
+		AccountId:      note.AccountId.String(),
+		Number:         note.Number,
+		TradeDate:      note.TradeDate,
+		Status:         string(note.Status),
+		Trades:         note.Trades,
+		ImportedTrades: note.ImportedTrades,
+		GrossBuys:      note.GrossBuys,
+		GrossSells:     note.GrossSells,
+		Fees:           note.Fees,
+		IRRF:           note.IRRF,
+		CreatedAt:      note.CreatedAt,
+		UpdatedAt:      note.UpdatedAt,
+	}).Error
+}
+
+func (r *InvestmentRepository) GetBrokerageNote(ctx context.Context, userId ulid.ULID, number string) (*investment.BrokerageNote, error) {
+	var row brokerageNoteDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ? AND number = ?", userId.String(), number).
+		First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainBrokerageNote(&row)
+}
+
+func (r *InvestmentRepository) UpdateBrokerageNoteProgress(ctx context.Context, noteID ulid.ULID, importedTrades int, status investment.NoteStatus) error {
+	return conn(ctx, r.DB).
+		Model(&brokerageNoteDB{}).
+		Where("id = ?", noteID.String()).
+		Updates(map[string]interface{}{
+			"imported_trades": importedTrades,
+			"status":          string(status),
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func (r *InvestmentRepository) ListBrokerageNotes(ctx context.Context, userId ulid.ULID) ([]*investment.BrokerageNote, error) {
+	var rows []brokerageNoteDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ?", userId.String()).
+		Order("trade_date DESC, number DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	notes := make([]*investment.BrokerageNote, 0, len(rows))
+	for i := range rows {
+		note, err := toDomainBrokerageNote(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
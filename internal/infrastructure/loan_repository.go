@@ -0,0 +1,278 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/loan"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+type LoanRepository struct {
+	DB *gorm.DB
+}
+
+var _ loan.LoanRepository = (*LoanRepository)(nil)
+
+type loanDB struct {
+	Id                 string    `gorm:"type:varchar(26);primaryKey"`
+	UserId             string    `gorm:"type:varchar(26);not null"`
+	AccountId          string    `gorm:"type:varchar(26);not null"`
+	CategoryId         *string   `gorm:"type:varchar(26)"`
+	Name               string    `gorm:"type:varchar(100);not null"`
+	Kind               string    `gorm:"type:varchar(10);not null"`
+	System             string    `gorm:"type:varchar(5);not null"`
+	Principal          float64   `gorm:"type:decimal(15,2);not null"`
+	InterestRate       float64   `gorm:"type:decimal(9,4);not null"`
+	RatePeriod         string    `gorm:"type:varchar(10);not null"`
+	TermMonths         int       `gorm:"not null"`
+	FirstDueDate       time.Time `gorm:"type:date;not null"`
+	OutstandingBalance float64   `gorm:"type:decimal(15,2);not null"`
+	PaidInstallments   int       `gorm:"not null"`
+	Status             string    `gorm:"type:varchar(10);not null"`
+	CreatedAt          time.Time `gorm:"not null"`
+	UpdatedAt          time.Time `gorm:"not null"`
+}
+
+func (loanDB) TableName() string {
+	return "loans"
+}
+
+type loanPaymentDB struct {
+	Id            string    `gorm:"type:varchar(26);primaryKey"`
+	LoanId        string    `gorm:"type:varchar(26);not null"`
+	UserId        string    `gorm:"type:varchar(26);not null"`
+	TransactionId string    `gorm:"type:varchar(26);not null"`
+	Kind          string    `gorm:"type:varchar(12);not null"`
+	Number        int       `gorm:"not null"`
+	Amount        float64   `gorm:"type:decimal(15,2);not null"`
+	Interest      float64   `gorm:"type:decimal(15,2);not null"`
+	Amortization  float64   `gorm:"type:decimal(15,2);not null"`
+	Strategy      string    `gorm:"type:varchar(20)"`
+	Date          time.Time `gorm:"type:date;not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (loanPaymentDB) TableName() string {
+	return "loan_payments"
+}
+
+func toDomainLoan(ldb *loanDB) (*loan.Loan, error) {
+	id, err := pkg.ParseULID(ldb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(ldb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := pkg.ParseULID(ldb.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loan.Loan{
+		Id:                 id,
+		UserId:             userID,
+		AccountId:          accountID,
+		CategoryId:         parseOptionalULID(ldb.CategoryId),
+		Name:               ldb.Name,
+		Kind:               loan.Kind(ldb.Kind),
+		System:             loan.System(ldb.System),
+		Principal:          ldb.Principal,
+		InterestRate:       ldb.InterestRate,
+		RatePeriod:         loan.RatePeriod(ldb.RatePeriod),
+		TermMonths:         ldb.TermMonths,
+		FirstDueDate:       ldb.FirstDueDate,
+		OutstandingBalance: ldb.OutstandingBalance,
+		PaidInstallments:   ldb.PaidInstallments,
+		Status:             loan.Status(ldb.Status),
+		CreatedAt:          ldb.CreatedAt,
+		UpdatedAt:          ldb.UpdatedAt,
+	}, nil
+}
+
+func toDBLoan(l *loan.Loan) *loanDB {
+	return &loanDB{
+		Id:                 l.Id.String(),
+		UserId:             l.UserId.String(),
+		AccountId:          l.AccountId.String(),
+		CategoryId:         optionalULIDString(l.CategoryId),
+		Name:               l.Name,
+		Kind:               string(l.Kind),
+		System:             string(l.System),
+		Principal:          l.Principal,
+		InterestRate:       l.InterestRate,
+		RatePeriod:         string(l.RatePeriod),
+		TermMonths:         l.TermMonths,
+		FirstDueDate:       l.FirstDueDate,
+		OutstandingBalance: l.OutstandingBalance,
+		PaidInstallments:   l.PaidInstallments,
+		Status:             string(l.Status),
+		CreatedAt:          l.CreatedAt,
+		UpdatedAt:          l.UpdatedAt,
+	}
+}
+
+func toDomainLoanPayment(pdb *loanPaymentDB) (*loan.Payment, error) {
+	id, err := pkg.ParseULID(pdb.Id)
+	if err != nil {
+		return nil, err
+	}
+	loanID, err := pkg.ParseULID(pdb.LoanId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(pdb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	transactionID, err := pkg.ParseULID(pdb.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loan.Payment{
+		Id:            id,
+		LoanId:        loanID,
+		UserId:        userID,
+		TransactionId: transactionID,
+		Kind:          loan.PaymentKind(pdb.Kind),
+		Number:        pdb.Number,
+		Amount:        pdb.Amount,
+		Interest:      pdb.Interest,
+		Amortization:  pdb.Amortization,
+		Strategy:      loan.ExtraStrategy(pdb.Strategy),
+		Date:          pdb.Date,
+		CreatedAt:     pdb.CreatedAt,
+	}, nil
+}
+
+func toDBLoanPayment(p *loan.Payment) *loanPaymentDB {
+	return &loanPaymentDB{
+		Id:            p.Id.String(),
+		LoanId:        p.LoanId.String(),
+		UserId:        p.UserId.String(),
+		TransactionId: p.TransactionId.String(),
+		Kind:          string(p.Kind),
+		Number:        p.Number,
+		Amount:        p.Amount,
+		Interest:      p.Interest,
+		Amortization:  p.Amortization,
+		Strategy:      string(p.Strategy),
+		Date:          p.Date,
+		CreatedAt:     p.CreatedAt,
+	}
+}
+
+func (r *LoanRepository) Create(ctx context.Context, l *loan.Loan) error {
+	return conn(ctx, r.DB).Create(toDBLoan(l)).Error
+}
+
+func (r *LoanRepository) Update(ctx context.Context, l *loan.Loan) error {
+	return conn(ctx, r.DB).Save(toDBLoan(l)).Error
+}
+
+func (r *LoanRepository) Delete(ctx context.Context, loanID, userID ulid.ULID) error {
+	result := conn(ctx, r.DB).
+		Where("id = ? AND user_id = ?", loanID.String(), userID.String()).
+		Delete(&loanDB{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *LoanRepository) GetByID(ctx context.Context, loanID, userID ulid.ULID) (*loan.Loan, error) {
+	var ldb loanDB
+	if err := conn(ctx, r.DB).
+		Where("id = ? AND user_id = ?", loanID.String(), userID.String()).
+		First(&ldb).Error; err != nil {
+		return nil, err
+	}
+	return toDomainLoan(&ldb)
+}
+
+func (r *LoanRepository) ListByUser(ctx context.Context, userID ulid.ULID) ([]*loan.Loan, error) {
+	var rows []loanDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ?", userID.String()).
+		Order("status ASC, first_due_date ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	loans := make([]*loan.Loan, 0, len(rows))
+	for i := range rows {
+		l, err := toDomainLoan(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, l)
+	}
+	return loans, nil
+}
+
+func (r *LoanRepository) UpdateProgress(ctx context.Context, loanID ulid.ULID, outstanding float64, paidInstallments int, status loan.Status) error {
+	return conn(ctx, r.DB).
+		Model(&loanDB{}).
+		Where("id = ?", loanID.String()).
+		Updates(map[string]interface{}{
+			"outstanding_balance": outstanding,
+			"paid_installments":   paidInstallments,
+			"status":              string(status),
+			"updated_at":          time.Now(),
+		}).Error
+}
+
+func (r *LoanRepository) CreatePayment(ctx context.Context, payment *loan.Payment) error {
+	return conn(ctx, r.DB).Create(toDBLoanPayment(payment)).Error
+}
+
+func (r *LoanRepository) ListPayments(ctx context.Context, loanID, userID ulid.ULID) ([]*loan.Payment, error) {
+	var rows []loanPaymentDB
+	if err := conn(ctx, r.DB).
+		Where("loan_id = ? AND user_id = ?", loanID.String(), userID.String()).
+		Order("date ASC, created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]*loan.Payment, 0, len(rows))
+	for i := range rows {
+		p, err := toDomainLoanPayment(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, nil
+}
+
+func (r *LoanRepository) GetPaymentByTransactionID(ctx context.Context, transactionID, userID ulid.ULID) (*loan.Payment, error) {
+	var pdb loanPaymentDB
+	if err := conn(ctx, r.DB).
+		Where("transaction_id = ? AND user_id = ?", transactionID.String(), userID.String()).
+		First(&pdb).Error; err != nil {
+		return nil, err
+	}
+	return toDomainLoanPayment(&pdb)
+}
+
+func (r *LoanRepository) DeletePayment(ctx context.Context, paymentID ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("id = ?", paymentID.String()).
+		Delete(&loanPaymentDB{}).Error
+}
+
+func (r *LoanRepository) DeletePaymentsByLoan(ctx context.Context, loanID, userID ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("loan_id = ? AND user_id = ?", loanID.String(), userID.String()).
+		Delete(&loanPaymentDB{}).Error
+}
@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/market"
+)
+
+// FilePriceProvider lê cotações e índices de arquivos CSV em um diretório local:
+//
+//	quotes.csv       symbol,date,price    (ex.: PETR4,2026-10-16,38.50)
+//	<indice>.csv     date,value           (ex.: cdi.csv com 2026-10-16,0.0551; ibov.csv com 2026-10-16,131250.40)
+//
+// A primeira linha de cada arquivo é tratada como cabeçalho.
+type FilePriceProvider struct {
+	Dir string
+}
+
+var _ market.PriceProvider = (*FilePriceProvider)(nil)
+
+func NewFilePriceProvider(dir string) *FilePriceProvider {
+	return &FilePriceProvider{Dir: dir}
+}
+
+func (p *FilePriceProvider) Name() string {
+	return "file"
+}
+
+func (p *FilePriceProvider) GetQuote(ctx context.Context, symbol string) (*market.Quote, error) {
+	records, err := readCSV(filepath.Join(p.Dir, "quotes.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *market.Quote
+	for _, record := range records {
+		if len(record) < 3 || !strings.EqualFold(strings.TrimSpace(record[0]), symbol) {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("quotes.csv: data invalida %q: %w", record[1], err)
+		}
+		price, err := parseDecimal(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("quotes.csv: preco invalido %q: %w", record[2], err)
+		}
+		if latest == nil || date.After(latest.Date) {
+			latest = &market.Quote{Symbol: strings.ToUpper(symbol), Price: price, Date: date, Source: p.Name()}
+		}
+	}
+
+	if latest == nil {
+		return nil, market.ErrSymbolNotFound
+	}
+	return latest, nil
+}
+
+func (p *FilePriceProvider) GetIndexSeries(ctx context.Context, index string, from, to time.Time) ([]market.IndexPoint, error) {
+	records, err := readCSV(filepath.Join(p.Dir, strings.ToLower(index)+".csv"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	points := make([]market.IndexPoint, 0, len(records))
+	for _, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("%s.csv: data invalida %q: %w", strings.ToLower(index), record[0], err)
+		}
+		if date.Before(from) || date.After(to) {
+			continue
+		}
+		value, err := parseDecimal(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s.csv: valor invalido %q: %w", strings.ToLower(index), record[1], err)
+		}
+		points = append(points, market.IndexPoint{Index: strings.ToUpper(index), Date: date, Value: value})
+	}
+	return points, nil
+}
+
+func readCSV(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records [][]string
+	first := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			first = false
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseDecimal aceita tanto "38.50" quanto "38,50"
+func parseDecimal(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, ".") {
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	return strconv.ParseFloat(value, 64)
+}
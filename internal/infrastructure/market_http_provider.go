@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/market"
+)
+
+// HTTPPriceProvider consulta um serviço de cotações com a API:
+//
+//	GET {base}/quotes/{symbol}                       -> {"symbol": "PETR4", "price": 38.5, "date": "2026-10-16"}
+//	GET {base}/indexes/{index}?from=AAAA-MM-DD&to=... -> [{"date": "2026-10-16", "value": 0.0551}]
+//
+// Quando configurado, o token é enviado no header Authorization como Bearer.
+type HTTPPriceProvider struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+var _ market.PriceProvider = (*HTTPPriceProvider)(nil)
+
+func NewHTTPPriceProvider(baseURL, token string, timeout time.Duration) *HTTPPriceProvider {
+	return &HTTPPriceProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type httpQuote struct {
+	Symbol string  `json:"symbol"`
+	Price  float64 `json:"price"`
+	Date   string  `json:"date"`
+}
+
+type httpIndexPoint struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+func (p *HTTPPriceProvider) Name() string {
+	return "http"
+}
+
+func (p *HTTPPriceProvider) GetQuote(ctx context.Context, symbol string) (*market.Quote, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	var body httpQuote
+	if err := p.get(ctx, "/quotes/"+url.PathEscape(symbol), nil, &body); err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if body.Date != "" {
+		parsed, err := time.Parse("2006-01-02", body.Date)
+		if err != nil {
+			return nil, fmt.Errorf("data invalida na cotacao de %s: %w", symbol, err)
+		}
+		date = parsed
+	}
+
+	return &market.Quote{
+		Symbol: symbol,
+		Price:  body.Price,
+		Date:   date,
+		Source: p.Name(),
+	}, nil
+}
+
+func (p *HTTPPriceProvider) GetIndexSeries(ctx context.Context, index string, from, to time.Time) ([]market.IndexPoint, error) {
+	query := url.Values{}
+	query.Set("from", from.Format("2006-01-02"))
+	query.Set("to", to.Format("2006-01-02"))
+
+	var body []httpIndexPoint
+	if err := p.get(ctx, "/indexes/"+url.PathEscape(strings.ToUpper(index)), query, &body); err != nil {
+		return nil, err
+	}
+
+	points := make([]market.IndexPoint, 0, len(body))
+	for _, item := range body {
+		date, err := time.Parse("2006-01-02", item.Date)
+		if err != nil {
+			return nil, fmt.Errorf("data invalida na serie %s: %w", index, err)
+		}
+		points = append(points, market.IndexPoint{Index: strings.ToUpper(index), Date: date, Value: item.Value})
+	}
+	return points, nil
+}
+
+func (p *HTTPPriceProvider) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	endpoint := p.BaseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return market.ErrSymbolNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("provedor de cotacoes respondeu %d para %s", resp.StatusCode, path)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
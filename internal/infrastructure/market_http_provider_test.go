@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"Fynance/internal/domain/market"
+)
+
+func TestHTTPPriceProviderGetQuote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/quotes/PETR4" {
+			t.Errorf("path = %q, want /quotes/PETR4", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want Bearer secret", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"symbol": "PETR4", "price": 38.5, "date": "2026-10-16"}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPPriceProvider(server.URL+"/", "secret", time.Second)
+	quote, err := provider.GetQuote(context.Background(), " petr4 ")
+	if err != nil {
+		t.Fatalf("GetQuote: %v", err)
+	}
+
+	if quote.Symbol != "PETR4" {
+		t.Errorf("Symbol = %q, want PETR4", quote.Symbol)
+	}
+	if quote.Price != 38.5 {
+		t.Errorf("Price = %v, want 38.5", quote.Price)
+	}
+	if want := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC); !quote.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", quote.Date, want)
+	}
+	if quote.Source != "http" {
+		t.Errorf("Source = %q, want http", quote.Source)
+	}
+}
+
+func TestHTTPPriceProviderGetIndexSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/indexes/CDI" {
+			t.Errorf("path = %q, want /indexes/CDI", r.URL.Path)
+		}
+		if from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to"); from != "2026-10-01" || to != "2026-10-02" {
+			t.Errorf("from/to = %s/%s, want 2026-10-01/2026-10-02", from, to)
+		}
+		_, _ = w.Write([]byte(`[{"date": "2026-10-01", "value": 0.0551}, {"date": "2026-10-02", "value": 0.0552}]`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPPriceProvider(server.URL, "", time.Second)
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	points, err := provider.GetIndexSeries(context.Background(), "cdi", from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetIndexSeries: %v", err)
+	}
+
+	if len(points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(points))
+	}
+	if points[0].Index != "CDI" || points[0].Value != 0.0551 || !points[0].Date.Equal(from) {
+		t.Errorf("points[0] = %+v", points[0])
+	}
+	if points[1].Value != 0.0552 {
+		t.Errorf("points[1].Value = %v, want 0.0552", points[1].Value)
+	}
+}
+
+func TestHTTPPriceProviderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr func(error) bool
+	}{
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			wantErr: func(err error) bool { return errors.Is(err, market.ErrSymbolNotFound) },
+		},
+		{
+			name:    "server error",
+			status:  http.StatusBadGateway,
+			wantErr: func(err error) bool { return err != nil && strings.Contains(err.Error(), "502") },
+		},
+		{
+			name:    "invalid date",
+			status:  http.StatusOK,
+			body:    `{"symbol": "PETR4", "price": 38.5, "date": "16/10/2026"}`,
+			wantErr: func(err error) bool { return err != nil && strings.Contains(err.Error(), "data invalida") },
+		},
+		{
+			name:    "invalid json",
+			status:  http.StatusOK,
+			body:    `{"price": `,
+			wantErr: func(err error) bool { return err != nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := NewHTTPPriceProvider(server.URL, "", time.Second)
+			quote, err := provider.GetQuote(context.Background(), "PETR4")
+			if !tt.wantErr(err) {
+				t.Fatalf("GetQuote error = %v", err)
+			}
+			if quote != nil {
+				t.Errorf("quote = %+v, want nil", quote)
+			}
+		})
+	}
+}
+
+func TestHTTPPriceProviderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	provider := NewHTTPPriceProvider(server.URL, "", 50*time.Millisecond)
+	start := time.Now()
+	_, err := provider.GetQuote(context.Background(), "PETR4")
+	if err == nil {
+		t.Fatal("GetQuote returned no error after the client timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("GetQuote took %v, want it to stop at the client timeout", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	provider.Client = &http.Client{}
+	if _, err := provider.GetQuote(ctx, "PETR4"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetQuote with expired context error = %v, want context.DeadlineExceeded", err)
+	}
+}
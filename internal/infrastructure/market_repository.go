@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Fynance/internal/domain/market"
+	"Fynance/internal/pkg"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MarketRepository struct {
+	DB *gorm.DB
+}
+
+var _ market.MarketRepository = (*MarketRepository)(nil)
+
+type priceHistoryDB struct {
+	Id        string    `gorm:"type:varchar(26);primaryKey"`
+	Symbol    string    `gorm:"type:varchar(20);not null"`
+	Date      time.Time `gorm:"type:date;not null"`
+	Price     float64   `gorm:"not null"`
+	Source    string    `gorm:"type:varchar(30);not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (priceHistoryDB) TableName() string {
+	return "price_history"
+}
+
+func toDomainPriceHistory(pdb *priceHistoryDB) (*market.PriceHistory, error) {
+	id, err := pkg.ParseULID(pdb.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &market.PriceHistory{
+		Id:        id,
+		Symbol:    pdb.Symbol,
+		Date:      pdb.Date,
+		Price:     pdb.Price,
+		Source:    pdb.Source,
+		CreatedAt: pdb.CreatedAt,
+	}, nil
+}
+
+// SavePrice grava a cotação do dia, substituindo uma cotação anterior da mesma data
+func (r *MarketRepository) SavePrice(ctx context.Context, p *market.PriceHistory) error {
+	pdb := &priceHistoryDB{
+		Id:        p.Id.String(),
+		Symbol:    p.Symbol,
+		Date:      p.Date,
+		Price:     p.Price,
+		Source:    p.Source,
+		CreatedAt: time.Now(),
+	}
+	return conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "source"}),
+	}).Create(pdb).Error
+}
+
+func (r *MarketRepository) GetLatestPrice(ctx context.Context, symbol string) (*market.PriceHistory, error) {
+	var row priceHistoryDB
+	if err := conn(ctx, r.DB).
+		Where("symbol = ?", symbol).
+		Order("date DESC").
+		First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainPriceHistory(&row)
+}
+
+func (r *MarketRepository) GetPriceHistory(ctx context.Context, symbol string, from, to time.Time) ([]*market.PriceHistory, error) {
+	var rows []priceHistoryDB
+	if err := conn(ctx, r.DB).
+		Where("symbol = ? AND date >= ? AND date <= ?", symbol, from, to).
+		Order("date ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	history := make([]*market.PriceHistory, 0, len(rows))
+	for i := range rows {
+		p, err := toDomainPriceHistory(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, p)
+	}
+	return history, nil
+}
+
+func (r *MarketRepository) SaveIndexRates(ctx context.Context, rates []*market.IndexRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "index_code"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value"}),
+	}).CreateInBatches(rates, 500).Error
+}
+
+func (r *MarketRepository) GetIndexRates(ctx context.Context, index string, from, to time.Time) ([]*market.IndexRate, error) {
+	var rates []*market.IndexRate
+	if err := conn(ctx, r.DB).
+		Where("index_code = ? AND date >= ? AND date <= ?", index, from, to).
+		Order("date ASC").
+		Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *MarketRepository) GetLatestIndexDate(ctx context.Context, index string) (*time.Time, error) {
+	var rate market.IndexRate
+	err := conn(ctx, r.DB).
+		Where("index_code = ?", index).
+		Order("date DESC").
+		First(&rate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rate.Date, nil
+}
@@ -0,0 +1,243 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/notification"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	DB *gorm.DB
+}
+
+var _ notification.Repository = (*NotificationRepository)(nil)
+
+type notificationDB struct {
+	Id        string         `gorm:"type:varchar(26);primaryKey"`
+	UserId    string         `gorm:"type:varchar(26);not null"`
+	Type      string         `gorm:"type:varchar(40);not null"`
+	Title     string         `gorm:"type:varchar(200);not null"`
+	Body      string         `gorm:"type:text;not null"`
+	Data      map[string]any `gorm:"type:text;serializer:json"`
+	ReadAt    *time.Time     `gorm:"type:timestamp"`
+	CreatedAt time.Time      `gorm:"not null"`
+}
+
+func (notificationDB) TableName() string {
+	return "notifications"
+}
+
+type notificationPreferencesDB struct {
+	UserId    string                                       `gorm:"type:varchar(26);primaryKey"`
+	Locale    string                                       `gorm:"type:varchar(10);not null"`
+	Channels  map[notification.Type][]notification.Channel `gorm:"type:text;serializer:json;not null"`
+	CreatedAt time.Time                                    `gorm:"not null"`
+	UpdatedAt time.Time                                    `gorm:"not null"`
+}
+
+func (notificationPreferencesDB) TableName() string {
+	return "notification_preferences"
+}
+
+type pushSubscriptionDB struct {
+	Id        string    `gorm:"type:varchar(26);primaryKey"`
+	UserId    string    `gorm:"type:varchar(26);not null"`
+	Endpoint  string    `gorm:"type:varchar(1000);not null"`
+	P256dh    string    `gorm:"type:varchar(200);not null"`
+	Auth      string    `gorm:"type:varchar(100);not null"`
+	UserAgent string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (pushSubscriptionDB) TableName() string {
+	return "push_subscriptions"
+}
+
+func toDomainNotification(ndb *notificationDB) (*notification.Notification, error) {
+	id, err := pkg.ParseULID(ndb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(ndb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &notification.Notification{
+		Id:        id,
+		UserId:    userID,
+		Type:      notification.Type(ndb.Type),
+		Title:     ndb.Title,
+		Body:      ndb.Body,
+		Data:      ndb.Data,
+		ReadAt:    ndb.ReadAt,
+		CreatedAt: ndb.CreatedAt,
+	}, nil
+}
+
+func toDomainPushSubscription(sdb *pushSubscriptionDB) (*notification.PushSubscription, error) {
+	id, err := pkg.ParseULID(sdb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(sdb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &notification.PushSubscription{
+		Id:        id,
+		UserId:    userID,
+		Endpoint:  sdb.Endpoint,
+		P256dh:    sdb.P256dh,
+		Auth:      sdb.Auth,
+		UserAgent: sdb.UserAgent,
+		CreatedAt: sdb.CreatedAt,
+	}, nil
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, n *notification.Notification) error {
+	row := &notificationDB{
+		Id:        n.Id.String(),
+		UserId:    n.UserId.String(),
+		Type:      string(n.Type),
+		Title:     n.Title,
+		Body:      n.Body,
+		Data:      n.Data,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+	return conn(ctx, r.DB).Create(row).Error
+}
+
+func (r *NotificationRepository) List(ctx context.Context, userID ulid.ULID, unreadOnly bool, pagination *pkg.PaginationParams) ([]*notification.Notification, int64, error) {
+	query := conn(ctx, r.DB).Model(&notificationDB{}).Where("user_id = ?", userID.String())
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	return pkg.Paginate(query, pagination, "created_at DESC, id DESC", toDomainNotification)
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID ulid.ULID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.DB).Model(&notificationDB{}).
+		Where("user_id = ? AND read_at IS NULL", userID.String()).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *NotificationRepository) SetReadAt(ctx context.Context, id, userID ulid.ULID, readAt *time.Time) error {
+	result := conn(ctx, r.DB).Model(&notificationDB{}).
+		Where("id = ? AND user_id = ?", id.String(), userID.String()).
+		Update("read_at", readAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID ulid.ULID, readAt time.Time) (int64, error) {
+	result := conn(ctx, r.DB).Model(&notificationDB{}).
+		Where("user_id = ? AND read_at IS NULL", userID.String()).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *NotificationRepository) Delete(ctx context.Context, id, userID ulid.ULID) error {
+	result := conn(ctx, r.DB).
+		Where("id = ? AND user_id = ?", id.String(), userID.String()).
+		Delete(&notificationDB{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID ulid.ULID) (*notification.Preferences, error) {
+	var row notificationPreferencesDB
+	if err := conn(ctx, r.DB).Where("user_id = ?", userID.String()).First(&row).Error; err != nil {
+		return nil, err
+	}
+	id, err := pkg.ParseULID(row.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &notification.Preferences{
+		UserId:    id,
+		Locale:    row.Locale,
+		Channels:  row.Channels,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+// SavePreferences cria ou substitui as preferências de notificação do usuário
+func (r *NotificationRepository) SavePreferences(ctx context.Context, preferences *notification.Preferences) error {
+	row := &notificationPreferencesDB{
+		UserId:    preferences.UserId.String(),
+		Locale:    preferences.Locale,
+		Channels:  preferences.Channels,
+		CreatedAt: preferences.CreatedAt,
+		UpdatedAt: preferences.UpdatedAt,
+	}
+	return conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"locale", "channels", "updated_at"}),
+	}).Create(row).Error
+}
+
+func (r *NotificationRepository) SavePushSubscription(ctx context.Context, subscription *notification.PushSubscription) error {
+	row := &pushSubscriptionDB{
+		Id:        subscription.Id.String(),
+		UserId:    subscription.UserId.String(),
+		Endpoint:  subscription.Endpoint,
+		P256dh:    subscription.P256dh,
+		Auth:      subscription.Auth,
+		UserAgent: subscription.UserAgent,
+		CreatedAt: subscription.CreatedAt,
+	}
+	return conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "p256dh", "auth", "user_agent"}),
+	}).Create(row).Error
+}
+
+func (r *NotificationRepository) ListPushSubscriptions(ctx context.Context, userID ulid.ULID) ([]*notification.PushSubscription, error) {
+	var rows []pushSubscriptionDB
+	if err := conn(ctx, r.DB).Where("user_id = ?", userID.String()).Order("created_at").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*notification.PushSubscription, 0, len(rows))
+	for i := range rows {
+		subscription, err := toDomainPushSubscription(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+func (r *NotificationRepository) DeletePushSubscription(ctx context.Context, userID ulid.ULID, endpoint string) error {
+	result := conn(ctx, r.DB).
+		Where("user_id = ? AND endpoint = ?", userID.String(), endpoint).
+		Delete(&pushSubscriptionDB{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
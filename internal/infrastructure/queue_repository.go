@@ -0,0 +1,358 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/queue"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QueueRepository struct {
+	DB *gorm.DB
+}
+
+var _ queue.Repository = (*QueueRepository)(nil)
+
+type jobDB struct {
+	Id          string     `gorm:"type:varchar(26);primaryKey"`
+	Type        string     `gorm:"type:varchar(100);not null"`
+	Payload     string     `gorm:"type:text;not null"`
+	Status      string     `gorm:"type:varchar(20);not null"`
+	Attempts    int        `gorm:"not null"`
+	MaxAttempts int        `gorm:"not null"`
+	RunAt       time.Time  `gorm:"type:timestamp;not null"`
+	UniqueKey   *string    `gorm:"type:varchar(150)"`
+	LockedBy    string     `gorm:"type:varchar(100)"`
+	LockedUntil *time.Time `gorm:"type:timestamp"`
+	LastError   string     `gorm:"type:text"`
+	StartedAt   *time.Time `gorm:"type:timestamp"`
+	FinishedAt  *time.Time `gorm:"type:timestamp"`
+	CreatedAt   time.Time  `gorm:"not null"`
+	UpdatedAt   time.Time  `gorm:"not null"`
+}
+
+func (jobDB) TableName() string {
+	return "jobs"
+}
+
+type jobScheduleDB struct {
+	Name      string     `gorm:"type:varchar(100);primaryKey"`
+	JobType   string     `gorm:"type:varchar(100);not null"`
+	Spec      string     `gorm:"type:varchar(100);not null"`
+	Payload   string     `gorm:"type:text;not null"`
+	NextRunAt time.Time  `gorm:"type:timestamp;not null"`
+	LastRunAt *time.Time `gorm:"type:timestamp"`
+	UpdatedAt time.Time  `gorm:"not null"`
+}
+
+func (jobScheduleDB) TableName() string {
+	return "job_schedules"
+}
+
+func toDomainJob(jdb *jobDB) (*queue.Job, error) {
+	id, err := pkg.ParseULID(jdb.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &queue.Job{
+		Id:          id,
+		Type:        jdb.Type,
+		Payload:     []byte(jdb.Payload),
+		Status:      queue.Status(jdb.Status),
+		Attempts:    jdb.Attempts,
+		MaxAttempts: jdb.MaxAttempts,
+		RunAt:       jdb.RunAt,
+		UniqueKey:   jdb.UniqueKey,
+		LockedBy:    jdb.LockedBy,
+		LockedUntil: jdb.LockedUntil,
+		LastError:   jdb.LastError,
+		StartedAt:   jdb.StartedAt,
+		FinishedAt:  jdb.FinishedAt,
+		CreatedAt:   jdb.CreatedAt,
+		UpdatedAt:   jdb.UpdatedAt,
+	}, nil
+}
+
+func toJobDB(job *queue.Job) *jobDB {
+	return &jobDB{
+		Id:          job.Id.String(),
+		Type:        job.Type,
+		Payload:     string(job.Payload),
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		UniqueKey:   job.UniqueKey,
+		LockedBy:    job.LockedBy,
+		LockedUntil: job.LockedUntil,
+		LastError:   job.LastError,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
+
+func toDomainJobSchedule(sdb *jobScheduleDB) *queue.Schedule {
+	return &queue.Schedule{
+		Name:      sdb.Name,
+		JobType:   sdb.JobType,
+		Spec:      sdb.Spec,
+		Payload:   []byte(sdb.Payload),
+		NextRunAt: sdb.NextRunAt,
+		LastRunAt: sdb.LastRunAt,
+		UpdatedAt: sdb.UpdatedAt,
+	}
+}
+
+func (r *QueueRepository) Create(ctx context.Context, job *queue.Job) (bool, error) {
+	result := conn(ctx, r.DB).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(toJobDB(job))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *QueueRepository) Claim(ctx context.Context, workerID string, now, leaseUntil time.Time, limit int) ([]*queue.Job, error) {
+	var rows []jobDB
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?)",
+				string(queue.StatusPending), now, string(queue.StatusRunning), now).
+			Order("run_at").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.Id)
+		}
+		return tx.Model(&jobDB{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(queue.StatusRunning),
+				"attempts":     gorm.Expr("attempts + 1"),
+				"locked_by":    workerID,
+				"locked_until": leaseUntil,
+				"started_at":   now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*queue.Job, 0, len(rows))
+	for i := range rows {
+		job, err := toDomainJob(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		job.Status = queue.StatusRunning
+		job.Attempts++
+		job.LockedBy = workerID
+		job.LockedUntil = &leaseUntil
+		job.StartedAt = &now
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// claimedBy restringe a atualização à tentativa reservada pelo worker, para que um worker cuja reserva
+// expirou não sobrescreva o resultado de quem retomou o job
+func claimedBy(db *gorm.DB, job *queue.Job) *gorm.DB {
+	return db.Model(&jobDB{}).
+		Where("id = ? AND status = ? AND locked_by = ? AND attempts = ?",
+			job.Id.String(), string(queue.StatusRunning), job.LockedBy, job.Attempts)
+}
+
+func (r *QueueRepository) ExtendLease(ctx context.Context, job *queue.Job, leaseUntil time.Time) error {
+	result := claimedBy(conn(ctx, r.DB), job).Update("locked_until", leaseUntil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *QueueRepository) Finish(ctx context.Context, job *queue.Job) error {
+	result := claimedBy(conn(ctx, r.DB), job).Updates(map[string]interface{}{
+		"status":       string(job.Status),
+		"run_at":       job.RunAt,
+		"last_error":   job.LastError,
+		"finished_at":  job.FinishedAt,
+		"locked_by":    "",
+		"locked_until": nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *QueueRepository) FindByID(ctx context.Context, id ulid.ULID) (*queue.Job, error) {
+	var row jobDB
+	if err := conn(ctx, r.DB).Where("id = ?", id.String()).First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainJob(&row)
+}
+
+func (r *QueueRepository) List(ctx context.Context, filter queue.Filter, pagination *pkg.PaginationParams) ([]*queue.Job, int64, error) {
+	query := conn(ctx, r.DB).Model(&jobDB{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	return pkg.Paginate(query, pagination, "created_at DESC, id DESC", toDomainJob)
+}
+
+func (r *QueueRepository) CountByStatus(ctx context.Context) ([]queue.StatusCount, error) {
+	var rows []struct {
+		Type   string
+		Status string
+		Total  int64
+	}
+	err := conn(ctx, r.DB).Model(&jobDB{}).
+		Select("type, status, COUNT(*) AS total").
+		Group("type, status").
+		Order("type, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]queue.StatusCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, queue.StatusCount{
+			Type:   row.Type,
+			Status: queue.Status(row.Status),
+			Total:  row.Total,
+		})
+	}
+	return counts, nil
+}
+
+func (r *QueueRepository) Requeue(ctx context.Context, id ulid.ULID, runAt time.Time) error {
+	result := conn(ctx, r.DB).Model(&jobDB{}).
+		Where("id = ? AND status = ?", id.String(), string(queue.StatusDead)).
+		Updates(map[string]interface{}{
+			"status":      string(queue.StatusPending),
+			"attempts":    0,
+			"run_at":      runAt,
+			"started_at":  nil,
+			"finished_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *QueueRepository) DeleteSucceededBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.DB).
+		Where("status = ? AND finished_at < ?", string(queue.StatusSucceeded), before).
+		Delete(&jobDB{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *QueueRepository) SaveSchedules(ctx context.Context, schedules []*queue.Schedule) error {
+	rows := make([]*jobScheduleDB, 0, len(schedules))
+	for _, schedule := range schedules {
+		rows = append(rows, &jobScheduleDB{
+			Name:      schedule.Name,
+			JobType:   schedule.JobType,
+			Spec:      schedule.Spec,
+			Payload:   string(schedule.Payload),
+			NextRunAt: schedule.NextRunAt,
+			LastRunAt: schedule.LastRunAt,
+			UpdatedAt: schedule.UpdatedAt,
+		})
+	}
+
+	// as expressões do SET leem a linha antiga, então o CASE compara o spec gravado com o novo
+	updates := append(clause.Set{{
+		Column: clause.Column{Name: "next_run_at"},
+		Value:  gorm.Expr("CASE WHEN job_schedules.spec <> excluded.spec THEN excluded.next_run_at ELSE job_schedules.next_run_at END"),
+	}}, clause.AssignmentColumns([]string{"job_type", "spec", "payload", "updated_at"})...)
+
+	return conn(ctx, r.DB).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: updates,
+		}).
+		Create(&rows).Error
+}
+
+func (r *QueueRepository) DeleteSchedulesExcept(ctx context.Context, names []string) error {
+	query := conn(ctx, r.DB)
+	if len(names) > 0 {
+		query = query.Where("name NOT IN ?", names)
+	} else {
+		query = query.Where("1 = 1")
+	}
+	return query.Delete(&jobScheduleDB{}).Error
+}
+
+func (r *QueueRepository) ClaimDueSchedules(ctx context.Context, now time.Time, limit int) ([]*queue.Schedule, error) {
+	var rows []jobScheduleDB
+	err := conn(ctx, r.DB).
+		Where("next_run_at <= ?", now).
+		Order("next_run_at").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*queue.Schedule, 0, len(rows))
+	for i := range rows {
+		schedules = append(schedules, toDomainJobSchedule(&rows[i]))
+	}
+	return schedules, nil
+}
+
+func (r *QueueRepository) UpdateSchedule(ctx context.Context, schedule *queue.Schedule) error {
+	return conn(ctx, r.DB).Model(&jobScheduleDB{}).
+		Where("name = ?", schedule.Name).
+		Updates(map[string]interface{}{
+			"next_run_at": schedule.NextRunAt,
+			"last_run_at": schedule.LastRunAt,
+		}).Error
+}
+
+func (r *QueueRepository) ListSchedules(ctx context.Context) ([]*queue.Schedule, error) {
+	var rows []jobScheduleDB
+	if err := conn(ctx, r.DB).Order("name").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*queue.Schedule, 0, len(rows))
+	for i := range rows {
+		schedules = append(schedules, toDomainJobSchedule(&rows[i]))
+	}
+	return schedules, nil
+}
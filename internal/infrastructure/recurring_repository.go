@@ -33,15 +33,93 @@ type recurringDB struct {
 	EndDate       *time.Time `gorm:"type:date;column:end_date"`
 	LastProcessed *time.Time `gorm:"type:date;column:last_processed"`
 	NextDue       time.Time  `gorm:"type:date;not null;column:next_due"`
+	SeriesDate    *time.Time `gorm:"type:date;column:series_date"`
+	SeriesIndex   int        `gorm:"not null;default:0;column:series_index"`
 	IsActive      bool       `gorm:"not null;default:true;column:is_active"`
 	CreatedAt     time.Time  `gorm:"not null;column:created_at"`
 	UpdatedAt     time.Time  `gorm:"not null;column:updated_at"`
+
+	Interval              int    `gorm:"not null;default:1;column:interval"`
+	Rule                  string `gorm:"type:varchar(255);column:rule"`
+	BusinessDayAdjustment string `gorm:"type:varchar(10);not null;default:NONE;column:business_day_adjustment"`
+	IsEstimated           bool   `gorm:"not null;default:false;column:is_estimated"`
+
+	Kind                 string  `gorm:"type:varchar(15);not null;default:TRANSACTION;column:kind"`
+	DestinationAccountId *string `gorm:"type:varchar(26);column:destination_account_id"`
+	GoalId               *string `gorm:"type:varchar(26);index;column:goal_id"`
+	InvestmentId         *string `gorm:"type:varchar(26);index;column:investment_id"`
+	CreditCardId         *string `gorm:"type:varchar(26);index;column:credit_card_id"`
 }
 
 func (recurringDB) TableName() string {
 	return "recurring_transactions"
 }
 
+type estimatedOccurrenceDB struct {
+	Id              string     `gorm:"type:varchar(26);primaryKey;column:id"`
+	RecurringId     string     `gorm:"type:varchar(26);not null;column:recurring_id"`
+	UserId          string     `gorm:"type:varchar(26);not null;column:user_id"`
+	DueDate         time.Time  `gorm:"type:date;not null;column:due_date"`
+	EstimatedAmount float64    `gorm:"type:decimal(15,2);not null;column:estimated_amount"`
+	ActualAmount    *float64   `gorm:"type:decimal(15,2);column:actual_amount"`
+	Status          string     `gorm:"type:varchar(10);not null;column:status"`
+	TransactionId   *string    `gorm:"type:varchar(26);column:transaction_id"`
+	ConfirmedAt     *time.Time `gorm:"type:timestamp;column:confirmed_at"`
+	CreatedAt       time.Time  `gorm:"not null;column:created_at"`
+	UpdatedAt       time.Time  `gorm:"not null;column:updated_at"`
+	Description     string     `gorm:"->;column:description"`
+}
+
+func (estimatedOccurrenceDB) TableName() string {
+	return "recurring_estimated_occurrences"
+}
+
+func toDomainEstimatedOccurrence(odb *estimatedOccurrenceDB) (*recurring.EstimatedOccurrence, error) {
+	id, err := pkg.ParseULID(odb.Id)
+	if err != nil {
+		return nil, err
+	}
+	recurringID, err := pkg.ParseULID(odb.RecurringId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(odb.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recurring.EstimatedOccurrence{
+		Id:              id,
+		RecurringId:     recurringID,
+		UserId:          userID,
+		DueDate:         odb.DueDate,
+		EstimatedAmount: odb.EstimatedAmount,
+		ActualAmount:    odb.ActualAmount,
+		Status:          recurring.OccurrenceStatus(odb.Status),
+		TransactionId:   parseOptionalULID(odb.TransactionId),
+		ConfirmedAt:     odb.ConfirmedAt,
+		CreatedAt:       odb.CreatedAt,
+		UpdatedAt:       odb.UpdatedAt,
+		Description:     odb.Description,
+	}, nil
+}
+
+func toDBEstimatedOccurrence(o *recurring.EstimatedOccurrence) *estimatedOccurrenceDB {
+	return &estimatedOccurrenceDB{
+		Id:              o.Id.String(),
+		RecurringId:     o.RecurringId.String(),
+		UserId:          o.UserId.String(),
+		DueDate:         o.DueDate,
+		EstimatedAmount: o.EstimatedAmount,
+		ActualAmount:    o.ActualAmount,
+		Status:          string(o.Status),
+		TransactionId:   optionalULIDString(o.TransactionId),
+		ConfirmedAt:     o.ConfirmedAt,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+	}
+}
+
 func toDomainRecurring(rdb *recurringDB) (*recurring.RecurringTransaction, error) {
 	id, err := pkg.ParseULID(rdb.Id)
 	if err != nil {
@@ -82,9 +160,25 @@ func toDomainRecurring(rdb *recurringDB) (*recurring.RecurringTransaction, error
 		EndDate:       rdb.EndDate,
 		LastProcessed: rdb.LastProcessed,
 		NextDue:       rdb.NextDue,
+		SeriesDate:    rdb.SeriesDate,
+		SeriesIndex:   rdb.SeriesIndex,
 		IsActive:      rdb.IsActive,
 		CreatedAt:     rdb.CreatedAt,
 		UpdatedAt:     rdb.UpdatedAt,
+
+		Interval:              rdb.Interval,
+		Rule:                  rdb.Rule,
+		BusinessDayAdjustment: recurring.BusinessDayAdjustment(rdb.BusinessDayAdjustment),
+		IsEstimated:           rdb.IsEstimated,
+
+		Kind:                 recurring.Kind(rdb.Kind),
+		DestinationAccountId: parseOptionalULID(rdb.DestinationAccountId),
+		GoalId:               parseOptionalULID(rdb.GoalId),
+		InvestmentId:         parseOptionalULID(rdb.InvestmentId),
+		CreditCardId:         parseOptionalULID(rdb.CreditCardId),
+	}
+	if rec.Kind == "" {
+		rec.Kind = recurring.KindTransaction
 	}
 	if rdb.CategoryName != "" {
 		rec.CategoryName = rdb.CategoryName
@@ -114,29 +208,45 @@ func toDBRecurring(r *recurring.RecurringTransaction) *recurringDB {
 		EndDate:       r.EndDate,
 		LastProcessed: r.LastProcessed,
 		NextDue:       r.NextDue,
+		SeriesDate:    r.SeriesDate,
+		SeriesIndex:   r.SeriesIndex,
 		IsActive:      r.IsActive,
 		CreatedAt:     r.CreatedAt,
 		UpdatedAt:     r.UpdatedAt,
+
+		Interval:              r.Interval,
+		Rule:                  r.Rule,
+		BusinessDayAdjustment: string(r.BusinessDayAdjustment),
+		IsEstimated:           r.IsEstimated,
+
+		Kind:                 string(r.Kind),
+		DestinationAccountId: optionalULIDString(r.DestinationAccountId),
+		GoalId:               optionalULIDString(r.GoalId),
+		InvestmentId:         optionalULIDString(r.InvestmentId),
+		CreditCardId:         optionalULIDString(r.CreditCardId),
 	}
 }
 
 func (r *RecurringRepository) Create(ctx context.Context, rec *recurring.RecurringTransaction) error {
 	rdb := toDBRecurring(rec)
-	return r.DB.WithContext(ctx).Table("recurring_transactions").Create(rdb).Error
+	return conn(ctx, r.DB).Table("recurring_transactions").Create(rdb).Error
 }
 
 func (r *RecurringRepository) Update(ctx context.Context, rec *recurring.RecurringTransaction) error {
 	rdb := toDBRecurring(rec)
-	return r.DB.WithContext(ctx).Model(&recurringDB{}).Where("id = ? AND user_id = ?", rdb.Id, rdb.UserId).Updates(rdb).Error
+	// Select("*") grava também valores zero, como is_active=false ao pausar
+	return conn(ctx, r.DB).Model(&recurringDB{}).Where("id = ? AND user_id = ?", rdb.Id, rdb.UserId).
+		Select("*").Omit("id", "user_id", "created_at", "category_name").
+		Updates(rdb).Error
 }
 
 func (r *RecurringRepository) Delete(ctx context.Context, recurringID, userID ulid.ULID) error {
-	return r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", recurringID.String(), userID.String()).Delete(&recurringDB{}).Error
+	return conn(ctx, r.DB).Where("id = ? AND user_id = ?", recurringID.String(), userID.String()).Delete(&recurringDB{}).Error
 }
 
 func (r *RecurringRepository) GetByID(ctx context.Context, recurringID, userID ulid.ULID) (*recurring.RecurringTransaction, error) {
 	var rdb recurringDB
-	err := r.DB.WithContext(ctx).
+	err := conn(ctx, r.DB).
 		Table("recurring_transactions r").
 		Select("r.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON r.category_id = c.id").
@@ -162,8 +272,8 @@ func (r *RecurringRepository) GetByUserID(ctx context.Context, userID ulid.ULID,
 	}
 	pagination.Normalize()
 
-	countQuery := r.DB.WithContext(ctx).Table("recurring_transactions r").Where("r.user_id = ?", userID.String())
-	dataQuery := r.DB.WithContext(ctx).
+	countQuery := conn(ctx, r.DB).Table("recurring_transactions r").Where("r.user_id = ?", userID.String())
+	dataQuery := conn(ctx, r.DB).
 		Table("recurring_transactions r").
 		Select("r.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON r.category_id = c.id").
@@ -200,7 +310,7 @@ func (r *RecurringRepository) GetActiveByUserID(ctx context.Context, userID ulid
 	}
 	pagination.Normalize()
 
-	baseQuery := r.DB.WithContext(ctx).Table("recurring_transactions").Where("user_id = ? AND is_active = ?", userID.String(), true)
+	baseQuery := conn(ctx, r.DB).Table("recurring_transactions").Where("user_id = ? AND is_active = ?", userID.String(), true)
 
 	var total int64
 	if err := baseQuery.Count(&total).Error; err != nil {
@@ -233,7 +343,7 @@ func (r *RecurringRepository) GetDueTransactions(ctx context.Context, date time.
 	}
 	pagination.Normalize()
 
-	baseQuery := r.DB.WithContext(ctx).Table("recurring_transactions").Where("is_active = ? AND next_due <= ?", true, date)
+	baseQuery := conn(ctx, r.DB).Table("recurring_transactions").Where("is_active = ? AND next_due <= ?", true, date)
 
 	var total int64
 	if err := baseQuery.Count(&total).Error; err != nil {
@@ -260,11 +370,102 @@ func (r *RecurringRepository) GetDueTransactions(ctx context.Context, date time.
 	return transactions, total, nil
 }
 
-func (r *RecurringRepository) UpdateLastProcessed(ctx context.Context, recurringID ulid.ULID, processedDate, nextDue time.Time) error {
-	return r.DB.WithContext(ctx).Model(&recurringDB{}).Where("id = ?", recurringID.String()).
+func (r *RecurringRepository) UpdateLastProcessed(ctx context.Context, recurringID ulid.ULID, processedDate, nextDue time.Time, position recurring.SeriesPosition) error {
+	var seriesDate *time.Time
+	if !position.IsZero() {
+		seriesDate = &position.Date
+	}
+	return conn(ctx, r.DB).Model(&recurringDB{}).Where("id = ?", recurringID.String()).
 		Updates(map[string]interface{}{
 			"last_processed": processedDate,
 			"next_due":       nextDue,
+			"series_date":    seriesDate,
+			"series_index":   position.Index,
 			"updated_at":     time.Now(),
 		}).Error
 }
+
+func (r *RecurringRepository) CreateOccurrence(ctx context.Context, occurrence *recurring.EstimatedOccurrence) error {
+	return conn(ctx, r.DB).Create(toDBEstimatedOccurrence(occurrence)).Error
+}
+
+func (r *RecurringRepository) UpdateOccurrence(ctx context.Context, occurrence *recurring.EstimatedOccurrence) error {
+	odb := toDBEstimatedOccurrence(occurrence)
+	return conn(ctx, r.DB).Model(&estimatedOccurrenceDB{}).Where("id = ? AND user_id = ?", odb.Id, odb.UserId).
+		Select("*").Omit("id", "recurring_id", "user_id", "created_at", "description").
+		Updates(odb).Error
+}
+
+func (r *RecurringRepository) GetOccurrenceByID(ctx context.Context, occurrenceID, userID ulid.ULID) (*recurring.EstimatedOccurrence, error) {
+	var odb estimatedOccurrenceDB
+	err := conn(ctx, r.DB).
+		Table("recurring_estimated_occurrences o").
+		Select("o.*, r.description").
+		Joins("LEFT JOIN recurring_transactions r ON o.recurring_id = r.id").
+		Where("o.id = ? AND o.user_id = ?", occurrenceID.String(), userID.String()).
+		Order("o.id").
+		First(&odb).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainEstimatedOccurrence(&odb)
+}
+
+func (r *RecurringRepository) GetOccurrenceByDueDate(ctx context.Context, recurringID ulid.ULID, dueDate time.Time) (*recurring.EstimatedOccurrence, error) {
+	var odb estimatedOccurrenceDB
+	err := conn(ctx, r.DB).
+		Where("recurring_id = ? AND due_date = ?", recurringID.String(), dueDate).
+		First(&odb).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainEstimatedOccurrence(&odb)
+}
+
+func (r *RecurringRepository) ListOccurrences(ctx context.Context, userID ulid.ULID, filters recurring.OccurrenceFilters) ([]*recurring.EstimatedOccurrence, error) {
+	query := conn(ctx, r.DB).
+		Table("recurring_estimated_occurrences o").
+		Select("o.*, r.description").
+		Joins("LEFT JOIN recurring_transactions r ON o.recurring_id = r.id").
+		Where("o.user_id = ?", userID.String())
+	if filters.RecurringId != nil {
+		query = query.Where("o.recurring_id = ?", filters.RecurringId.String())
+	}
+	if filters.Status != nil {
+		query = query.Where("o.status = ?", string(*filters.Status))
+	}
+	if filters.OverdueOnly {
+		query = query.Where("o.status = ? AND o.due_date < ?", string(recurring.OccurrencePending), time.Now().Truncate(24*time.Hour))
+	}
+
+	var rows []estimatedOccurrenceDB
+	if err := query.Order("o.due_date ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]*recurring.EstimatedOccurrence, 0, len(rows))
+	for i := range rows {
+		occurrence, err := toDomainEstimatedOccurrence(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
+
+func (r *RecurringRepository) ListConfirmedAmounts(ctx context.Context, recurringID ulid.ULID, limit int) ([]float64, error) {
+	var amounts []float64
+	err := conn(ctx, r.DB).Model(&estimatedOccurrenceDB{}).
+		Where("recurring_id = ? AND status = ? AND actual_amount IS NOT NULL", recurringID.String(), string(recurring.OccurrenceConfirmed)).
+		Order("due_date DESC").
+		Limit(limit).
+		Pluck("actual_amount", &amounts).Error
+	return amounts, err
+}
+
+func (r *RecurringRepository) DeleteOccurrencesByRecurring(ctx context.Context, recurringID, userID ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("recurring_id = ? AND user_id = ?", recurringID.String(), userID.String()).
+		Delete(&estimatedOccurrenceDB{}).Error
+}
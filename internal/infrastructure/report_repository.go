@@ -344,3 +344,41 @@ func (r *ReportRepository) GetCategoryReport(userID, categoryID ulid.ULID, start
 		Transactions: transactions,
 	}, nil
 }
+
+func (r *ReportRepository) GetCategoryMonthlyAmounts(userID ulid.ULID, txType string, startDate, endDate time.Time) ([]report.CategoryMonthAmount, error) {
+	type result struct {
+		CategoryId   string
+		CategoryName string
+		Year         int
+		Month        int
+		Amount       float64
+	}
+
+	var results []result
+	err := r.DB.Table("transactions t").
+		Select("t.category_id, c.name as category_name, EXTRACT(YEAR FROM t.date)::int as year, EXTRACT(MONTH FROM t.date)::int as month, COALESCE(SUM(ABS(t.amount)), 0) as amount").
+		Joins("LEFT JOIN categories c ON t.category_id = c.id").
+		Where("t.user_id = ? AND t.type = ? AND t.date BETWEEN ? AND ? AND t.category_id IS NOT NULL", userID.String(), txType, startDate, endDate).
+		Group("t.category_id, c.name, EXTRACT(YEAR FROM t.date), EXTRACT(MONTH FROM t.date)").
+		Order("year ASC, month ASC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := make([]report.CategoryMonthAmount, 0, len(results))
+	for _, res := range results {
+		categoryID, err := pkg.ParseULID(res.CategoryId)
+		if err != nil {
+			continue
+		}
+		amounts = append(amounts, report.CategoryMonthAmount{
+			CategoryId:   categoryID,
+			CategoryName: res.CategoryName,
+			Month:        res.Month,
+			Year:         res.Year,
+			Amount:       res.Amount,
+		})
+	}
+	return amounts, nil
+}
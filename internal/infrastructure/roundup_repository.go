@@ -0,0 +1,310 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/roundup"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+type RoundUpRepository struct {
+	DB *gorm.DB
+}
+
+var _ roundup.RoundUpRepository = (*RoundUpRepository)(nil)
+
+type roundUpRuleDB struct {
+	Id          string    `gorm:"type:varchar(26);primaryKey"`
+	UserId      string    `gorm:"type:varchar(26);not null"`
+	AccountId   string    `gorm:"type:varchar(26);not null"`
+	GoalId      string    `gorm:"type:varchar(26);not null"`
+	Increment   int       `gorm:"not null"`
+	Frequency   string    `gorm:"type:varchar(20);not null"`
+	IsActive    bool      `gorm:"not null;default:true"`
+	NextSweepAt time.Time `gorm:"not null"`
+	LastSweepAt *time.Time
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (roundUpRuleDB) TableName() string {
+	return "roundup_rules"
+}
+
+type roundUpEntryDB struct {
+	Id            string    `gorm:"type:varchar(26);primaryKey"`
+	UserId        string    `gorm:"type:varchar(26);not null"`
+	RuleId        string    `gorm:"type:varchar(26);not null"`
+	AccountId     string    `gorm:"type:varchar(26);not null"`
+	GoalId        string    `gorm:"type:varchar(26);not null"`
+	TransactionId string    `gorm:"type:varchar(26);not null"`
+	ExpenseAmount float64   `gorm:"type:decimal(15,2);not null"`
+	Amount        float64   `gorm:"type:decimal(15,2);not null"`
+	Status        string    `gorm:"type:varchar(20);not null"`
+	Date          time.Time `gorm:"type:date;not null"`
+	SweptAt       *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (roundUpEntryDB) TableName() string {
+	return "roundup_entries"
+}
+
+func toDomainRoundUpRule(rdb *roundUpRuleDB) (*roundup.Rule, error) {
+	id, err := pkg.ParseULID(rdb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(rdb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := pkg.ParseULID(rdb.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	goalID, err := pkg.ParseULID(rdb.GoalId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &roundup.Rule{
+		Id:          id,
+		UserId:      userID,
+		AccountId:   accountID,
+		GoalId:      goalID,
+		Increment:   rdb.Increment,
+		Frequency:   roundup.SweepFrequency(rdb.Frequency),
+		IsActive:    rdb.IsActive,
+		NextSweepAt: rdb.NextSweepAt,
+		LastSweepAt: rdb.LastSweepAt,
+		CreatedAt:   rdb.CreatedAt,
+		UpdatedAt:   rdb.UpdatedAt,
+	}, nil
+}
+
+func toDBRoundUpRule(r *roundup.Rule) *roundUpRuleDB {
+	return &roundUpRuleDB{
+		Id:          r.Id.String(),
+		UserId:      r.UserId.String(),
+		AccountId:   r.AccountId.String(),
+		GoalId:      r.GoalId.String(),
+		Increment:   r.Increment,
+		Frequency:   string(r.Frequency),
+		IsActive:    r.IsActive,
+		NextSweepAt: r.NextSweepAt,
+		LastSweepAt: r.LastSweepAt,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func toDomainRoundUpEntry(edb *roundUpEntryDB) (*roundup.Entry, error) {
+	id, err := pkg.ParseULID(edb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(edb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	ruleID, err := pkg.ParseULID(edb.RuleId)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := pkg.ParseULID(edb.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	goalID, err := pkg.ParseULID(edb.GoalId)
+	if err != nil {
+		return nil, err
+	}
+	transactionID, err := pkg.ParseULID(edb.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &roundup.Entry{
+		Id:            id,
+		UserId:        userID,
+		RuleId:        ruleID,
+		AccountId:     accountID,
+		GoalId:        goalID,
+		TransactionId: transactionID,
+		ExpenseAmount: edb.ExpenseAmount,
+		Amount:        edb.Amount,
+		Status:        roundup.EntryStatus(edb.Status),
+		Date:          edb.Date,
+		SweptAt:       edb.SweptAt,
+		CreatedAt:     edb.CreatedAt,
+	}, nil
+}
+
+func toDomainRoundUpRules(rows []roundUpRuleDB) ([]*roundup.Rule, error) {
+	rules := make([]*roundup.Rule, 0, len(rows))
+	for i := range rows {
+		rule, err := toDomainRoundUpRule(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *RoundUpRepository) CreateRule(ctx context.Context, rule *roundup.Rule) error {
+	return conn(ctx, r.DB).Create(toDBRoundUpRule(rule)).Error
+}
+
+func (r *RoundUpRepository) UpdateRule(ctx context.Context, rule *roundup.Rule) error {
+	return conn(ctx, r.DB).Save(toDBRoundUpRule(rule)).Error
+}
+
+func (r *RoundUpRepository) DeleteRule(ctx context.Context, ruleID, userID ulid.ULID) error {
+	result := conn(ctx, r.DB).
+		Where("id = ? AND user_id = ?", ruleID.String(), userID.String()).
+		Delete(&roundUpRuleDB{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *RoundUpRepository) GetRuleByID(ctx context.Context, ruleID, userID ulid.ULID) (*roundup.Rule, error) {
+	var rdb roundUpRuleDB
+	if err := conn(ctx, r.DB).
+		Where("id = ? AND user_id = ?", ruleID.String(), userID.String()).
+		First(&rdb).Error; err != nil {
+		return nil, err
+	}
+	return toDomainRoundUpRule(&rdb)
+}
+
+func (r *RoundUpRepository) GetRuleByAccount(ctx context.Context, accountID, userID ulid.ULID) (*roundup.Rule, error) {
+	var rdb roundUpRuleDB
+	if err := conn(ctx, r.DB).
+		Where("account_id = ? AND user_id = ?", accountID.String(), userID.String()).
+		First(&rdb).Error; err != nil {
+		return nil, err
+	}
+	return toDomainRoundUpRule(&rdb)
+}
+
+func (r *RoundUpRepository) ListRules(ctx context.Context, userID ulid.ULID) ([]*roundup.Rule, error) {
+	var rows []roundUpRuleDB
+	if err := conn(ctx, r.DB).
+		Where("user_id = ?", userID.String()).
+		Order("created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toDomainRoundUpRules(rows)
+}
+
+func (r *RoundUpRepository) GetDueRules(ctx context.Context, now time.Time, limit int) ([]*roundup.Rule, error) {
+	var rows []roundUpRuleDB
+	if err := conn(ctx, r.DB).
+		Where("is_active = ? AND next_sweep_at <= ?", true, now).
+		Order("next_sweep_at ASC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toDomainRoundUpRules(rows)
+}
+
+func (r *RoundUpRepository) CreateEntry(ctx context.Context, e *roundup.Entry) error {
+	edb := &roundUpEntryDB{
+		Id:            e.Id.String(),
+		UserId:        e.UserId.String(),
+		RuleId:        e.RuleId.String(),
+		AccountId:     e.AccountId.String(),
+		GoalId:        e.GoalId.String(),
+		TransactionId: e.TransactionId.String(),
+		ExpenseAmount: e.ExpenseAmount,
+		Amount:        e.Amount,
+		Status:        string(e.Status),
+		Date:          e.Date,
+		SweptAt:       e.SweptAt,
+		CreatedAt:     e.CreatedAt,
+	}
+	return conn(ctx, r.DB).Create(edb).Error
+}
+
+func (r *RoundUpRepository) DeletePendingEntryByTransaction(ctx context.Context, transactionID, userID ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("transaction_id = ? AND user_id = ? AND status = ?", transactionID.String(), userID.String(), string(roundup.EntryPending)).
+		Delete(&roundUpEntryDB{}).Error
+}
+
+func (r *RoundUpRepository) DeletePendingEntriesByRule(ctx context.Context, ruleID ulid.ULID) error {
+	return conn(ctx, r.DB).
+		Where("rule_id = ? AND status = ?", ruleID.String(), string(roundup.EntryPending)).
+		Delete(&roundUpEntryDB{}).Error
+}
+
+func (r *RoundUpRepository) GetPendingEntries(ctx context.Context, ruleID ulid.ULID) ([]*roundup.Entry, error) {
+	var rows []roundUpEntryDB
+	if err := conn(ctx, r.DB).
+		Where("rule_id = ? AND status = ?", ruleID.String(), string(roundup.EntryPending)).
+		Order("date ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]*roundup.Entry, 0, len(rows))
+	for i := range rows {
+		entry, err := toDomainRoundUpEntry(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (r *RoundUpRepository) MarkEntriesSwept(ctx context.Context, entryIDs []ulid.ULID, sweptAt time.Time) error {
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(entryIDs))
+	for i, id := range entryIDs {
+		ids[i] = id.String()
+	}
+
+	return conn(ctx, r.DB).Model(&roundUpEntryDB{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":   string(roundup.EntrySwept),
+			"swept_at": sweptAt,
+		}).Error
+}
+
+func (r *RoundUpRepository) GetMonthlySummary(ctx context.Context, userID ulid.ULID, start, end time.Time) ([]roundup.MonthlySummary, error) {
+	var rows []roundup.MonthlySummary
+
+	err := conn(ctx, r.DB).Model(&roundUpEntryDB{}).
+		Select(`EXTRACT(YEAR FROM date)::int AS year,
+			EXTRACT(MONTH FROM date)::int AS month,
+			COUNT(*) AS expenses,
+			COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0) AS swept,
+			COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0) AS pending`,
+			string(roundup.EntrySwept), string(roundup.EntryPending)).
+		Where("user_id = ? AND date >= ? AND date < ?", userID.String(), start, end).
+		Group("year, month").
+		Order("year, month").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
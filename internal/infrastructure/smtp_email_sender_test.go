@@ -0,0 +1,398 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Fynance/internal/domain/notification"
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/user"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// receivedEmail é uma mensagem aceita pelo servidor SMTP falso
+type receivedEmail struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// fakeSMTPServer aceita mensagens sem TLS nem autenticação e as entrega no canal Messages
+type fakeSMTPServer struct {
+	listener net.Listener
+	Messages chan receivedEmail
+	wg       sync.WaitGroup
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener, Messages: make(chan receivedEmail, 10)}
+	server.wg.Add(1)
+	go server.serve(t)
+	t.Cleanup(func() {
+		listener.Close()
+		server.wg.Wait()
+	})
+	return server
+}
+
+func (f *fakeSMTPServer) sender() *SMTPEmailSender {
+	host, port, _ := net.SplitHostPort(f.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return NewSMTPEmailSender(host, portNumber, "", "", "Fynance <noreply@fynance.test>", SMTPTLSModeStartTLS, 5*time.Second)
+}
+
+func (f *fakeSMTPServer) serve(t *testing.T) {
+	defer f.wg.Done()
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			defer conn.Close()
+			if err := f.session(conn); err != nil && err != io.EOF {
+				t.Errorf("smtp session: %v", err)
+			}
+		}()
+	}
+}
+
+func (f *fakeSMTPServer) session(conn net.Conn) error {
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	text := textproto.NewConn(conn)
+	if err := text.PrintfLine("220 fake ESMTP"); err != nil {
+		return err
+	}
+
+	var current receivedEmail
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return err
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			err = text.PrintfLine("250-fake\r\n250 8BITMIME")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			current = receivedEmail{From: smtpPath(line[len("MAIL FROM:"):])}
+			err = text.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			current.To = append(current.To, smtpPath(line[len("RCPT TO:"):]))
+			err = text.PrintfLine("250 OK")
+		case command == "DATA":
+			if err := text.PrintfLine("354 send data"); err != nil {
+				return err
+			}
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return err
+			}
+			if err := parseReceivedEmail(data, &current); err != nil {
+				return err
+			}
+			f.Messages <- current
+			err = text.PrintfLine("250 OK")
+		case command == "QUIT":
+			return text.PrintfLine("221 bye")
+		default:
+			err = text.PrintfLine("502 not implemented")
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// smtpPath extrai o endereço entre <> de MAIL FROM e RCPT TO, ignorando parâmetros como BODY=8BITMIME
+func smtpPath(argument string) string {
+	start := strings.Index(argument, "<")
+	end := strings.Index(argument, ">")
+	if start < 0 || end < start {
+		return strings.TrimSpace(argument)
+	}
+	return argument[start+1 : end]
+}
+
+func parseReceivedEmail(data []byte, email *receivedEmail) error {
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(data))))
+	if err != nil {
+		return err
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+	email.Subject = subject
+	email.Body = string(body)
+	return nil
+}
+
+// waitEmail espera a próxima mensagem; sem mensagem no prazo devolve false
+func (f *fakeSMTPServer) waitEmail(timeout time.Duration) (receivedEmail, bool) {
+	select {
+	case msg := <-f.Messages:
+		return msg, true
+	case <-time.After(timeout):
+		return receivedEmail{}, false
+	}
+}
+
+// memoryNotificationRepository guarda caixa de entrada e preferências em memória
+type memoryNotificationRepository struct {
+	mu            sync.Mutex
+	notifications []*notification.Notification
+	preferences   map[ulid.ULID]*notification.Preferences
+}
+
+var _ notification.Repository = (*memoryNotificationRepository)(nil)
+
+func (r *memoryNotificationRepository) Create(ctx context.Context, n *notification.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+func (r *memoryNotificationRepository) List(ctx context.Context, userID ulid.ULID, unreadOnly bool, pagination *pkg.PaginationParams) ([]*notification.Notification, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*notification.Notification
+	for _, n := range r.notifications {
+		if n.UserId == userID && (!unreadOnly || n.ReadAt == nil) {
+			out = append(out, n)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (r *memoryNotificationRepository) CountUnread(ctx context.Context, userID ulid.ULID) (int64, error) {
+	items, _, err := r.List(ctx, userID, true, nil)
+	return int64(len(items)), err
+}
+
+func (r *memoryNotificationRepository) SetReadAt(ctx context.Context, id, userID ulid.ULID, readAt *time.Time) error {
+	return nil
+}
+
+func (r *memoryNotificationRepository) MarkAllRead(ctx context.Context, userID ulid.ULID, readAt time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryNotificationRepository) Delete(ctx context.Context, id, userID ulid.ULID) error {
+	return nil
+}
+
+func (r *memoryNotificationRepository) GetPreferences(ctx context.Context, userID ulid.ULID) (*notification.Preferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	preferences, ok := r.preferences[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *preferences
+	return &copied, nil
+}
+
+func (r *memoryNotificationRepository) SavePreferences(ctx context.Context, preferences *notification.Preferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.preferences == nil {
+		r.preferences = make(map[ulid.ULID]*notification.Preferences)
+	}
+	copied := *preferences
+	r.preferences[preferences.UserId] = &copied
+	return nil
+}
+
+func (r *memoryNotificationRepository) SavePushSubscription(ctx context.Context, subscription *notification.PushSubscription) error {
+	return nil
+}
+
+func (r *memoryNotificationRepository) ListPushSubscriptions(ctx context.Context, userID ulid.ULID) ([]*notification.PushSubscription, error) {
+	return nil, nil
+}
+
+func (r *memoryNotificationRepository) DeletePushSubscription(ctx context.Context, userID ulid.ULID, endpoint string) error {
+	return nil
+}
+
+func (r *memoryNotificationRepository) inbox(userID ulid.ULID) []*notification.Notification {
+	items, _, _ := r.List(context.Background(), userID, false, nil)
+	return items
+}
+
+type staticUsers map[ulid.ULID]*user.User
+
+func (u staticUsers) GetByID(ctx context.Context, id ulid.ULID) (*user.User, error) {
+	found, ok := u[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found, nil
+}
+
+// staticUserChecker adapta staticUsers para a checagem de usuário dos services
+type staticUserChecker struct {
+	users staticUsers
+}
+
+func (c staticUserChecker) Exists(ctx context.Context, id ulid.ULID) error {
+	_, err := c.users.GetByID(ctx, id)
+	return err
+}
+
+func (c staticUserChecker) GetByID(ctx context.Context, id ulid.ULID) (interface{}, error) {
+	return c.users.GetByID(ctx, id)
+}
+
+func TestSMTPEmailSenderSend(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	err := server.sender().Send(context.Background(), "Ana <ana@fynance.test>", "Orçamento de Alimentação estourado", "Os gastos chegaram a R$ 1.250,00.")
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	msg, ok := server.waitEmail(time.Second)
+	if !ok {
+		t.Fatal("no message received")
+	}
+	if msg.From != "noreply@fynance.test" {
+		t.Errorf("From = %q, want noreply@fynance.test", msg.From)
+	}
+	if len(msg.To) != 1 || msg.To[0] != "ana@fynance.test" {
+		t.Errorf("To = %v, want [ana@fynance.test]", msg.To)
+	}
+	if msg.Subject != "Orçamento de Alimentação estourado" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if !strings.HasPrefix(msg.Body, "Os gastos chegaram a R$ 1.250,00.") {
+		t.Errorf("Body = %q", msg.Body)
+	}
+}
+
+func TestSMTPEmailSenderInvalidRecipient(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	if err := server.sender().Send(context.Background(), "not an address", "Assunto", "Corpo"); err == nil {
+		t.Fatal("Send accepted an invalid recipient")
+	}
+	if _, ok := server.waitEmail(100 * time.Millisecond); ok {
+		t.Fatal("message sent to an invalid recipient")
+	}
+}
+
+func TestNotifyEmailRespectsInboxAndChannelPreferences(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	repo := &memoryNotificationRepository{}
+
+	pro := &user.User{Id: pkg.GenerateULIDObject(), Email: "pro@fynance.test", Plan: user.PlanPro}
+	free := &user.User{Id: pkg.GenerateULIDObject(), Email: "free@fynance.test", Plan: user.PlanFree}
+	users := staticUsers{pro.Id: pro, free.Id: free}
+	service := notification.NewService(repo, users, server.sender(), nil, shared.NewUserCheckerService(staticUserChecker{users: users}))
+
+	ctx := context.Background()
+	budgetData := map[string]any{"category": "Alimentação", "spent": 1250.0, "amount": 1000.0}
+	notify := func(u *user.User, notificationType notification.Type, data map[string]any) {
+		t.Helper()
+		if err := service.Notify(ctx, u.Id, string(notificationType), data); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+
+	// canais padrão de orçamento estourado: caixa de entrada e e-mail
+	notify(pro, notification.TypeBudgetExceeded, budgetData)
+	msg, ok := server.waitEmail(2 * time.Second)
+	if !ok {
+		t.Fatal("default channels: no email sent")
+	}
+	if len(msg.To) != 1 || msg.To[0] != pro.Email {
+		t.Errorf("To = %v, want [%s]", msg.To, pro.Email)
+	}
+	if msg.Subject != "Orçamento de Alimentação estourado" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	inbox := repo.inbox(pro.Id)
+	if len(inbox) != 1 || inbox[0].Title != msg.Subject {
+		t.Fatalf("default channels: inbox = %d items, want 1 with the email subject", len(inbox))
+	}
+
+	// e-mail desligado pelo usuário: só a caixa de entrada
+	if _, err := service.UpdatePreferences(ctx, pro.Id, nil, map[notification.Type][]notification.Channel{
+		notification.TypeBudgetExceeded: {notification.ChannelInApp},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	notify(pro, notification.TypeBudgetExceeded, budgetData)
+	if msg, ok := server.waitEmail(200 * time.Millisecond); ok {
+		t.Fatalf("in-app only: unexpected email %q", msg.Subject)
+	}
+	if got := len(repo.inbox(pro.Id)); got != 2 {
+		t.Fatalf("in-app only: inbox = %d items, want 2", got)
+	}
+
+	// só e-mail, no idioma escolhido: nada novo na caixa de entrada
+	locale := notification.LocaleEn
+	if _, err := service.UpdatePreferences(ctx, pro.Id, &locale, map[notification.Type][]notification.Channel{
+		notification.TypeBudgetExceeded: {notification.ChannelEmail},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	notify(pro, notification.TypeBudgetExceeded, budgetData)
+	msg, ok = server.waitEmail(2 * time.Second)
+	if !ok {
+		t.Fatal("email only: no email sent")
+	}
+	if msg.Subject != "Alimentação budget exceeded" {
+		t.Errorf("email only: Subject = %q", msg.Subject)
+	}
+	if got := len(repo.inbox(pro.Id)); got != 2 {
+		t.Fatalf("email only: inbox = %d items, want 2", got)
+	}
+
+	// plano sem notificações: só caixa de entrada, exceto eventos de segurança
+	notify(free, notification.TypeBudgetExceeded, budgetData)
+	if msg, ok := server.waitEmail(200 * time.Millisecond); ok {
+		t.Fatalf("free plan: unexpected email %q", msg.Subject)
+	}
+	if got := len(repo.inbox(free.Id)); got != 1 {
+		t.Fatalf("free plan: inbox = %d items, want 1", got)
+	}
+
+	notify(free, notification.TypeSecurityLogin, map[string]any{"at": time.Now(), "method": "password"})
+	msg, ok = server.waitEmail(2 * time.Second)
+	if !ok {
+		t.Fatal("security event: no email sent on the free plan")
+	}
+	if len(msg.To) != 1 || msg.To[0] != free.Email {
+		t.Errorf("security event: To = %v, want [%s]", msg.To, free.Email)
+	}
+}
@@ -59,21 +59,21 @@ func toDBCategory(c *transaction.Category) *categoryDB {
 
 func (r *TransactionCategoryRepository) Create(ctx context.Context, category *transaction.Category) error {
 	cdb := toDBCategory(category)
-	return r.DB.WithContext(ctx).Table("categories").Create(&cdb).Error
+	return conn(ctx, r.DB).Table("categories").Create(&cdb).Error
 }
 
 func (r *TransactionCategoryRepository) Update(ctx context.Context, category *transaction.Category) error {
 	cdb := toDBCategory(category)
-	return r.DB.WithContext(ctx).Table("categories").Where("id = ?", cdb.Id).Updates(&cdb).Error
+	return conn(ctx, r.DB).Table("categories").Where("id = ?", cdb.Id).Updates(&cdb).Error
 }
 
 func (r *TransactionCategoryRepository) Delete(ctx context.Context, categoryID ulid.ULID, userID ulid.ULID) error {
-	return r.DB.WithContext(ctx).Table("categories").Where("id = ? AND user_id = ?", categoryID.String(), userID.String()).Delete(&categoryDB{}).Error
+	return conn(ctx, r.DB).Table("categories").Where("id = ? AND user_id = ?", categoryID.String(), userID.String()).Delete(&categoryDB{}).Error
 }
 
 func (r *TransactionCategoryRepository) GetByID(ctx context.Context, categoryID ulid.ULID, userID ulid.ULID) (*transaction.Category, error) {
 	var row categoryDB
-	err := r.DB.WithContext(ctx).Table("categories").Where("id = ? AND user_id = ?", categoryID.String(), userID.String()).First(&row).Error
+	err := conn(ctx, r.DB).Table("categories").Where("id = ? AND user_id = ?", categoryID.String(), userID.String()).First(&row).Error
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +86,7 @@ func (r *TransactionCategoryRepository) GetAll(ctx context.Context, userID ulid.
 	}
 	pagination.Normalize()
 
-	baseQuery := r.DB.WithContext(ctx).Table("categories").Where("user_id = ?", userID.String())
+	baseQuery := conn(ctx, r.DB).Table("categories").Where("user_id = ?", userID.String())
 
 	var total int64
 	if err := baseQuery.Count(&total).Error; err != nil {
@@ -118,7 +118,7 @@ func (r *TransactionCategoryRepository) GetByUserID(ctx context.Context, userID
 	}
 	pagination.Normalize()
 
-	baseQuery := r.DB.WithContext(ctx).Table("categories").Where("user_id = ?", userID.String())
+	baseQuery := conn(ctx, r.DB).Table("categories").Where("user_id = ?", userID.String())
 
 	var total int64
 	if err := baseQuery.Count(&total).Error; err != nil {
@@ -149,13 +149,13 @@ func (r *TransactionCategoryRepository) GetByName(ctx context.Context, CategoryN
 	searchName := strings.TrimSpace(CategoryName)
 	searchLower := strings.ToLower(searchName)
 
-	err := r.DB.WithContext(ctx).Table("categories").
+	err := conn(ctx, r.DB).Table("categories").
 		Where("user_id = ? AND (LOWER(TRIM(name)) = ? OR name = ?)", userID.String(), searchLower, searchName).
 		First(&row).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			var allRows []categoryDB
-			err2 := r.DB.WithContext(ctx).Table("categories").
+			err2 := conn(ctx, r.DB).Table("categories").
 				Where("user_id = ?", userID.String()).
 				Find(&allRows).Error
 			if err2 != nil {
@@ -175,7 +175,7 @@ func (r *TransactionCategoryRepository) GetByName(ctx context.Context, CategoryN
 
 func (r *TransactionCategoryRepository) GetAllWithoutLimit(ctx context.Context, userID ulid.ULID) ([]*transaction.Category, error) {
 	var rows []categoryDB
-	err := r.DB.WithContext(ctx).Table("categories").
+	err := conn(ctx, r.DB).Table("categories").
 		Where("user_id = ?", userID.String()).
 		Order("name ASC").
 		Find(&rows).Error
@@ -195,6 +195,6 @@ func (r *TransactionCategoryRepository) GetAllWithoutLimit(ctx context.Context,
 
 func (r *TransactionCategoryRepository) BelongsToUser(ctx context.Context, categoryID ulid.ULID, userID ulid.ULID) (bool, error) {
 	var count int64
-	err := r.DB.WithContext(ctx).Table("categories").Where("id = ? AND user_id = ?", categoryID.String(), userID.String()).Count(&count).Error
+	err := conn(ctx, r.DB).Table("categories").Where("id = ? AND user_id = ?", categoryID.String(), userID.String()).Count(&count).Error
 	return count > 0, err
 }
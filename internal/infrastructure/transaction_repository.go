@@ -116,21 +116,21 @@ func toDBTransaction(t *transaction.Transaction) *transactionDB {
 
 func (r *TransactionRepository) Create(ctx context.Context, t *transaction.Transaction) error {
 	tdb := toDBTransaction(t)
-	return r.DB.WithContext(ctx).Table("transactions").Create(tdb).Error
+	return conn(ctx, r.DB).Table("transactions").Create(tdb).Error
 }
 
 func (r *TransactionRepository) Update(ctx context.Context, t *transaction.Transaction) error {
 	tdb := toDBTransaction(t)
-	return r.DB.WithContext(ctx).Table("transactions").Where("id = ?", tdb.Id).Updates(tdb).Error
+	return conn(ctx, r.DB).Table("transactions").Where("id = ?", tdb.Id).Updates(tdb).Error
 }
 
 func (r *TransactionRepository) Delete(ctx context.Context, transactionID ulid.ULID) error {
-	return r.DB.WithContext(ctx).Table("transactions").Where("id = ?", transactionID.String()).Delete(&transactionDB{}).Error
+	return conn(ctx, r.DB).Table("transactions").Where("id = ?", transactionID.String()).Delete(&transactionDB{}).Error
 }
 
 func (r *TransactionRepository) GetByID(ctx context.Context, transactionID ulid.ULID) (*transaction.Transaction, error) {
 	var tdb transactionDB
-	err := r.DB.WithContext(ctx).Table("transactions t").
+	err := conn(ctx, r.DB).Table("transactions t").
 		Select("t.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.id = ?", transactionID.String()).
@@ -143,7 +143,7 @@ func (r *TransactionRepository) GetByID(ctx context.Context, transactionID ulid.
 
 func (r *TransactionRepository) GetByIDAndUser(ctx context.Context, transactionID, userID ulid.ULID) (*transaction.Transaction, error) {
 	var tdb transactionDB
-	err := r.DB.WithContext(ctx).Table("transactions t").
+	err := conn(ctx, r.DB).Table("transactions t").
 		Select("t.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.id = ? AND t.user_id = ?", transactionID.String(), userID.String()).
@@ -155,8 +155,8 @@ func (r *TransactionRepository) GetByIDAndUser(ctx context.Context, transactionI
 }
 
 func (r *TransactionRepository) GetAll(ctx context.Context, userID ulid.ULID, accountID *ulid.ULID, filters *transaction.TransactionFilters, pagination *pkg.PaginationParams) ([]*transaction.Transaction, int64, error) {
-	countQuery := r.DB.WithContext(ctx).Table("transactions t").Where("t.user_id = ?", userID.String())
-	dataQuery := r.DB.WithContext(ctx).Table("transactions t").
+	countQuery := conn(ctx, r.DB).Table("transactions t").Where("t.user_id = ?", userID.String())
+	dataQuery := conn(ctx, r.DB).Table("transactions t").
 		Select("t.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.user_id = ?", userID.String())
@@ -223,18 +223,18 @@ func (r *TransactionRepository) GetAll(ctx context.Context, userID ulid.ULID, ac
 }
 
 func (r *TransactionRepository) GetByAmount(ctx context.Context, amount float64, pagination *pkg.PaginationParams) ([]*transaction.Transaction, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("transactions").Where("amount = ?", amount)
+	baseQuery := conn(ctx, r.DB).Table("transactions").Where("amount = ?", amount)
 	return pkg.Paginate(baseQuery, pagination, "date DESC, created_at DESC", toDomainTransaction)
 }
 
 func (r *TransactionRepository) GetByName(ctx context.Context, name string, pagination *pkg.PaginationParams) ([]*transaction.Transaction, int64, error) {
-	baseQuery := r.DB.WithContext(ctx).Table("transactions").Where("description LIKE ?", "%"+name+"%")
+	baseQuery := conn(ctx, r.DB).Table("transactions").Where("description LIKE ?", "%"+name+"%")
 	return pkg.Paginate(baseQuery, pagination, "date DESC, created_at DESC", toDomainTransaction)
 }
 
 func (r *TransactionRepository) GetByCategory(ctx context.Context, categoryID ulid.ULID, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*transaction.Transaction, int64, error) {
-	countQuery := r.DB.WithContext(ctx).Table("transactions t").Where("t.user_id = ? AND t.category_id = ?", userID.String(), categoryID.String())
-	dataQuery := r.DB.WithContext(ctx).Table("transactions t").
+	countQuery := conn(ctx, r.DB).Table("transactions t").Where("t.user_id = ? AND t.category_id = ?", userID.String(), categoryID.String())
+	dataQuery := conn(ctx, r.DB).Table("transactions t").
 		Select("t.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.user_id = ? AND t.category_id = ?", userID.String(), categoryID.String())
@@ -268,8 +268,8 @@ func (r *TransactionRepository) GetByCategory(ctx context.Context, categoryID ul
 }
 
 func (r *TransactionRepository) GetByInvestmentID(ctx context.Context, investmentID ulid.ULID, userID ulid.ULID, pagination *pkg.PaginationParams) ([]*transaction.Transaction, int64, error) {
-	countQuery := r.DB.WithContext(ctx).Table("transactions t").Where("t.investment_id = ? AND t.user_id = ?", investmentID.String(), userID.String())
-	dataQuery := r.DB.WithContext(ctx).Table("transactions t").
+	countQuery := conn(ctx, r.DB).Table("transactions t").Where("t.investment_id = ? AND t.user_id = ?", investmentID.String(), userID.String())
+	dataQuery := conn(ctx, r.DB).Table("transactions t").
 		Select("t.*, c.name as category_name").
 		Joins("LEFT JOIN categories c ON t.category_id = c.id").
 		Where("t.investment_id = ? AND t.user_id = ?", investmentID.String(), userID.String())
@@ -304,6 +304,6 @@ func (r *TransactionRepository) GetByInvestmentID(ctx context.Context, investmen
 
 func (r *TransactionRepository) GetNumberOfTransactions(ctx context.Context, userID ulid.ULID) (int64, error) {
 	var count int64
-	err := r.DB.WithContext(ctx).Model(&transaction.Transaction{}).Where("user_id = ?", userID.String()).Count(&count).Error
+	err := conn(ctx, r.DB).Model(&transaction.Transaction{}).Where("user_id = ?", userID.String()).Count(&count).Error
 	return count, err
 }
@@ -0,0 +1,42 @@
+package infrastructure
+
+import (
+	"context"
+
+	"Fynance/internal/domain/shared"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// GormTransactor abre transações do gorm e guarda a conexão no ctx para os repositórios
+type GormTransactor struct {
+	DB *gorm.DB
+}
+
+var _ shared.Transactor = (*GormTransactor)(nil)
+
+func (t *GormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	ctx, runHooks := shared.WithCommitHooks(ctx)
+	err := t.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	if err != nil {
+		return err
+	}
+	runHooks()
+	return nil
+}
+
+// conn devolve a transação aberta no ctx pelo GormTransactor ou, fora dela, a conexão do repositório
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
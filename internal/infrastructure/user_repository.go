@@ -70,7 +70,7 @@ func toDBUser(u *user.User) *userDB {
 
 func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 	udb := toDBUser(u)
-	if err := r.DB.WithContext(ctx).Table("users").Create(udb).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("users").Create(udb).Error; err != nil {
 		return appErrors.NewDatabaseError(err)
 	}
 	return nil
@@ -78,14 +78,14 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	udb := toDBUser(u)
-	if err := r.DB.WithContext(ctx).Table("users").Where("id = ?", udb.Id).Updates(udb).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("users").Where("id = ?", udb.Id).Updates(udb).Error; err != nil {
 		return appErrors.NewDatabaseError(err)
 	}
 	return nil
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id ulid.ULID) error {
-	result := r.DB.WithContext(ctx).Table("users").Where("id = ?", id.String()).Delete(&userDB{})
+	result := conn(ctx, r.DB).Table("users").Where("id = ?", id.String()).Delete(&userDB{})
 	if result.Error != nil {
 		return appErrors.NewDatabaseError(result.Error)
 	}
@@ -97,7 +97,7 @@ func (r *UserRepository) Delete(ctx context.Context, id ulid.ULID) error {
 
 func (r *UserRepository) GetByID(ctx context.Context, id ulid.ULID) (*user.User, error) {
 	var udb userDB
-	if err := r.DB.WithContext(ctx).Table("users").Where("id = ?", id.String()).First(&udb).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("users").Where("id = ?", id.String()).First(&udb).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, appErrors.ErrUserNotFound.WithError(err)
 		}
@@ -108,7 +108,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id ulid.ULID) (*user.User,
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	var udb userDB
-	if err := r.DB.WithContext(ctx).Table("users").Where("email = ?", email).First(&udb).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("users").Where("email = ?", email).First(&udb).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, appErrors.ErrUserNotFound.WithError(err)
 		}
@@ -119,7 +119,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 
 func (r *UserRepository) GetPlan(ctx context.Context, id ulid.ULID) (user.Plan, error) {
 	var udb userDB
-	if err := r.DB.WithContext(ctx).Table("users").Select("plan").Where("id = ?", id.String()).First(&udb).Error; err != nil {
+	if err := conn(ctx, r.DB).Table("users").Select("plan").Where("id = ?", id.String()).First(&udb).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return "", appErrors.ErrUserNotFound.WithError(err)
 		}
@@ -127,3 +127,29 @@ func (r *UserRepository) GetPlan(ctx context.Context, id ulid.ULID) (user.Plan,
 	}
 	return user.Plan(udb.Plan), nil
 }
+
+func (r *UserRepository) ListIDs(ctx context.Context, pagination *pkg.PaginationParams) ([]ulid.ULID, int64, error) {
+	pagination = pkg.NormalizePagination(pagination)
+
+	query := conn(ctx, r.DB).Table("users")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, appErrors.NewDatabaseError(err)
+	}
+
+	var rows []string
+	if err := query.Order("id ASC").Offset(pagination.Offset()).Limit(pagination.Limit).Pluck("id", &rows).Error; err != nil {
+		return nil, 0, appErrors.NewDatabaseError(err)
+	}
+
+	ids := make([]ulid.ULID, 0, len(rows))
+	for _, row := range rows {
+		id, err := pkg.ParseULID(row)
+		if err != nil {
+			return nil, 0, appErrors.ErrInternalServer.WithError(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, total, nil
+}
@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"Fynance/internal/domain/webhook"
+)
+
+// webhookResponseLimit limita quanto da resposta do endpoint é lido
+const webhookResponseLimit = 4096
+
+// HTTPWebhookSender entrega os eventos por POST. Redirecionamentos não são seguidos e, a menos que
+// AllowPrivateNetworks esteja ativo, conexões para endereços de loopback, rede privada, link-local
+// ou não roteáveis são recusadas na hora da conexão, depois da resolução de DNS.
+type HTTPWebhookSender struct {
+	Client *http.Client
+}
+
+var _ webhook.Sender = (*HTTPWebhookSender)(nil)
+
+func NewHTTPWebhookSender(timeout time.Duration, allowPrivateNetworks bool) *HTTPWebhookSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = rejectPrivateAddress
+	}
+	transport := &http.Transport{
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &HTTPWebhookSender{
+		Client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *HTTPWebhookSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (*webhook.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("User-Agent", "Fynance-Webhooks/1.0")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	return &webhook.Response{StatusCode: resp.StatusCode, Body: string(detail)}, nil
+}
+
+var errPrivateAddress = errors.New("destino em rede privada nao permitido")
+
+func rejectPrivateAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() || !ip.IsGlobalUnicast() || isSharedAddress(ip) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, ip)
+	}
+	return nil
+}
+
+// isSharedAddress cobre a faixa de CGNAT (100.64.0.0/10), que IsPrivate não inclui
+func isSharedAddress(ip netip.Addr) bool {
+	return netip.MustParsePrefix("100.64.0.0/10").Contains(ip)
+}
@@ -0,0 +1,457 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Fynance/internal/domain/shared"
+	"Fynance/internal/domain/user"
+	"Fynance/internal/domain/webhook"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// webhookRequest é uma requisição recebida pelo receptor de teste
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver é um endpoint httptest que guarda as requisições e responde com o status configurado
+type webhookReceiver struct {
+	*httptest.Server
+	status atomic.Int32
+
+	mu       sync.Mutex
+	requests []webhookRequest
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+	receiver := &webhookReceiver{}
+	receiver.status.Store(http.StatusOK)
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		receiver.requests = append(receiver.requests, webhookRequest{header: r.Header.Clone(), body: body})
+		receiver.mu.Unlock()
+		w.WriteHeader(int(receiver.status.Load()))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) received() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.requests)
+}
+
+// memoryWebhookRepository guarda endpoints e entregas em memória, com as mesmas regras de reserva
+// e de entrega única por evento do repositório em banco
+type memoryWebhookRepository struct {
+	mu         sync.Mutex
+	endpoints  map[ulid.ULID]*webhook.Endpoint
+	deliveries []*webhook.Delivery
+}
+
+var _ webhook.Repository = (*memoryWebhookRepository)(nil)
+
+func newMemoryWebhookRepository() *memoryWebhookRepository {
+	return &memoryWebhookRepository{endpoints: make(map[ulid.ULID]*webhook.Endpoint)}
+}
+
+func copyDelivery(delivery *webhook.Delivery) *webhook.Delivery {
+	cp := *delivery
+	return &cp
+}
+
+func (r *memoryWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *endpoint
+	r.endpoints[endpoint.Id] = &cp
+	return nil
+}
+
+func (r *memoryWebhookRepository) UpdateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	return r.CreateEndpoint(ctx, endpoint)
+}
+
+func (r *memoryWebhookRepository) DeleteEndpoint(ctx context.Context, id, userID ulid.ULID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.endpoints, id)
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetEndpoint(ctx context.Context, id, userID ulid.ULID) (*webhook.Endpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	endpoint, ok := r.endpoints[id]
+	if !ok || endpoint.UserId != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *endpoint
+	return &cp, nil
+}
+
+func (r *memoryWebhookRepository) ListEndpoints(ctx context.Context, userID ulid.ULID) ([]*webhook.Endpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var endpoints []*webhook.Endpoint
+	for _, endpoint := range r.endpoints {
+		if endpoint.UserId == userID {
+			cp := *endpoint
+			endpoints = append(endpoints, &cp)
+		}
+	}
+	return endpoints, nil
+}
+
+func (r *memoryWebhookRepository) CountEndpoints(ctx context.Context, userID ulid.ULID) (int64, error) {
+	endpoints, err := r.ListEndpoints(ctx, userID)
+	return int64(len(endpoints)), err
+}
+
+func (r *memoryWebhookRepository) CreateDelivery(ctx context.Context, delivery *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, copyDelivery(delivery))
+	return nil
+}
+
+func (r *memoryWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, delivery := range deliveries {
+		duplicate := slices.ContainsFunc(r.deliveries, func(existing *webhook.Delivery) bool {
+			return existing.RedeliveryOf == nil && existing.EndpointId == delivery.EndpointId && existing.EventId == delivery.EventId
+		})
+		if !duplicate {
+			r.deliveries = append(r.deliveries, copyDelivery(delivery))
+		}
+	}
+	return nil
+}
+
+func (r *memoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.deliveries {
+		if existing.Id == delivery.Id {
+			r.deliveries[i] = copyDelivery(delivery)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryWebhookRepository) GetDelivery(ctx context.Context, id, endpointID, userID ulid.ULID) (*webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, delivery := range r.deliveries {
+		if delivery.Id == id && delivery.EndpointId == endpointID && delivery.UserId == userID {
+			return copyDelivery(delivery), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryWebhookRepository) ListDeliveries(ctx context.Context, endpointID, userID ulid.ULID, status webhook.DeliveryStatus, pagination *pkg.PaginationParams) ([]*webhook.Delivery, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deliveries []*webhook.Delivery
+	for _, delivery := range r.deliveries {
+		if delivery.EndpointId == endpointID && delivery.UserId == userID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+	return deliveries, int64(len(deliveries)), nil
+}
+
+func (r *memoryWebhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []*webhook.Delivery
+	for _, delivery := range r.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if delivery.Status != webhook.DeliveryPending || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		lease := leaseUntil
+		delivery.NextAttemptAt = &lease
+		claimed = append(claimed, copyDelivery(delivery))
+	}
+	return claimed, nil
+}
+
+func (r *memoryWebhookRepository) all() []*webhook.Delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deliveries := make([]*webhook.Delivery, 0, len(r.deliveries))
+	for _, delivery := range r.deliveries {
+		deliveries = append(deliveries, copyDelivery(delivery))
+	}
+	return deliveries
+}
+
+// newWebhookTestService monta o service com o sender HTTP real, liberado para o receptor em loopback,
+// e um endpoint ativo inscrito em transaction.created
+func newWebhookTestService(t *testing.T, receiver *webhookReceiver) (*webhook.Service, *memoryWebhookRepository, *webhook.Endpoint) {
+	t.Helper()
+	owner := &user.User{Id: pkg.GenerateULIDObject()}
+	users := staticUsers{owner.Id: owner}
+
+	repo := newMemoryWebhookRepository()
+	service := webhook.NewService(repo, NewHTTPWebhookSender(time.Second, true), shared.NewUserCheckerService(staticUserChecker{users: users}))
+
+	endpoint := &webhook.Endpoint{
+		Id:     pkg.GenerateULIDObject(),
+		UserId: owner.Id,
+		URL:    receiver.URL + "/hooks",
+		Secret: "whsec_test",
+		Events: []string{shared.EventTransactionCreated},
+		Active: true,
+	}
+	if err := repo.CreateEndpoint(context.Background(), endpoint); err != nil {
+		t.Fatalf("CreateEndpoint: %v", err)
+	}
+	return service, repo, endpoint
+}
+
+// verifySignature confere X-Fynance-Signature como um receptor faria: recalcula o HMAC-SHA256 de
+// "<t>.<corpo>" com o segredo e compara em tempo constante
+func verifySignature(t *testing.T, secret, header string, body []byte) {
+	t.Helper()
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp da assinatura invalido em %q", header)
+	}
+	if age := time.Since(time.Unix(unix, 0)); age < -time.Minute || age > time.Minute {
+		t.Errorf("timestamp da assinatura com %v de diferença", age)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(signature), []byte(want)) {
+		t.Errorf("v1 = %q, want %q", signature, want)
+	}
+}
+
+func TestWebhookDeliverySignature(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	service, repo, endpoint := newWebhookTestService(t, receiver)
+	ctx := context.Background()
+
+	data := map[string]any{"description": "Mercado", "amount": 152.3}
+	if err := service.Publish(ctx, endpoint.UserId, shared.EventTransactionCreated, data); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	// a publicação só registra a entrega; o envio fica com o job
+	if got := len(receiver.received()); got != 0 {
+		t.Fatalf("requisições antes do job = %d, want 0", got)
+	}
+
+	if err := service.ProcessDueDeliveries(ctx, time.Now()); err != nil {
+		t.Fatalf("ProcessDueDeliveries: %v", err)
+	}
+
+	requests := receiver.received()
+	if len(requests) != 1 {
+		t.Fatalf("requisições = %d, want 1", len(requests))
+	}
+	deliveries := repo.all()
+	if len(deliveries) != 1 {
+		t.Fatalf("entregas = %d, want 1", len(deliveries))
+	}
+	delivery := deliveries[0]
+
+	request := requests[0]
+	if got := request.header.Get(webhook.HeaderEvent); got != shared.EventTransactionCreated {
+		t.Errorf("%s = %q, want %q", webhook.HeaderEvent, got, shared.EventTransactionCreated)
+	}
+	if got := request.header.Get(webhook.HeaderDelivery); got != delivery.Id.String() {
+		t.Errorf("%s = %q, want %q", webhook.HeaderDelivery, got, delivery.Id)
+	}
+	if got := request.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if string(request.body) != string(delivery.Payload) {
+		t.Errorf("corpo = %s, want %s", request.body, delivery.Payload)
+	}
+	verifySignature(t, endpoint.Secret, request.header.Get(webhook.HeaderSignature), request.body)
+
+	if delivery.Status != webhook.DeliverySucceeded || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusOK {
+		t.Errorf("entrega = status %s, tentativas %d, resposta %d; want SUCCEEDED, 1, 200", delivery.Status, delivery.Attempts, delivery.ResponseStatus)
+	}
+	if delivery.NextAttemptAt != nil || delivery.DeliveredAt == nil {
+		t.Errorf("NextAttemptAt = %v, DeliveredAt = %v; want nil e preenchido", delivery.NextAttemptAt, delivery.DeliveredAt)
+	}
+}
+
+func TestWebhookDeliveryRetryBackoff(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	receiver.status.Store(http.StatusInternalServerError)
+	service, repo, endpoint := newWebhookTestService(t, receiver)
+	service.Retry = webhook.RetryPolicy{MaxAttempts: 4, BaseDelay: 30 * time.Second, MaxDelay: time.Minute}
+	ctx := context.Background()
+
+	if err := service.Publish(ctx, endpoint.UserId, shared.EventTransactionCreated, map[string]any{"amount": 10}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	// a espera dobra a cada falha até o teto da política
+	wantDelays := []time.Duration{30 * time.Second, time.Minute, time.Minute}
+	now := time.Now()
+	for attempt, want := range wantDelays {
+		if err := service.ProcessDueDeliveries(ctx, now); err != nil {
+			t.Fatalf("ProcessDueDeliveries: %v", err)
+		}
+		delivery := repo.all()[0]
+		if delivery.Status != webhook.DeliveryPending || delivery.Attempts != attempt+1 {
+			t.Fatalf("tentativa %d: status %s com %d tentativas, want PENDING com %d", attempt+1, delivery.Status, delivery.Attempts, attempt+1)
+		}
+		if delivery.NextAttemptAt == nil || delivery.LastAttemptAt == nil {
+			t.Fatalf("tentativa %d sem próxima tentativa agendada", attempt+1)
+		}
+		if got := delivery.NextAttemptAt.Sub(*delivery.LastAttemptAt); got != want {
+			t.Errorf("tentativa %d: espera = %v, want %v", attempt+1, got, want)
+		}
+		if delivery.ResponseStatus != http.StatusInternalServerError || delivery.Error == "" {
+			t.Errorf("tentativa %d: resposta %d, erro %q", attempt+1, delivery.ResponseStatus, delivery.Error)
+		}
+
+		// antes do prazo a entrega não é reenviada
+		if err := service.ProcessDueDeliveries(ctx, delivery.NextAttemptAt.Add(-time.Second)); err != nil {
+			t.Fatalf("ProcessDueDeliveries: %v", err)
+		}
+		if got := len(receiver.received()); got != attempt+1 {
+			t.Fatalf("requisições = %d antes do prazo, want %d", got, attempt+1)
+		}
+		now = *delivery.NextAttemptAt
+	}
+
+	// a última tentativa esgota a política e encerra a entrega
+	if err := service.ProcessDueDeliveries(ctx, now); err != nil {
+		t.Fatalf("ProcessDueDeliveries: %v", err)
+	}
+	delivery := repo.all()[0]
+	if delivery.Status != webhook.DeliveryFailed || delivery.Attempts != 4 || delivery.NextAttemptAt != nil {
+		t.Errorf("entrega = status %s, tentativas %d, próxima %v; want FAILED, 4, nil", delivery.Status, delivery.Attempts, delivery.NextAttemptAt)
+	}
+	if err := service.ProcessDueDeliveries(ctx, now.Add(time.Hour)); err != nil {
+		t.Fatalf("ProcessDueDeliveries: %v", err)
+	}
+	if got := len(receiver.received()); got != 4 {
+		t.Errorf("requisições = %d, want 4", got)
+	}
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	receiver.status.Store(http.StatusServiceUnavailable)
+	service, repo, endpoint := newWebhookTestService(t, receiver)
+	service.Retry = webhook.RetryPolicy{MaxAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Second}
+	ctx := context.Background()
+
+	if err := service.Publish(ctx, endpoint.UserId, shared.EventTransactionCreated, map[string]any{"amount": 10}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := service.ProcessDueDeliveries(ctx, time.Now()); err != nil {
+		t.Fatalf("ProcessDueDeliveries: %v", err)
+	}
+	original := repo.all()[0]
+	if original.Status != webhook.DeliveryFailed {
+		t.Fatalf("entrega original = %s, want FAILED", original.Status)
+	}
+
+	receiver.status.Store(http.StatusNoContent)
+	redelivery, err := service.Redeliver(ctx, endpoint.Id, original.Id, endpoint.UserId)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+
+	if redelivery.Id == original.Id || redelivery.RedeliveryOf == nil || *redelivery.RedeliveryOf != original.Id {
+		t.Errorf("reenvio = id %s, RedeliveryOf %v; want nova entrega ligada a %s", redelivery.Id, redelivery.RedeliveryOf, original.Id)
+	}
+	if redelivery.EventId != original.EventId {
+		t.Errorf("EventId = %s, want %s", redelivery.EventId, original.EventId)
+	}
+	if redelivery.Status != webhook.DeliverySucceeded || redelivery.Attempts != 1 {
+		t.Errorf("reenvio = status %s, tentativas %d; want SUCCEEDED, 1", redelivery.Status, redelivery.Attempts)
+	}
+
+	requests := receiver.received()
+	if len(requests) != 2 {
+		t.Fatalf("requisições = %d, want 2", len(requests))
+	}
+	request := requests[1]
+	if string(request.body) != string(original.Payload) {
+		t.Errorf("corpo do reenvio = %s, want o corpo original %s", request.body, original.Payload)
+	}
+	if got := request.header.Get(webhook.HeaderDelivery); got != redelivery.Id.String() {
+		t.Errorf("%s = %q, want %q", webhook.HeaderDelivery, got, redelivery.Id)
+	}
+	verifySignature(t, endpoint.Secret, request.header.Get(webhook.HeaderSignature), request.body)
+
+	// o histórico da entrega original é mantido
+	deliveries := repo.all()
+	if len(deliveries) != 2 || deliveries[0].Status != webhook.DeliveryFailed {
+		t.Errorf("entregas = %d, original %s; want 2 com a original FAILED", len(deliveries), deliveries[0].Status)
+	}
+
+}
+
+func TestWebhookDeliveryOfRemovedEndpointFails(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	service, repo, endpoint := newWebhookTestService(t, receiver)
+	ctx := context.Background()
+
+	if err := service.Publish(ctx, endpoint.UserId, shared.EventTransactionCreated, map[string]any{"amount": 10}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := repo.DeleteEndpoint(ctx, endpoint.Id, endpoint.UserId); err != nil {
+		t.Fatalf("DeleteEndpoint: %v", err)
+	}
+
+	if err := service.ProcessDueDeliveries(ctx, time.Now()); err != nil {
+		t.Fatalf("ProcessDueDeliveries: %v", err)
+	}
+	delivery := repo.all()[0]
+	if delivery.Status != webhook.DeliveryFailed || delivery.NextAttemptAt != nil || delivery.Error != "webhook removido" {
+		t.Errorf("entrega = status %s, próxima %v, erro %q; want FAILED, nil, webhook removido", delivery.Status, delivery.NextAttemptAt, delivery.Error)
+	}
+	if got := len(receiver.received()); got != 0 {
+		t.Errorf("requisições = %d, want 0", got)
+	}
+}
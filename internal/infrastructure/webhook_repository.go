@@ -0,0 +1,310 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"Fynance/internal/domain/webhook"
+	"Fynance/internal/pkg"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+var _ webhook.Repository = (*WebhookRepository)(nil)
+
+type webhookEndpointDB struct {
+	Id          string    `gorm:"type:varchar(26);primaryKey"`
+	UserId      string    `gorm:"type:varchar(26);not null"`
+	URL         string    `gorm:"column:url;type:varchar(500);not null"`
+	Description string    `gorm:"type:varchar(255)"`
+	Secret      string    `gorm:"type:varchar(100);not null"`
+	Events      []string  `gorm:"type:text;serializer:json;not null"`
+	Active      bool      `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (webhookEndpointDB) TableName() string {
+	return "webhook_endpoints"
+}
+
+type webhookDeliveryDB struct {
+	Id             string     `gorm:"type:varchar(26);primaryKey"`
+	EndpointId     string     `gorm:"type:varchar(26);not null"`
+	UserId         string     `gorm:"type:varchar(26);not null"`
+	EventId        string     `gorm:"type:varchar(26);not null"`
+	EventType      string     `gorm:"type:varchar(50);not null"`
+	Payload        string     `gorm:"type:text;not null"`
+	Status         string     `gorm:"type:varchar(20);not null"`
+	Attempts       int        `gorm:"not null"`
+	NextAttemptAt  *time.Time `gorm:"type:timestamp"`
+	LastAttemptAt  *time.Time `gorm:"type:timestamp"`
+	ResponseStatus int        `gorm:"not null"`
+	ResponseBody   string     `gorm:"type:text"`
+	Error          string     `gorm:"type:text"`
+	DurationMs     int64      `gorm:"not null"`
+	RedeliveryOf   *string    `gorm:"type:varchar(26)"`
+	DeliveredAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt      time.Time  `gorm:"not null"`
+}
+
+func (webhookDeliveryDB) TableName() string {
+	return "webhook_deliveries"
+}
+
+func toDomainWebhookEndpoint(edb *webhookEndpointDB) (*webhook.Endpoint, error) {
+	id, err := pkg.ParseULID(edb.Id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(edb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	events := edb.Events
+	if events == nil {
+		events = []string{}
+	}
+	return &webhook.Endpoint{
+		Id:          id,
+		UserId:      userID,
+		URL:         edb.URL,
+		Description: edb.Description,
+		Secret:      edb.Secret,
+		Events:      events,
+		Active:      edb.Active,
+		CreatedAt:   edb.CreatedAt,
+		UpdatedAt:   edb.UpdatedAt,
+	}, nil
+}
+
+func toWebhookEndpointDB(endpoint *webhook.Endpoint) *webhookEndpointDB {
+	return &webhookEndpointDB{
+		Id:          endpoint.Id.String(),
+		UserId:      endpoint.UserId.String(),
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		Secret:      endpoint.Secret,
+		Events:      endpoint.Events,
+		Active:      endpoint.Active,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+}
+
+func toDomainWebhookDelivery(ddb *webhookDeliveryDB) (*webhook.Delivery, error) {
+	id, err := pkg.ParseULID(ddb.Id)
+	if err != nil {
+		return nil, err
+	}
+	endpointID, err := pkg.ParseULID(ddb.EndpointId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := pkg.ParseULID(ddb.UserId)
+	if err != nil {
+		return nil, err
+	}
+	eventID, err := pkg.ParseULID(ddb.EventId)
+	if err != nil {
+		return nil, err
+	}
+	redeliveryOf, err := pkg.MustParseULIDPtr(ddb.RedeliveryOf)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook.Delivery{
+		Id:             id,
+		EndpointId:     endpointID,
+		UserId:         userID,
+		EventId:        eventID,
+		EventType:      ddb.EventType,
+		Payload:        []byte(ddb.Payload),
+		Status:         webhook.DeliveryStatus(ddb.Status),
+		Attempts:       ddb.Attempts,
+		NextAttemptAt:  ddb.NextAttemptAt,
+		LastAttemptAt:  ddb.LastAttemptAt,
+		ResponseStatus: ddb.ResponseStatus,
+		ResponseBody:   ddb.ResponseBody,
+		Error:          ddb.Error,
+		DurationMs:     ddb.DurationMs,
+		RedeliveryOf:   redeliveryOf,
+		DeliveredAt:    ddb.DeliveredAt,
+		CreatedAt:      ddb.CreatedAt,
+	}, nil
+}
+
+func toWebhookDeliveryDB(delivery *webhook.Delivery) *webhookDeliveryDB {
+	var redeliveryOf *string
+	if delivery.RedeliveryOf != nil {
+		value := delivery.RedeliveryOf.String()
+		redeliveryOf = &value
+	}
+	return &webhookDeliveryDB{
+		Id:             delivery.Id.String(),
+		EndpointId:     delivery.EndpointId.String(),
+		UserId:         delivery.UserId.String(),
+		EventId:        delivery.EventId.String(),
+		EventType:      delivery.EventType,
+		Payload:        string(delivery.Payload),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		Error:          delivery.Error,
+		DurationMs:     delivery.DurationMs,
+		RedeliveryOf:   redeliveryOf,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	return conn(ctx, r.DB).Create(toWebhookEndpointDB(endpoint)).Error
+}
+
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	result := conn(ctx, r.DB).
+		Where("id = ? AND user_id = ?", endpoint.Id.String(), endpoint.UserId.String()).
+		Select("url", "description", "secret", "events", "active", "updated_at").
+		Updates(toWebhookEndpointDB(endpoint))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id, userID ulid.ULID) error {
+	return conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id.String(), userID.String()).Delete(&webhookEndpointDB{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("endpoint_id = ?", id.String()).Delete(&webhookDeliveryDB{}).Error
+	})
+}
+
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, id, userID ulid.ULID) (*webhook.Endpoint, error) {
+	var row webhookEndpointDB
+	if err := conn(ctx, r.DB).Where("id = ? AND user_id = ?", id.String(), userID.String()).First(&row).Error; err != nil {
+		return nil, err
+	}
+	return toDomainWebhookEndpoint(&row)
+}
+
+func (r *WebhookRepository) ListEndpoints(ctx context.Context, userID ulid.ULID) ([]*webhook.Endpoint, error) {
+	var rows []webhookEndpointDB
+	if err := conn(ctx, r.DB).Where("user_id = ?", userID.String()).Order("created_at").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]*webhook.Endpoint, 0, len(rows))
+	for i := range rows {
+		endpoint, err := toDomainWebhookEndpoint(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+func (r *WebhookRepository) CountEndpoints(ctx context.Context, userID ulid.ULID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.DB).Model(&webhookEndpointDB{}).Where("user_id = ?", userID.String()).Count(&count).Error
+	return count, err
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *webhook.Delivery) error {
+	return conn(ctx, r.DB).Create(toWebhookDeliveryDB(delivery)).Error
+}
+
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	rows := make([]*webhookDeliveryDB, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		rows = append(rows, toWebhookDeliveryDB(delivery))
+	}
+	return conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *webhook.Delivery) error {
+	return conn(ctx, r.DB).
+		Where("id = ?", delivery.Id.String()).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "response_body", "error", "duration_ms", "delivered_at").
+		Updates(toWebhookDeliveryDB(delivery)).Error
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id, endpointID, userID ulid.ULID) (*webhook.Delivery, error) {
+	var row webhookDeliveryDB
+	err := conn(ctx, r.DB).
+		Where("id = ? AND endpoint_id = ? AND user_id = ?", id.String(), endpointID.String(), userID.String()).
+		First(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainWebhookDelivery(&row)
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, endpointID, userID ulid.ULID, status webhook.DeliveryStatus, pagination *pkg.PaginationParams) ([]*webhook.Delivery, int64, error) {
+	query := conn(ctx, r.DB).Model(&webhookDeliveryDB{}).
+		Where("endpoint_id = ? AND user_id = ?", endpointID.String(), userID.String())
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	return pkg.Paginate(query, pagination, "created_at DESC, id DESC", toDomainWebhookDelivery)
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*webhook.Delivery, error) {
+	var rows []webhookDeliveryDB
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("status = ? AND next_attempt_at <= ?", string(webhook.DeliveryPending), now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.Id)
+		}
+		return tx.Model(&webhookDeliveryDB{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*webhook.Delivery, 0, len(rows))
+	for i := range rows {
+		delivery, err := toDomainWebhookDelivery(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
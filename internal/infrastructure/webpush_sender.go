@@ -0,0 +1,208 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/notification"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// webPushRecordSize é o tamanho de registro anunciado no cabeçalho aes128gcm; a mensagem cabe em um registro
+	webPushRecordSize = 4096
+	// webPushMaxPayload é o maior payload aceito pelos serviços de push depois de cifrado
+	webPushMaxPayload = 3993
+	vapidTokenTTL     = 12 * time.Hour
+)
+
+// WebPushSender envia notificações Web Push (RFC 8030) cifradas com aes128gcm (RFC 8291) e
+// identificadas pelo servidor com VAPID (RFC 8292). As chaves VAPID são as de P-256 em base64url:
+// a privada com os 32 bytes do escalar e a pública no formato não comprimido.
+type WebPushSender struct {
+	Subject    string
+	TTL        time.Duration
+	Client     *http.Client
+	publicKey  string
+	privateKey *ecdsa.PrivateKey
+}
+
+var _ notification.PushSender = (*WebPushSender)(nil)
+
+func NewWebPushSender(publicKey, privateKey, subject string, ttl, timeout time.Duration) (*WebPushSender, error) {
+	rawPrivate, err := decodeBase64URL(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID_PRIVATE_KEY invalida: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), rawPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID_PRIVATE_KEY invalida: %w", err)
+	}
+	ecdhKey, err := key.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	derived := base64.RawURLEncoding.EncodeToString(ecdhKey.PublicKey().Bytes())
+	if publicKey != "" && strings.TrimRight(publicKey, "=") != derived {
+		return nil, errors.New("VAPID_PUBLIC_KEY nao corresponde a VAPID_PRIVATE_KEY")
+	}
+	if !strings.HasPrefix(subject, "mailto:") && !strings.HasPrefix(subject, "https://") {
+		return nil, errors.New("VAPID_SUBJECT deve comecar com mailto: ou https://")
+	}
+
+	return &WebPushSender{
+		Subject:    subject,
+		TTL:        ttl,
+		Client:     &http.Client{Timeout: timeout},
+		publicKey:  derived,
+		privateKey: key,
+	}, nil
+}
+
+func (s *WebPushSender) PublicKey() string {
+	return s.publicKey
+}
+
+func (s *WebPushSender) Send(ctx context.Context, subscription *notification.PushSubscription, payload []byte) error {
+	if len(payload) > webPushMaxPayload {
+		return fmt.Errorf("payload de push com %d bytes excede o limite de %d", len(payload), webPushMaxPayload)
+	}
+
+	endpoint, err := url.Parse(subscription.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return fmt.Errorf("endpoint de push invalido: %s", subscription.Endpoint)
+	}
+
+	body, err := encryptWebPush(subscription, payload)
+	if err != nil {
+		return err
+	}
+	token, err := s.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "vapid t="+token+", k="+s.publicKey)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(s.TTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return notification.ErrSubscriptionGone
+	case resp.StatusCode >= 300:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("servico de push respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// vapidToken assina o JWT que identifica o servidor para a origem do serviço de push
+func (s *WebPushSender) vapidToken(audience string) (string, error) {
+	claims := jwt.MapClaims{
+		"aud": audience,
+		"exp": time.Now().Add(vapidTokenTTL).Unix(),
+		"sub": s.Subject,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(s.privateKey)
+}
+
+// encryptWebPush cifra o payload para a inscrição com uma chave efêmera, produzindo um único registro
+// aes128gcm: salt (16) | tamanho do registro (4) | tamanho da chave (1) | chave pública efêmera (65) | dados
+func encryptWebPush(subscription *notification.PushSubscription, payload []byte) ([]byte, error) {
+	rawUserAgentKey, err := decodeBase64URL(subscription.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("chave p256dh invalida: %w", err)
+	}
+	userAgentKey, err := ecdh.P256().NewPublicKey(rawUserAgentKey)
+	if err != nil {
+		return nil, fmt.Errorf("chave p256dh invalida: %w", err)
+	}
+	authSecret, err := decodeBase64URL(subscription.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("segredo auth invalido")
+	}
+
+	ephemeral, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := ephemeral.ECDH(userAgentKey)
+	if err != nil {
+		return nil, err
+	}
+	serverKey := ephemeral.PublicKey().Bytes()
+
+	keyInfo := "WebPush: info\x00" + string(userAgentKey.Bytes()) + string(serverKey)
+	ikm, err := hkdf.Key(sha256.New, sharedSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	contentKey, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 0x02 delimita o último (e único) registro
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	header := make([]byte, 0, 16+4+1+len(serverKey))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(serverKey)))
+	header = append(header, serverKey...)
+	return append(header, ciphertext...), nil
+}
+
+// decodeBase64URL aceita base64url com ou sem padding, como enviado pelos navegadores
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(value), "="))
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader é o cabeçalho com o token dos endpoints administrativos
+const AdminTokenHeader = "X-Admin-Token"
+
+func respondAdmin(c *gin.Context, err *appErrors.AppError) {
+	c.JSON(err.StatusCode, gin.H{
+		"error":   err.Code,
+		"message": err.Message,
+	})
+	c.Abort()
+}
+
+// RequireAdminToken libera a rota só para quem envia o token configurado em ADMIN_TOKEN. Sem token
+// configurado as rotas administrativas ficam desativadas.
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			err := appErrors.WrapError(nil, appErrors.ErrForbidden.Code, "Endpoints administrativos desativados", http.StatusForbidden)
+			respondAdmin(c, err)
+			return
+		}
+
+		provided := c.GetHeader(AdminTokenHeader)
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			logger.Warn().Str("path", c.FullPath()).Str("ip", c.ClientIP()).Msg("token administrativo inválido")
+			respondAdmin(c, appErrors.ErrUnauthorized)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,99 @@
+package pkg
+
+import "time"
+
+// BrazilianHolidays retorna os feriados nacionais (incluindo Carnaval e Corpus Christi,
+// que não abrem o mercado financeiro) do ano informado, em UTC e sem horário
+func BrazilianHolidays(year int) []time.Time {
+	easter := easterSunday(year)
+	holidays := []time.Time{
+		date(year, time.January, 1),   // Confraternização Universal
+		date(year, time.April, 21),    // Tiradentes
+		date(year, time.May, 1),       // Dia do Trabalho
+		date(year, time.September, 7), // Independência
+		date(year, time.October, 12),  // Nossa Senhora Aparecida
+		date(year, time.November, 2),  // Finados
+		date(year, time.November, 15), // Proclamação da República
+		date(year, time.December, 25), // Natal
+		easter.AddDate(0, 0, -48),     // Carnaval (segunda)
+		easter.AddDate(0, 0, -47),     // Carnaval (terça)
+		easter.AddDate(0, 0, -2),      // Sexta-feira Santa
+		easter.AddDate(0, 0, 60),      // Corpus Christi
+	}
+	if year >= 2024 {
+		holidays = append(holidays, date(year, time.November, 20)) // Dia Nacional de Zumbi e da Consciência Negra
+	}
+	return holidays
+}
+
+// IsBrazilianHoliday indica se a data é feriado nacional
+func IsBrazilianHoliday(t time.Time) bool {
+	day := date(t.Year(), t.Month(), t.Day())
+	for _, h := range BrazilianHolidays(t.Year()) {
+		if h.Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBusinessDay indica se a data é dia útil (não é fim de semana nem feriado nacional)
+func IsBusinessDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return !IsBrazilianHoliday(t)
+}
+
+// NextBusinessDay retorna a própria data se for dia útil, ou o próximo dia útil
+func NextBusinessDay(t time.Time) time.Time {
+	for !IsBusinessDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// PreviousBusinessDay retorna a própria data se for dia útil, ou o dia útil anterior
+func PreviousBusinessDay(t time.Time) time.Time {
+	for !IsBusinessDay(t) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+// BusinessDaysBetween conta os dias úteis no intervalo [from, to)
+func BusinessDaysBetween(from, to time.Time) int {
+	from = date(from.Year(), from.Month(), from.Day())
+	to = date(to.Year(), to.Month(), to.Day())
+
+	count := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if IsBusinessDay(d) {
+			count++
+		}
+	}
+	return count
+}
+
+// easterSunday calcula o domingo de Páscoa pelo algoritmo de Meeus/Jones/Butcher
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
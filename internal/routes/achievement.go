@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"net/http"
+
+	"Fynance/internal/contracts"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetAchievements(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	summary, err := h.AchievementService.GetSummary(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.AchievementSummaryResponse{Summary: summary})
+}
@@ -7,6 +7,7 @@ import (
 
 	"Fynance/internal/contracts"
 	"Fynance/internal/domain/budget"
+	"Fynance/internal/domain/plan"
 	appErrors "Fynance/internal/errors"
 	"Fynance/internal/pkg"
 
@@ -253,3 +254,95 @@ func (h *Handler) GetBudgetStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, status)
 }
+
+func (h *Handler) ListBudgetTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, contracts.BudgetTemplateListResponse{Templates: budget.RuleTemplates})
+}
+
+func (h *Handler) PreviewBudgetTemplate(c *gin.Context) {
+	var body contracts.BudgetTemplatePreviewRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	userPlan, err := h.UserService.GetPlan(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	result, err := h.BudgetService.GeneratePlan(ctx, &budget.GeneratePlanRequest{
+		UserId:     userID,
+		Mode:       budget.TemplateMode(body.Mode),
+		Template:   body.Template,
+		Income:     body.Income,
+		Months:     body.Months,
+		Month:      body.Month,
+		Year:       body.Year,
+		MaxBudgets: plan.GetLimits(userPlan).MaxBudgets,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.BudgetTemplatePreviewResponse{Plan: result})
+}
+
+func (h *Handler) ApplyBudgetTemplate(c *gin.Context) {
+	var body contracts.BudgetTemplateApplyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	items := make([]budget.ApplyPlanItem, 0, len(body.Items))
+	for _, item := range body.Items {
+		categoryID, err := pkg.ParseULID(item.CategoryId)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("category_id", "formato inválido"))
+			return
+		}
+		items = append(items, budget.ApplyPlanItem{CategoryId: categoryID, Amount: item.Amount})
+	}
+
+	ctx := c.Request.Context()
+	userPlan, err := h.UserService.GetPlan(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	result, err := h.BudgetService.ApplyPlan(ctx, &budget.ApplyPlanRequest{
+		UserId:      userID,
+		Month:       body.Month,
+		Year:        body.Year,
+		AlertAt:     body.AlertAt,
+		IsRecurring: body.IsRecurring,
+		Items:       items,
+		MaxBudgets:  plan.GetLimits(userPlan).MaxBudgets,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, contracts.BudgetTemplateApplyResponse{
+		Message: "Orcamentos criados com sucesso",
+		Result:  result,
+	})
+}
@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/calendar"
+	appErrors "Fynance/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetUpcoming(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	days := calendar.DefaultDays
+	if d := c.Query("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 || parsed > calendar.MaxDays {
+			h.respondError(c, appErrors.NewValidationError("days", "deve estar entre 1 e 365"))
+			return
+		}
+		days = parsed
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.CalendarService.Upcoming(ctx, userID, days)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) GetReminderSettings(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := h.CalendarService.GetSettings(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) UpdateReminderSettings(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var body contracts.ReminderSettingsUpdateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := h.CalendarService.UpdateSettings(ctx, userID, body.Enabled, body.LeadDays)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) RotateCalendarFeedToken(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := h.CalendarService.RotateFeedToken(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.CalendarFeedTokenResponse{
+		Message:  "Link do calendario gerado com sucesso",
+		Token:    token,
+		FeedPath: "/api/calendar/" + token + ".ics",
+	})
+}
+
+func (h *Handler) RevokeCalendarFeedToken(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.CalendarService.RevokeFeedToken(ctx, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Link do calendario desativado com sucesso"})
+}
+
+// GetCalendarFeed serve o feed iCalendar; o token no caminho substitui a autenticação, para que
+// aplicativos de calendário possam assinar o link
+func (h *Handler) GetCalendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	ctx := c.Request.Context()
+	feed, err := h.CalendarService.Feed(ctx, token)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "inline; filename=fynance.ics")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
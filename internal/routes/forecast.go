@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"Fynance/internal/domain/forecast"
+	appErrors "Fynance/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetForecast(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	days := forecast.DefaultDays
+	if d := c.Query("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 || parsed > forecast.MaxDays {
+			h.respondError(c, appErrors.NewValidationError("days", "deve estar entre 1 e 365"))
+			return
+		}
+		days = parsed
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.ForecastService.GetForecast(ctx, userID, days)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
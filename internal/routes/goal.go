@@ -3,9 +3,11 @@ package routes
 import (
 	"Fynance/internal/contracts"
 	"Fynance/internal/domain/goal"
+	"Fynance/internal/domain/investment"
 	appErrors "Fynance/internal/errors"
 	"Fynance/internal/pkg"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -197,6 +199,21 @@ func (h *Handler) ContributeToGoal(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	if body.InvestmentID != "" {
+		investmentID, err := pkg.ParseULID(body.InvestmentID)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("investment_id", "formato inválido"))
+			return
+		}
+		err = h.GoalService.MakeInvestmentContribution(ctx, goalID, investmentID, accountID, userID, body.Amount, body.Description)
+		if err != nil {
+			h.respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Aporte realizado com sucesso"})
+		return
+	}
+
 	if err := h.GoalService.MakeContribution(ctx, goalID, accountID, userID, body.Amount, body.Description); err != nil {
 		h.respondError(c, err)
 		return
@@ -330,3 +347,187 @@ func (h *Handler) DeleteContribution(c *gin.Context) {
 
 	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Contribuição removida com sucesso"})
 }
+
+func (h *Handler) SetGoalSchedule(c *gin.Context) {
+	var body contracts.GoalScheduleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	goalID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	accountID, err := pkg.ParseULID(body.AccountID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("account_id", "formato inválido"))
+		return
+	}
+
+	dayOfMonth := body.DayOfMonth
+	if dayOfMonth == 0 {
+		dayOfMonth = time.Now().Day()
+	}
+
+	ctx := c.Request.Context()
+	schedule, err := h.GoalService.SetContributionSchedule(ctx, &goal.ScheduleRequest{
+		GoalId:       goalID,
+		UserId:       userID,
+		AccountId:    accountID,
+		Amount:       body.Amount,
+		UseSuggested: body.UseSuggested,
+		Frequency:    goal.ScheduleFrequency(body.Frequency),
+		DayOfMonth:   dayOfMonth,
+		DayOfWeek:    body.DayOfWeek,
+		StartDate:    body.StartDate,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, GoalScheduleResponse{Schedule: schedule})
+}
+
+func (h *Handler) GetGoalSchedule(c *gin.Context) {
+	goalID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	schedule, err := h.GoalService.GetContributionSchedule(ctx, goalID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, GoalScheduleResponse{Schedule: schedule})
+}
+
+func (h *Handler) DeleteGoalSchedule(c *gin.Context) {
+	goalID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.GoalService.DeleteContributionSchedule(ctx, goalID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Aporte automatico removido com sucesso"})
+}
+
+func (h *Handler) ListGoalInvestments(c *gin.Context) {
+	goalID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	investments, err := h.GoalService.GetGoalInvestments(ctx, goalID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	if investments == nil {
+		investments = []*investment.Investment{}
+	}
+
+	c.JSON(http.StatusOK, GoalInvestmentListResponse{Investments: investments, Total: len(investments)})
+}
+
+func (h *Handler) LinkGoalInvestment(c *gin.Context) {
+	var body contracts.GoalInvestmentLinkRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	goalID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	investmentID, err := pkg.ParseULID(body.InvestmentID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("investment_id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.GoalService.LinkInvestment(ctx, goalID, investmentID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Investimento vinculado a meta com sucesso"})
+}
+
+func (h *Handler) UnlinkGoalInvestment(c *gin.Context) {
+	goalID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	investmentID, err := pkg.ParseULID(c.Param("investment_id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("investment_id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.GoalService.UnlinkInvestment(ctx, goalID, investmentID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Investimento desvinculado da meta com sucesso"})
+}
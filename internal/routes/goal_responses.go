@@ -1,6 +1,9 @@
 package routes
 
-import domainGoal "Fynance/internal/domain/goal"
+import (
+	domainGoal "Fynance/internal/domain/goal"
+	"Fynance/internal/domain/investment"
+)
 
 // Response types for goal routes
 type GoalResponse struct {
@@ -20,3 +23,12 @@ type GoalContributionListResponse struct {
 type GoalProgressResponse struct {
 	Progress *domainGoal.GoalProgress `json:"progress"`
 }
+
+type GoalScheduleResponse struct {
+	Schedule *domainGoal.ContributionSchedule `json:"schedule"`
+}
+
+type GoalInvestmentListResponse struct {
+	Investments []*investment.Investment `json:"investments"`
+	Total       int                      `json:"total"`
+}
@@ -2,16 +2,25 @@ package routes
 
 import (
 	"Fynance/internal/domain/account"
+	"Fynance/internal/domain/achievement"
 	"Fynance/internal/domain/auth"
 	"Fynance/internal/domain/budget"
+	"Fynance/internal/domain/calendar"
 	"Fynance/internal/domain/creditcard"
 	"Fynance/internal/domain/dashboard"
+	"Fynance/internal/domain/forecast"
 	"Fynance/internal/domain/goal"
 	"Fynance/internal/domain/investment"
+	"Fynance/internal/domain/loan"
+	"Fynance/internal/domain/market"
+	"Fynance/internal/domain/notification"
+	"Fynance/internal/domain/queue"
 	"Fynance/internal/domain/recurring"
 	"Fynance/internal/domain/report"
+	"Fynance/internal/domain/roundup"
 	"Fynance/internal/domain/transaction"
 	"Fynance/internal/domain/user"
+	"Fynance/internal/domain/webhook"
 	appErrors "Fynance/internal/errors"
 	"Fynance/internal/infrastructure"
 	"Fynance/internal/logger"
@@ -23,18 +32,27 @@ import (
 )
 
 type Handler struct {
-	UserService        user.Service
-	AuthService        auth.Service
-	JwtService         *middleware.JwtService
-	TransactionService transaction.Service
-	GoalService        goal.Service
-	InvestmentService  investment.Service
-	AccountService     account.Service
-	BudgetService      budget.Service
-	DashboardService   dashboard.Service
-	RecurringService   recurring.Service
-	ReportService      report.Service
-	CreditCardService  creditcard.Service
+	UserService         user.Service
+	AuthService         auth.Service
+	JwtService          *middleware.JwtService
+	TransactionService  transaction.Service
+	GoalService         goal.Service
+	InvestmentService   investment.Service
+	AccountService      account.Service
+	BudgetService       budget.Service
+	DashboardService    dashboard.Service
+	RecurringService    recurring.Service
+	ReportService       report.Service
+	CreditCardService   creditcard.Service
+	AchievementService  achievement.Service
+	RoundUpService      roundup.Service
+	MarketService       market.Service
+	LoanService         loan.Service
+	ForecastService     forecast.Service
+	CalendarService     calendar.Service
+	NotificationService notification.Service
+	WebhookService      webhook.Service
+	Queue               *queue.Queue
 
 	AccountRepository     *infrastructure.AccountRepository
 	TransactionRepository *infrastructure.TransactionRepository
@@ -49,6 +49,7 @@ func (h *HealthScoreHandler) GetHealthScore(c *gin.Context) {
 		Color:           scoreRange.Color,
 		BudgetHealth:    result.BudgetHealth,
 		GoalsHealth:     result.GoalsHealth,
+		DebtHealth:      result.DebtHealth,
 		SavingsHealth:   0,
 		Recommendations: result.Factors,
 	})
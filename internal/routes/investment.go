@@ -39,6 +39,17 @@ func (h *Handler) CreateInvestment(c *gin.Context) {
 		Name:          body.Name,
 		InitialAmount: body.InitialAmount,
 		ReturnRate:    body.ReturnRate,
+		Ticker:        body.Ticker,
+		Quantity:      body.Quantity,
+		UnitPrice:     body.UnitPrice,
+		Fees:          body.Fees,
+		Indexer:       body.Indexer,
+		IndexerRate:   body.IndexerRate,
+		MaturityDate:  body.MaturityDate,
+		AssetClass:    body.AssetClass,
+	}
+	if body.TradeDate != nil {
+		req.TradeDate = *body.TradeDate
 	}
 
 	ctx := c.Request.Context()
@@ -163,12 +174,16 @@ func (h *Handler) MakeWithdraw(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	if err := h.InvestmentService.MakeWithdraw(ctx, investmentID, accountID, userID, body.Amount, body.Description); err != nil {
+	redemption, err := h.InvestmentService.MakeWithdraw(ctx, investmentID, accountID, userID, body.Amount, body.Description)
+	if err != nil {
 		h.respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Resgate realizado com sucesso"})
+	c.JSON(http.StatusOK, InvestmentWithdrawResponse{
+		Message:    "Resgate realizado com sucesso",
+		Redemption: redemption,
+	})
 }
 
 func (h *Handler) GetInvestmentReturn(c *gin.Context) {
@@ -252,6 +267,9 @@ func (h *Handler) UpdateInvestment(c *gin.Context) {
 	if body.CurrentBalance != nil {
 		updateReq.CurrentBalance = body.CurrentBalance
 	}
+	if body.AssetClass != nil {
+		updateReq.AssetClass = body.AssetClass
+	}
 
 	ctx := c.Request.Context()
 	if err := h.InvestmentService.UpdateInvestment(ctx, investmentID, userID, updateReq); err != nil {
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/investment"
+	appErrors "Fynance/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetAllocation(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	contribution := 0.0
+	if v := c.Query("contribution"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			h.respondError(c, appErrors.NewValidationError("contribution", "deve ser um valor maior ou igual a zero"))
+			return
+		}
+		contribution = parsed
+	}
+
+	ctx := c.Request.Context()
+	report, err := h.InvestmentService.GetAllocation(ctx, userID, contribution)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AllocationResponse{Allocation: report})
+}
+
+func (h *Handler) GetAllocationTargets(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	targets, err := h.InvestmentService.GetAllocationTargets(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AllocationTargetsResponse{Targets: targets})
+}
+
+func (h *Handler) SetAllocationTargets(c *gin.Context) {
+	var body contracts.AllocationTargetsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	inputs := make([]investment.AllocationTargetInput, 0, len(body.Targets))
+	for _, t := range body.Targets {
+		inputs = append(inputs, investment.AllocationTargetInput{Class: t.Class, Weight: t.Weight})
+	}
+
+	ctx := c.Request.Context()
+	targets, err := h.InvestmentService.SetAllocationTargets(ctx, userID, inputs)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AllocationTargetsResponse{Targets: targets})
+}
@@ -0,0 +1,99 @@
+package routes
+
+import (
+	"net/http"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/investment"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) PreviewBrokerageNotes(c *gin.Context) {
+	req, ok := h.bindBrokerageNoteRequest(c, false)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	notes, err := h.InvestmentService.PreviewBrokerageNotes(ctx, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, BrokerageNotesPreviewResponse{Notes: notes})
+}
+
+func (h *Handler) ImportBrokerageNotes(c *gin.Context) {
+	req, ok := h.bindBrokerageNoteRequest(c, true)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	notes, err := h.InvestmentService.ImportBrokerageNotes(ctx, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, BrokerageNotesImportResponse{Message: "Notas importadas com sucesso", Notes: notes})
+}
+
+func (h *Handler) ListBrokerageNotes(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	notes, err := h.InvestmentService.ListBrokerageNotes(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, BrokerageNotesResponse{Notes: notes})
+}
+
+func (h *Handler) bindBrokerageNoteRequest(c *gin.Context, requireAccount bool) (investment.BrokerageImportRequest, bool) {
+	var body contracts.BrokerageNoteRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return investment.BrokerageImportRequest{}, false
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return investment.BrokerageImportRequest{}, false
+	}
+
+	req := investment.BrokerageImportRequest{
+		UserId:  userID,
+		Format:  investment.NoteFormat(body.Format),
+		Content: body.Content,
+		Tickers: body.Tickers,
+		Types:   body.Types,
+	}
+
+	if body.AccountID == "" {
+		if requireAccount {
+			h.respondError(c, appErrors.NewValidationError("account_id", "é obrigatório"))
+			return investment.BrokerageImportRequest{}, false
+		}
+		return req, true
+	}
+
+	accountID, err := pkg.ParseULID(body.AccountID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("account_id", "formato inválido"))
+		return investment.BrokerageImportRequest{}, false
+	}
+	req.AccountId = accountID
+	return req, true
+}
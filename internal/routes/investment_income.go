@@ -0,0 +1,199 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/investment"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) RecordInvestmentIncome(c *gin.Context) {
+	investmentID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	var body contracts.InvestmentIncomeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	accountID, err := pkg.ParseULID(body.AccountID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("account_id", "formato inválido"))
+		return
+	}
+
+	req := investment.IncomeRequest{
+		InvestmentId:  investmentID,
+		AccountId:     accountID,
+		UserId:        userID,
+		Type:          investment.IncomeType(body.Type),
+		AmountPerUnit: body.AmountPerUnit,
+		GrossAmount:   body.Amount,
+		WithheldTax:   body.WithheldTax,
+		ExDate:        body.ExDate,
+		Description:   body.Description,
+	}
+	if body.PaymentDate != nil {
+		req.PaymentDate = *body.PaymentDate
+	}
+
+	ctx := c.Request.Context()
+	event, err := h.InvestmentService.RecordIncome(ctx, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	message := "Provento recebido com sucesso"
+	if event.Status == investment.IncomeAnnounced {
+		message = "Provento anunciado registrado com sucesso"
+	}
+	c.JSON(http.StatusCreated, InvestmentIncomeResponse{Message: message, Income: event})
+}
+
+func (h *Handler) GetInvestmentIncome(c *gin.Context) {
+	investmentID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	summary, err := h.InvestmentService.GetIncomeSummary(ctx, investmentID, userID, time.Now().UTC())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, InvestmentIncomeSummaryResponse{Summary: summary})
+}
+
+func (h *Handler) GetMonthlyIncome(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	months := 12
+	if m := c.Query("months"); m != "" {
+		parsed, err := strconv.Atoi(m)
+		if err != nil || parsed < 1 || parsed > 60 {
+			h.respondError(c, appErrors.NewValidationError("months", "deve estar entre 1 e 60"))
+			return
+		}
+		months = parsed
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.InvestmentService.GetMonthlyIncome(ctx, userID, months, time.Now().UTC())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	total := 0.0
+	for _, m := range result {
+		total += m.Total
+	}
+	c.JSON(http.StatusOK, MonthlyIncomeResponse{Months: result, Total: total})
+}
+
+func (h *Handler) GetIncomeCalendar(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	days := 90
+	if d := c.Query("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 || parsed > 366 {
+			h.respondError(c, appErrors.NewValidationError("days", "deve estar entre 1 e 366"))
+			return
+		}
+		days = parsed
+	}
+
+	from := time.Now().UTC()
+	ctx := c.Request.Context()
+	events, err := h.InvestmentService.GetIncomeCalendar(ctx, userID, from, from.AddDate(0, 0, days))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	total := 0.0
+	for _, e := range events {
+		total += e.NetAmount
+	}
+	c.JSON(http.StatusOK, IncomeCalendarResponse{Events: events, Total: total})
+}
+
+func (h *Handler) ReceiveInvestmentIncome(c *gin.Context) {
+	eventID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	event, err := h.InvestmentService.ReceiveIncome(ctx, eventID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, InvestmentIncomeResponse{Message: "Provento recebido com sucesso", Income: event})
+}
+
+func (h *Handler) DeleteInvestmentIncome(c *gin.Context) {
+	eventID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.InvestmentService.DeleteIncome(ctx, eventID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Provento excluido com sucesso"})
+}
@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"Fynance/internal/domain/investment"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetInvestmentPerformance(c *gin.Context) {
+	investmentID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	query, err := parsePerformanceQuery(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	performance, err := h.InvestmentService.GetPerformance(ctx, investmentID, userID, query, time.Now().UTC())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PerformanceResponse{Performance: performance})
+}
+
+func (h *Handler) GetPortfolioPerformance(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	query, err := parsePerformanceQuery(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	performance, err := h.InvestmentService.GetPortfolioPerformance(ctx, userID, query, time.Now().UTC())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PerformanceResponse{Performance: performance})
+}
+
+func parsePerformanceQuery(c *gin.Context) (investment.PerformanceQuery, error) {
+	query := investment.PerformanceQuery{Period: strings.ToUpper(c.Query("period"))}
+
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return query, appErrors.NewValidationError("from", "formato inválido, use AAAA-MM-DD")
+		}
+		query.From = &parsed
+	}
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return query, appErrors.NewValidationError("to", "formato inválido, use AAAA-MM-DD")
+		}
+		query.To = &parsed
+	}
+	return query, nil
+}
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"net/http"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/investment"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetInvestmentPosition(c *gin.Context) {
+	investmentID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	position, err := h.InvestmentService.GetPosition(ctx, investmentID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, InvestmentPositionResponse{Position: position})
+}
+
+func (h *Handler) BuyInvestmentLot(c *gin.Context) {
+	req, ok := h.bindTradeRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	position, err := h.InvestmentService.Buy(ctx, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, InvestmentPositionResponse{Position: position})
+}
+
+func (h *Handler) SellInvestmentLot(c *gin.Context) {
+	req, ok := h.bindTradeRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	position, redemption, err := h.InvestmentService.Sell(ctx, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, InvestmentPositionResponse{Position: position, Redemption: redemption})
+}
+
+func (h *Handler) bindTradeRequest(c *gin.Context) (investment.TradeRequest, bool) {
+	investmentID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return investment.TradeRequest{}, false
+	}
+
+	var body contracts.InvestmentTradeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return investment.TradeRequest{}, false
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return investment.TradeRequest{}, false
+	}
+
+	accountID, err := pkg.ParseULID(body.AccountID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("account_id", "formato inválido"))
+		return investment.TradeRequest{}, false
+	}
+
+	req := investment.TradeRequest{
+		InvestmentId: investmentID,
+		AccountId:    accountID,
+		UserId:       userID,
+		Quantity:     body.Quantity,
+		UnitPrice:    body.UnitPrice,
+		Fees:         body.Fees,
+		Description:  body.Description,
+	}
+	if body.TradeDate != nil {
+		req.TradeDate = *body.TradeDate
+	}
+	return req, true
+}
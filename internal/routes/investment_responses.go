@@ -15,3 +15,61 @@ type InvestmentListResponse struct {
 type InvestmentSingleResponse struct {
 	Investment *investment.Investment `json:"investment"`
 }
+
+type InvestmentPositionResponse struct {
+	Position   *investment.Position   `json:"position"`
+	Redemption *investment.Redemption `json:"redemption,omitempty"`
+}
+
+type InvestmentWithdrawResponse struct {
+	Message    string                 `json:"message"`
+	Redemption *investment.Redemption `json:"redemption"`
+}
+
+type InvestmentYieldResponse struct {
+	Valuation *investment.FixedIncomeValuation `json:"valuation"`
+}
+
+type InvestmentIncomeResponse struct {
+	Message string                  `json:"message"`
+	Income  *investment.IncomeEvent `json:"income"`
+}
+
+type InvestmentIncomeSummaryResponse struct {
+	Summary *investment.IncomeSummary `json:"summary"`
+}
+
+type MonthlyIncomeResponse struct {
+	Months []investment.MonthlyIncome `json:"months"`
+	Total  float64                    `json:"total"`
+}
+
+type IncomeCalendarResponse struct {
+	Events []*investment.IncomeEvent `json:"events"`
+	Total  float64                   `json:"total"`
+}
+
+type AllocationTargetsResponse struct {
+	Targets []*investment.AllocationTarget `json:"targets"`
+}
+
+type AllocationResponse struct {
+	Allocation *investment.AllocationReport `json:"allocation"`
+}
+
+type PerformanceResponse struct {
+	Performance *investment.Performance `json:"performance"`
+}
+
+type BrokerageNotesPreviewResponse struct {
+	Notes []*investment.ParsedNote `json:"notes"`
+}
+
+type BrokerageNotesImportResponse struct {
+	Message string                   `json:"message"`
+	Notes   []*investment.ParsedNote `json:"notes"`
+}
+
+type BrokerageNotesResponse struct {
+	Notes []*investment.BrokerageNote `json:"notes"`
+}
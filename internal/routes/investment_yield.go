@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetInvestmentYield(c *gin.Context) {
+	investmentID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	at := time.Now().UTC()
+	if d := c.Query("date"); d != "" {
+		parsed, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("date", "formato inválido, use AAAA-MM-DD"))
+			return
+		}
+		at = parsed
+	}
+
+	ctx := c.Request.Context()
+	valuation, err := h.InvestmentService.GetFixedIncomeValuation(ctx, investmentID, userID, at)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, InvestmentYieldResponse{Valuation: valuation})
+}
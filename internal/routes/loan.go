@@ -0,0 +1,291 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/loan"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+)
+
+func (h *Handler) CreateLoan(c *gin.Context) {
+	var body contracts.LoanCreateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	accountID, err := pkg.ParseULID(body.AccountID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("account_id", "formato inválido"))
+		return
+	}
+
+	categoryID, err := parseOptionalID("category_id", body.CategoryID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	created, err := h.LoanService.CreateLoan(ctx, loan.CreateLoanRequest{
+		UserId:       userID,
+		AccountId:    accountID,
+		CategoryId:   categoryID,
+		Name:         body.Name,
+		Kind:         loan.Kind(body.Kind),
+		System:       loan.System(body.System),
+		Principal:    body.Principal,
+		InterestRate: body.InterestRate,
+		RatePeriod:   loan.RatePeriod(body.RatePeriod),
+		TermMonths:   body.TermMonths,
+		FirstDueDate: body.FirstDueDate,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, LoanResponse{Loan: created})
+}
+
+func (h *Handler) ListLoans(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	loans, err := h.LoanService.ListLoans(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	summary, err := h.LoanService.GetDebtSummary(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LoanListResponse{Loans: loans, Total: len(loans), Summary: summary})
+}
+
+func (h *Handler) GetLoan(c *gin.Context) {
+	loanID, userID, ok := h.loanParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	found, err := h.LoanService.GetLoan(ctx, loanID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LoanResponse{Loan: found})
+}
+
+func (h *Handler) UpdateLoan(c *gin.Context) {
+	var body contracts.LoanUpdateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	loanID, userID, ok := h.loanParams(c)
+	if !ok {
+		return
+	}
+
+	accountID, err := parseOptionalID("account_id", body.AccountID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	categoryID, err := parseOptionalID("category_id", body.CategoryID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	updated, err := h.LoanService.UpdateLoan(ctx, loanID, userID, loan.UpdateLoanRequest{
+		Name:       body.Name,
+		AccountId:  accountID,
+		CategoryId: categoryID,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LoanResponse{Loan: updated})
+}
+
+func (h *Handler) DeleteLoan(c *gin.Context) {
+	loanID, userID, ok := h.loanParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.LoanService.DeleteLoan(ctx, loanID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Emprestimo removido com sucesso"})
+}
+
+func (h *Handler) GetLoanSchedule(c *gin.Context) {
+	loanID, userID, ok := h.loanParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	found, err := h.LoanService.GetLoan(ctx, loanID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	schedule, err := h.LoanService.GetSchedule(ctx, loanID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LoanScheduleResponse{Loan: found, Schedule: schedule})
+}
+
+func (h *Handler) PayLoanInstallment(c *gin.Context) {
+	loanID, userID, ok := h.loanParams(c)
+	if !ok {
+		return
+	}
+
+	var body contracts.LoanInstallmentPaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+			return
+		}
+	}
+
+	accountID, err := parseOptionalID("account_id", body.AccountID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	payment, schedule, err := h.LoanService.PayInstallment(ctx, loan.PaymentRequest{
+		LoanId:    loanID,
+		UserId:    userID,
+		AccountId: accountID,
+		Date:      body.Date,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, LoanPaymentResponse{Payment: payment, Schedule: schedule})
+}
+
+func (h *Handler) CreateLoanExtraPayment(c *gin.Context) {
+	var body contracts.LoanExtraPaymentRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	loanID, userID, ok := h.loanParams(c)
+	if !ok {
+		return
+	}
+
+	accountID, err := parseOptionalID("account_id", body.AccountID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	payment, schedule, err := h.LoanService.ExtraPayment(ctx, loan.PaymentRequest{
+		LoanId:    loanID,
+		UserId:    userID,
+		AccountId: accountID,
+		Date:      body.Date,
+		Amount:    body.Amount,
+		Strategy:  loan.ExtraStrategy(body.Strategy),
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, LoanPaymentResponse{Payment: payment, Schedule: schedule})
+}
+
+func (h *Handler) SimulateLoanExtraPayment(c *gin.Context) {
+	loanID, userID, ok := h.loanParams(c)
+	if !ok {
+		return
+	}
+
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("amount", "deve ser um numero maior que zero"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	simulation, err := h.LoanService.SimulateExtraPayment(ctx, loanID, userID, amount)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, simulation)
+}
+
+func (h *Handler) loanParams(c *gin.Context) (ulid.ULID, ulid.ULID, bool) {
+	loanID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+	return loanID, userID, true
+}
+
+func parseOptionalID(field string, value *string) (*ulid.ULID, error) {
+	if value == nil {
+		return nil, nil
+	}
+	id, err := pkg.ParseULID(*value)
+	if err != nil {
+		return nil, appErrors.NewValidationError(field, "formato inválido")
+	}
+	return &id, nil
+}
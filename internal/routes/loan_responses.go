@@ -0,0 +1,24 @@
+package routes
+
+import "Fynance/internal/domain/loan"
+
+// Response types for loan routes
+type LoanResponse struct {
+	Loan *loan.Loan `json:"loan"`
+}
+
+type LoanListResponse struct {
+	Loans   []*loan.Loan      `json:"loans"`
+	Total   int               `json:"total"`
+	Summary *loan.DebtSummary `json:"summary"`
+}
+
+type LoanScheduleResponse struct {
+	Loan     *loan.Loan     `json:"loan"`
+	Schedule *loan.Schedule `json:"schedule"`
+}
+
+type LoanPaymentResponse struct {
+	Payment  *loan.Payment  `json:"payment"`
+	Schedule *loan.Schedule `json:"schedule"`
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	appErrors "Fynance/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetPriceHistory(c *gin.Context) {
+	if _, err := h.GetUserIDFromContext(c); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	symbol := strings.ToUpper(strings.TrimSpace(c.Param("symbol")))
+
+	endDate := time.Now().UTC()
+	startDate := endDate.AddDate(-1, 0, 0)
+
+	if sd := c.Query("start_date"); sd != "" {
+		parsed, err := time.Parse("2006-01-02", sd)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("start_date", "formato inválido, use AAAA-MM-DD"))
+			return
+		}
+		startDate = parsed
+	}
+
+	if ed := c.Query("end_date"); ed != "" {
+		parsed, err := time.Parse("2006-01-02", ed)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("end_date", "formato inválido, use AAAA-MM-DD"))
+			return
+		}
+		endDate = parsed
+	}
+
+	ctx := c.Request.Context()
+	history, err := h.MarketService.GetPriceHistory(ctx, symbol, startDate, endDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PriceHistoryResponse{Symbol: symbol, Prices: history, Total: len(history)})
+}
@@ -0,0 +1,10 @@
+package routes
+
+import "Fynance/internal/domain/market"
+
+// Response types for market routes
+type PriceHistoryResponse struct {
+	Symbol string                 `json:"symbol"`
+	Prices []*market.PriceHistory `json:"prices"`
+	Total  int                    `json:"total"`
+}
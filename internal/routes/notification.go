@@ -0,0 +1,245 @@
+package routes
+
+import (
+	"net/http"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/notification"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+)
+
+func (h *Handler) ListNotifications(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	unreadOnly := c.Query("unread") == "true"
+	pagination := h.parsePagination(c)
+
+	ctx := c.Request.Context()
+	notifications, total, err := h.NotificationService.ListNotifications(ctx, userID, unreadOnly, pagination)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.NewPaginatedResponse(notifications, pagination.Page, pagination.Limit, total))
+}
+
+func (h *Handler) GetUnreadNotificationCount(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	count, err := h.NotificationService.CountUnread(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.NotificationUnreadCountResponse{Unread: count})
+}
+
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	notificationID, userID, ok := h.notificationParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.NotificationService.MarkRead(ctx, notificationID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Notificacao marcada como lida"})
+}
+
+func (h *Handler) MarkNotificationUnread(c *gin.Context) {
+	notificationID, userID, ok := h.notificationParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.NotificationService.MarkUnread(ctx, notificationID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Notificacao marcada como nao lida"})
+}
+
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	updated, err := h.NotificationService.MarkAllRead(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.NotificationReadAllResponse{
+		Message: "Notificacoes marcadas como lidas",
+		Updated: updated,
+	})
+}
+
+func (h *Handler) DeleteNotification(c *gin.Context) {
+	notificationID, userID, ok := h.notificationParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.NotificationService.DeleteNotification(ctx, notificationID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Notificacao removida com sucesso"})
+}
+
+func (h *Handler) GetNotificationPreferences(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	preferences, err := h.NotificationService.GetPreferences(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+func (h *Handler) UpdateNotificationPreferences(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var body contracts.NotificationPreferencesUpdateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	channels := make(map[notification.Type][]notification.Channel, len(body.Channels))
+	for notificationType, selected := range body.Channels {
+		list := make([]notification.Channel, 0, len(selected))
+		for _, channel := range selected {
+			list = append(list, notification.Channel(channel))
+		}
+		channels[notification.Type(notificationType)] = list
+	}
+
+	ctx := c.Request.Context()
+	preferences, err := h.NotificationService.UpdatePreferences(ctx, userID, body.Locale, channels)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+func (h *Handler) GetPushPublicKey(c *gin.Context) {
+	publicKey, err := h.NotificationService.PushPublicKey()
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.PushPublicKeyResponse{PublicKey: publicKey})
+}
+
+func (h *Handler) SubscribePush(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var body contracts.PushSubscriptionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	ctx := c.Request.Context()
+	subscription := &notification.PushSubscription{
+		UserId:    userID,
+		Endpoint:  body.Endpoint,
+		P256dh:    body.Keys.P256dh,
+		Auth:      body.Keys.Auth,
+		UserAgent: userAgent,
+	}
+	if err := h.NotificationService.Subscribe(ctx, subscription); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, contracts.MessageResponse{Message: "Notificacoes push ativadas neste navegador"})
+}
+
+func (h *Handler) UnsubscribePush(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var body contracts.PushUnsubscribeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.NotificationService.Unsubscribe(ctx, userID, body.Endpoint); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Notificacoes push desativadas neste navegador"})
+}
+
+func (h *Handler) notificationParams(c *gin.Context) (ulid.ULID, ulid.ULID, bool) {
+	notificationID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+	return notificationID, userID, true
+}
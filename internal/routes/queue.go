@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"Fynance/internal/domain/queue"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ListJobs(c *gin.Context) {
+	filter := queue.Filter{
+		Status: queue.Status(strings.ToUpper(c.Query("status"))),
+		Type:   c.Query("type"),
+	}
+	pagination := h.parsePagination(c)
+
+	ctx := c.Request.Context()
+	jobs, total, err := h.Queue.ListJobs(ctx, filter, pagination)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.NewPaginatedResponse(jobs, pagination.Page, pagination.Limit, total))
+}
+
+func (h *Handler) GetJobStats(c *gin.Context) {
+	ctx := c.Request.Context()
+	counts, err := h.Queue.Stats(ctx)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, JobStatsResponse{Counts: counts})
+}
+
+func (h *Handler) ListJobSchedules(c *gin.Context) {
+	ctx := c.Request.Context()
+	schedules, err := h.Queue.ListSchedules(ctx)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, JobScheduleListResponse{Schedules: schedules, Total: len(schedules)})
+}
+
+func (h *Handler) GetJob(c *gin.Context) {
+	jobID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.Queue.GetJob(ctx, jobID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *Handler) RetryJob(c *gin.Context) {
+	jobID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.Queue.RetryJob(ctx, jobID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import "Fynance/internal/domain/queue"
+
+// Response types for admin job routes
+type JobStatsResponse struct {
+	Counts []queue.StatusCount `json:"counts"`
+}
+
+type JobScheduleListResponse struct {
+	Schedules []*queue.Schedule `json:"schedules"`
+	Total     int               `json:"total"`
+}
@@ -25,16 +25,9 @@ func (h *Handler) CreateRecurring(c *gin.Context) {
 		return
 	}
 
-	categoryID, err := pkg.ParseULID(body.CategoryId)
-	if err != nil {
-		h.respondError(c, appErrors.NewValidationError("category_id", "formato inválido"))
-		return
-	}
-
 	req := &recurring.CreateRecurringRequest{
 		UserId:      userID,
 		Type:        body.Type,
-		CategoryId:  categoryID,
 		Amount:      body.Amount,
 		Description: body.Description,
 		Frequency:   recurring.FrequencyType(body.Frequency),
@@ -42,6 +35,38 @@ func (h *Handler) CreateRecurring(c *gin.Context) {
 		DayOfWeek:   body.DayOfWeek,
 		StartDate:   body.StartDate,
 		EndDate:     body.EndDate,
+
+		Interval:              body.Interval,
+		Rule:                  body.Rule,
+		BusinessDayAdjustment: recurring.BusinessDayAdjustment(body.BusinessDayAdjustment),
+		IsEstimated:           body.IsEstimated,
+		Kind:                  recurring.Kind(body.Kind),
+	}
+
+	if body.CategoryId != "" {
+		categoryID, err := pkg.ParseULID(body.CategoryId)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("category_id", "formato inválido"))
+			return
+		}
+		req.CategoryId = categoryID
+	}
+
+	if req.DestinationAccountId, err = parseOptionalID("destination_account_id", body.DestinationAccountId); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if req.GoalId, err = parseOptionalID("goal_id", body.GoalId); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if req.InvestmentId, err = parseOptionalID("investment_id", body.InvestmentId); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if req.CreditCardId, err = parseOptionalID("credit_card_id", body.CreditCardId); err != nil {
+		h.respondError(c, err)
+		return
 	}
 
 	if body.AccountId != "" {
@@ -53,7 +78,7 @@ func (h *Handler) CreateRecurring(c *gin.Context) {
 		req.AccountId = &accountID
 	}
 
-	if req.DayOfMonth == 0 {
+	if req.DayOfMonth == 0 && req.Rule == "" {
 		req.DayOfMonth = time.Now().Day()
 	}
 
@@ -138,6 +163,12 @@ func (h *Handler) UpdateRecurring(c *gin.Context) {
 		IsActive:    body.IsActive,
 		EndDate:     body.EndDate,
 		NextDue:     body.NextDue,
+		Rule:        body.Rule,
+		IsEstimated: body.IsEstimated,
+	}
+	if body.BusinessDayAdjustment != nil {
+		adjustment := recurring.BusinessDayAdjustment(*body.BusinessDayAdjustment)
+		req.BusinessDayAdjustment = &adjustment
 	}
 
 	ctx := c.Request.Context()
@@ -265,3 +296,169 @@ func (h *Handler) ProcessRecurring(c *gin.Context) {
 		Recurring:   rec,
 	})
 }
+
+func (h *Handler) ListDetectedSubscriptions(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	detected, err := h.RecurringService.DetectSubscriptions(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.RecurringDetectedResponse{Subscriptions: detected, Total: len(detected)})
+}
+
+func (h *Handler) ConvertDetectedSubscription(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var body contracts.RecurringConvertDetectedRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+			return
+		}
+	}
+
+	accountID, err := parseOptionalID("account_id", body.AccountId)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	categoryID, err := parseOptionalID("category_id", body.CategoryId)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	rec, err := h.RecurringService.ConvertDetected(ctx, &recurring.ConvertDetectedRequest{
+		UserId:     userID,
+		Key:        c.Param("key"),
+		AccountId:  accountID,
+		CategoryId: categoryID,
+		Amount:     body.Amount,
+		StartDate:  body.StartDate,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, contracts.RecurringCreateResponse{
+		Message:   "Transacao recorrente criada com sucesso",
+		Recurring: rec,
+	})
+}
+
+func (h *Handler) ListRecurringOccurrences(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var filters recurring.OccurrenceFilters
+	if status := c.Query("status"); status != "" {
+		occurrenceStatus := recurring.OccurrenceStatus(status)
+		filters.Status = &occurrenceStatus
+	}
+	if value := c.Query("recurring_id"); value != "" {
+		recurringID, err := pkg.ParseULID(value)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("recurring_id", "formato inválido"))
+			return
+		}
+		filters.RecurringId = &recurringID
+	}
+	filters.OverdueOnly = c.Query("overdue") == "true"
+
+	ctx := c.Request.Context()
+	occurrences, err := h.RecurringService.ListOccurrences(ctx, userID, filters)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.RecurringOccurrenceListResponse{Occurrences: occurrences, Total: len(occurrences)})
+}
+
+func (h *Handler) ConfirmRecurringOccurrence(c *gin.Context) {
+	occurrenceID, err := pkg.ParseULID(c.Param("occurrenceId"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("occurrenceId", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var body contracts.RecurringOccurrenceConfirmRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	accountID, err := parseOptionalID("account_id", body.AccountId)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	occurrence, tx, err := h.RecurringService.ConfirmOccurrence(ctx, &recurring.ConfirmOccurrenceRequest{
+		OccurrenceId: occurrenceID,
+		UserId:       userID,
+		Amount:       body.Amount,
+		AccountId:    accountID,
+		Date:         body.Date,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.RecurringOccurrenceResponse{
+		Message:     "Ocorrencia confirmada com sucesso",
+		Occurrence:  occurrence,
+		Transaction: tx,
+	})
+}
+
+func (h *Handler) SkipRecurringOccurrence(c *gin.Context) {
+	occurrenceID, err := pkg.ParseULID(c.Param("occurrenceId"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("occurrenceId", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	occurrence, err := h.RecurringService.SkipOccurrence(ctx, occurrenceID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.RecurringOccurrenceResponse{
+		Message:    "Ocorrencia ignorada com sucesso",
+		Occurrence: occurrence,
+	})
+}
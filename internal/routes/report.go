@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -174,3 +176,56 @@ func (h *Handler) GetPeriodReport(c *gin.Context) {
 
 	c.JSON(http.StatusOK, contracts.MonthlyReportResponse{Report: report})
 }
+
+func (h *Handler) GetTaxReport(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	report, err := h.ReportService.GetTaxReport(ctx, userID, taxReportYear(c))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.TaxReportResponse{Report: report})
+}
+
+func (h *Handler) ExportTaxReport(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	year := taxReportYear(c)
+	ctx := c.Request.Context()
+	report, err := h.ReportService.GetTaxReport(ctx, userID, year)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		h.respondError(c, appErrors.ErrInternalServer.WithError(err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=irpf-%d.csv", year))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// taxReportYear usa por padrão o ano-calendário anterior, que é o declarado no ano corrente
+func taxReportYear(c *gin.Context) int {
+	year := time.Now().Year() - 1
+	if y := c.Query("year"); y != "" {
+		if parsed, err := strconv.Atoi(y); err == nil && parsed >= 2000 && parsed <= 2100 {
+			year = parsed
+		}
+	}
+	return year
+}
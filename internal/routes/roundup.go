@@ -0,0 +1,207 @@
+package routes
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/roundup"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+)
+
+func (h *Handler) CreateRoundUpRule(c *gin.Context) {
+	var body contracts.RoundUpRuleCreateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	accountID, err := pkg.ParseULID(body.AccountID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("account_id", "formato inválido"))
+		return
+	}
+
+	goalID, err := pkg.ParseULID(body.GoalID)
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("goal_id", "formato inválido"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	rule, err := h.RoundUpService.CreateRule(ctx, &roundup.RuleRequest{
+		UserId:    userID,
+		AccountId: accountID,
+		GoalId:    goalID,
+		Increment: body.Increment,
+		Frequency: roundup.SweepFrequency(body.Frequency),
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, RoundUpRuleResponse{Rule: rule})
+}
+
+func (h *Handler) ListRoundUpRules(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	rules, err := h.RoundUpService.ListRules(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RoundUpRuleListResponse{Rules: rules, Total: len(rules)})
+}
+
+func (h *Handler) UpdateRoundUpRule(c *gin.Context) {
+	var body contracts.RoundUpRuleUpdateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ParseValidationErrors(err))
+		return
+	}
+
+	ruleID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	req := &roundup.RuleUpdateRequest{
+		Id:        ruleID,
+		UserId:    userID,
+		Increment: body.Increment,
+		IsActive:  body.IsActive,
+	}
+	if body.GoalID != nil {
+		goalID, err := pkg.ParseULID(*body.GoalID)
+		if err != nil {
+			h.respondError(c, appErrors.NewValidationError("goal_id", "formato inválido"))
+			return
+		}
+		req.GoalId = &goalID
+	}
+	if body.Frequency != nil {
+		frequency := roundup.SweepFrequency(*body.Frequency)
+		req.Frequency = &frequency
+	}
+
+	ctx := c.Request.Context()
+	rule, err := h.RoundUpService.UpdateRule(ctx, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RoundUpRuleResponse{Rule: rule})
+}
+
+func (h *Handler) DeleteRoundUpRule(c *gin.Context) {
+	ruleID, userID, ok := h.roundUpRuleParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.RoundUpService.DeleteRule(ctx, ruleID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Regra de arredondamento removida com sucesso"})
+}
+
+func (h *Handler) SweepRoundUpRule(c *gin.Context) {
+	ruleID, userID, ok := h.roundUpRuleParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	amount, err := h.RoundUpService.Sweep(ctx, ruleID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.RoundUpSweepResponse{
+		Message: "Arredondamento transferido para a meta",
+		Amount:  amount,
+	})
+}
+
+func (h *Handler) GetRoundUpSummary(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	months := 12
+	if value := c.Query("months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 36 {
+			h.respondError(c, appErrors.NewValidationError("months", "deve ser um numero entre 1 e 36"))
+			return
+		}
+		months = parsed
+	}
+
+	ctx := c.Request.Context()
+	summary, err := h.RoundUpService.GetMonthlySummary(ctx, userID, months, time.Now())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	total := 0.0
+	for _, month := range summary {
+		total += month.Swept
+	}
+
+	c.JSON(http.StatusOK, RoundUpSummaryResponse{
+		Months:     summary,
+		TotalSaved: math.Round(total*100) / 100,
+	})
+}
+
+func (h *Handler) roundUpRuleParams(c *gin.Context) (ulid.ULID, ulid.ULID, bool) {
+	ruleID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	return ruleID, userID, true
+}
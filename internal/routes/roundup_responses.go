@@ -0,0 +1,18 @@
+package routes
+
+import "Fynance/internal/domain/roundup"
+
+// Response types for round-up routes
+type RoundUpRuleResponse struct {
+	Rule *roundup.Rule `json:"rule"`
+}
+
+type RoundUpRuleListResponse struct {
+	Rules []*roundup.Rule `json:"rules"`
+	Total int             `json:"total"`
+}
+
+type RoundUpSummaryResponse struct {
+	Months     []roundup.MonthlySummary `json:"months"`
+	TotalSaved float64                  `json:"totalSaved"`
+}
@@ -0,0 +1,204 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"Fynance/internal/contracts"
+	"Fynance/internal/domain/webhook"
+	appErrors "Fynance/internal/errors"
+	"Fynance/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+)
+
+func (h *Handler) ListWebhookEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, contracts.WebhookEventTypesResponse{Events: webhook.EventTypes})
+}
+
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	var body contracts.WebhookEndpointCreateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	endpoint, err := h.WebhookService.CreateEndpoint(ctx, userID, body.URL, body.Description, body.Events)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, WebhookEndpointSecretResponse{Endpoint: endpoint, Secret: endpoint.Secret})
+}
+
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	endpoints, err := h.WebhookService.ListEndpoints(ctx, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookEndpointListResponse{Webhooks: endpoints, Total: len(endpoints)})
+}
+
+func (h *Handler) GetWebhook(c *gin.Context) {
+	endpointID, userID, ok := h.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	endpoint, err := h.WebhookService.GetEndpoint(ctx, endpointID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	endpointID, userID, ok := h.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var body contracts.WebhookEndpointUpdateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.respondError(c, appErrors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	endpoint, err := h.WebhookService.UpdateEndpoint(ctx, endpointID, userID, webhook.EndpointUpdate{
+		URL:         body.URL,
+		Description: body.Description,
+		Events:      body.Events,
+		Active:      body.Active,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	endpointID, userID, ok := h.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.WebhookService.DeleteEndpoint(ctx, endpointID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.MessageResponse{Message: "Webhook removido com sucesso"})
+}
+
+func (h *Handler) RotateWebhookSecret(c *gin.Context) {
+	endpointID, userID, ok := h.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	endpoint, err := h.WebhookService.RotateSecret(ctx, endpointID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookEndpointSecretResponse{Endpoint: endpoint, Secret: endpoint.Secret})
+}
+
+func (h *Handler) PingWebhook(c *gin.Context) {
+	endpointID, userID, ok := h.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	delivery, err := h.WebhookService.Ping(ctx, endpointID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *Handler) ListWebhookDeliveries(c *gin.Context) {
+	endpointID, userID, ok := h.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	status := webhook.DeliveryStatus(strings.ToUpper(c.Query("status")))
+	pagination := h.parsePagination(c)
+
+	ctx := c.Request.Context()
+	deliveries, total, err := h.WebhookService.ListDeliveries(ctx, endpointID, userID, status, pagination)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.NewPaginatedResponse(deliveries, pagination.Page, pagination.Limit, total))
+}
+
+func (h *Handler) RedeliverWebhook(c *gin.Context) {
+	endpointID, userID, ok := h.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := pkg.ParseULID(c.Param("deliveryId"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("deliveryId", "formato inválido"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	delivery, err := h.WebhookService.Redeliver(ctx, endpointID, deliveryID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *Handler) webhookParams(c *gin.Context) (ulid.ULID, ulid.ULID, bool) {
+	endpointID, err := pkg.ParseULID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, appErrors.NewValidationError("id", "formato inválido"))
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	userID, err := h.GetUserIDFromContext(c)
+	if err != nil {
+		h.respondError(c, err)
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+	return endpointID, userID, true
+}
@@ -0,0 +1,15 @@
+package routes
+
+import "Fynance/internal/domain/webhook"
+
+// Response types for webhook routes
+type WebhookEndpointListResponse struct {
+	Webhooks []*webhook.Endpoint `json:"webhooks"`
+	Total    int                 `json:"total"`
+}
+
+// WebhookEndpointSecretResponse inclui o segredo de assinatura, exibido só na criação e na rotação
+type WebhookEndpointSecretResponse struct {
+	*webhook.Endpoint
+	Secret string `json:"secret"`
+}